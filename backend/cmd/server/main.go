@@ -3,21 +3,36 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-	"connectrpc.com/connect"
+	"github.com/go-sql-driver/mysql"
 	"github.com/wcygan/simple-connect-web-stack/internal/db"
+	"github.com/wcygan/simple-connect-web-stack/internal/features"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
 	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/reminder"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+	"github.com/wcygan/simple-connect-web-stack/internal/server"
 	"github.com/wcygan/simple-connect-web-stack/internal/service"
-	"buf.build/gen/go/wcygan/simple-connect-web-stack/connectrpc/go/todo/v1/todov1connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
 )
 
+// gitCommit is set at build time with -ldflags "-X main.gitCommit=<sha>"
+var gitCommit string
+
 func main() {
 	// Get database URL from environment
 	dbURL := os.Getenv("DATABASE_URL")
@@ -25,54 +40,338 @@ func main() {
 		dbURL = "root:root@tcp(localhost:3306)/todos?parseTime=true"
 	}
 
-	// Connect to database
-	database, err := sql.Open("mysql", dbURL)
+	// Statements such as "SET SESSION wait_timeout=600" to run on every new connection
+	sessionInit := db.ParseSessionInit(os.Getenv("DATABASE_INIT_SQL"))
+
+	// Connect to database; the URL scheme selects MySQL or SQLite
+	database, dialect, err := db.Open(dbURL, sessionInit...)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer database.Close()
+
+	// Optionally connect to a read replica for GetTask/ListTasks
+	var replica *sql.DB
+	if replicaURL := os.Getenv("DATABASE_REPLICA_URL"); replicaURL != "" {
+		var replicaDialect db.Dialect
+		replica, replicaDialect, err = db.Open(replicaURL, sessionInit...)
+		if err != nil {
+			log.Fatalf("Failed to connect to read replica: %v", err)
+		}
+		if replicaDialect != db.MySQL || dialect != db.MySQL {
+			log.Fatalf("Invalid DATABASE_REPLICA_URL: read replicas require MySQL")
+		}
+	}
 
 	// Wait for database to be ready
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	
-	for {
-		if err := database.PingContext(ctx); err == nil {
-			break
-		}
-		log.Println("Waiting for database to be ready...")
-		time.Sleep(2 * time.Second)
-		if ctx.Err() != nil {
-			log.Fatalf("Database connection timeout: %v", ctx.Err())
-		}
+	if err := waitForDB(ctx, database, 2*time.Second); err != nil {
+		log.Fatalf("Database not available: %v", err)
 	}
 
 	// Initialize database schema
-	if err := db.InitDB(database); err != nil {
+	if err := db.InitSchema(database, dialect); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// UNIQUE_TITLES=service checks titles as tasks are written; =database also
+	// adds a unique index
+	uniqueTitles := os.Getenv("UNIQUE_TITLES")
+	switch uniqueTitles {
+	case "", "service":
+	case "database":
+		ensureIndex := db.EnsureUniqueTitleIndex
+		if dialect == db.SQLite {
+			ensureIndex = db.EnsureSQLiteUniqueTitleIndex
+		}
+		if err := ensureIndex(database); err != nil {
+			log.Fatalf("Failed to enforce unique titles: %v", err)
+		}
+	default:
+		log.Fatalf("Invalid UNIQUE_TITLES: %q must be service or database", uniqueTitles)
+	}
+
+	// Optional behaviors, from FEATURES and per-flag overrides
+	flags, warnings := features.Load(os.LookupEnv)
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	if enabled := flags.List(); len(enabled) > 0 {
+		log.Printf("Enabled features: %s", strings.Join(enabled, ", "))
+	}
+
+	// The case_folded_search feature searches a generated lowercase title
+	// column, which the SQLite schema always has
+	caseFoldedSearch := flags.Enabled(features.CaseFoldedSearch)
+	if caseFoldedSearch && dialect == db.MySQL {
+		if err := db.EnsureTitleLowerColumn(database); err != nil {
+			log.Fatalf("Failed to add title_lower column: %v", err)
+		}
+	}
+
 	// Set up logging and middleware
-	logLevel := middleware.GetLogLevel(os.Getenv("LOG_LEVEL"))
+	logLevel, err := readLogLevel()
+	if err != nil {
+		log.Fatalf("Invalid LOG_LEVEL_FILE: %v", err)
+	}
 	logger := middleware.NewStructuredLogger(logLevel)
-	middlewareStack := middleware.NewMiddlewareStack(logger)
+	logKeyStyle, err := middleware.ParseLogKeyStyle(os.Getenv("LOG_KEY_STYLE"))
+	if err != nil {
+		log.Fatalf("Invalid LOG_KEY_STYLE: %v", err)
+	}
+	logger.SetKeyStyle(logKeyStyle)
+	logger.SetIncludeInstanceID(os.Getenv("LOG_INSTANCE_ID") == "true")
 
-	// Create service
-	todoService := service.NewTodoService(database)
+	// Optionally keep the last N request errors in memory for a debug endpoint
+	var recentErrors *middleware.RecentErrors
+	if flags.Enabled(features.DebugErrors) {
+		recentErrors = middleware.NewRecentErrors(getEnvInt("DEBUG_ERRORS_CAPACITY", 50))
+	}
 
-	// Create HTTP mux
-	mux := http.NewServeMux()
+	latencyBudgets, err := middleware.ParseLatencyBudgets(os.Getenv("SLO_BUDGETS"))
+	if err != nil {
+		log.Fatalf("Invalid SLO_BUDGETS: %v", err)
+	}
+	trustedProxies := getTrustedProxies()
+
+	requestLogMode, err := middleware.ParseRequestLogMode(os.Getenv("REQUEST_LOG_MODE"))
+	if err != nil {
+		log.Fatalf("Invalid REQUEST_LOG_MODE: %v", err)
+	}
 
-	// Mount the TodoService with Connect interceptors
-	interceptors := middlewareStack.GetConnectInterceptors()
-	path, handler := todov1connect.NewTodoServiceHandler(todoService, connect.WithInterceptors(interceptors...))
-	mux.Handle(path, handler)
+	// Keep frequently polled probe endpoints out of the request logs
+	logExclusions := middleware.DefaultLogExclusions
+	if spec, ok := os.LookupEnv("LOG_EXCLUDE_PATHS"); ok {
+		logExclusions = middleware.ParseLogExclusions(spec)
+	}
 
-	// Apply middleware stack (includes logging, recovery, request ID, etc.)
-	finalHandler := middlewareStack.WrapHandler(mux)
-	
-	// Add CORS middleware on top
-	corsHandler := withCORS(finalHandler)
+	securityHeaders := middleware.DefaultSecurityHeaders()
+	if csp, ok := os.LookupEnv("CONTENT_SECURITY_POLICY"); ok {
+		securityHeaders.ContentSecurityPolicy = csp
+	}
+	securityHeaders, err = middleware.ParseDisabledSecurityHeaders(securityHeaders, os.Getenv("SECURITY_HEADERS_DISABLED"))
+	if err != nil {
+		log.Fatalf("Invalid SECURITY_HEADERS_DISABLED: %v", err)
+	}
+
+	envelopeMode, err := middleware.ParseEnvelopeMode(os.Getenv("RESPONSE_ENVELOPE"))
+	if err != nil {
+		log.Fatalf("Invalid RESPONSE_ENVELOPE: %v", err)
+	}
+
+	// Optional fault injection for exercising client retries outside production
+	chaos := getChaos()
+
+	// Per-API-key request quotas, counted over a sliding minute
+	apiKeyQuotas, err := middleware.ParseAPIKeyQuotas(os.Getenv("API_KEY_QUOTAS"))
+	if err != nil {
+		log.Fatalf("Invalid API_KEY_QUOTAS: %v", err)
+	}
+	keyQuotas := middleware.NewKeyQuotas(apiKeyQuotas, time.Minute)
+
+	// API key authentication, blocking client IPs that fail repeatedly
+	authPolicy := middleware.DefaultAuthFailurePolicy
+	authPolicy.MaxFailures = getEnvInt("AUTH_MAX_FAILURES", authPolicy.MaxFailures)
+	authPolicy.Window = getEnvDuration("AUTH_BLOCK_DURATION", authPolicy.Window)
+	apiKeyAuth := middleware.NewAPIKeyAuth(middleware.ParseAPIKeys(os.Getenv("API_KEYS")), logger, authPolicy)
+
+	// Multi-tenancy: scope every RPC to the tenant named in X-Tenant-ID
+	tenancy := flags.Enabled(features.Tenancy)
+	defaultTenant := os.Getenv("DEFAULT_TENANT")
+	if tenancy && defaultTenant != "" {
+		if err := middleware.ValidateTenantID(defaultTenant); err != nil {
+			log.Fatalf("Invalid DEFAULT_TENANT: %v", err)
+		}
+	}
+
+	// Choose how new task IDs are generated (uuid by default, ulid or sequential)
+	idGen, err := repository.NewIDGenerator(ctx, database, os.Getenv("ID_STRATEGY"))
+	if err != nil {
+		log.Fatalf("Invalid ID_STRATEGY: %v", err)
+	}
+
+	// Create repository and service
+	repoOpts := []repository.Option{
+		repository.WithIDGenerator(idGen),
+		repository.WithApproximateCountThreshold(uint32(getEnvInt("APPROXIMATE_COUNT_THRESHOLD", 0))),
+	}
+	if flags.Enabled(features.PreparedStatements) {
+		repoOpts = append(repoOpts, repository.WithPreparedStatements())
+	}
+	if replica != nil {
+		repoOpts = append(repoOpts,
+			repository.WithReadReplica(replica),
+			repository.WithPrimaryReadWindow(getEnvDuration("PRIMARY_READ_WINDOW", 0)),
+		)
+	}
+	if caseFoldedSearch {
+		repoOpts = append(repoOpts, repository.WithCaseFoldedSearch())
+	}
+	if flags.Enabled(features.WindowedCount) {
+		repoOpts = append(repoOpts, repository.WithWindowedCount())
+	}
+	// SQLite always stamps timestamps in the application; MySQL can opt in
+	if flags.Enabled(features.AppTimestamps) {
+		repoOpts = append(repoOpts, repository.WithAppTimestamps())
+	}
+	if flags.Enabled(features.ListVersion) {
+		repoOpts = append(repoOpts, repository.WithListVersion())
+	}
+	if flags.Enabled(features.SoftDelete) {
+		repoOpts = append(repoOpts, repository.WithSoftDelete())
+	}
+	if flags.Enabled(features.SkipCreateReread) {
+		repoOpts = append(repoOpts, repository.WithoutCreateReread())
+	}
+	if value := os.Getenv("DEFAULT_SORT"); value != "" {
+		field, order, err := parseDefaultSort(value)
+		if err != nil {
+			log.Fatalf("Invalid DEFAULT_SORT: %v", err)
+		}
+		repoOpts = append(repoOpts, repository.WithDefaultSort(field, order))
+	}
+	if uniqueTitles != "" {
+		repoOpts = append(repoOpts, repository.WithUniqueTitles())
+	}
+	var repo repository.TodoRepository
+	if dialect == db.SQLite {
+		repo = repository.NewSQLiteTodoRepositoryWithLogger(database, logger, repoOpts...)
+	} else {
+		repo = repository.NewMySQLTodoRepositoryWithLogger(database, logger, repoOpts...)
+	}
+	// The undecorated repository releases its prepared statements at shutdown
+	store := repo
+
+	// Optionally cache GetTask lookups in memory
+	var cache *repository.CachingTodoRepository
+	if size := getEnvInt("CACHE_SIZE", 0); size > 0 {
+		cache = repository.NewCachingTodoRepository(repo, size, getEnvDuration("CACHE_TTL", 30*time.Second))
+		repo = cache
+	}
+
+	// Optionally log the duration of every repository call, including cache hits
+	if flags.Enabled(features.InstrumentRepository) {
+		repo = repository.NewInstrumentedTodoRepository(repo, repository.LogCallRecorder{Logger: logger})
+	}
+	archiveAfter := getEnvDuration("ARCHIVE_AFTER", 0)
+	trashRetention := getEnvDuration("TRASH_RETENTION", 0)
+	// Optionally suggest truncated titles and reject titles containing blocked terms
+	validation := validator.Config{
+		SuggestTruncation:    flags.Enabled(features.SuggestTitleTruncation),
+		RequireLetterOrDigit: flags.Enabled(features.RequireTitleLetterOrDigit),
+		MaxQueryLength:       getEnvInt("MAX_QUERY_LENGTH", validator.DefaultMaxQueryLength),
+	}
+	if path := os.Getenv("TITLE_BLOCKLIST_FILE"); path != "" {
+		validation.Blocklist, err = validator.LoadBlocklist(path)
+		if err != nil {
+			log.Fatalf("Invalid TITLE_BLOCKLIST_FILE: %v", err)
+		}
+	}
+
+	serviceConfig := service.Config{
+		PublicBaseURL: os.Getenv("PUBLIC_BASE_URL"),
+		MaxTasks:      uint32(getEnvInt("MAX_TASKS", 0)),
+		ArchiveAfter:  archiveAfter,
+		Validation:    validation,
+
+		TrashRetention: trashRetention,
+
+		WriteNoOpUpdates: flags.Enabled(features.WriteNoOpUpdates),
+		Logger:           logger,
+		GitCommit:        buildCommit(),
+
+		ChangePollInterval: getEnvDuration("CHANGE_POLL_INTERVAL", time.Second),
+	}
+
+	// Background goroutines are started through this so shutdown can wait for them
+	background := newBackgroundTasks()
+
+	// Re-read the log level on SIGHUP so verbosity can be raised during an
+	// incident without a restart
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+	background.Go("log-level-reload", func(ctx context.Context) {
+		runLogLevelReload(ctx, reload, logger)
+	})
+
+	// Periodically archive old completed tasks when a retention period is set
+	if archiveAfter > 0 {
+		archiveInterval := getEnvDuration("ARCHIVE_INTERVAL", time.Hour)
+		if archiveInterval == 0 {
+			log.Fatalf("Invalid ARCHIVE_INTERVAL: must be greater than zero")
+		}
+		background.Go("archiver", func(ctx context.Context) {
+			runArchiver(ctx, repo, logger, archiveAfter, archiveInterval)
+		})
+	}
+
+	// Periodically purge tasks that have been in the trash past the retention period
+	if trashRetention > 0 {
+		if !flags.Enabled(features.SoftDelete) {
+			log.Fatalf("Invalid TRASH_RETENTION: requires the soft_delete feature")
+		}
+		purgeInterval := getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour)
+		if purgeInterval == 0 {
+			log.Fatalf("Invalid TRASH_PURGE_INTERVAL: must be greater than zero")
+		}
+		background.Go("trash-purger", func(ctx context.Context) {
+			runTrashPurger(ctx, repo, logger, trashRetention, purgeInterval)
+		})
+	}
+
+	// Emit an event when a task becomes due while still incomplete
+	if reminderInterval := getEnvDuration("REMINDER_INTERVAL", time.Minute); reminderInterval > 0 {
+		scheduler := reminder.NewScheduler(repo, nil, logger)
+		background.Go("reminders", func(ctx context.Context) {
+			scheduler.Run(ctx, reminderInterval)
+		})
+	}
+
+	// Report cache effectiveness for each interval
+	if cache != nil {
+		statsInterval := getEnvDuration("CACHE_STATS_INTERVAL", time.Minute)
+		if statsInterval > 0 {
+			background.Go("cache-stats", func(ctx context.Context) {
+				runCacheStats(ctx, cache, logger, statsInterval)
+			})
+		}
+	}
+
+	// Assemble the service, middleware stack, health endpoints and CORS
+	debugPath := os.Getenv("DEBUG_ERRORS_PATH")
+	handler, err := server.NewServer(database, server.Config{
+		Dialect:    dialect,
+		Repository: repo,
+		Logger:     logger,
+		Service:    serviceConfig,
+		Middleware: func(stack *middleware.MiddlewareStack) {
+			stack.ErrorHandler().SetLatencyBudgets(latencyBudgets)
+			stack.ErrorHandler().SetTrustedProxies(trustedProxies)
+			stack.ErrorHandler().SetLogExclusions(logExclusions)
+			stack.ErrorHandler().SetRequestLogMode(requestLogMode)
+			stack.SetSecurityHeaders(securityHeaders)
+			stack.SetEnvelopeMode(envelopeMode)
+			stack.SetChaos(chaos)
+			stack.SetAPIKeyAuth(apiKeyAuth)
+			stack.SetKeyQuotas(keyQuotas)
+			if tenancy {
+				stack.EnableTenancy(defaultTenant)
+			}
+		},
+		RecentErrors:     recentErrors,
+		RecentErrorsPath: debugPath,
+		MaxMessageBytes:  getEnvInt("MAX_MESSAGE_BYTES", 4<<20),
+		// Reject unknown fields in JSON requests rather than silently dropping them
+		StrictJSON:        flags.Enabled(features.StrictJSON),
+		DisableGRPCHealth: os.Getenv("GRPC_HEALTH") == "false",
+		Reflection:        reflectionEnabled(),
+		Version:           os.Getenv("SERVICE_VERSION"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to build server: %v", err)
+	}
 
 	// Get port from environment or default to 3007
 	port := os.Getenv("PORT")
@@ -81,15 +380,12 @@ func main() {
 	}
 
 	// Create server
-	server := &http.Server{
-		Addr:    ":" + port,
-		Handler: corsHandler,
-	}
+	httpServer := newHTTPServer(":"+port, handler)
 
 	// Start server in goroutine
 	go func() {
 		log.Printf("Server starting on :%s", port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed: %v", err)
 		}
 	}()
@@ -99,31 +395,379 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	// Graceful shutdown
-	log.Println("Shutting down server...")
+	// Graceful shutdown: stop background work, drain HTTP, then close the database
+	// so nothing still running can hit "database is closed"
+	log.Println("Shutting down: stopping background tasks...")
+	background.Stop()
+
+	log.Println("Shutting down: draining HTTP server...")
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Shutting down: closing database...")
+	if closer, ok := store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("Failed to close repository: %v", err)
+		}
+	}
+	if err := database.Close(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	if replica != nil {
+		if err := replica.Close(); err != nil {
+			log.Printf("Failed to close read replica: %v", err)
+		}
 	}
 
 	log.Println("Server exited")
 }
 
-// withCORS adds CORS headers to support browser requests
-func withCORS(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Allow requests from the frontend
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Connect-Protocol-Version")
-		
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+// reflectionEnabled reports whether gRPC reflection should be served. It
+// defaults to on outside production so local tooling works out of the box.
+func reflectionEnabled() bool {
+	if value := os.Getenv("ENABLE_REFLECTION"); value != "" {
+		return value == "true"
+	}
+	return os.Getenv("ENVIRONMENT") != "production"
+}
+
+// newHTTPServer returns the server for handler on addr. Against slowloris
+// clients, READ_HEADER_TIMEOUT bounds how long a client may take to send its
+// headers and MAX_HEADER_BYTES bounds how much header data it may send.
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: getEnvDuration("READ_HEADER_TIMEOUT", 10*time.Second),
+		MaxHeaderBytes:    getEnvInt("MAX_HEADER_BYTES", http.DefaultMaxHeaderBytes),
+	}
+}
+
+// getChaos reads the fault injection settings: CHAOS_LATENCY_MS delays each RPC
+// by up to that many milliseconds and CHAOS_ERROR_RATE fails that fraction of
+// RPCs with Unavailable. CHAOS_SEED fixes the random sequence. Chaos is never
+// enabled in production; it returns nil when nothing is configured.
+func getChaos() *middleware.Chaos {
+	latency := time.Duration(getEnvInt("CHAOS_LATENCY_MS", 0)) * time.Millisecond
+	errorRate := 0.0
+	if value := os.Getenv("CHAOS_ERROR_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Fatalf("Invalid CHAOS_ERROR_RATE: %q must be a number between 0 and 1", value)
+		}
+		errorRate = rate
+	}
+	if latency == 0 && errorRate == 0 {
+		return nil
+	}
+	if os.Getenv("ENVIRONMENT") == "production" {
+		log.Println("Ignoring CHAOS_LATENCY_MS and CHAOS_ERROR_RATE in production")
+		return nil
+	}
+
+	seed := time.Now().UnixNano()
+	if value := os.Getenv("CHAOS_SEED"); value != "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid CHAOS_SEED: %q must be an integer", value)
+		}
+		seed = n
+	}
+	log.Printf("Chaos enabled: up to %v latency, %.2f error rate", latency, errorRate)
+	return middleware.NewChaos(latency, errorRate, seed)
+}
+
+// pinger is the part of *sql.DB that waitForDB needs
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// waitForDB pings db until it answers, retrying transient connectivity errors
+// every interval. Errors that retrying cannot fix, such as rejected credentials
+// or an unusable address in the DSN, are returned immediately; otherwise the
+// wait ends with ctx's error.
+func waitForDB(ctx context.Context, db pinger, interval time.Duration) error {
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if !isRetryableDBError(err) {
+			return fmt.Errorf("database rejected connection, not retrying: %w", err)
+		}
+
+		log.Printf("Waiting for database to be ready: %v", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database connection timeout: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// isRetryableDBError reports whether a ping failure may clear up on its own,
+// e.g. the server still starting or its hostname not yet resolvable
+func isRetryableDBError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1044, 1045, 1698: // access denied
+			return false
+		case 1049: // unknown database
+			return false
+		}
+		return true
+	}
+
+	var addrErr *net.AddrError
+	var netErr net.UnknownNetworkError
+	if errors.As(err, &addrErr) || errors.As(err, &netErr) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.Err == "unknown port" {
+		return false
+	}
+	return true
+}
+
+// buildCommit returns the commit injected via ldflags, falling back to the VCS
+// revision the Go toolchain stamps into binaries built from a checkout
+func buildCommit() string {
+	if gitCommit != "" {
+		return gitCommit
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				return setting.Value
+			}
+		}
+	}
+	return ""
+}
+
+// getEnvInt reads a non-negative integer environment variable, exiting on malformed values
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid %s: %q must be a non-negative integer", key, value)
+	}
+	return n
+}
+
+// getTrustedProxies reads TRUST_PROXY: "true" trusts one proxy hop, a number
+// trusts that many, and unset or "false" ignores forwarded headers
+func getTrustedProxies() int {
+	switch value := os.Getenv("TRUST_PROXY"); value {
+	case "", "false":
+		return 0
+	case "true":
+		return 1
+	default:
+		return getEnvInt("TRUST_PROXY", 0)
+	}
+}
+
+// parseDefaultSort parses DEFAULT_SORT, a sort field optionally followed by a
+// direction, such as "title:asc" or "updated_at"
+func parseDefaultSort(value string) (todov1.SortField, todov1.SortOrder, error) {
+	name, direction, _ := strings.Cut(strings.ToLower(strings.TrimSpace(value)), ":")
+
+	field, ok := todov1.SortField_value["SORT_FIELD_"+strings.ToUpper(name)]
+	if !ok || field == int32(todov1.SortField_SORT_FIELD_UNSPECIFIED) {
+		return 0, 0, fmt.Errorf("unknown sort field %q: use created_at, updated_at, title or position", name)
+	}
+
+	order := todov1.SortOrder_SORT_ORDER_UNSPECIFIED
+	switch direction {
+	case "":
+	case "asc":
+		order = todov1.SortOrder_SORT_ORDER_ASC
+	case "desc":
+		order = todov1.SortOrder_SORT_ORDER_DESC
+	default:
+		return 0, 0, fmt.Errorf("unknown sort direction %q: use asc or desc", direction)
+	}
+	return todov1.SortField(field), order, nil
+}
+
+// getEnvDuration reads a duration environment variable such as "2s", exiting on malformed values
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Fatalf("Invalid %s: %q must be a non-negative duration", key, value)
+	}
+	return d
+}
+
+// backgroundTasks tracks long-running goroutines that must finish before shutdown
+// proceeds to close shared resources such as the database
+type backgroundTasks struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newBackgroundTasks creates an empty background task group
+func newBackgroundTasks() *backgroundTasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundTasks{ctx: ctx, cancel: cancel}
+}
+
+// Go runs fn in a goroutine; fn must return promptly once ctx is cancelled
+func (b *backgroundTasks) Go(name string, fn func(ctx context.Context)) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		fn(b.ctx)
+		log.Printf("Background task %s stopped", name)
+	}()
+}
+
+// Stop signals every background task to stop and waits for them to return
+func (b *backgroundTasks) Stop() {
+	b.cancel()
+	b.wg.Wait()
+}
+
+// runArchiver archives completed tasks older than retention every interval until ctx is cancelled
+func runArchiver(ctx context.Context, repo repository.TodoRepository, logger *middleware.StructuredLogger, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-ticker.C:
+			archived, err := repo.ArchiveCompleted(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.Error(ctx, "Failed to archive completed tasks", err, nil)
+				continue
+			}
+			if archived > 0 {
+				logger.Info(ctx, "Archived completed tasks", map[string]interface{}{
+					"archived":  archived,
+					"retention": retention.String(),
+				})
+			}
 		}
-		
-		h.ServeHTTP(w, r)
-	})
-}
\ No newline at end of file
+	}
+}
+
+// runTrashPurger permanently removes tasks deleted more than retention ago every
+// interval until ctx is cancelled
+func runTrashPurger(ctx context.Context, repo repository.TodoRepository, logger *middleware.StructuredLogger, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := repo.PurgeDeleted(ctx, time.Now().Add(-retention), service.PurgeBatchSize)
+			if err != nil {
+				logger.Error(ctx, "Failed to purge deleted tasks", err, nil)
+				continue
+			}
+			if purged > 0 {
+				logger.Info(ctx, "Purged deleted tasks", map[string]interface{}{
+					"purged":    purged,
+					"retention": retention.String(),
+				})
+			}
+		}
+	}
+}
+
+// readLogLevel returns the level named in LOG_LEVEL_FILE when it is set, so an
+// edited file can be picked up on SIGHUP, and otherwise the level in LOG_LEVEL
+func readLogLevel() (middleware.LogLevel, error) {
+	path := os.Getenv("LOG_LEVEL_FILE")
+	if path == "" {
+		return middleware.GetLogLevel(os.Getenv("LOG_LEVEL")), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return middleware.LevelInfo, err
+	}
+	return middleware.GetLogLevel(strings.ToLower(strings.TrimSpace(string(data)))), nil
+}
+
+// runLogLevelReload applies the level from readLogLevel to logger each time a
+// signal arrives, logging every change through logger, until ctx is cancelled
+func runLogLevelReload(ctx context.Context, signals <-chan os.Signal, logger *middleware.StructuredLogger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			level, err := readLogLevel()
+			if err != nil {
+				logger.Error(ctx, "Failed to reload log level", err, map[string]interface{}{
+					"log_level": logger.Level().String(),
+				})
+				continue
+			}
+			previous := logger.Level()
+			if level == previous {
+				continue
+			}
+			// Announce the change under the more verbose of the two levels so
+			// it is not filtered out when logging gets quieter
+			fields := map[string]interface{}{
+				"previous_log_level": previous.String(),
+				"log_level":          level.String(),
+			}
+			if level > previous {
+				logger.Info(ctx, "Log level changed", fields)
+				logger.SetLevel(level)
+			} else {
+				logger.SetLevel(level)
+				logger.Info(ctx, "Log level changed", fields)
+			}
+		}
+	}
+}
+
+// runCacheStats logs cache hits, misses and evictions for each interval, plus
+// the hit ratio over that interval and the current size, until ctx is cancelled
+func runCacheStats(ctx context.Context, cache *repository.CachingTodoRepository, logger *middleware.StructuredLogger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := cache.Stats()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := cache.Stats()
+			window := current.Sub(last)
+			last = current
+
+			logger.LogMetrics(ctx, map[string]interface{}{
+				"cache_hits":      window.Hits,
+				"cache_misses":    window.Misses,
+				"cache_evictions": window.Evictions,
+				"cache_hit_ratio": window.HitRatio(),
+				"cache_size":      window.Size,
+				"window":          interval.String(),
+			})
+		}
+	}
+}
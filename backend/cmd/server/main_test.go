@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestReflectionEnabled(t *testing.T) {
+	t.Setenv("ENABLE_REFLECTION", "")
+	t.Setenv("ENVIRONMENT", "development")
+	if !reflectionEnabled() {
+		t.Error("Expected reflection on by default in development")
+	}
+
+	t.Setenv("ENVIRONMENT", "production")
+	if reflectionEnabled() {
+		t.Error("Expected reflection off by default in production")
+	}
+
+	t.Setenv("ENABLE_REFLECTION", "true")
+	if !reflectionEnabled() {
+		t.Error("Expected ENABLE_REFLECTION=true to override the environment default")
+	}
+}
+
+func TestParseDefaultSort(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantField todov1.SortField
+		wantOrder todov1.SortOrder
+		wantErr   bool
+	}{
+		{value: "title:asc", wantField: todov1.SortField_SORT_FIELD_TITLE, wantOrder: todov1.SortOrder_SORT_ORDER_ASC},
+		{value: "Updated_At:DESC", wantField: todov1.SortField_SORT_FIELD_UPDATED_AT, wantOrder: todov1.SortOrder_SORT_ORDER_DESC},
+		{value: "position", wantField: todov1.SortField_SORT_FIELD_POSITION},
+		{value: "unspecified", wantErr: true},
+		{value: "priority", wantErr: true},
+		{value: "title:sideways", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			field, order, err := parseDefaultSort(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected %q to be rejected", tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if field != tt.wantField || order != tt.wantOrder {
+				t.Errorf("Expected %v %v, got %v %v", tt.wantField, tt.wantOrder, field, order)
+			}
+		})
+	}
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	t.Setenv("MAX_HEADER_BYTES", "")
+	t.Setenv("READ_HEADER_TIMEOUT", "")
+	server := newHTTPServer(":3007", http.NotFoundHandler())
+	if server.MaxHeaderBytes != 1<<20 || server.ReadHeaderTimeout != 10*time.Second {
+		t.Errorf("Expected 1MB headers and a 10s header timeout by default, got %d and %v", server.MaxHeaderBytes, server.ReadHeaderTimeout)
+	}
+
+	t.Setenv("MAX_HEADER_BYTES", "8192")
+	t.Setenv("READ_HEADER_TIMEOUT", "2s")
+	server = newHTTPServer(":3007", http.NotFoundHandler())
+	if server.MaxHeaderBytes != 8192 || server.ReadHeaderTimeout != 2*time.Second {
+		t.Errorf("Expected the configured limits, got %d and %v", server.MaxHeaderBytes, server.ReadHeaderTimeout)
+	}
+	if server.Addr != ":3007" || server.Handler == nil {
+		t.Errorf("Expected the address and handler to be kept, got %q", server.Addr)
+	}
+}
+
+// flakyPinger fails with err for the first failures pings, then succeeds
+type flakyPinger struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (p *flakyPinger) PingContext(ctx context.Context) error {
+	p.calls++
+	if p.calls <= p.failures {
+		return p.err
+	}
+	return nil
+}
+
+func TestReadLogLevel(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_LEVEL_FILE", "")
+	if level, err := readLogLevel(); err != nil || level != middleware.LevelWarn {
+		t.Errorf("Expected LOG_LEVEL to apply without a file, got %v %v", level, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "log-level")
+	if err := os.WriteFile(path, []byte("DEBUG\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write level file: %v", err)
+	}
+	t.Setenv("LOG_LEVEL_FILE", path)
+	if level, err := readLogLevel(); err != nil || level != middleware.LevelDebug {
+		t.Errorf("Expected the file to override LOG_LEVEL, got %v %v", level, err)
+	}
+
+	t.Setenv("LOG_LEVEL_FILE", filepath.Join(t.TempDir(), "missing"))
+	if _, err := readLogLevel(); err == nil {
+		t.Error("Expected an error for a missing level file")
+	}
+}
+
+func TestRunLogLevelReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log-level")
+	t.Setenv("LOG_LEVEL_FILE", path)
+
+	var out bytes.Buffer
+	logger := middleware.NewStructuredLogger(middleware.LevelInfo)
+	logger.SetOutput(&out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		runLogLevelReload(ctx, signals, logger)
+		close(done)
+	}()
+
+	// The second signal is only received once the first has been handled,
+	// and re-reading an unchanged file logs no change
+	reload := func(level string) {
+		t.Helper()
+		if level == "" {
+			os.Remove(path)
+		} else if err := os.WriteFile(path, []byte(level), 0o644); err != nil {
+			t.Fatalf("Failed to write level file: %v", err)
+		}
+		signals <- syscall.SIGHUP
+		signals <- syscall.SIGHUP
+	}
+
+	// Raising the level is announced before quieter logging takes effect
+	reload("error")
+	// An unreadable file is reported and the level kept
+	reload("")
+	reload("debug")
+	cancel()
+	<-done
+
+	if logger.Level() != middleware.LevelDebug {
+		t.Errorf("Expected DEBUG after reloading, got %s", logger.Level())
+	}
+	logs := out.String()
+	for _, want := range []string{
+		`"message":"Log level changed","fields":{"log_level":"ERROR","previous_log_level":"INFO"}`,
+		`"message":"Failed to reload log level"`,
+		`"message":"Log level changed","fields":{"log_level":"DEBUG","previous_log_level":"ERROR"}`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("Expected logs to contain %s, got:\n%s", want, logs)
+		}
+	}
+}
+
+func TestWaitForDB(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	t.Run("bad DSN fails fast", func(t *testing.T) {
+		database, err := sql.Open("mysql", "root:root@tcp(localhost:notaport)/todos")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer database.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		start := time.Now()
+		err = waitForDB(ctx, database, time.Second)
+		if err == nil {
+			t.Fatal("Expected an error for an unusable DSN")
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Errorf("Expected to fail without retrying, took %v", time.Since(start))
+		}
+	})
+
+	t.Run("bad credentials fail fast", func(t *testing.T) {
+		db := &flakyPinger{failures: 100, err: &mysql.MySQLError{Number: 1045, Message: "Access denied for user 'root'"}}
+
+		err := waitForDB(context.Background(), db, time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "not retrying") {
+			t.Errorf("Expected a non-retryable error, got %v", err)
+		}
+		if db.calls != 1 {
+			t.Errorf("Expected a single ping, got %d", db.calls)
+		}
+	})
+
+	t.Run("slow start is retried", func(t *testing.T) {
+		db := &flakyPinger{failures: 3, err: refused}
+
+		if err := waitForDB(context.Background(), db, time.Millisecond); err != nil {
+			t.Fatalf("Expected the database to become ready, got %v", err)
+		}
+		if db.calls != 4 {
+			t.Errorf("Expected 4 pings, got %d", db.calls)
+		}
+	})
+
+	t.Run("gives up at the deadline", func(t *testing.T) {
+		db := &flakyPinger{failures: 1 << 30, err: refused}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := waitForDB(ctx, db, time.Millisecond)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected a deadline error, got %v", err)
+		}
+	})
+}
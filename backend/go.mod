@@ -3,8 +3,6 @@ module github.com/wcygan/simple-connect-web-stack
 go 1.24
 
 require (
-	buf.build/gen/go/wcygan/simple-connect-web-stack/connectrpc/go v1.18.1-20250615194027-1ba9625cc7f0.1
-	buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go v1.36.6-20250615194027-1ba9625cc7f0.1
 	connectrpc.com/connect v1.18.1
 	github.com/go-sql-driver/mysql v1.8.1
 	github.com/google/uuid v1.6.0
@@ -12,7 +10,14 @@ require (
 	google.golang.org/protobuf v1.36.6
 )
 
-require github.com/mattn/go-sqlite3 v1.14.28
+require (
+	connectrpc.com/grpchealth v1.3.0
+	connectrpc.com/grpcreflect v1.3.0
+	github.com/DATA-DOG/go-sqlmock v1.5.2
+	github.com/mattn/go-sqlite3 v1.14.28
+	github.com/oklog/ulid/v2 v2.1.1
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250324211829-b45e905df463
+)
 
 require (
 	filippo.io/edwards25519 v1.1.0 // indirect
@@ -5,7 +5,8 @@ import (
 	"fmt"
 )
 
-// InitDB creates the tasks table if it doesn't exist
+// InitDB creates the tasks, list_versions and deleted_tasks tables if they
+// don't exist
 func InitDB(db *sql.DB) error {
 	query := `
 		CREATE TABLE IF NOT EXISTS tasks (
@@ -14,8 +15,18 @@ func InitDB(db *sql.DB) error {
 			completed BOOLEAN DEFAULT FALSE,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			archived_at TIMESTAMP NULL DEFAULT NULL,
+			tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+			position DOUBLE NOT NULL DEFAULT 0,
+			due_date TIMESTAMP NULL DEFAULT NULL,
+			reminded_at TIMESTAMP NULL DEFAULT NULL,
 			INDEX idx_created_at (created_at),
-			INDEX idx_completed (completed)
+			INDEX idx_completed (completed),
+			INDEX idx_archived_at (archived_at),
+			INDEX idx_title (title),
+			INDEX idx_tenant_created_at (tenant_id, created_at),
+			INDEX idx_position (position),
+			INDEX idx_due_date (due_date)
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 	`
 
@@ -24,5 +35,133 @@ func InitDB(db *sql.DB) error {
 		return fmt.Errorf("failed to create tasks table: %w", err)
 	}
 
+	// Tables created before archiving existed lack the archived_at column
+	if err := addColumnIfMissing(db, "archived_at",
+		"ALTER TABLE tasks ADD COLUMN archived_at TIMESTAMP NULL DEFAULT NULL, ADD INDEX idx_archived_at (archived_at)"); err != nil {
+		return err
+	}
+
+	// Title lookups back the optional unique-title check
+	if err := addIndexIfMissing(db, "idx_title", "ALTER TABLE tasks ADD INDEX idx_title (title)"); err != nil {
+		return err
+	}
+
+	// Tables created before multi-tenancy hold only default-tenant tasks
+	if err := addColumnIfMissing(db, "tenant_id",
+		"ALTER TABLE tasks ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default', ADD INDEX idx_tenant_created_at (tenant_id, created_at)"); err != nil {
+		return err
+	}
+
+	// Tables created before manual ordering start with every position at 0;
+	// ties fall back to id order until the first reorder rebalances them
+	if err := addColumnIfMissing(db, "position",
+		"ALTER TABLE tasks ADD COLUMN position DOUBLE NOT NULL DEFAULT 0, ADD INDEX idx_position (position)"); err != nil {
+		return err
+	}
+
+	// Tables created before due dates hold only unscheduled tasks
+	if err := addColumnIfMissing(db, "due_date",
+		"ALTER TABLE tasks ADD COLUMN due_date TIMESTAMP NULL DEFAULT NULL, ADD INDEX idx_due_date (due_date)"); err != nil {
+		return err
+	}
+
+	// Covers the per-state task counts behind GetStats
+	if err := addIndexIfMissing(db, "idx_tenant_state",
+		"ALTER TABLE tasks ADD INDEX idx_tenant_state (tenant_id, archived_at, completed)"); err != nil {
+		return err
+	}
+
+	// reminded_at records when the due-date reminder for a task was sent
+	if err := addColumnIfMissing(db, "reminded_at",
+		"ALTER TABLE tasks ADD COLUMN reminded_at TIMESTAMP NULL DEFAULT NULL"); err != nil {
+		return err
+	}
+
+	// list_versions holds the per-tenant counters behind repository.WithListVersion
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS list_versions (
+			tenant_id VARCHAR(64) PRIMARY KEY,
+			version BIGINT NOT NULL DEFAULT 0
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`); err != nil {
+		return fmt.Errorf("failed to create list_versions table: %w", err)
+	}
+
+	// deleted_tasks holds tasks removed while repository.WithSoftDelete is on
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS deleted_tasks (
+			id VARCHAR(36) PRIMARY KEY,
+			title VARCHAR(255) NOT NULL,
+			completed BOOLEAN DEFAULT FALSE,
+			created_at TIMESTAMP NULL DEFAULT NULL,
+			updated_at TIMESTAMP NULL DEFAULT NULL,
+			archived_at TIMESTAMP NULL DEFAULT NULL,
+			tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+			position DOUBLE NOT NULL DEFAULT 0,
+			due_date TIMESTAMP NULL DEFAULT NULL,
+			reminded_at TIMESTAMP NULL DEFAULT NULL,
+			deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			INDEX idx_deleted_at (deleted_at),
+			INDEX idx_tenant_deleted_at (tenant_id, deleted_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci
+	`); err != nil {
+		return fmt.Errorf("failed to create deleted_tasks table: %w", err)
+	}
+
+	return nil
+}
+
+// EnsureUniqueTitleIndex adds a unique index on (tenant_id, title) so the database
+// rejects duplicate titles within a tenant. It fails if the table already contains
+// duplicates.
+func EnsureUniqueTitleIndex(db *sql.DB) error {
+	return addIndexIfMissing(db, "uniq_tenant_title", "ALTER TABLE tasks ADD UNIQUE INDEX uniq_tenant_title (tenant_id, title)")
+}
+
+// EnsureTitleLowerColumn adds title_lower, a stored lowercase copy of title with a
+// binary collation, so case-folded search behaves the same under any table collation
+func EnsureTitleLowerColumn(db *sql.DB) error {
+	return addColumnIfMissing(db, "title_lower",
+		"ALTER TABLE tasks ADD COLUMN title_lower VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin "+
+			"GENERATED ALWAYS AS (LOWER(title)) STORED, ADD INDEX idx_title_lower (title_lower)")
+}
+
+// addIndexIfMissing runs alter when the tasks table has no index with the given name
+func addIndexIfMissing(db *sql.DB, index, alter string) error {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'tasks' AND INDEX_NAME = ?
+	`, index).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to inspect tasks indexes: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(alter); err != nil {
+		return fmt.Errorf("failed to add %s index: %w", index, err)
+	}
+	return nil
+}
+
+// addColumnIfMissing runs alter when the tasks table has no column with the given name
+func addColumnIfMissing(db *sql.DB, column, alter string) error {
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(*) FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'tasks' AND COLUMN_NAME = ?
+	`, column).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to inspect tasks columns: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(alter); err != nil {
+		return fmt.Errorf("failed to add %s column: %w", column, err)
+	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// ParseSessionInit splits DATABASE_INIT_SQL into statements on ";", dropping
+// empty ones, e.g. "SET SESSION sql_mode='STRICT_ALL_TABLES'; SET SESSION wait_timeout=600"
+func ParseSessionInit(spec string) []string {
+	var statements []string
+	for _, statement := range strings.Split(spec, ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+// sessionInitConnector runs statements on every connection it opens, before
+// the pool hands it out. The statements run after any session parameters in
+// the DSN, so they take precedence over them.
+type sessionInitConnector struct {
+	base       driver.Connector
+	statements []string
+}
+
+// Connect opens a connection and initializes its session. A failing statement
+// closes the connection and fails the operation that needed it; the pool stays
+// usable and tries again with the next new connection.
+func (c *sessionInitConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("connection init: driver does not support Exec")
+	}
+	for _, statement := range c.statements {
+		if _, err := execer.ExecContext(ctx, statement, nil); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("connection init %q failed: %w", statement, err)
+		}
+	}
+	return conn, nil
+}
+
+// Driver returns the underlying driver
+func (c *sessionInitConnector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// dsnConnector adapts a driver without its own Connector to sql.OpenDB
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
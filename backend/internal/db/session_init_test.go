@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseSessionInit(t *testing.T) {
+	got := ParseSessionInit(" SET SESSION sql_mode='STRICT_ALL_TABLES';; SET SESSION wait_timeout=600; ")
+	want := []string{"SET SESSION sql_mode='STRICT_ALL_TABLES'", "SET SESSION wait_timeout=600"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if ParseSessionInit("") != nil {
+		t.Error("Expected no statements for an empty spec")
+	}
+}
+
+func TestOpen_SessionInit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("runs on every new connection", func(t *testing.T) {
+		// Each :memory: connection is a separate database, so the temp table
+		// only exists if init ran on that connection
+		database, _, err := Open("sqlite://:memory:", "CREATE TEMP TABLE session_marker (id INTEGER)", "INSERT INTO session_marker VALUES (1)")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		defer database.Close()
+		database.SetMaxIdleConns(0) // Every query gets a fresh connection
+
+		for i := 0; i < 3; i++ {
+			var count int
+			if err := database.QueryRowContext(ctx, "SELECT COUNT(*) FROM session_marker").Scan(&count); err != nil {
+				t.Fatalf("Expected init SQL to have run on connection %d: %v", i, err)
+			}
+			if count != 1 {
+				t.Errorf("Expected init SQL to run once per connection, got %d rows", count)
+			}
+		}
+	})
+
+	t.Run("failure closes the connection", func(t *testing.T) {
+		database, _, err := Open("sqlite://:memory:", "NOT VALID SQL")
+		if err != nil {
+			t.Fatalf("Expected Open to defer init to the first connection, got %v", err)
+		}
+		defer database.Close()
+
+		err = database.PingContext(ctx)
+		if err == nil || !strings.Contains(err.Error(), "connection init") {
+			t.Fatalf("Expected a connection init error, got %v", err)
+		}
+		if open := database.Stats().OpenConnections; open != 0 {
+			t.Errorf("Expected the failed connection to be closed, got %d open", open)
+		}
+	})
+}
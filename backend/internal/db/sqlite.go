@@ -0,0 +1,72 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// InitSQLite creates the tasks table and its indexes, and the list_versions
+// and deleted_tasks tables, in a SQLite database.
+// SQLite has no ON UPDATE CURRENT_TIMESTAMP, so updated_at is set by the
+// repository (see repository.NewSQLiteTodoRepository). title_lower is always
+// present, so WithCaseFoldedSearch needs no extra migration.
+func InitSQLite(db *sql.DB) error {
+	statements := []string{`
+		CREATE TABLE IF NOT EXISTS tasks (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			completed BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			archived_at DATETIME NULL DEFAULT NULL,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			position REAL NOT NULL DEFAULT 0,
+			due_date DATETIME NULL DEFAULT NULL,
+			reminded_at DATETIME NULL DEFAULT NULL,
+			title_lower TEXT GENERATED ALWAYS AS (lower(title)) STORED
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_created_at ON tasks (created_at)",
+		"CREATE INDEX IF NOT EXISTS idx_completed ON tasks (completed)",
+		"CREATE INDEX IF NOT EXISTS idx_archived_at ON tasks (archived_at)",
+		"CREATE INDEX IF NOT EXISTS idx_title ON tasks (title)",
+		"CREATE INDEX IF NOT EXISTS idx_tenant_created_at ON tasks (tenant_id, created_at)",
+		"CREATE INDEX IF NOT EXISTS idx_position ON tasks (position)",
+		"CREATE INDEX IF NOT EXISTS idx_due_date ON tasks (due_date)",
+		"CREATE INDEX IF NOT EXISTS idx_title_lower ON tasks (title_lower)",
+		"CREATE INDEX IF NOT EXISTS idx_tenant_state ON tasks (tenant_id, archived_at, completed)",
+		`CREATE TABLE IF NOT EXISTS list_versions (
+			tenant_id TEXT PRIMARY KEY,
+			version INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE IF NOT EXISTS deleted_tasks (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			completed BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at DATETIME NULL DEFAULT NULL,
+			updated_at DATETIME NULL DEFAULT NULL,
+			archived_at DATETIME NULL DEFAULT NULL,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			position REAL NOT NULL DEFAULT 0,
+			due_date DATETIME NULL DEFAULT NULL,
+			reminded_at DATETIME NULL DEFAULT NULL,
+			deleted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		"CREATE INDEX IF NOT EXISTS idx_deleted_at ON deleted_tasks (deleted_at)",
+		"CREATE INDEX IF NOT EXISTS idx_tenant_deleted_at ON deleted_tasks (tenant_id, deleted_at)",
+	}
+
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return fmt.Errorf("failed to create tasks table: %w", err)
+		}
+	}
+	return nil
+}
+
+// EnsureSQLiteUniqueTitleIndex is EnsureUniqueTitleIndex for SQLite databases
+func EnsureSQLiteUniqueTitleIndex(db *sql.DB) error {
+	if _, err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS uniq_tenant_title ON tasks (tenant_id, title)"); err != nil {
+		return fmt.Errorf("failed to add uniq_tenant_title index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Package features loads the on/off switches for optional server behaviors
+// from the environment.
+package features
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flag names. Each can be listed in FEATURES or set on its own through the
+// environment variable of the same name in upper case, such as LIST_VERSION.
+const (
+	CaseFoldedSearch          = "case_folded_search"
+	PreparedStatements        = "prepared_statements"
+	WindowedCount             = "windowed_count"
+	AppTimestamps             = "app_timestamps"
+	ListVersion               = "list_version"
+	SoftDelete                = "soft_delete"
+	InstrumentRepository      = "instrument_repository"
+	WriteNoOpUpdates          = "write_noop_updates"
+	SuggestTitleTruncation    = "suggest_title_truncation"
+	RequireTitleLetterOrDigit = "require_title_letter_or_digit"
+	Tenancy                   = "tenancy"
+	DebugErrors               = "debug_errors"
+	StrictJSON                = "strict_json"
+	SkipCreateReread          = "skip_create_reread"
+)
+
+// known lists every flag; all default to off
+var known = []string{
+	CaseFoldedSearch,
+	PreparedStatements,
+	WindowedCount,
+	AppTimestamps,
+	ListVersion,
+	SoftDelete,
+	InstrumentRepository,
+	WriteNoOpUpdates,
+	SuggestTitleTruncation,
+	RequireTitleLetterOrDigit,
+	Tenancy,
+	DebugErrors,
+	StrictJSON,
+	SkipCreateReread,
+}
+
+// FeatureFlags records which optional behaviors are enabled. The zero value
+// has every flag off.
+type FeatureFlags struct {
+	enabled map[string]bool
+}
+
+// Load reads the FEATURES variable, a comma-separated list of flags to turn
+// on, then applies each flag's own variable as an override, so
+// FEATURES=list_version with LIST_VERSION=false leaves list versions off.
+// lookup is usually os.LookupEnv. Unknown names in FEATURES and overrides
+// that are not booleans are ignored and returned as warnings.
+func Load(lookup func(string) (string, bool)) (FeatureFlags, []string) {
+	flags := FeatureFlags{enabled: map[string]bool{}}
+	var warnings []string
+
+	if value, ok := lookup("FEATURES"); ok {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !isKnown(name) {
+				warnings = append(warnings, fmt.Sprintf("unknown feature flag %q in FEATURES", name))
+				continue
+			}
+			flags.enabled[name] = true
+		}
+	}
+
+	for _, name := range known {
+		key := strings.ToUpper(name)
+		value, ok := lookup(key)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("ignoring %s=%q: expected true or false", key, value))
+			continue
+		}
+		flags.enabled[name] = enabled
+	}
+
+	return flags, warnings
+}
+
+// Enabled reports whether the named flag is on
+func (f FeatureFlags) Enabled(name string) bool {
+	return f.enabled[name]
+}
+
+// List returns the enabled flags in sorted order, for logging at startup
+func (f FeatureFlags) List() []string {
+	var names []string
+	for name, enabled := range f.enabled {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isKnown reports whether name is a defined flag
+func isKnown(name string) bool {
+	for _, flag := range known {
+		if flag == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package features
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// env returns a lookup function over vars, standing in for os.LookupEnv
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	flags, warnings := Load(env(nil))
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+	for _, name := range known {
+		if flags.Enabled(name) {
+			t.Errorf("Expected %s to be off by default", name)
+		}
+	}
+	if (FeatureFlags{}).Enabled(ListVersion) {
+		t.Error("Expected the zero value to have every flag off")
+	}
+}
+
+func TestLoad_FeaturesList(t *testing.T) {
+	flags, warnings := Load(env(map[string]string{"FEATURES": " List_Version, windowed_count ,,"}))
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+	if !flags.Enabled(ListVersion) || !flags.Enabled(WindowedCount) {
+		t.Errorf("Expected listed flags to be on, got %v", flags.List())
+	}
+	if flags.Enabled(Tenancy) {
+		t.Error("Expected unlisted flags to stay off")
+	}
+	if got, want := flags.List(), []string{ListVersion, WindowedCount}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+}
+
+func TestLoad_OverridePrecedence(t *testing.T) {
+	flags, warnings := Load(env(map[string]string{
+		"FEATURES":       "list_version,tenancy",
+		"TENANCY":        "false",
+		"APP_TIMESTAMPS": "true",
+		"WINDOWED_COUNT": "",
+		"LIST_VERSION":   " ",
+	}))
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+
+	tests := map[string]bool{
+		ListVersion:   true,  // listed, blank override ignored
+		Tenancy:       false, // listed, overridden off
+		AppTimestamps: true,  // unlisted, overridden on
+		WindowedCount: false, // unlisted, empty override ignored
+	}
+	for name, want := range tests {
+		if got := flags.Enabled(name); got != want {
+			t.Errorf("Enabled(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestLoad_Warnings(t *testing.T) {
+	flags, warnings := Load(env(map[string]string{
+		"FEATURES":    "time_travel,list_version",
+		"STRICT_JSON": "yes",
+	}))
+
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %v", warnings)
+	}
+	if !strings.Contains(warnings[0], `"time_travel"`) {
+		t.Errorf("Expected a warning naming the unknown flag, got %q", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "STRICT_JSON") {
+		t.Errorf("Expected a warning naming the bad override, got %q", warnings[1])
+	}
+	if !flags.Enabled(ListVersion) || flags.Enabled(StrictJSON) || flags.Enabled("time_travel") {
+		t.Errorf("Expected only known, valid flags to apply, got %v", flags.List())
+	}
+}
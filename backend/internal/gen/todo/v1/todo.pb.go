@@ -0,0 +1,3080 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: todo/v1/todo.proto
+
+package todov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DueDateFilter options for task filtering
+type DueDateFilter int32
+
+const (
+	DueDateFilter_DUE_DATE_FILTER_UNSPECIFIED DueDateFilter = 0 // Same as ANY
+	DueDateFilter_DUE_DATE_FILTER_ANY         DueDateFilter = 1
+	DueDateFilter_DUE_DATE_FILTER_SCHEDULED   DueDateFilter = 2 // Tasks with a due date
+	DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED DueDateFilter = 3 // Tasks without a due date
+)
+
+// Enum value maps for DueDateFilter.
+var (
+	DueDateFilter_name = map[int32]string{
+		0: "DUE_DATE_FILTER_UNSPECIFIED",
+		1: "DUE_DATE_FILTER_ANY",
+		2: "DUE_DATE_FILTER_SCHEDULED",
+		3: "DUE_DATE_FILTER_UNSCHEDULED",
+	}
+	DueDateFilter_value = map[string]int32{
+		"DUE_DATE_FILTER_UNSPECIFIED": 0,
+		"DUE_DATE_FILTER_ANY":         1,
+		"DUE_DATE_FILTER_SCHEDULED":   2,
+		"DUE_DATE_FILTER_UNSCHEDULED": 3,
+	}
+)
+
+func (x DueDateFilter) Enum() *DueDateFilter {
+	p := new(DueDateFilter)
+	*p = x
+	return p
+}
+
+func (x DueDateFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DueDateFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[0].Descriptor()
+}
+
+func (DueDateFilter) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[0]
+}
+
+func (x DueDateFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DueDateFilter.Descriptor instead.
+func (DueDateFilter) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+// StatusFilter options for task filtering
+type StatusFilter int32
+
+const (
+	StatusFilter_STATUS_FILTER_UNSPECIFIED StatusFilter = 0
+	StatusFilter_STATUS_FILTER_ALL         StatusFilter = 1
+	StatusFilter_STATUS_FILTER_COMPLETED   StatusFilter = 2
+	StatusFilter_STATUS_FILTER_PENDING     StatusFilter = 3
+)
+
+// Enum value maps for StatusFilter.
+var (
+	StatusFilter_name = map[int32]string{
+		0: "STATUS_FILTER_UNSPECIFIED",
+		1: "STATUS_FILTER_ALL",
+		2: "STATUS_FILTER_COMPLETED",
+		3: "STATUS_FILTER_PENDING",
+	}
+	StatusFilter_value = map[string]int32{
+		"STATUS_FILTER_UNSPECIFIED": 0,
+		"STATUS_FILTER_ALL":         1,
+		"STATUS_FILTER_COMPLETED":   2,
+		"STATUS_FILTER_PENDING":     3,
+	}
+)
+
+func (x StatusFilter) Enum() *StatusFilter {
+	p := new(StatusFilter)
+	*p = x
+	return p
+}
+
+func (x StatusFilter) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StatusFilter) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[1].Descriptor()
+}
+
+func (StatusFilter) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[1]
+}
+
+func (x StatusFilter) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StatusFilter.Descriptor instead.
+func (StatusFilter) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+// SortField options for task sorting
+type SortField int32
+
+const (
+	SortField_SORT_FIELD_UNSPECIFIED SortField = 0
+	SortField_SORT_FIELD_CREATED_AT  SortField = 1
+	SortField_SORT_FIELD_UPDATED_AT  SortField = 2
+	SortField_SORT_FIELD_TITLE       SortField = 3
+	SortField_SORT_FIELD_POSITION    SortField = 4 // Manual order; ascending unless DESC is requested
+	SortField_SORT_FIELD_RELEVANCE   SortField = 5 // Best query matches first, then newest; default order without a query
+)
+
+// Enum value maps for SortField.
+var (
+	SortField_name = map[int32]string{
+		0: "SORT_FIELD_UNSPECIFIED",
+		1: "SORT_FIELD_CREATED_AT",
+		2: "SORT_FIELD_UPDATED_AT",
+		3: "SORT_FIELD_TITLE",
+		4: "SORT_FIELD_POSITION",
+		5: "SORT_FIELD_RELEVANCE",
+	}
+	SortField_value = map[string]int32{
+		"SORT_FIELD_UNSPECIFIED": 0,
+		"SORT_FIELD_CREATED_AT":  1,
+		"SORT_FIELD_UPDATED_AT":  2,
+		"SORT_FIELD_TITLE":       3,
+		"SORT_FIELD_POSITION":    4,
+		"SORT_FIELD_RELEVANCE":   5,
+	}
+)
+
+func (x SortField) Enum() *SortField {
+	p := new(SortField)
+	*p = x
+	return p
+}
+
+func (x SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[2].Descriptor()
+}
+
+func (SortField) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[2]
+}
+
+func (x SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortField.Descriptor instead.
+func (SortField) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
+// SortOrder options
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0
+	SortOrder_SORT_ORDER_ASC         SortOrder = 1
+	SortOrder_SORT_ORDER_DESC        SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASC",
+		2: "SORT_ORDER_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASC":         1,
+		"SORT_ORDER_DESC":        2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[3].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[3]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+// DeleteResultStatus describes the outcome of deleting a single task
+type DeleteResultStatus int32
+
+const (
+	DeleteResultStatus_DELETE_RESULT_STATUS_UNSPECIFIED DeleteResultStatus = 0
+	DeleteResultStatus_DELETE_RESULT_STATUS_DELETED     DeleteResultStatus = 1
+	DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND   DeleteResultStatus = 2
+	DeleteResultStatus_DELETE_RESULT_STATUS_ERROR       DeleteResultStatus = 3
+)
+
+// Enum value maps for DeleteResultStatus.
+var (
+	DeleteResultStatus_name = map[int32]string{
+		0: "DELETE_RESULT_STATUS_UNSPECIFIED",
+		1: "DELETE_RESULT_STATUS_DELETED",
+		2: "DELETE_RESULT_STATUS_NOT_FOUND",
+		3: "DELETE_RESULT_STATUS_ERROR",
+	}
+	DeleteResultStatus_value = map[string]int32{
+		"DELETE_RESULT_STATUS_UNSPECIFIED": 0,
+		"DELETE_RESULT_STATUS_DELETED":     1,
+		"DELETE_RESULT_STATUS_NOT_FOUND":   2,
+		"DELETE_RESULT_STATUS_ERROR":       3,
+	}
+)
+
+func (x DeleteResultStatus) Enum() *DeleteResultStatus {
+	p := new(DeleteResultStatus)
+	*p = x
+	return p
+}
+
+func (x DeleteResultStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteResultStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_todo_v1_todo_proto_enumTypes[4].Descriptor()
+}
+
+func (DeleteResultStatus) Type() protoreflect.EnumType {
+	return &file_todo_v1_todo_proto_enumTypes[4]
+}
+
+func (x DeleteResultStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteResultStatus.Descriptor instead.
+func (DeleteResultStatus) EnumDescriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+// Task represents a todo item. Timestamps are UTC instants.
+type Task struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                   // UUID v4
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                             // Task title (max 255 chars)
+	Completed     bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`                    // Completion status
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`    // Creation timestamp
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`    // Last update timestamp
+	ArchivedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"` // Archive timestamp, unset while active
+	Position      float64                `protobuf:"fixed64,7,opt,name=position,proto3" json:"position,omitempty"`                     // Manual ordering key, ascending
+	DueDate       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`          // When the task is due, unset if unscheduled
+	LocalTimes    *LocalTimes            `protobuf:"bytes,9,opt,name=local_times,json=localTimes,proto3" json:"local_times,omitempty"` // Set when the request sends an X-Timezone header
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_todo_v1_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Task) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Task) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Task) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *Task) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Task) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *Task) GetPosition() float64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *Task) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *Task) GetLocalTimes() *LocalTimes {
+	if x != nil {
+		return x.LocalTimes
+	}
+	return nil
+}
+
+// LocalTimes renders a task's timestamps in a client-requested time zone for
+// display, as RFC 3339 strings with that zone's offset. Unset timestamps are empty.
+type LocalTimes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimeZone      string                 `protobuf:"bytes,1,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"` // IANA time zone name from the X-Timezone header
+	CreatedAt     string                 `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ArchivedAt    string                 `protobuf:"bytes,4,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	DueDate       string                 `protobuf:"bytes,5,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocalTimes) Reset() {
+	*x = LocalTimes{}
+	mi := &file_todo_v1_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocalTimes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocalTimes) ProtoMessage() {}
+
+func (x *LocalTimes) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocalTimes.ProtoReflect.Descriptor instead.
+func (*LocalTimes) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LocalTimes) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *LocalTimes) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *LocalTimes) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *LocalTimes) GetArchivedAt() string {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return ""
+}
+
+func (x *LocalTimes) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+// CreateTaskRequest contains the data needed to create a new task
+type CreateTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`                    // Required, max 255 chars
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`                          // Optional client-generated UUID; generated server-side when empty
+	DueDate       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"` // Optional due date
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskRequest) Reset() {
+	*x = CreateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskRequest) ProtoMessage() {}
+
+func (x *CreateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateTaskRequest) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+// CreateTaskResponse returns the newly created task
+type CreateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTaskResponse) Reset() {
+	*x = CreateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTaskResponse) ProtoMessage() {}
+
+func (x *CreateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// GetTaskRequest identifies which task to retrieve
+type GetTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Task UUID
+	// Optional; when the task was last updated strictly before this time the
+	// response carries not_modified instead of the task. updated_at has whole
+	// second precision on MySQL, so a task updated in the same second as this
+	// time is returned again.
+	IfModifiedSince *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=if_modified_since,json=ifModifiedSince,proto3" json:"if_modified_since,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetTaskRequest) Reset() {
+	*x = GetTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskRequest) ProtoMessage() {}
+
+func (x *GetTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetTaskRequest) GetIfModifiedSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IfModifiedSince
+	}
+	return nil
+}
+
+// GetTaskResponse returns the requested task
+type GetTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	NotModified   bool                   `protobuf:"varint,2,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"` // Task unchanged since if_modified_since or If-None-Match; task is unset
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTaskResponse) Reset() {
+	*x = GetTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskResponse) ProtoMessage() {}
+
+func (x *GetTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+func (x *GetTaskResponse) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
+	}
+	return false
+}
+
+// ListTasksRequest contains filters and pagination options
+type ListTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Pagination
+	Page     uint32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                         // Page number (1-based), default: 1
+	PageSize uint32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // Items per page, default: 20, max: 100
+	// Filters
+	Query  string       `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`                              // Search in title
+	Status StatusFilter `protobuf:"varint,4,opt,name=status,proto3,enum=todo.v1.StatusFilter" json:"status,omitempty"` // Filter by completion status
+	// Sorting
+	SortBy    SortField `protobuf:"varint,5,opt,name=sort_by,json=sortBy,proto3,enum=todo.v1.SortField" json:"sort_by,omitempty"`          // Field to sort by
+	SortOrder SortOrder `protobuf:"varint,6,opt,name=sort_order,json=sortOrder,proto3,enum=todo.v1.SortOrder" json:"sort_order,omitempty"` // Sort direction
+	// Archived tasks are hidden unless requested
+	IncludeArchived bool `protobuf:"varint,7,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	// Return pending and completed tasks as separate groups, each paginated
+	// independently; status must be unset or ALL
+	GroupByStatus bool `protobuf:"varint,8,opt,name=group_by_status,json=groupByStatus,proto3" json:"group_by_status,omitempty"`
+	// Filter by whether a due date is set
+	DueDate DueDateFilter `protobuf:"varint,9,opt,name=due_date,json=dueDate,proto3,enum=todo.v1.DueDateFilter" json:"due_date,omitempty"`
+	// Return only the IDs of the page's tasks in task_ids, for clients that
+	// sync IDs first and fetch details on demand; cannot be combined with
+	// group_by_status
+	IdsOnly       bool `protobuf:"varint,10,opt,name=ids_only,json=idsOnly,proto3" json:"ids_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTasksRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTasksRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ListTasksRequest) GetStatus() StatusFilter {
+	if x != nil {
+		return x.Status
+	}
+	return StatusFilter_STATUS_FILTER_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetSortBy() SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return SortField_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetGroupByStatus() bool {
+	if x != nil {
+		return x.GroupByStatus
+	}
+	return false
+}
+
+func (x *ListTasksRequest) GetDueDate() DueDateFilter {
+	if x != nil {
+		return x.DueDate
+	}
+	return DueDateFilter_DUE_DATE_FILTER_UNSPECIFIED
+}
+
+func (x *ListTasksRequest) GetIdsOnly() bool {
+	if x != nil {
+		return x.IdsOnly
+	}
+	return false
+}
+
+// ListTasksResponse returns paginated tasks
+type ListTasksResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Tasks      []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Pagination *PaginationMetadata    `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	// Set instead of tasks/pagination when group_by_status is requested
+	Pending   *TaskGroup `protobuf:"bytes,3,opt,name=pending,proto3" json:"pending,omitempty"`
+	Completed *TaskGroup `protobuf:"bytes,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	// Page unchanged since the If-None-Match ETag; tasks and pagination are unset
+	NotModified bool `protobuf:"varint,5,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`
+	// List version read before the page; 0 when versions are not tracked
+	ListVersion uint64 `protobuf:"varint,6,opt,name=list_version,json=listVersion,proto3" json:"list_version,omitempty"`
+	// Set instead of tasks when ids_only is requested, in page order
+	TaskIds       []string `protobuf:"bytes,7,rep,name=task_ids,json=taskIds,proto3" json:"task_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetPagination() *PaginationMetadata {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetPending() *TaskGroup {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetCompleted() *TaskGroup {
+	if x != nil {
+		return x.Completed
+	}
+	return nil
+}
+
+func (x *ListTasksResponse) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
+	}
+	return false
+}
+
+func (x *ListTasksResponse) GetListVersion() uint64 {
+	if x != nil {
+		return x.ListVersion
+	}
+	return 0
+}
+
+func (x *ListTasksResponse) GetTaskIds() []string {
+	if x != nil {
+		return x.TaskIds
+	}
+	return nil
+}
+
+// ListTasksStreamResponse is one message of a ListTasksStream: chunks of tasks
+// in page order, then a single final pagination message
+type ListTasksStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ListTasksStreamResponse_Chunk
+	//	*ListTasksStreamResponse_Pagination
+	Payload       isListTasksStreamResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksStreamResponse) Reset() {
+	*x = ListTasksStreamResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksStreamResponse) ProtoMessage() {}
+
+func (x *ListTasksStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksStreamResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksStreamResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTasksStreamResponse) GetPayload() isListTasksStreamResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ListTasksStreamResponse) GetChunk() *TaskChunk {
+	if x != nil {
+		if x, ok := x.Payload.(*ListTasksStreamResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+func (x *ListTasksStreamResponse) GetPagination() *PaginationMetadata {
+	if x != nil {
+		if x, ok := x.Payload.(*ListTasksStreamResponse_Pagination); ok {
+			return x.Pagination
+		}
+	}
+	return nil
+}
+
+type isListTasksStreamResponse_Payload interface {
+	isListTasksStreamResponse_Payload()
+}
+
+type ListTasksStreamResponse_Chunk struct {
+	Chunk *TaskChunk `protobuf:"bytes,1,opt,name=chunk,proto3,oneof"`
+}
+
+type ListTasksStreamResponse_Pagination struct {
+	Pagination *PaginationMetadata `protobuf:"bytes,2,opt,name=pagination,proto3,oneof"`
+}
+
+func (*ListTasksStreamResponse_Chunk) isListTasksStreamResponse_Payload() {}
+
+func (*ListTasksStreamResponse_Pagination) isListTasksStreamResponse_Payload() {}
+
+// TaskChunk is a consecutive run of tasks from a streamed page
+type TaskChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskChunk) Reset() {
+	*x = TaskChunk{}
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskChunk) ProtoMessage() {}
+
+func (x *TaskChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskChunk.ProtoReflect.Descriptor instead.
+func (*TaskChunk) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TaskChunk) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// TaskGroup is one page of tasks sharing a completion status
+type TaskGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	Pagination    *PaginationMetadata    `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskGroup) Reset() {
+	*x = TaskGroup{}
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskGroup) ProtoMessage() {}
+
+func (x *TaskGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskGroup.ProtoReflect.Descriptor instead.
+func (*TaskGroup) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TaskGroup) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *TaskGroup) GetPagination() *PaginationMetadata {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+// PaginationMetadata provides pagination information
+type PaginationMetadata struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Page            uint32                 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                                              // Current page
+	PageSize        uint32                 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                      // Items per page
+	TotalPages      uint32                 `protobuf:"varint,3,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`                // Total number of pages
+	TotalItems      uint32                 `protobuf:"varint,4,opt,name=total_items,json=totalItems,proto3" json:"total_items,omitempty"`                // Total number of items
+	HasPrevious     bool                   `protobuf:"varint,5,opt,name=has_previous,json=hasPrevious,proto3" json:"has_previous,omitempty"`             // Whether there's a previous page
+	HasNext         bool                   `protobuf:"varint,6,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`                         // Whether there's a next page
+	TotalUnfiltered uint32                 `protobuf:"varint,7,opt,name=total_unfiltered,json=totalUnfiltered,proto3" json:"total_unfiltered,omitempty"` // Total number of items ignoring query/status filters
+	Approximate     bool                   `protobuf:"varint,8,opt,name=approximate,proto3" json:"approximate,omitempty"`                                // Whether total_items is a storage engine estimate
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PaginationMetadata) Reset() {
+	*x = PaginationMetadata{}
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaginationMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaginationMetadata) ProtoMessage() {}
+
+func (x *PaginationMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaginationMetadata.ProtoReflect.Descriptor instead.
+func (*PaginationMetadata) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PaginationMetadata) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *PaginationMetadata) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *PaginationMetadata) GetTotalPages() uint32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *PaginationMetadata) GetTotalItems() uint32 {
+	if x != nil {
+		return x.TotalItems
+	}
+	return 0
+}
+
+func (x *PaginationMetadata) GetHasPrevious() bool {
+	if x != nil {
+		return x.HasPrevious
+	}
+	return false
+}
+
+func (x *PaginationMetadata) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *PaginationMetadata) GetTotalUnfiltered() uint32 {
+	if x != nil {
+		return x.TotalUnfiltered
+	}
+	return 0
+}
+
+func (x *PaginationMetadata) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+// UpdateTaskRequest contains the task update data
+type UpdateTaskRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                            // Task UUID
+	Title        string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`                                      // New title (optional)
+	Completed    bool                   `protobuf:"varint,3,opt,name=completed,proto3" json:"completed,omitempty"`                             // New completion status
+	DueDate      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`                   // New due date (optional; unset keeps the current one)
+	ClearDueDate bool                   `protobuf:"varint,5,opt,name=clear_due_date,json=clearDueDate,proto3" json:"clear_due_date,omitempty"` // Remove the due date; cannot be combined with due_date or update_mask
+	// Fields to write: "title", "completed" and/or "due_date" (an unset
+	// due_date clears it). Unset keeps the default of always writing completed,
+	// and title and due_date when given.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskRequest) Reset() {
+	*x = UpdateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskRequest) ProtoMessage() {}
+
+func (x *UpdateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *UpdateTaskRequest) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetDueDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueDate
+	}
+	return nil
+}
+
+func (x *UpdateTaskRequest) GetClearDueDate() bool {
+	if x != nil {
+		return x.ClearDueDate
+	}
+	return false
+}
+
+func (x *UpdateTaskRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// UpdateTaskResponse returns the updated task
+type UpdateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTaskResponse) Reset() {
+	*x = UpdateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTaskResponse) ProtoMessage() {}
+
+func (x *UpdateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTaskResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UpdateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// DeleteTaskRequest identifies which task to delete
+type DeleteTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Task UUID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskRequest) Reset() {
+	*x = DeleteTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskRequest) ProtoMessage() {}
+
+func (x *DeleteTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DeleteTasksRequest identifies which tasks to delete
+type DeleteTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"` // Task UUIDs, max 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTasksRequest) Reset() {
+	*x = DeleteTasksRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTasksRequest) ProtoMessage() {}
+
+func (x *DeleteTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTasksRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTasksRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DeleteTasksRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// DeleteTaskResult reports the outcome for one id in a batch delete
+type DeleteTaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                          // Task UUID
+	Status        DeleteResultStatus     `protobuf:"varint,2,opt,name=status,proto3,enum=todo.v1.DeleteResultStatus" json:"status,omitempty"` // Outcome of the delete
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`                                    // Error message when status is ERROR
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTaskResult) Reset() {
+	*x = DeleteTaskResult{}
+	mi := &file_todo_v1_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTaskResult) ProtoMessage() {}
+
+func (x *DeleteTaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTaskResult.ProtoReflect.Descriptor instead.
+func (*DeleteTaskResult) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteTaskResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteTaskResult) GetStatus() DeleteResultStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeleteResultStatus_DELETE_RESULT_STATUS_UNSPECIFIED
+}
+
+func (x *DeleteTaskResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// DeleteTasksResponse returns per-id results and a summary
+type DeleteTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*DeleteTaskResult    `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	DeletedCount  uint32                 `protobuf:"varint,2,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`      // Number of tasks deleted
+	NotFoundCount uint32                 `protobuf:"varint,3,opt,name=not_found_count,json=notFoundCount,proto3" json:"not_found_count,omitempty"` // Number of ids that did not exist
+	ErrorCount    uint32                 `protobuf:"varint,4,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`            // Number of ids that failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTasksResponse) Reset() {
+	*x = DeleteTasksResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTasksResponse) ProtoMessage() {}
+
+func (x *DeleteTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTasksResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTasksResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteTasksResponse) GetResults() []*DeleteTaskResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *DeleteTasksResponse) GetDeletedCount() uint32 {
+	if x != nil {
+		return x.DeletedCount
+	}
+	return 0
+}
+
+func (x *DeleteTasksResponse) GetNotFoundCount() uint32 {
+	if x != nil {
+		return x.NotFoundCount
+	}
+	return 0
+}
+
+func (x *DeleteTasksResponse) GetErrorCount() uint32 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+// DuplicateTaskRequest identifies the task to copy
+type DuplicateTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                               // Source task UUID
+	AddCopySuffix bool                   `protobuf:"varint,2,opt,name=add_copy_suffix,json=addCopySuffix,proto3" json:"add_copy_suffix,omitempty"` // Append " (copy)" to the copied title
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateTaskRequest) Reset() {
+	*x = DuplicateTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateTaskRequest) ProtoMessage() {}
+
+func (x *DuplicateTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateTaskRequest.ProtoReflect.Descriptor instead.
+func (*DuplicateTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DuplicateTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DuplicateTaskRequest) GetAddCopySuffix() bool {
+	if x != nil {
+		return x.AddCopySuffix
+	}
+	return false
+}
+
+// DuplicateTaskResponse returns the newly created copy
+type DuplicateTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateTaskResponse) Reset() {
+	*x = DuplicateTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateTaskResponse) ProtoMessage() {}
+
+func (x *DuplicateTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateTaskResponse.ProtoReflect.Descriptor instead.
+func (*DuplicateTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DuplicateTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// ReorderTaskRequest moves a task within the manual ordering
+type ReorderTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                          // Task to move
+	AfterId       string                 `protobuf:"bytes,2,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"` // Task to place it after; empty moves it to the top
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderTaskRequest) Reset() {
+	*x = ReorderTaskRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderTaskRequest) ProtoMessage() {}
+
+func (x *ReorderTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderTaskRequest.ProtoReflect.Descriptor instead.
+func (*ReorderTaskRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReorderTaskRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReorderTaskRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+// ReorderTaskResponse returns the moved task with its new position
+type ReorderTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReorderTaskResponse) Reset() {
+	*x = ReorderTaskResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReorderTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReorderTaskResponse) ProtoMessage() {}
+
+func (x *ReorderTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReorderTaskResponse.ProtoReflect.Descriptor instead.
+func (*ReorderTaskResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReorderTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// ArchiveOldCompletedRequest sets the retention period for the archive run
+type ArchiveOldCompletedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OlderThanDays uint32                 `protobuf:"varint,1,opt,name=older_than_days,json=olderThanDays,proto3" json:"older_than_days,omitempty"` // Archive tasks completed before this many days ago; 0 uses the server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveOldCompletedRequest) Reset() {
+	*x = ArchiveOldCompletedRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveOldCompletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveOldCompletedRequest) ProtoMessage() {}
+
+func (x *ArchiveOldCompletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveOldCompletedRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveOldCompletedRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ArchiveOldCompletedRequest) GetOlderThanDays() uint32 {
+	if x != nil {
+		return x.OlderThanDays
+	}
+	return 0
+}
+
+// ArchiveOldCompletedResponse reports how many tasks were archived
+type ArchiveOldCompletedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ArchivedCount uint32                 `protobuf:"varint,1,opt,name=archived_count,json=archivedCount,proto3" json:"archived_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveOldCompletedResponse) Reset() {
+	*x = ArchiveOldCompletedResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveOldCompletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveOldCompletedResponse) ProtoMessage() {}
+
+func (x *ArchiveOldCompletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveOldCompletedResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveOldCompletedResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ArchiveOldCompletedResponse) GetArchivedCount() uint32 {
+	if x != nil {
+		return x.ArchivedCount
+	}
+	return 0
+}
+
+// PurgeDeletedRequest sets the retention period for the purge
+type PurgeDeletedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OlderThanDays uint32                 `protobuf:"varint,1,opt,name=older_than_days,json=olderThanDays,proto3" json:"older_than_days,omitempty"` // Purge tasks deleted before this many days ago; 0 uses the server default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeletedRequest) Reset() {
+	*x = PurgeDeletedRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeletedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeletedRequest) ProtoMessage() {}
+
+func (x *PurgeDeletedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeletedRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDeletedRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PurgeDeletedRequest) GetOlderThanDays() uint32 {
+	if x != nil {
+		return x.OlderThanDays
+	}
+	return 0
+}
+
+// PurgeDeletedResponse reports how many tasks were permanently removed
+type PurgeDeletedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurgedCount   uint32                 `protobuf:"varint,1,opt,name=purged_count,json=purgedCount,proto3" json:"purged_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDeletedResponse) Reset() {
+	*x = PurgeDeletedResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDeletedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDeletedResponse) ProtoMessage() {}
+
+func (x *PurgeDeletedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDeletedResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDeletedResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PurgeDeletedResponse) GetPurgedCount() uint32 {
+	if x != nil {
+		return x.PurgedCount
+	}
+	return 0
+}
+
+// HealthCheckResponse indicates service health
+type HealthCheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                                    // "ok" when the primary database is reachable
+	ReplicaStatus string                 `protobuf:"bytes,2,opt,name=replica_status,json=replicaStatus,proto3" json:"replica_status,omitempty"` // "ok" or "unavailable"; empty without a read replica
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResponse) Reset() {
+	*x = HealthCheckResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResponse) ProtoMessage() {}
+
+func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *HealthCheckResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthCheckResponse) GetReplicaStatus() string {
+	if x != nil {
+		return x.ReplicaStatus
+	}
+	return ""
+}
+
+// GetVersionResponse identifies the running build
+type GetVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`                      // SERVICE_NAME
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`                      // SERVICE_VERSION
+	Environment   string                 `protobuf:"bytes,3,opt,name=environment,proto3" json:"environment,omitempty"`              // ENVIRONMENT
+	GoVersion     string                 `protobuf:"bytes,4,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"` // Go toolchain the binary was built with
+	GitCommit     string                 `protobuf:"bytes,5,opt,name=git_commit,json=gitCommit,proto3" json:"git_commit,omitempty"` // Commit injected at build time, "unknown" if absent
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionResponse) Reset() {
+	*x = GetVersionResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionResponse) ProtoMessage() {}
+
+func (x *GetVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetVersionResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetVersionResponse) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetEnvironment() string {
+	if x != nil {
+		return x.Environment
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *GetVersionResponse) GetGitCommit() string {
+	if x != nil {
+		return x.GitCommit
+	}
+	return ""
+}
+
+// DebugEchoResponse describes the request as the server saw it
+type DebugEchoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Headers       []*DebugHeader         `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`                         // Request headers sorted by name; credentials are redacted
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`                       // "connect", "grpc" or "grpcweb"
+	ClientIp      string                 `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`       // Client address after applying TRUSTED_PROXIES
+	PeerAddr      string                 `protobuf:"bytes,4,opt,name=peer_addr,json=peerAddr,proto3" json:"peer_addr,omitempty"`       // Address of the direct peer
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`    // Request ID assigned by the server
+	Procedure     string                 `protobuf:"bytes,6,opt,name=procedure,proto3" json:"procedure,omitempty"`                     // Full procedure name
+	HttpMethod    string                 `protobuf:"bytes,7,opt,name=http_method,json=httpMethod,proto3" json:"http_method,omitempty"` // POST, or GET for Connect GET requests
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugEchoResponse) Reset() {
+	*x = DebugEchoResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugEchoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugEchoResponse) ProtoMessage() {}
+
+func (x *DebugEchoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugEchoResponse.ProtoReflect.Descriptor instead.
+func (*DebugEchoResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DebugEchoResponse) GetHeaders() []*DebugHeader {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *DebugEchoResponse) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *DebugEchoResponse) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
+func (x *DebugEchoResponse) GetPeerAddr() string {
+	if x != nil {
+		return x.PeerAddr
+	}
+	return ""
+}
+
+func (x *DebugEchoResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *DebugEchoResponse) GetProcedure() string {
+	if x != nil {
+		return x.Procedure
+	}
+	return ""
+}
+
+func (x *DebugEchoResponse) GetHttpMethod() string {
+	if x != nil {
+		return x.HttpMethod
+	}
+	return ""
+}
+
+// DebugHeader is one request header and its values
+type DebugHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DebugHeader) Reset() {
+	*x = DebugHeader{}
+	mi := &file_todo_v1_todo_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DebugHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebugHeader) ProtoMessage() {}
+
+func (x *DebugHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebugHeader.ProtoReflect.Descriptor instead.
+func (*DebugHeader) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DebugHeader) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DebugHeader) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// DiagnoseStorageResponse reports data drift in the tasks table, e.g. after
+// manual database edits
+type DiagnoseStorageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalRows     int64                  `protobuf:"varint,1,opt,name=total_rows,json=totalRows,proto3" json:"total_rows,omitempty"` // Rows checked, across every tenant
+	Checks        []*StorageCheck        `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`                         // One entry per check, including passing ones
+	Healthy       bool                   `protobuf:"varint,3,opt,name=healthy,proto3" json:"healthy,omitempty"`                      // True when no check found a problem
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnoseStorageResponse) Reset() {
+	*x = DiagnoseStorageResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnoseStorageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnoseStorageResponse) ProtoMessage() {}
+
+func (x *DiagnoseStorageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnoseStorageResponse.ProtoReflect.Descriptor instead.
+func (*DiagnoseStorageResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DiagnoseStorageResponse) GetTotalRows() int64 {
+	if x != nil {
+		return x.TotalRows
+	}
+	return 0
+}
+
+func (x *DiagnoseStorageResponse) GetChecks() []*StorageCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *DiagnoseStorageResponse) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+// StorageCheck is the result of one integrity check
+type StorageCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                            // "null_timestamps", "invalid_ids", "overlong_titles" or "duplicate_ids"
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`                         // Offending rows; 0 when the check passed
+	SampleIds     []string               `protobuf:"bytes,3,rep,name=sample_ids,json=sampleIds,proto3" json:"sample_ids,omitempty"` // Up to 20 offending task IDs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageCheck) Reset() {
+	*x = StorageCheck{}
+	mi := &file_todo_v1_todo_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageCheck) ProtoMessage() {}
+
+func (x *StorageCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageCheck.ProtoReflect.Descriptor instead.
+func (*StorageCheck) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StorageCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StorageCheck) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *StorageCheck) GetSampleIds() []string {
+	if x != nil {
+		return x.SampleIds
+	}
+	return nil
+}
+
+// ListChangedSinceRequest asks for tasks created or updated after since.
+// Changes are ordered by (updated_at, id), and many tasks can share a second,
+// so polls resume from the next_since and next_since_id pair rather than the
+// timestamp alone. Deleted tasks are not reported.
+type ListChangedSinceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`                                         // Exclusive; unset returns the oldest changes first
+	WaitTimeoutMs uint32                 `protobuf:"varint,2,opt,name=wait_timeout_ms,json=waitTimeoutMs,proto3" json:"wait_timeout_ms,omitempty"` // How long to wait for a change; 0 returns at once, at most 30000
+	SinceId       string                 `protobuf:"bytes,3,opt,name=since_id,json=sinceId,proto3" json:"since_id,omitempty"`                      // With since, also returns tasks updated at since whose id sorts after it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangedSinceRequest) Reset() {
+	*x = ListChangedSinceRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangedSinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangedSinceRequest) ProtoMessage() {}
+
+func (x *ListChangedSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangedSinceRequest.ProtoReflect.Descriptor instead.
+func (*ListChangedSinceRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListChangedSinceRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListChangedSinceRequest) GetWaitTimeoutMs() uint32 {
+	if x != nil {
+		return x.WaitTimeoutMs
+	}
+	return 0
+}
+
+func (x *ListChangedSinceRequest) GetSinceId() string {
+	if x != nil {
+		return x.SinceId
+	}
+	return ""
+}
+
+// ListChangedSinceResponse holds the changed tasks, oldest change first. It is
+// empty when nothing changed before the wait timed out.
+type ListChangedSinceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`                                  // At most 100 tasks
+	NextSince     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=next_since,json=nextSince,proto3" json:"next_since,omitempty"`         // Pass as since in the next poll
+	HasMore       bool                   `protobuf:"varint,3,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`              // More changes are ready; poll again without waiting
+	NextSinceId   string                 `protobuf:"bytes,4,opt,name=next_since_id,json=nextSinceId,proto3" json:"next_since_id,omitempty"` // Pass as since_id in the next poll
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChangedSinceResponse) Reset() {
+	*x = ListChangedSinceResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChangedSinceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChangedSinceResponse) ProtoMessage() {}
+
+func (x *ListChangedSinceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChangedSinceResponse.ProtoReflect.Descriptor instead.
+func (*ListChangedSinceResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListChangedSinceResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ListChangedSinceResponse) GetNextSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextSince
+	}
+	return nil
+}
+
+func (x *ListChangedSinceResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *ListChangedSinceResponse) GetNextSinceId() string {
+	if x != nil {
+		return x.NextSinceId
+	}
+	return ""
+}
+
+// CountCreatedSinceRequest asks how many tasks were created in a window
+type CountCreatedSinceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"` // Inclusive start of the window; required
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountCreatedSinceRequest) Reset() {
+	*x = CountCreatedSinceRequest{}
+	mi := &file_todo_v1_todo_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountCreatedSinceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountCreatedSinceRequest) ProtoMessage() {}
+
+func (x *CountCreatedSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountCreatedSinceRequest.ProtoReflect.Descriptor instead.
+func (*CountCreatedSinceRequest) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CountCreatedSinceRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+// CountCreatedSinceResponse holds the number of tasks, archived ones
+// included, created at or after since. Deleted tasks are not counted.
+type CountCreatedSinceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountCreatedSinceResponse) Reset() {
+	*x = CountCreatedSinceResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountCreatedSinceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountCreatedSinceResponse) ProtoMessage() {}
+
+func (x *CountCreatedSinceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountCreatedSinceResponse.ProtoReflect.Descriptor instead.
+func (*CountCreatedSinceResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *CountCreatedSinceResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// GetListVersionResponse holds the list version, which advances on every
+// change to the caller's tasks
+type GetListVersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ListVersion   uint64                 `protobuf:"varint,1,opt,name=list_version,json=listVersion,proto3" json:"list_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetListVersionResponse) Reset() {
+	*x = GetListVersionResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetListVersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetListVersionResponse) ProtoMessage() {}
+
+func (x *GetListVersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetListVersionResponse.ProtoReflect.Descriptor instead.
+func (*GetListVersionResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetListVersionResponse) GetListVersion() uint64 {
+	if x != nil {
+		return x.ListVersion
+	}
+	return 0
+}
+
+// GetStatsResponse counts tasks by state
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Active        uint32                 `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`       // Pending, not archived
+	Completed     uint32                 `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"` // Completed, not archived
+	Archived      uint32                 `protobuf:"varint,3,opt,name=archived,proto3" json:"archived,omitempty"`
+	Deleted       uint32                 `protobuf:"varint,4,opt,name=deleted,proto3" json:"deleted,omitempty"` // In the trash; always 0 unless SOFT_DELETE is on
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_todo_v1_todo_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_v1_todo_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_v1_todo_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetStatsResponse) GetActive() uint32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetCompleted() uint32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetArchived() uint32 {
+	if x != nil {
+		return x.Archived
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetDeleted() uint32 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+var File_todo_v1_todo_proto protoreflect.FileDescriptor
+
+const file_todo_v1_todo_proto_rawDesc = "" +
+	"\n" +
+	"\x12todo/v1/todo.proto\x12\atodo.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\x86\x03\n" +
+	"\x04Task\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\bR\tcompleted\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\varchived_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x1a\n" +
+	"\bposition\x18\a \x01(\x01R\bposition\x125\n" +
+	"\bdue_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x124\n" +
+	"\vlocal_times\x18\t \x01(\v2\x13.todo.v1.LocalTimesR\n" +
+	"localTimes\"\xa3\x01\n" +
+	"\n" +
+	"LocalTimes\x12\x1b\n" +
+	"\ttime_zone\x18\x01 \x01(\tR\btimeZone\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\tR\tupdatedAt\x12\x1f\n" +
+	"\varchived_at\x18\x04 \x01(\tR\n" +
+	"archivedAt\x12\x19\n" +
+	"\bdue_date\x18\x05 \x01(\tR\adueDate\"p\n" +
+	"\x11CreateTaskRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x125\n" +
+	"\bdue_date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\"7\n" +
+	"\x12CreateTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"h\n" +
+	"\x0eGetTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12F\n" +
+	"\x11if_modified_since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0fifModifiedSince\"W\n" +
+	"\x0fGetTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\x12!\n" +
+	"\fnot_modified\x18\x02 \x01(\bR\vnotModified\"\x89\x03\n" +
+	"\x10ListTasksRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\x12\x14\n" +
+	"\x05query\x18\x03 \x01(\tR\x05query\x12-\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x15.todo.v1.StatusFilterR\x06status\x12+\n" +
+	"\asort_by\x18\x05 \x01(\x0e2\x12.todo.v1.SortFieldR\x06sortBy\x121\n" +
+	"\n" +
+	"sort_order\x18\x06 \x01(\x0e2\x12.todo.v1.SortOrderR\tsortOrder\x12)\n" +
+	"\x10include_archived\x18\a \x01(\bR\x0fincludeArchived\x12&\n" +
+	"\x0fgroup_by_status\x18\b \x01(\bR\rgroupByStatus\x121\n" +
+	"\bdue_date\x18\t \x01(\x0e2\x16.todo.v1.DueDateFilterR\adueDate\x12\x19\n" +
+	"\bids_only\x18\n" +
+	" \x01(\bR\aidsOnly\"\xb6\x02\n" +
+	"\x11ListTasksResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12;\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1b.todo.v1.PaginationMetadataR\n" +
+	"pagination\x12,\n" +
+	"\apending\x18\x03 \x01(\v2\x12.todo.v1.TaskGroupR\apending\x120\n" +
+	"\tcompleted\x18\x04 \x01(\v2\x12.todo.v1.TaskGroupR\tcompleted\x12!\n" +
+	"\fnot_modified\x18\x05 \x01(\bR\vnotModified\x12!\n" +
+	"\flist_version\x18\x06 \x01(\x04R\vlistVersion\x12\x19\n" +
+	"\btask_ids\x18\a \x03(\tR\ataskIds\"\x8f\x01\n" +
+	"\x17ListTasksStreamResponse\x12*\n" +
+	"\x05chunk\x18\x01 \x01(\v2\x12.todo.v1.TaskChunkH\x00R\x05chunk\x12=\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1b.todo.v1.PaginationMetadataH\x00R\n" +
+	"paginationB\t\n" +
+	"\apayload\"0\n" +
+	"\tTaskChunk\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\"m\n" +
+	"\tTaskGroup\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x12;\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x1b.todo.v1.PaginationMetadataR\n" +
+	"pagination\"\x92\x02\n" +
+	"\x12PaginationMetadata\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\rR\bpageSize\x12\x1f\n" +
+	"\vtotal_pages\x18\x03 \x01(\rR\n" +
+	"totalPages\x12\x1f\n" +
+	"\vtotal_items\x18\x04 \x01(\rR\n" +
+	"totalItems\x12!\n" +
+	"\fhas_previous\x18\x05 \x01(\bR\vhasPrevious\x12\x19\n" +
+	"\bhas_next\x18\x06 \x01(\bR\ahasNext\x12)\n" +
+	"\x10total_unfiltered\x18\a \x01(\rR\x0ftotalUnfiltered\x12 \n" +
+	"\vapproximate\x18\b \x01(\bR\vapproximate\"\xf1\x01\n" +
+	"\x11UpdateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1c\n" +
+	"\tcompleted\x18\x03 \x01(\bR\tcompleted\x125\n" +
+	"\bdue_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\adueDate\x12$\n" +
+	"\x0eclear_due_date\x18\x05 \x01(\bR\fclearDueDate\x12;\n" +
+	"\vupdate_mask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"7\n" +
+	"\x12UpdateTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"#\n" +
+	"\x11DeleteTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"&\n" +
+	"\x12DeleteTasksRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"m\n" +
+	"\x10DeleteTaskResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x123\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1b.todo.v1.DeleteResultStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xb8\x01\n" +
+	"\x13DeleteTasksResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.todo.v1.DeleteTaskResultR\aresults\x12#\n" +
+	"\rdeleted_count\x18\x02 \x01(\rR\fdeletedCount\x12&\n" +
+	"\x0fnot_found_count\x18\x03 \x01(\rR\rnotFoundCount\x12\x1f\n" +
+	"\verror_count\x18\x04 \x01(\rR\n" +
+	"errorCount\"N\n" +
+	"\x14DuplicateTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0fadd_copy_suffix\x18\x02 \x01(\bR\raddCopySuffix\":\n" +
+	"\x15DuplicateTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"?\n" +
+	"\x12ReorderTaskRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bafter_id\x18\x02 \x01(\tR\aafterId\"8\n" +
+	"\x13ReorderTaskResponse\x12!\n" +
+	"\x04task\x18\x01 \x01(\v2\r.todo.v1.TaskR\x04task\"D\n" +
+	"\x1aArchiveOldCompletedRequest\x12&\n" +
+	"\x0folder_than_days\x18\x01 \x01(\rR\rolderThanDays\"D\n" +
+	"\x1bArchiveOldCompletedResponse\x12%\n" +
+	"\x0earchived_count\x18\x01 \x01(\rR\rarchivedCount\"=\n" +
+	"\x13PurgeDeletedRequest\x12&\n" +
+	"\x0folder_than_days\x18\x01 \x01(\rR\rolderThanDays\"9\n" +
+	"\x14PurgeDeletedResponse\x12!\n" +
+	"\fpurged_count\x18\x01 \x01(\rR\vpurgedCount\"T\n" +
+	"\x13HealthCheckResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12%\n" +
+	"\x0ereplica_status\x18\x02 \x01(\tR\rreplicaStatus\"\xa8\x01\n" +
+	"\x12GetVersionResponse\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12 \n" +
+	"\venvironment\x18\x03 \x01(\tR\venvironment\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x04 \x01(\tR\tgoVersion\x12\x1d\n" +
+	"\n" +
+	"git_commit\x18\x05 \x01(\tR\tgitCommit\"\xf7\x01\n" +
+	"\x11DebugEchoResponse\x12.\n" +
+	"\aheaders\x18\x01 \x03(\v2\x14.todo.v1.DebugHeaderR\aheaders\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x1b\n" +
+	"\tclient_ip\x18\x03 \x01(\tR\bclientIp\x12\x1b\n" +
+	"\tpeer_addr\x18\x04 \x01(\tR\bpeerAddr\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\x12\x1c\n" +
+	"\tprocedure\x18\x06 \x01(\tR\tprocedure\x12\x1f\n" +
+	"\vhttp_method\x18\a \x01(\tR\n" +
+	"httpMethod\"9\n" +
+	"\vDebugHeader\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"\x81\x01\n" +
+	"\x17DiagnoseStorageResponse\x12\x1d\n" +
+	"\n" +
+	"total_rows\x18\x01 \x01(\x03R\ttotalRows\x12-\n" +
+	"\x06checks\x18\x02 \x03(\v2\x15.todo.v1.StorageCheckR\x06checks\x12\x18\n" +
+	"\ahealthy\x18\x03 \x01(\bR\ahealthy\"W\n" +
+	"\fStorageCheck\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1d\n" +
+	"\n" +
+	"sample_ids\x18\x03 \x03(\tR\tsampleIds\"\x8e\x01\n" +
+	"\x17ListChangedSinceRequest\x120\n" +
+	"\x05since\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12&\n" +
+	"\x0fwait_timeout_ms\x18\x02 \x01(\rR\rwaitTimeoutMs\x12\x19\n" +
+	"\bsince_id\x18\x03 \x01(\tR\asinceId\"\xb9\x01\n" +
+	"\x18ListChangedSinceResponse\x12#\n" +
+	"\x05tasks\x18\x01 \x03(\v2\r.todo.v1.TaskR\x05tasks\x129\n" +
+	"\n" +
+	"next_since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tnextSince\x12\x19\n" +
+	"\bhas_more\x18\x03 \x01(\bR\ahasMore\x12\"\n" +
+	"\rnext_since_id\x18\x04 \x01(\tR\vnextSinceId\"L\n" +
+	"\x18CountCreatedSinceRequest\x120\n" +
+	"\x05since\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\"1\n" +
+	"\x19CountCreatedSinceResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\";\n" +
+	"\x16GetListVersionResponse\x12!\n" +
+	"\flist_version\x18\x01 \x01(\x04R\vlistVersion\"~\n" +
+	"\x10GetStatsResponse\x12\x16\n" +
+	"\x06active\x18\x01 \x01(\rR\x06active\x12\x1c\n" +
+	"\tcompleted\x18\x02 \x01(\rR\tcompleted\x12\x1a\n" +
+	"\barchived\x18\x03 \x01(\rR\barchived\x12\x18\n" +
+	"\adeleted\x18\x04 \x01(\rR\adeleted*\x89\x01\n" +
+	"\rDueDateFilter\x12\x1f\n" +
+	"\x1bDUE_DATE_FILTER_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DUE_DATE_FILTER_ANY\x10\x01\x12\x1d\n" +
+	"\x19DUE_DATE_FILTER_SCHEDULED\x10\x02\x12\x1f\n" +
+	"\x1bDUE_DATE_FILTER_UNSCHEDULED\x10\x03*|\n" +
+	"\fStatusFilter\x12\x1d\n" +
+	"\x19STATUS_FILTER_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11STATUS_FILTER_ALL\x10\x01\x12\x1b\n" +
+	"\x17STATUS_FILTER_COMPLETED\x10\x02\x12\x19\n" +
+	"\x15STATUS_FILTER_PENDING\x10\x03*\xa6\x01\n" +
+	"\tSortField\x12\x1a\n" +
+	"\x16SORT_FIELD_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SORT_FIELD_CREATED_AT\x10\x01\x12\x19\n" +
+	"\x15SORT_FIELD_UPDATED_AT\x10\x02\x12\x14\n" +
+	"\x10SORT_FIELD_TITLE\x10\x03\x12\x17\n" +
+	"\x13SORT_FIELD_POSITION\x10\x04\x12\x18\n" +
+	"\x14SORT_FIELD_RELEVANCE\x10\x05*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x01\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x02*\xa0\x01\n" +
+	"\x12DeleteResultStatus\x12$\n" +
+	" DELETE_RESULT_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cDELETE_RESULT_STATUS_DELETED\x10\x01\x12\"\n" +
+	"\x1eDELETE_RESULT_STATUS_NOT_FOUND\x10\x02\x12\x1e\n" +
+	"\x1aDELETE_RESULT_STATUS_ERROR\x10\x032\x99\v\n" +
+	"\vTodoService\x12E\n" +
+	"\n" +
+	"CreateTask\x12\x1a.todo.v1.CreateTaskRequest\x1a\x1b.todo.v1.CreateTaskResponse\x12<\n" +
+	"\aGetTask\x12\x17.todo.v1.GetTaskRequest\x1a\x18.todo.v1.GetTaskResponse\x12B\n" +
+	"\tListTasks\x12\x19.todo.v1.ListTasksRequest\x1a\x1a.todo.v1.ListTasksResponse\x12P\n" +
+	"\x0fListTasksStream\x12\x19.todo.v1.ListTasksRequest\x1a .todo.v1.ListTasksStreamResponse0\x01\x12W\n" +
+	"\x10ListChangedSince\x12 .todo.v1.ListChangedSinceRequest\x1a!.todo.v1.ListChangedSinceResponse\x12Z\n" +
+	"\x11CountCreatedSince\x12!.todo.v1.CountCreatedSinceRequest\x1a\".todo.v1.CountCreatedSinceResponse\x12I\n" +
+	"\x0eGetListVersion\x12\x16.google.protobuf.Empty\x1a\x1f.todo.v1.GetListVersionResponse\x12=\n" +
+	"\bGetStats\x12\x16.google.protobuf.Empty\x1a\x19.todo.v1.GetStatsResponse\x12E\n" +
+	"\n" +
+	"UpdateTask\x12\x1a.todo.v1.UpdateTaskRequest\x1a\x1b.todo.v1.UpdateTaskResponse\x12@\n" +
+	"\n" +
+	"DeleteTask\x12\x1a.todo.v1.DeleteTaskRequest\x1a\x16.google.protobuf.Empty\x12H\n" +
+	"\vDeleteTasks\x12\x1b.todo.v1.DeleteTasksRequest\x1a\x1c.todo.v1.DeleteTasksResponse\x12N\n" +
+	"\rDuplicateTask\x12\x1d.todo.v1.DuplicateTaskRequest\x1a\x1e.todo.v1.DuplicateTaskResponse\x12H\n" +
+	"\vReorderTask\x12\x1b.todo.v1.ReorderTaskRequest\x1a\x1c.todo.v1.ReorderTaskResponse\x12`\n" +
+	"\x13ArchiveOldCompleted\x12#.todo.v1.ArchiveOldCompletedRequest\x1a$.todo.v1.ArchiveOldCompletedResponse\x12K\n" +
+	"\fPurgeDeleted\x12\x1c.todo.v1.PurgeDeletedRequest\x1a\x1d.todo.v1.PurgeDeletedResponse\x12C\n" +
+	"\vHealthCheck\x12\x16.google.protobuf.Empty\x1a\x1c.todo.v1.HealthCheckResponse\x12A\n" +
+	"\n" +
+	"GetVersion\x12\x16.google.protobuf.Empty\x1a\x1b.todo.v1.GetVersionResponse\x12?\n" +
+	"\tDebugEcho\x12\x16.google.protobuf.Empty\x1a\x1a.todo.v1.DebugEchoResponse\x12K\n" +
+	"\x0fDiagnoseStorage\x12\x16.google.protobuf.Empty\x1a .todo.v1.DiagnoseStorageResponseB\x9d\x01\n" +
+	"\vcom.todo.v1B\tTodoProtoP\x01ZFgithub.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1;todov1\xa2\x02\x03TXX\xaa\x02\aTodo.V1\xca\x02\aTodo\\V1\xe2\x02\x13Todo\\V1\\GPBMetadata\xea\x02\bTodo::V1b\x06proto3"
+
+var (
+	file_todo_v1_todo_proto_rawDescOnce sync.Once
+	file_todo_v1_todo_proto_rawDescData []byte
+)
+
+func file_todo_v1_todo_proto_rawDescGZIP() []byte {
+	file_todo_v1_todo_proto_rawDescOnce.Do(func() {
+		file_todo_v1_todo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_v1_todo_proto_rawDesc), len(file_todo_v1_todo_proto_rawDesc)))
+	})
+	return file_todo_v1_todo_proto_rawDescData
+}
+
+var file_todo_v1_todo_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_todo_v1_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_todo_v1_todo_proto_goTypes = []any{
+	(DueDateFilter)(0),                  // 0: todo.v1.DueDateFilter
+	(StatusFilter)(0),                   // 1: todo.v1.StatusFilter
+	(SortField)(0),                      // 2: todo.v1.SortField
+	(SortOrder)(0),                      // 3: todo.v1.SortOrder
+	(DeleteResultStatus)(0),             // 4: todo.v1.DeleteResultStatus
+	(*Task)(nil),                        // 5: todo.v1.Task
+	(*LocalTimes)(nil),                  // 6: todo.v1.LocalTimes
+	(*CreateTaskRequest)(nil),           // 7: todo.v1.CreateTaskRequest
+	(*CreateTaskResponse)(nil),          // 8: todo.v1.CreateTaskResponse
+	(*GetTaskRequest)(nil),              // 9: todo.v1.GetTaskRequest
+	(*GetTaskResponse)(nil),             // 10: todo.v1.GetTaskResponse
+	(*ListTasksRequest)(nil),            // 11: todo.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),           // 12: todo.v1.ListTasksResponse
+	(*ListTasksStreamResponse)(nil),     // 13: todo.v1.ListTasksStreamResponse
+	(*TaskChunk)(nil),                   // 14: todo.v1.TaskChunk
+	(*TaskGroup)(nil),                   // 15: todo.v1.TaskGroup
+	(*PaginationMetadata)(nil),          // 16: todo.v1.PaginationMetadata
+	(*UpdateTaskRequest)(nil),           // 17: todo.v1.UpdateTaskRequest
+	(*UpdateTaskResponse)(nil),          // 18: todo.v1.UpdateTaskResponse
+	(*DeleteTaskRequest)(nil),           // 19: todo.v1.DeleteTaskRequest
+	(*DeleteTasksRequest)(nil),          // 20: todo.v1.DeleteTasksRequest
+	(*DeleteTaskResult)(nil),            // 21: todo.v1.DeleteTaskResult
+	(*DeleteTasksResponse)(nil),         // 22: todo.v1.DeleteTasksResponse
+	(*DuplicateTaskRequest)(nil),        // 23: todo.v1.DuplicateTaskRequest
+	(*DuplicateTaskResponse)(nil),       // 24: todo.v1.DuplicateTaskResponse
+	(*ReorderTaskRequest)(nil),          // 25: todo.v1.ReorderTaskRequest
+	(*ReorderTaskResponse)(nil),         // 26: todo.v1.ReorderTaskResponse
+	(*ArchiveOldCompletedRequest)(nil),  // 27: todo.v1.ArchiveOldCompletedRequest
+	(*ArchiveOldCompletedResponse)(nil), // 28: todo.v1.ArchiveOldCompletedResponse
+	(*PurgeDeletedRequest)(nil),         // 29: todo.v1.PurgeDeletedRequest
+	(*PurgeDeletedResponse)(nil),        // 30: todo.v1.PurgeDeletedResponse
+	(*HealthCheckResponse)(nil),         // 31: todo.v1.HealthCheckResponse
+	(*GetVersionResponse)(nil),          // 32: todo.v1.GetVersionResponse
+	(*DebugEchoResponse)(nil),           // 33: todo.v1.DebugEchoResponse
+	(*DebugHeader)(nil),                 // 34: todo.v1.DebugHeader
+	(*DiagnoseStorageResponse)(nil),     // 35: todo.v1.DiagnoseStorageResponse
+	(*StorageCheck)(nil),                // 36: todo.v1.StorageCheck
+	(*ListChangedSinceRequest)(nil),     // 37: todo.v1.ListChangedSinceRequest
+	(*ListChangedSinceResponse)(nil),    // 38: todo.v1.ListChangedSinceResponse
+	(*CountCreatedSinceRequest)(nil),    // 39: todo.v1.CountCreatedSinceRequest
+	(*CountCreatedSinceResponse)(nil),   // 40: todo.v1.CountCreatedSinceResponse
+	(*GetListVersionResponse)(nil),      // 41: todo.v1.GetListVersionResponse
+	(*GetStatsResponse)(nil),            // 42: todo.v1.GetStatsResponse
+	(*timestamppb.Timestamp)(nil),       // 43: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),       // 44: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),               // 45: google.protobuf.Empty
+}
+var file_todo_v1_todo_proto_depIdxs = []int32{
+	43, // 0: todo.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	43, // 1: todo.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	43, // 2: todo.v1.Task.archived_at:type_name -> google.protobuf.Timestamp
+	43, // 3: todo.v1.Task.due_date:type_name -> google.protobuf.Timestamp
+	6,  // 4: todo.v1.Task.local_times:type_name -> todo.v1.LocalTimes
+	43, // 5: todo.v1.CreateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
+	5,  // 6: todo.v1.CreateTaskResponse.task:type_name -> todo.v1.Task
+	43, // 7: todo.v1.GetTaskRequest.if_modified_since:type_name -> google.protobuf.Timestamp
+	5,  // 8: todo.v1.GetTaskResponse.task:type_name -> todo.v1.Task
+	1,  // 9: todo.v1.ListTasksRequest.status:type_name -> todo.v1.StatusFilter
+	2,  // 10: todo.v1.ListTasksRequest.sort_by:type_name -> todo.v1.SortField
+	3,  // 11: todo.v1.ListTasksRequest.sort_order:type_name -> todo.v1.SortOrder
+	0,  // 12: todo.v1.ListTasksRequest.due_date:type_name -> todo.v1.DueDateFilter
+	5,  // 13: todo.v1.ListTasksResponse.tasks:type_name -> todo.v1.Task
+	16, // 14: todo.v1.ListTasksResponse.pagination:type_name -> todo.v1.PaginationMetadata
+	15, // 15: todo.v1.ListTasksResponse.pending:type_name -> todo.v1.TaskGroup
+	15, // 16: todo.v1.ListTasksResponse.completed:type_name -> todo.v1.TaskGroup
+	14, // 17: todo.v1.ListTasksStreamResponse.chunk:type_name -> todo.v1.TaskChunk
+	16, // 18: todo.v1.ListTasksStreamResponse.pagination:type_name -> todo.v1.PaginationMetadata
+	5,  // 19: todo.v1.TaskChunk.tasks:type_name -> todo.v1.Task
+	5,  // 20: todo.v1.TaskGroup.tasks:type_name -> todo.v1.Task
+	16, // 21: todo.v1.TaskGroup.pagination:type_name -> todo.v1.PaginationMetadata
+	43, // 22: todo.v1.UpdateTaskRequest.due_date:type_name -> google.protobuf.Timestamp
+	44, // 23: todo.v1.UpdateTaskRequest.update_mask:type_name -> google.protobuf.FieldMask
+	5,  // 24: todo.v1.UpdateTaskResponse.task:type_name -> todo.v1.Task
+	4,  // 25: todo.v1.DeleteTaskResult.status:type_name -> todo.v1.DeleteResultStatus
+	21, // 26: todo.v1.DeleteTasksResponse.results:type_name -> todo.v1.DeleteTaskResult
+	5,  // 27: todo.v1.DuplicateTaskResponse.task:type_name -> todo.v1.Task
+	5,  // 28: todo.v1.ReorderTaskResponse.task:type_name -> todo.v1.Task
+	34, // 29: todo.v1.DebugEchoResponse.headers:type_name -> todo.v1.DebugHeader
+	36, // 30: todo.v1.DiagnoseStorageResponse.checks:type_name -> todo.v1.StorageCheck
+	43, // 31: todo.v1.ListChangedSinceRequest.since:type_name -> google.protobuf.Timestamp
+	5,  // 32: todo.v1.ListChangedSinceResponse.tasks:type_name -> todo.v1.Task
+	43, // 33: todo.v1.ListChangedSinceResponse.next_since:type_name -> google.protobuf.Timestamp
+	43, // 34: todo.v1.CountCreatedSinceRequest.since:type_name -> google.protobuf.Timestamp
+	7,  // 35: todo.v1.TodoService.CreateTask:input_type -> todo.v1.CreateTaskRequest
+	9,  // 36: todo.v1.TodoService.GetTask:input_type -> todo.v1.GetTaskRequest
+	11, // 37: todo.v1.TodoService.ListTasks:input_type -> todo.v1.ListTasksRequest
+	11, // 38: todo.v1.TodoService.ListTasksStream:input_type -> todo.v1.ListTasksRequest
+	37, // 39: todo.v1.TodoService.ListChangedSince:input_type -> todo.v1.ListChangedSinceRequest
+	39, // 40: todo.v1.TodoService.CountCreatedSince:input_type -> todo.v1.CountCreatedSinceRequest
+	45, // 41: todo.v1.TodoService.GetListVersion:input_type -> google.protobuf.Empty
+	45, // 42: todo.v1.TodoService.GetStats:input_type -> google.protobuf.Empty
+	17, // 43: todo.v1.TodoService.UpdateTask:input_type -> todo.v1.UpdateTaskRequest
+	19, // 44: todo.v1.TodoService.DeleteTask:input_type -> todo.v1.DeleteTaskRequest
+	20, // 45: todo.v1.TodoService.DeleteTasks:input_type -> todo.v1.DeleteTasksRequest
+	23, // 46: todo.v1.TodoService.DuplicateTask:input_type -> todo.v1.DuplicateTaskRequest
+	25, // 47: todo.v1.TodoService.ReorderTask:input_type -> todo.v1.ReorderTaskRequest
+	27, // 48: todo.v1.TodoService.ArchiveOldCompleted:input_type -> todo.v1.ArchiveOldCompletedRequest
+	29, // 49: todo.v1.TodoService.PurgeDeleted:input_type -> todo.v1.PurgeDeletedRequest
+	45, // 50: todo.v1.TodoService.HealthCheck:input_type -> google.protobuf.Empty
+	45, // 51: todo.v1.TodoService.GetVersion:input_type -> google.protobuf.Empty
+	45, // 52: todo.v1.TodoService.DebugEcho:input_type -> google.protobuf.Empty
+	45, // 53: todo.v1.TodoService.DiagnoseStorage:input_type -> google.protobuf.Empty
+	8,  // 54: todo.v1.TodoService.CreateTask:output_type -> todo.v1.CreateTaskResponse
+	10, // 55: todo.v1.TodoService.GetTask:output_type -> todo.v1.GetTaskResponse
+	12, // 56: todo.v1.TodoService.ListTasks:output_type -> todo.v1.ListTasksResponse
+	13, // 57: todo.v1.TodoService.ListTasksStream:output_type -> todo.v1.ListTasksStreamResponse
+	38, // 58: todo.v1.TodoService.ListChangedSince:output_type -> todo.v1.ListChangedSinceResponse
+	40, // 59: todo.v1.TodoService.CountCreatedSince:output_type -> todo.v1.CountCreatedSinceResponse
+	41, // 60: todo.v1.TodoService.GetListVersion:output_type -> todo.v1.GetListVersionResponse
+	42, // 61: todo.v1.TodoService.GetStats:output_type -> todo.v1.GetStatsResponse
+	18, // 62: todo.v1.TodoService.UpdateTask:output_type -> todo.v1.UpdateTaskResponse
+	45, // 63: todo.v1.TodoService.DeleteTask:output_type -> google.protobuf.Empty
+	22, // 64: todo.v1.TodoService.DeleteTasks:output_type -> todo.v1.DeleteTasksResponse
+	24, // 65: todo.v1.TodoService.DuplicateTask:output_type -> todo.v1.DuplicateTaskResponse
+	26, // 66: todo.v1.TodoService.ReorderTask:output_type -> todo.v1.ReorderTaskResponse
+	28, // 67: todo.v1.TodoService.ArchiveOldCompleted:output_type -> todo.v1.ArchiveOldCompletedResponse
+	30, // 68: todo.v1.TodoService.PurgeDeleted:output_type -> todo.v1.PurgeDeletedResponse
+	31, // 69: todo.v1.TodoService.HealthCheck:output_type -> todo.v1.HealthCheckResponse
+	32, // 70: todo.v1.TodoService.GetVersion:output_type -> todo.v1.GetVersionResponse
+	33, // 71: todo.v1.TodoService.DebugEcho:output_type -> todo.v1.DebugEchoResponse
+	35, // 72: todo.v1.TodoService.DiagnoseStorage:output_type -> todo.v1.DiagnoseStorageResponse
+	54, // [54:73] is the sub-list for method output_type
+	35, // [35:54] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_todo_v1_todo_proto_init() }
+func file_todo_v1_todo_proto_init() {
+	if File_todo_v1_todo_proto != nil {
+		return
+	}
+	file_todo_v1_todo_proto_msgTypes[8].OneofWrappers = []any{
+		(*ListTasksStreamResponse_Chunk)(nil),
+		(*ListTasksStreamResponse_Pagination)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_v1_todo_proto_rawDesc), len(file_todo_v1_todo_proto_rawDesc)),
+			NumEnums:      5,
+			NumMessages:   38,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_todo_v1_todo_proto_goTypes,
+		DependencyIndexes: file_todo_v1_todo_proto_depIdxs,
+		EnumInfos:         file_todo_v1_todo_proto_enumTypes,
+		MessageInfos:      file_todo_v1_todo_proto_msgTypes,
+	}.Build()
+	File_todo_v1_todo_proto = out.File
+	file_todo_v1_todo_proto_goTypes = nil
+	file_todo_v1_todo_proto_depIdxs = nil
+}
@@ -0,0 +1,671 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: todo/v1/todo.proto
+
+package todov1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TodoServiceName is the fully-qualified name of the TodoService service.
+	TodoServiceName = "todo.v1.TodoService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TodoServiceCreateTaskProcedure is the fully-qualified name of the TodoService's CreateTask RPC.
+	TodoServiceCreateTaskProcedure = "/todo.v1.TodoService/CreateTask"
+	// TodoServiceGetTaskProcedure is the fully-qualified name of the TodoService's GetTask RPC.
+	TodoServiceGetTaskProcedure = "/todo.v1.TodoService/GetTask"
+	// TodoServiceListTasksProcedure is the fully-qualified name of the TodoService's ListTasks RPC.
+	TodoServiceListTasksProcedure = "/todo.v1.TodoService/ListTasks"
+	// TodoServiceListTasksStreamProcedure is the fully-qualified name of the TodoService's
+	// ListTasksStream RPC.
+	TodoServiceListTasksStreamProcedure = "/todo.v1.TodoService/ListTasksStream"
+	// TodoServiceListChangedSinceProcedure is the fully-qualified name of the TodoService's
+	// ListChangedSince RPC.
+	TodoServiceListChangedSinceProcedure = "/todo.v1.TodoService/ListChangedSince"
+	// TodoServiceCountCreatedSinceProcedure is the fully-qualified name of the TodoService's
+	// CountCreatedSince RPC.
+	TodoServiceCountCreatedSinceProcedure = "/todo.v1.TodoService/CountCreatedSince"
+	// TodoServiceGetListVersionProcedure is the fully-qualified name of the TodoService's
+	// GetListVersion RPC.
+	TodoServiceGetListVersionProcedure = "/todo.v1.TodoService/GetListVersion"
+	// TodoServiceGetStatsProcedure is the fully-qualified name of the TodoService's GetStats RPC.
+	TodoServiceGetStatsProcedure = "/todo.v1.TodoService/GetStats"
+	// TodoServiceUpdateTaskProcedure is the fully-qualified name of the TodoService's UpdateTask RPC.
+	TodoServiceUpdateTaskProcedure = "/todo.v1.TodoService/UpdateTask"
+	// TodoServiceDeleteTaskProcedure is the fully-qualified name of the TodoService's DeleteTask RPC.
+	TodoServiceDeleteTaskProcedure = "/todo.v1.TodoService/DeleteTask"
+	// TodoServiceDeleteTasksProcedure is the fully-qualified name of the TodoService's DeleteTasks RPC.
+	TodoServiceDeleteTasksProcedure = "/todo.v1.TodoService/DeleteTasks"
+	// TodoServiceDuplicateTaskProcedure is the fully-qualified name of the TodoService's DuplicateTask
+	// RPC.
+	TodoServiceDuplicateTaskProcedure = "/todo.v1.TodoService/DuplicateTask"
+	// TodoServiceReorderTaskProcedure is the fully-qualified name of the TodoService's ReorderTask RPC.
+	TodoServiceReorderTaskProcedure = "/todo.v1.TodoService/ReorderTask"
+	// TodoServiceArchiveOldCompletedProcedure is the fully-qualified name of the TodoService's
+	// ArchiveOldCompleted RPC.
+	TodoServiceArchiveOldCompletedProcedure = "/todo.v1.TodoService/ArchiveOldCompleted"
+	// TodoServicePurgeDeletedProcedure is the fully-qualified name of the TodoService's PurgeDeleted
+	// RPC.
+	TodoServicePurgeDeletedProcedure = "/todo.v1.TodoService/PurgeDeleted"
+	// TodoServiceHealthCheckProcedure is the fully-qualified name of the TodoService's HealthCheck RPC.
+	TodoServiceHealthCheckProcedure = "/todo.v1.TodoService/HealthCheck"
+	// TodoServiceGetVersionProcedure is the fully-qualified name of the TodoService's GetVersion RPC.
+	TodoServiceGetVersionProcedure = "/todo.v1.TodoService/GetVersion"
+	// TodoServiceDebugEchoProcedure is the fully-qualified name of the TodoService's DebugEcho RPC.
+	TodoServiceDebugEchoProcedure = "/todo.v1.TodoService/DebugEcho"
+	// TodoServiceDiagnoseStorageProcedure is the fully-qualified name of the TodoService's
+	// DiagnoseStorage RPC.
+	TodoServiceDiagnoseStorageProcedure = "/todo.v1.TodoService/DiagnoseStorage"
+)
+
+// TodoServiceClient is a client for the todo.v1.TodoService service.
+type TodoServiceClient interface {
+	// Create a new task
+	CreateTask(context.Context, *connect.Request[v1.CreateTaskRequest]) (*connect.Response[v1.CreateTaskResponse], error)
+	// Get a specific task by ID
+	GetTask(context.Context, *connect.Request[v1.GetTaskRequest]) (*connect.Response[v1.GetTaskResponse], error)
+	// List tasks with pagination, filtering, and search
+	ListTasks(context.Context, *connect.Request[v1.ListTasksRequest]) (*connect.Response[v1.ListTasksResponse], error)
+	// Stream one large page of tasks in chunks as they are read, followed by
+	// its pagination metadata
+	ListTasksStream(context.Context, *connect.Request[v1.ListTasksRequest]) (*connect.ServerStreamForClient[v1.ListTasksStreamResponse], error)
+	// Return tasks changed after a point in time, waiting up to a timeout for a
+	// change when there is none yet (long polling)
+	ListChangedSince(context.Context, *connect.Request[v1.ListChangedSinceRequest]) (*connect.Response[v1.ListChangedSinceResponse], error)
+	// Count the tasks created at or after a point in time, e.g. for a
+	// "created this week" widget
+	CountCreatedSince(context.Context, *connect.Request[v1.CountCreatedSinceRequest]) (*connect.Response[v1.CountCreatedSinceResponse], error)
+	// Return the current list version, so clients can skip a full ListTasks
+	// when nothing has changed since their last fetch
+	GetListVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetListVersionResponse], error)
+	// Count tasks by state, including tasks in the trash, for monitoring
+	GetStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStatsResponse], error)
+	// Update an existing task
+	UpdateTask(context.Context, *connect.Request[v1.UpdateTaskRequest]) (*connect.Response[v1.UpdateTaskResponse], error)
+	// Delete a task
+	DeleteTask(context.Context, *connect.Request[v1.DeleteTaskRequest]) (*connect.Response[emptypb.Empty], error)
+	// Delete multiple tasks, reporting a result for each id
+	DeleteTasks(context.Context, *connect.Request[v1.DeleteTasksRequest]) (*connect.Response[v1.DeleteTasksResponse], error)
+	// Create a new pending task copied from an existing one
+	DuplicateTask(context.Context, *connect.Request[v1.DuplicateTaskRequest]) (*connect.Response[v1.DuplicateTaskResponse], error)
+	// Move a task directly after another in the manual ordering
+	ReorderTask(context.Context, *connect.Request[v1.ReorderTaskRequest]) (*connect.Response[v1.ReorderTaskResponse], error)
+	// Archive completed tasks that have not changed within the retention period
+	ArchiveOldCompleted(context.Context, *connect.Request[v1.ArchiveOldCompletedRequest]) (*connect.Response[v1.ArchiveOldCompletedResponse], error)
+	// Permanently remove tasks that have been in the trash longer than the retention period
+	PurgeDeleted(context.Context, *connect.Request[v1.PurgeDeletedRequest]) (*connect.Response[v1.PurgeDeletedResponse], error)
+	// Health check endpoint
+	HealthCheck(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.HealthCheckResponse], error)
+	// Build and deployment information for the running server
+	GetVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetVersionResponse], error)
+	// Echo what the server received, for debugging clients behind proxies;
+	// unimplemented in production
+	DebugEcho(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DebugEchoResponse], error)
+	// Run read-only integrity checks over the tasks table, for diagnostics;
+	// unimplemented in production
+	DiagnoseStorage(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DiagnoseStorageResponse], error)
+}
+
+// NewTodoServiceClient constructs a client for the todo.v1.TodoService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTodoServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TodoServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	todoServiceMethods := v1.File_todo_v1_todo_proto.Services().ByName("TodoService").Methods()
+	return &todoServiceClient{
+		createTask: connect.NewClient[v1.CreateTaskRequest, v1.CreateTaskResponse](
+			httpClient,
+			baseURL+TodoServiceCreateTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("CreateTask")),
+			connect.WithClientOptions(opts...),
+		),
+		getTask: connect.NewClient[v1.GetTaskRequest, v1.GetTaskResponse](
+			httpClient,
+			baseURL+TodoServiceGetTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("GetTask")),
+			connect.WithClientOptions(opts...),
+		),
+		listTasks: connect.NewClient[v1.ListTasksRequest, v1.ListTasksResponse](
+			httpClient,
+			baseURL+TodoServiceListTasksProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("ListTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		listTasksStream: connect.NewClient[v1.ListTasksRequest, v1.ListTasksStreamResponse](
+			httpClient,
+			baseURL+TodoServiceListTasksStreamProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("ListTasksStream")),
+			connect.WithClientOptions(opts...),
+		),
+		listChangedSince: connect.NewClient[v1.ListChangedSinceRequest, v1.ListChangedSinceResponse](
+			httpClient,
+			baseURL+TodoServiceListChangedSinceProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("ListChangedSince")),
+			connect.WithClientOptions(opts...),
+		),
+		countCreatedSince: connect.NewClient[v1.CountCreatedSinceRequest, v1.CountCreatedSinceResponse](
+			httpClient,
+			baseURL+TodoServiceCountCreatedSinceProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("CountCreatedSince")),
+			connect.WithClientOptions(opts...),
+		),
+		getListVersion: connect.NewClient[emptypb.Empty, v1.GetListVersionResponse](
+			httpClient,
+			baseURL+TodoServiceGetListVersionProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("GetListVersion")),
+			connect.WithClientOptions(opts...),
+		),
+		getStats: connect.NewClient[emptypb.Empty, v1.GetStatsResponse](
+			httpClient,
+			baseURL+TodoServiceGetStatsProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("GetStats")),
+			connect.WithClientOptions(opts...),
+		),
+		updateTask: connect.NewClient[v1.UpdateTaskRequest, v1.UpdateTaskResponse](
+			httpClient,
+			baseURL+TodoServiceUpdateTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("UpdateTask")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteTask: connect.NewClient[v1.DeleteTaskRequest, emptypb.Empty](
+			httpClient,
+			baseURL+TodoServiceDeleteTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("DeleteTask")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteTasks: connect.NewClient[v1.DeleteTasksRequest, v1.DeleteTasksResponse](
+			httpClient,
+			baseURL+TodoServiceDeleteTasksProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("DeleteTasks")),
+			connect.WithClientOptions(opts...),
+		),
+		duplicateTask: connect.NewClient[v1.DuplicateTaskRequest, v1.DuplicateTaskResponse](
+			httpClient,
+			baseURL+TodoServiceDuplicateTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("DuplicateTask")),
+			connect.WithClientOptions(opts...),
+		),
+		reorderTask: connect.NewClient[v1.ReorderTaskRequest, v1.ReorderTaskResponse](
+			httpClient,
+			baseURL+TodoServiceReorderTaskProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("ReorderTask")),
+			connect.WithClientOptions(opts...),
+		),
+		archiveOldCompleted: connect.NewClient[v1.ArchiveOldCompletedRequest, v1.ArchiveOldCompletedResponse](
+			httpClient,
+			baseURL+TodoServiceArchiveOldCompletedProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("ArchiveOldCompleted")),
+			connect.WithClientOptions(opts...),
+		),
+		purgeDeleted: connect.NewClient[v1.PurgeDeletedRequest, v1.PurgeDeletedResponse](
+			httpClient,
+			baseURL+TodoServicePurgeDeletedProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("PurgeDeleted")),
+			connect.WithClientOptions(opts...),
+		),
+		healthCheck: connect.NewClient[emptypb.Empty, v1.HealthCheckResponse](
+			httpClient,
+			baseURL+TodoServiceHealthCheckProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("HealthCheck")),
+			connect.WithClientOptions(opts...),
+		),
+		getVersion: connect.NewClient[emptypb.Empty, v1.GetVersionResponse](
+			httpClient,
+			baseURL+TodoServiceGetVersionProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("GetVersion")),
+			connect.WithClientOptions(opts...),
+		),
+		debugEcho: connect.NewClient[emptypb.Empty, v1.DebugEchoResponse](
+			httpClient,
+			baseURL+TodoServiceDebugEchoProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("DebugEcho")),
+			connect.WithClientOptions(opts...),
+		),
+		diagnoseStorage: connect.NewClient[emptypb.Empty, v1.DiagnoseStorageResponse](
+			httpClient,
+			baseURL+TodoServiceDiagnoseStorageProcedure,
+			connect.WithSchema(todoServiceMethods.ByName("DiagnoseStorage")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// todoServiceClient implements TodoServiceClient.
+type todoServiceClient struct {
+	createTask          *connect.Client[v1.CreateTaskRequest, v1.CreateTaskResponse]
+	getTask             *connect.Client[v1.GetTaskRequest, v1.GetTaskResponse]
+	listTasks           *connect.Client[v1.ListTasksRequest, v1.ListTasksResponse]
+	listTasksStream     *connect.Client[v1.ListTasksRequest, v1.ListTasksStreamResponse]
+	listChangedSince    *connect.Client[v1.ListChangedSinceRequest, v1.ListChangedSinceResponse]
+	countCreatedSince   *connect.Client[v1.CountCreatedSinceRequest, v1.CountCreatedSinceResponse]
+	getListVersion      *connect.Client[emptypb.Empty, v1.GetListVersionResponse]
+	getStats            *connect.Client[emptypb.Empty, v1.GetStatsResponse]
+	updateTask          *connect.Client[v1.UpdateTaskRequest, v1.UpdateTaskResponse]
+	deleteTask          *connect.Client[v1.DeleteTaskRequest, emptypb.Empty]
+	deleteTasks         *connect.Client[v1.DeleteTasksRequest, v1.DeleteTasksResponse]
+	duplicateTask       *connect.Client[v1.DuplicateTaskRequest, v1.DuplicateTaskResponse]
+	reorderTask         *connect.Client[v1.ReorderTaskRequest, v1.ReorderTaskResponse]
+	archiveOldCompleted *connect.Client[v1.ArchiveOldCompletedRequest, v1.ArchiveOldCompletedResponse]
+	purgeDeleted        *connect.Client[v1.PurgeDeletedRequest, v1.PurgeDeletedResponse]
+	healthCheck         *connect.Client[emptypb.Empty, v1.HealthCheckResponse]
+	getVersion          *connect.Client[emptypb.Empty, v1.GetVersionResponse]
+	debugEcho           *connect.Client[emptypb.Empty, v1.DebugEchoResponse]
+	diagnoseStorage     *connect.Client[emptypb.Empty, v1.DiagnoseStorageResponse]
+}
+
+// CreateTask calls todo.v1.TodoService.CreateTask.
+func (c *todoServiceClient) CreateTask(ctx context.Context, req *connect.Request[v1.CreateTaskRequest]) (*connect.Response[v1.CreateTaskResponse], error) {
+	return c.createTask.CallUnary(ctx, req)
+}
+
+// GetTask calls todo.v1.TodoService.GetTask.
+func (c *todoServiceClient) GetTask(ctx context.Context, req *connect.Request[v1.GetTaskRequest]) (*connect.Response[v1.GetTaskResponse], error) {
+	return c.getTask.CallUnary(ctx, req)
+}
+
+// ListTasks calls todo.v1.TodoService.ListTasks.
+func (c *todoServiceClient) ListTasks(ctx context.Context, req *connect.Request[v1.ListTasksRequest]) (*connect.Response[v1.ListTasksResponse], error) {
+	return c.listTasks.CallUnary(ctx, req)
+}
+
+// ListTasksStream calls todo.v1.TodoService.ListTasksStream.
+func (c *todoServiceClient) ListTasksStream(ctx context.Context, req *connect.Request[v1.ListTasksRequest]) (*connect.ServerStreamForClient[v1.ListTasksStreamResponse], error) {
+	return c.listTasksStream.CallServerStream(ctx, req)
+}
+
+// ListChangedSince calls todo.v1.TodoService.ListChangedSince.
+func (c *todoServiceClient) ListChangedSince(ctx context.Context, req *connect.Request[v1.ListChangedSinceRequest]) (*connect.Response[v1.ListChangedSinceResponse], error) {
+	return c.listChangedSince.CallUnary(ctx, req)
+}
+
+// CountCreatedSince calls todo.v1.TodoService.CountCreatedSince.
+func (c *todoServiceClient) CountCreatedSince(ctx context.Context, req *connect.Request[v1.CountCreatedSinceRequest]) (*connect.Response[v1.CountCreatedSinceResponse], error) {
+	return c.countCreatedSince.CallUnary(ctx, req)
+}
+
+// GetListVersion calls todo.v1.TodoService.GetListVersion.
+func (c *todoServiceClient) GetListVersion(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetListVersionResponse], error) {
+	return c.getListVersion.CallUnary(ctx, req)
+}
+
+// GetStats calls todo.v1.TodoService.GetStats.
+func (c *todoServiceClient) GetStats(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStatsResponse], error) {
+	return c.getStats.CallUnary(ctx, req)
+}
+
+// UpdateTask calls todo.v1.TodoService.UpdateTask.
+func (c *todoServiceClient) UpdateTask(ctx context.Context, req *connect.Request[v1.UpdateTaskRequest]) (*connect.Response[v1.UpdateTaskResponse], error) {
+	return c.updateTask.CallUnary(ctx, req)
+}
+
+// DeleteTask calls todo.v1.TodoService.DeleteTask.
+func (c *todoServiceClient) DeleteTask(ctx context.Context, req *connect.Request[v1.DeleteTaskRequest]) (*connect.Response[emptypb.Empty], error) {
+	return c.deleteTask.CallUnary(ctx, req)
+}
+
+// DeleteTasks calls todo.v1.TodoService.DeleteTasks.
+func (c *todoServiceClient) DeleteTasks(ctx context.Context, req *connect.Request[v1.DeleteTasksRequest]) (*connect.Response[v1.DeleteTasksResponse], error) {
+	return c.deleteTasks.CallUnary(ctx, req)
+}
+
+// DuplicateTask calls todo.v1.TodoService.DuplicateTask.
+func (c *todoServiceClient) DuplicateTask(ctx context.Context, req *connect.Request[v1.DuplicateTaskRequest]) (*connect.Response[v1.DuplicateTaskResponse], error) {
+	return c.duplicateTask.CallUnary(ctx, req)
+}
+
+// ReorderTask calls todo.v1.TodoService.ReorderTask.
+func (c *todoServiceClient) ReorderTask(ctx context.Context, req *connect.Request[v1.ReorderTaskRequest]) (*connect.Response[v1.ReorderTaskResponse], error) {
+	return c.reorderTask.CallUnary(ctx, req)
+}
+
+// ArchiveOldCompleted calls todo.v1.TodoService.ArchiveOldCompleted.
+func (c *todoServiceClient) ArchiveOldCompleted(ctx context.Context, req *connect.Request[v1.ArchiveOldCompletedRequest]) (*connect.Response[v1.ArchiveOldCompletedResponse], error) {
+	return c.archiveOldCompleted.CallUnary(ctx, req)
+}
+
+// PurgeDeleted calls todo.v1.TodoService.PurgeDeleted.
+func (c *todoServiceClient) PurgeDeleted(ctx context.Context, req *connect.Request[v1.PurgeDeletedRequest]) (*connect.Response[v1.PurgeDeletedResponse], error) {
+	return c.purgeDeleted.CallUnary(ctx, req)
+}
+
+// HealthCheck calls todo.v1.TodoService.HealthCheck.
+func (c *todoServiceClient) HealthCheck(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.HealthCheckResponse], error) {
+	return c.healthCheck.CallUnary(ctx, req)
+}
+
+// GetVersion calls todo.v1.TodoService.GetVersion.
+func (c *todoServiceClient) GetVersion(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetVersionResponse], error) {
+	return c.getVersion.CallUnary(ctx, req)
+}
+
+// DebugEcho calls todo.v1.TodoService.DebugEcho.
+func (c *todoServiceClient) DebugEcho(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.DebugEchoResponse], error) {
+	return c.debugEcho.CallUnary(ctx, req)
+}
+
+// DiagnoseStorage calls todo.v1.TodoService.DiagnoseStorage.
+func (c *todoServiceClient) DiagnoseStorage(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[v1.DiagnoseStorageResponse], error) {
+	return c.diagnoseStorage.CallUnary(ctx, req)
+}
+
+// TodoServiceHandler is an implementation of the todo.v1.TodoService service.
+type TodoServiceHandler interface {
+	// Create a new task
+	CreateTask(context.Context, *connect.Request[v1.CreateTaskRequest]) (*connect.Response[v1.CreateTaskResponse], error)
+	// Get a specific task by ID
+	GetTask(context.Context, *connect.Request[v1.GetTaskRequest]) (*connect.Response[v1.GetTaskResponse], error)
+	// List tasks with pagination, filtering, and search
+	ListTasks(context.Context, *connect.Request[v1.ListTasksRequest]) (*connect.Response[v1.ListTasksResponse], error)
+	// Stream one large page of tasks in chunks as they are read, followed by
+	// its pagination metadata
+	ListTasksStream(context.Context, *connect.Request[v1.ListTasksRequest], *connect.ServerStream[v1.ListTasksStreamResponse]) error
+	// Return tasks changed after a point in time, waiting up to a timeout for a
+	// change when there is none yet (long polling)
+	ListChangedSince(context.Context, *connect.Request[v1.ListChangedSinceRequest]) (*connect.Response[v1.ListChangedSinceResponse], error)
+	// Count the tasks created at or after a point in time, e.g. for a
+	// "created this week" widget
+	CountCreatedSince(context.Context, *connect.Request[v1.CountCreatedSinceRequest]) (*connect.Response[v1.CountCreatedSinceResponse], error)
+	// Return the current list version, so clients can skip a full ListTasks
+	// when nothing has changed since their last fetch
+	GetListVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetListVersionResponse], error)
+	// Count tasks by state, including tasks in the trash, for monitoring
+	GetStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStatsResponse], error)
+	// Update an existing task
+	UpdateTask(context.Context, *connect.Request[v1.UpdateTaskRequest]) (*connect.Response[v1.UpdateTaskResponse], error)
+	// Delete a task
+	DeleteTask(context.Context, *connect.Request[v1.DeleteTaskRequest]) (*connect.Response[emptypb.Empty], error)
+	// Delete multiple tasks, reporting a result for each id
+	DeleteTasks(context.Context, *connect.Request[v1.DeleteTasksRequest]) (*connect.Response[v1.DeleteTasksResponse], error)
+	// Create a new pending task copied from an existing one
+	DuplicateTask(context.Context, *connect.Request[v1.DuplicateTaskRequest]) (*connect.Response[v1.DuplicateTaskResponse], error)
+	// Move a task directly after another in the manual ordering
+	ReorderTask(context.Context, *connect.Request[v1.ReorderTaskRequest]) (*connect.Response[v1.ReorderTaskResponse], error)
+	// Archive completed tasks that have not changed within the retention period
+	ArchiveOldCompleted(context.Context, *connect.Request[v1.ArchiveOldCompletedRequest]) (*connect.Response[v1.ArchiveOldCompletedResponse], error)
+	// Permanently remove tasks that have been in the trash longer than the retention period
+	PurgeDeleted(context.Context, *connect.Request[v1.PurgeDeletedRequest]) (*connect.Response[v1.PurgeDeletedResponse], error)
+	// Health check endpoint
+	HealthCheck(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.HealthCheckResponse], error)
+	// Build and deployment information for the running server
+	GetVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetVersionResponse], error)
+	// Echo what the server received, for debugging clients behind proxies;
+	// unimplemented in production
+	DebugEcho(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DebugEchoResponse], error)
+	// Run read-only integrity checks over the tasks table, for diagnostics;
+	// unimplemented in production
+	DiagnoseStorage(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DiagnoseStorageResponse], error)
+}
+
+// NewTodoServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTodoServiceHandler(svc TodoServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	todoServiceMethods := v1.File_todo_v1_todo_proto.Services().ByName("TodoService").Methods()
+	todoServiceCreateTaskHandler := connect.NewUnaryHandler(
+		TodoServiceCreateTaskProcedure,
+		svc.CreateTask,
+		connect.WithSchema(todoServiceMethods.ByName("CreateTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceGetTaskHandler := connect.NewUnaryHandler(
+		TodoServiceGetTaskProcedure,
+		svc.GetTask,
+		connect.WithSchema(todoServiceMethods.ByName("GetTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceListTasksHandler := connect.NewUnaryHandler(
+		TodoServiceListTasksProcedure,
+		svc.ListTasks,
+		connect.WithSchema(todoServiceMethods.ByName("ListTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceListTasksStreamHandler := connect.NewServerStreamHandler(
+		TodoServiceListTasksStreamProcedure,
+		svc.ListTasksStream,
+		connect.WithSchema(todoServiceMethods.ByName("ListTasksStream")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceListChangedSinceHandler := connect.NewUnaryHandler(
+		TodoServiceListChangedSinceProcedure,
+		svc.ListChangedSince,
+		connect.WithSchema(todoServiceMethods.ByName("ListChangedSince")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceCountCreatedSinceHandler := connect.NewUnaryHandler(
+		TodoServiceCountCreatedSinceProcedure,
+		svc.CountCreatedSince,
+		connect.WithSchema(todoServiceMethods.ByName("CountCreatedSince")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceGetListVersionHandler := connect.NewUnaryHandler(
+		TodoServiceGetListVersionProcedure,
+		svc.GetListVersion,
+		connect.WithSchema(todoServiceMethods.ByName("GetListVersion")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceGetStatsHandler := connect.NewUnaryHandler(
+		TodoServiceGetStatsProcedure,
+		svc.GetStats,
+		connect.WithSchema(todoServiceMethods.ByName("GetStats")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceUpdateTaskHandler := connect.NewUnaryHandler(
+		TodoServiceUpdateTaskProcedure,
+		svc.UpdateTask,
+		connect.WithSchema(todoServiceMethods.ByName("UpdateTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceDeleteTaskHandler := connect.NewUnaryHandler(
+		TodoServiceDeleteTaskProcedure,
+		svc.DeleteTask,
+		connect.WithSchema(todoServiceMethods.ByName("DeleteTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceDeleteTasksHandler := connect.NewUnaryHandler(
+		TodoServiceDeleteTasksProcedure,
+		svc.DeleteTasks,
+		connect.WithSchema(todoServiceMethods.ByName("DeleteTasks")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceDuplicateTaskHandler := connect.NewUnaryHandler(
+		TodoServiceDuplicateTaskProcedure,
+		svc.DuplicateTask,
+		connect.WithSchema(todoServiceMethods.ByName("DuplicateTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceReorderTaskHandler := connect.NewUnaryHandler(
+		TodoServiceReorderTaskProcedure,
+		svc.ReorderTask,
+		connect.WithSchema(todoServiceMethods.ByName("ReorderTask")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceArchiveOldCompletedHandler := connect.NewUnaryHandler(
+		TodoServiceArchiveOldCompletedProcedure,
+		svc.ArchiveOldCompleted,
+		connect.WithSchema(todoServiceMethods.ByName("ArchiveOldCompleted")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServicePurgeDeletedHandler := connect.NewUnaryHandler(
+		TodoServicePurgeDeletedProcedure,
+		svc.PurgeDeleted,
+		connect.WithSchema(todoServiceMethods.ByName("PurgeDeleted")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceHealthCheckHandler := connect.NewUnaryHandler(
+		TodoServiceHealthCheckProcedure,
+		svc.HealthCheck,
+		connect.WithSchema(todoServiceMethods.ByName("HealthCheck")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceGetVersionHandler := connect.NewUnaryHandler(
+		TodoServiceGetVersionProcedure,
+		svc.GetVersion,
+		connect.WithSchema(todoServiceMethods.ByName("GetVersion")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceDebugEchoHandler := connect.NewUnaryHandler(
+		TodoServiceDebugEchoProcedure,
+		svc.DebugEcho,
+		connect.WithSchema(todoServiceMethods.ByName("DebugEcho")),
+		connect.WithHandlerOptions(opts...),
+	)
+	todoServiceDiagnoseStorageHandler := connect.NewUnaryHandler(
+		TodoServiceDiagnoseStorageProcedure,
+		svc.DiagnoseStorage,
+		connect.WithSchema(todoServiceMethods.ByName("DiagnoseStorage")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/todo.v1.TodoService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TodoServiceCreateTaskProcedure:
+			todoServiceCreateTaskHandler.ServeHTTP(w, r)
+		case TodoServiceGetTaskProcedure:
+			todoServiceGetTaskHandler.ServeHTTP(w, r)
+		case TodoServiceListTasksProcedure:
+			todoServiceListTasksHandler.ServeHTTP(w, r)
+		case TodoServiceListTasksStreamProcedure:
+			todoServiceListTasksStreamHandler.ServeHTTP(w, r)
+		case TodoServiceListChangedSinceProcedure:
+			todoServiceListChangedSinceHandler.ServeHTTP(w, r)
+		case TodoServiceCountCreatedSinceProcedure:
+			todoServiceCountCreatedSinceHandler.ServeHTTP(w, r)
+		case TodoServiceGetListVersionProcedure:
+			todoServiceGetListVersionHandler.ServeHTTP(w, r)
+		case TodoServiceGetStatsProcedure:
+			todoServiceGetStatsHandler.ServeHTTP(w, r)
+		case TodoServiceUpdateTaskProcedure:
+			todoServiceUpdateTaskHandler.ServeHTTP(w, r)
+		case TodoServiceDeleteTaskProcedure:
+			todoServiceDeleteTaskHandler.ServeHTTP(w, r)
+		case TodoServiceDeleteTasksProcedure:
+			todoServiceDeleteTasksHandler.ServeHTTP(w, r)
+		case TodoServiceDuplicateTaskProcedure:
+			todoServiceDuplicateTaskHandler.ServeHTTP(w, r)
+		case TodoServiceReorderTaskProcedure:
+			todoServiceReorderTaskHandler.ServeHTTP(w, r)
+		case TodoServiceArchiveOldCompletedProcedure:
+			todoServiceArchiveOldCompletedHandler.ServeHTTP(w, r)
+		case TodoServicePurgeDeletedProcedure:
+			todoServicePurgeDeletedHandler.ServeHTTP(w, r)
+		case TodoServiceHealthCheckProcedure:
+			todoServiceHealthCheckHandler.ServeHTTP(w, r)
+		case TodoServiceGetVersionProcedure:
+			todoServiceGetVersionHandler.ServeHTTP(w, r)
+		case TodoServiceDebugEchoProcedure:
+			todoServiceDebugEchoHandler.ServeHTTP(w, r)
+		case TodoServiceDiagnoseStorageProcedure:
+			todoServiceDiagnoseStorageHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTodoServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTodoServiceHandler struct{}
+
+func (UnimplementedTodoServiceHandler) CreateTask(context.Context, *connect.Request[v1.CreateTaskRequest]) (*connect.Response[v1.CreateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.CreateTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) GetTask(context.Context, *connect.Request[v1.GetTaskRequest]) (*connect.Response[v1.GetTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.GetTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) ListTasks(context.Context, *connect.Request[v1.ListTasksRequest]) (*connect.Response[v1.ListTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.ListTasks is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) ListTasksStream(context.Context, *connect.Request[v1.ListTasksRequest], *connect.ServerStream[v1.ListTasksStreamResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.ListTasksStream is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) ListChangedSince(context.Context, *connect.Request[v1.ListChangedSinceRequest]) (*connect.Response[v1.ListChangedSinceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.ListChangedSince is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) CountCreatedSince(context.Context, *connect.Request[v1.CountCreatedSinceRequest]) (*connect.Response[v1.CountCreatedSinceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.CountCreatedSince is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) GetListVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetListVersionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.GetListVersion is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) GetStats(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetStatsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.GetStats is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) UpdateTask(context.Context, *connect.Request[v1.UpdateTaskRequest]) (*connect.Response[v1.UpdateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.UpdateTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) DeleteTask(context.Context, *connect.Request[v1.DeleteTaskRequest]) (*connect.Response[emptypb.Empty], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.DeleteTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) DeleteTasks(context.Context, *connect.Request[v1.DeleteTasksRequest]) (*connect.Response[v1.DeleteTasksResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.DeleteTasks is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) DuplicateTask(context.Context, *connect.Request[v1.DuplicateTaskRequest]) (*connect.Response[v1.DuplicateTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.DuplicateTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) ReorderTask(context.Context, *connect.Request[v1.ReorderTaskRequest]) (*connect.Response[v1.ReorderTaskResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.ReorderTask is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) ArchiveOldCompleted(context.Context, *connect.Request[v1.ArchiveOldCompletedRequest]) (*connect.Response[v1.ArchiveOldCompletedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.ArchiveOldCompleted is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) PurgeDeleted(context.Context, *connect.Request[v1.PurgeDeletedRequest]) (*connect.Response[v1.PurgeDeletedResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.PurgeDeleted is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) HealthCheck(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.HealthCheckResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.HealthCheck is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) GetVersion(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.GetVersionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.GetVersion is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) DebugEcho(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DebugEchoResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.DebugEcho is not implemented"))
+}
+
+func (UnimplementedTodoServiceHandler) DiagnoseStorage(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[v1.DiagnoseStorageResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("todo.v1.TodoService.DiagnoseStorage is not implemented"))
+}
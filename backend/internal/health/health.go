@@ -0,0 +1,63 @@
+// Package health serves the standard gRPC health checking protocol
+// (grpc.health.v1) and a matching /readyz probe from a single readiness check.
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+)
+
+// Checker reports readiness by pinging a dependency such as the database.
+// The empty service name reports on the server as a whole.
+type Checker struct {
+	ping     func(ctx context.Context) error
+	services map[string]bool
+}
+
+// NewChecker creates a Checker backed by ping for the named services
+func NewChecker(ping func(ctx context.Context) error, services ...string) *Checker {
+	known := map[string]bool{"": true}
+	for _, service := range services {
+		known[service] = true
+	}
+	return &Checker{ping: ping, services: known}
+}
+
+// Ready reports whether the server can serve traffic
+func (c *Checker) Ready(ctx context.Context) error {
+	return c.ping(ctx)
+}
+
+// Check implements grpchealth.Checker, pinging on every call
+func (c *Checker) Check(ctx context.Context, req *grpchealth.CheckRequest) (*grpchealth.CheckResponse, error) {
+	if !c.services[req.Service] {
+		return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("unknown service %q", req.Service))
+	}
+
+	status := grpchealth.StatusServing
+	if err := c.Ready(ctx); err != nil {
+		status = grpchealth.StatusNotServing
+	}
+	return &grpchealth.CheckResponse{Status: status}, nil
+}
+
+// NewHandler returns the path and handler for the grpc.health.v1.Health
+// service. Watch is not supported; clients fall back to polling Check.
+func NewHandler(checker *Checker, opts ...connect.HandlerOption) (string, http.Handler) {
+	return grpchealth.NewHandler(checker, opts...)
+}
+
+// ReadyzHandler responds 200 when ready and 503 otherwise
+func (c *Checker) ReadyzHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Ready(r.Context()); err != nil {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/grpchealth"
+	"github.com/stretchr/testify/assert"
+)
+
+// checkStatus calls grpc.health.v1.Health/Check for service with Connect JSON,
+// returning the HTTP status and the reported serving status
+func checkStatus(t *testing.T, url, service string) (int, string) {
+	t.Helper()
+	body := strings.NewReader(`{"service":"` + service + `"}`)
+	resp, err := http.Post(url+"/"+grpchealth.HealthV1ServiceName+"/Check", "application/json", body)
+	if err != nil {
+		t.Fatalf("Failed to call Check: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var msg struct {
+		Status string `json:"status"`
+	}
+	json.NewDecoder(resp.Body).Decode(&msg)
+	return resp.StatusCode, msg.Status
+}
+
+func TestChecker_Check(t *testing.T) {
+	var pingErr error
+	checker := NewChecker(func(ctx context.Context) error { return pingErr }, "todo.v1.TodoService")
+	mux := http.NewServeMux()
+	mux.Handle(NewHandler(checker))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("serving when the database is up", func(t *testing.T) {
+		for _, service := range []string{"", "todo.v1.TodoService"} {
+			code, status := checkStatus(t, server.URL, service)
+
+			assert.Equal(t, http.StatusOK, code)
+			assert.Equal(t, "SERVING_STATUS_SERVING", status)
+		}
+	})
+
+	t.Run("not serving when the database is down", func(t *testing.T) {
+		pingErr = errors.New("connection refused")
+		defer func() { pingErr = nil }()
+
+		code, status := checkStatus(t, server.URL, "")
+
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "SERVING_STATUS_NOT_SERVING", status)
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		code, _ := checkStatus(t, server.URL, "other.v1.Service")
+
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
+
+func TestChecker_ReadyzHandler(t *testing.T) {
+	var pingErr error
+	handler := NewChecker(func(ctx context.Context) error { return pingErr }).ReadyzHandler()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	pingErr = errors.New("connection refused")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,150 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// DecodeErrorKind classifies why a JSON request body could not be decoded
+type DecodeErrorKind string
+
+const (
+	DecodeErrorEmptyBody    DecodeErrorKind = "empty_body"
+	DecodeErrorTruncated    DecodeErrorKind = "truncated"
+	DecodeErrorSyntax       DecodeErrorKind = "syntax"
+	DecodeErrorType         DecodeErrorKind = "type"
+	DecodeErrorUnknownField DecodeErrorKind = "unknown_field"
+	DecodeErrorTrailingData DecodeErrorKind = "trailing_data"
+	DecodeErrorTooLarge     DecodeErrorKind = "too_large"
+)
+
+// DecodeError describes a malformed JSON request body
+type DecodeError struct {
+	Kind    DecodeErrorKind
+	Offset  int64  // byte offset of the problem, when known
+	Field   string // offending field, for unknown-field and type errors
+	Message string
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Message
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeJSON decodes a single JSON value from the request body into dst,
+// rejecting unknown fields and trailing data. Failures are returned as *DecodeError.
+func DecodeJSON(r *http.Request, dst interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(dst); err != nil {
+		return classifyDecodeError(err)
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return classifyDecodeError(err)
+		}
+		return &DecodeError{
+			Kind:    DecodeErrorTrailingData,
+			Offset:  decoder.InputOffset(),
+			Message: fmt.Sprintf("request body must contain a single JSON value (extra data at byte %d)", decoder.InputOffset()),
+		}
+	}
+
+	return nil
+}
+
+// classifyDecodeError converts an encoding/json error, or the error of a body
+// limited by http.MaxBytesReader, into a *DecodeError
+func classifyDecodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return &DecodeError{
+			Kind:    DecodeErrorTooLarge,
+			Message: fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit),
+			Err:     err,
+		}
+	case errors.Is(err, io.EOF):
+		return &DecodeError{Kind: DecodeErrorEmptyBody, Message: "request body must not be empty", Err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{Kind: DecodeErrorTruncated, Message: "request body contains truncated JSON", Err: err}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{
+			Kind:    DecodeErrorSyntax,
+			Offset:  syntaxErr.Offset,
+			Message: fmt.Sprintf("request body contains malformed JSON at byte %d", syntaxErr.Offset),
+			Err:     err,
+		}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			Kind:    DecodeErrorType,
+			Offset:  typeErr.Offset,
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("field %q must be of type %s", typeErr.Field, typeErr.Type),
+			Err:     err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for DisallowUnknownFields
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &DecodeError{
+			Kind:    DecodeErrorUnknownField,
+			Field:   field,
+			Message: fmt.Sprintf("request body contains unknown field %q", field),
+			Err:     err,
+		}
+	default:
+		return fmt.Errorf("failed to decode request body: %w", err)
+	}
+}
+
+// WriteDecodeError responds with 400 for a *DecodeError, 413 with code
+// RESOURCE_EXHAUSTED for a body over its http.MaxBytesReader limit, and 500
+// otherwise
+func WriteDecodeError(w http.ResponseWriter, err error) {
+	response := middleware.ErrorResponse{
+		Code:      "INVALID_JSON",
+		Message:   err.Error(),
+		Timestamp: time.Now(),
+	}
+	status := http.StatusBadRequest
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		response.Details = map[string]string{"kind": string(decodeErr.Kind)}
+		if decodeErr.Field != "" {
+			response.Details["field"] = decodeErr.Field
+		}
+		if decodeErr.Offset > 0 {
+			response.Details["offset"] = fmt.Sprintf("%d", decodeErr.Offset)
+		}
+		if decodeErr.Kind == DecodeErrorTooLarge {
+			status = http.StatusRequestEntityTooLarge
+			response.Code = "RESOURCE_EXHAUSTED"
+		}
+	} else {
+		status = http.StatusInternalServerError
+		response.Code = "INTERNAL_ERROR"
+		response.Message = "An internal server error occurred"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
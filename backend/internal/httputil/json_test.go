@@ -0,0 +1,148 @@
+package httputil
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type importPayload struct {
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantKind  DecodeErrorKind
+		wantField string
+	}{
+		{name: "valid", body: `{"title":"Buy milk","completed":true}`},
+		{name: "empty body", body: ``, wantKind: DecodeErrorEmptyBody},
+		{name: "truncated", body: `{"title":"Buy mi`, wantKind: DecodeErrorTruncated},
+		{name: "syntax error", body: `{"title" "Buy milk"}`, wantKind: DecodeErrorSyntax},
+		{name: "extra field", body: `{"title":"Buy milk","priority":1}`, wantKind: DecodeErrorUnknownField, wantField: "priority"},
+		{name: "wrong type", body: `{"title":42}`, wantKind: DecodeErrorType, wantField: "title"},
+		{name: "trailing data", body: `{"title":"a"}{"title":"b"}`, wantKind: DecodeErrorTrailingData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(tt.body))
+
+			var payload importPayload
+			err := DecodeJSON(req, &payload)
+
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if payload.Title != "Buy milk" || !payload.Completed {
+					t.Errorf("Unexpected payload: %+v", payload)
+				}
+				return
+			}
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("Expected *DecodeError, got %v", err)
+			}
+			if decodeErr.Kind != tt.wantKind {
+				t.Errorf("Expected kind %q, got %q", tt.wantKind, decodeErr.Kind)
+			}
+			if decodeErr.Field != tt.wantField {
+				t.Errorf("Expected field %q, got %q", tt.wantField, decodeErr.Field)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_SyntaxOffset(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(`{"title":,}`))
+
+	var payload importPayload
+	err := DecodeJSON(req, &payload)
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Expected *DecodeError, got %v", err)
+	}
+	if decodeErr.Offset != 10 {
+		t.Errorf("Expected offset 10, got %d", decodeErr.Offset)
+	}
+	if !strings.Contains(decodeErr.Message, "byte 10") {
+		t.Errorf("Expected message to include offset, got %q", decodeErr.Message)
+	}
+}
+
+func TestWriteDecodeError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(`{"title":"a","extra":true}`))
+	var payload importPayload
+	err := DecodeJSON(req, &payload)
+
+	rec := httptest.NewRecorder()
+	WriteDecodeError(rec, err)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", ct)
+	}
+
+	var body struct {
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Details map[string]string `json:"details"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if body.Code != "INVALID_JSON" {
+		t.Errorf("Expected code INVALID_JSON, got %q", body.Code)
+	}
+	if body.Details["kind"] != string(DecodeErrorUnknownField) || body.Details["field"] != "extra" {
+		t.Errorf("Unexpected details: %v", body.Details)
+	}
+}
+
+func TestWriteDecodeError_NonDecodeError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteDecodeError(rec, errors.New("read failed"))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+}
+
+func TestDecodeJSON_BodyTooLarge(t *testing.T) {
+	for _, body := range []string{
+		`{"title":"` + strings.Repeat("a", 64) + `"}`,
+		// The value fits but the trailing data does not
+		`{"title":"a"}` + strings.Repeat(" ", 64) + `{}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		req.Body = http.MaxBytesReader(rec, req.Body, 32)
+
+		var payload importPayload
+		err := DecodeJSON(req, &payload)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Kind != DecodeErrorTooLarge {
+			t.Fatalf("Expected a too_large *DecodeError, got %v", err)
+		}
+
+		WriteDecodeError(rec, err)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Expected status 413, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), `"code":"RESOURCE_EXHAUSTED"`) {
+			t.Errorf("Expected code RESOURCE_EXHAUSTED, got %s", rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// Principal identifies the authenticated caller of a request. It must only
+// carry identifiers, never credentials, because it is written to every log line.
+type Principal struct {
+	Kind string // e.g. "user" or "api_key"
+	ID   string
+}
+
+// String formats the principal as "kind:id" for logging
+func (p Principal) String() string {
+	if p.Kind == "" {
+		return p.ID
+	}
+	return p.Kind + ":" + p.ID
+}
+
+// APIKeyPrincipal identifies an API key caller by a short hash of the key so
+// the key itself never reaches the logs
+func APIKeyPrincipal(key string) Principal {
+	sum := sha256.Sum256([]byte(key))
+	return Principal{Kind: "api_key", ID: hex.EncodeToString(sum[:8])}
+}
+
+// PrincipalResolver returns the authenticated principal for a request, or false
+// when the request is anonymous
+type PrincipalResolver func(ctx context.Context, header http.Header) (Principal, bool)
+
+// ActorInterceptor stores the resolved principal in the context so every log
+// written while handling the request, including repository operations, carries
+// it. Streaming RPCs are attributed the same way as unary ones.
+func ActorInterceptor(resolve PrincipalResolver) connect.Interceptor {
+	return &actorInterceptor{resolve: resolve}
+}
+
+// actorInterceptor implements ActorInterceptor
+type actorInterceptor struct {
+	resolve PrincipalResolver
+}
+
+// WrapUnary attributes unary RPCs to the resolved principal
+func (i *actorInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return next(i.attribute(ctx, req.Header()), req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *actorInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler attributes streaming RPCs to the resolved principal
+func (i *actorInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		return next(i.attribute(ctx, conn.RequestHeader()), conn)
+	}
+}
+
+// attribute returns ctx carrying the principal resolved from header, if any
+func (i *actorInterceptor) attribute(ctx context.Context, header http.Header) context.Context {
+	if principal, ok := i.resolve(ctx, header); ok {
+		ctx = WithActor(ctx, principal.String())
+	}
+	return ctx
+}
+
+// WithActor adds the acting principal to the context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, "actor", actor)
+}
+
+// getActor extracts the acting principal from context
+func getActor(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if actor, ok := ctx.Value("actor").(string); ok {
+		return actor
+	}
+	return ""
+}
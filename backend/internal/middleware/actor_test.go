@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func bearerResolver(ctx context.Context, header http.Header) (Principal, bool) {
+	key, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer ")
+	if !ok || key == "" {
+		return Principal{}, false
+	}
+	return APIKeyPrincipal(key), true
+}
+
+func TestActorInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &StructuredLogger{
+		logger: log.New(&buf, "", 0),
+	}
+	logger.SetLevel(LevelInfo)
+
+	// Stand-in for a handler whose repository logs a database operation
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		logger.LogDatabaseOperation(WithSource(ctx, "repository.Create"), "INSERT tasks", time.Millisecond, true, 1)
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := ActorInterceptor(bearerResolver).WrapUnary(next)
+
+	t.Run("authenticated request", func(t *testing.T) {
+		buf.Reset()
+		const secret = "sk-live-abc123"
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set("Authorization", "Bearer "+secret)
+
+		if _, err := handler(context.Background(), req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var entry LogEntry
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse log JSON: %v", err)
+		}
+		if entry.Actor != APIKeyPrincipal(secret).String() {
+			t.Errorf("Expected actor %q, got %q", APIKeyPrincipal(secret).String(), entry.Actor)
+		}
+		if entry.Source != "repository.Create" {
+			t.Errorf("Expected repository source, got %q", entry.Source)
+		}
+		if strings.Contains(buf.String(), secret) {
+			t.Error("API key leaked into logs")
+		}
+	})
+
+	t.Run("anonymous request", func(t *testing.T) {
+		buf.Reset()
+
+		if _, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var entry LogEntry
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse log JSON: %v", err)
+		}
+		if entry.Actor != "" {
+			t.Errorf("Expected no actor, got %q", entry.Actor)
+		}
+	})
+}
+
+func TestMiddlewareStack_PrincipalResolver(t *testing.T) {
+	stack := NewMiddlewareStack(NewStructuredLogger(LevelInfo))
+	if got := len(stack.GetConnectInterceptors()); got != 1 {
+		t.Errorf("Expected 1 interceptor without a resolver, got %d", got)
+	}
+
+	stack.SetPrincipalResolver(bearerResolver)
+	if got := len(stack.GetConnectInterceptors()); got != 2 {
+		t.Errorf("Expected 2 interceptors with a resolver, got %d", got)
+	}
+}
@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// AuthFailurePolicy sets when repeated authentication failures from one client
+// IP get that IP temporarily blocked
+type AuthFailurePolicy struct {
+	// MaxFailures is how many failures within Window trip the block; zero
+	// never blocks
+	MaxFailures int
+	// Window is how long failures are remembered, and how long a block lasts
+	Window time.Duration
+}
+
+// DefaultAuthFailurePolicy blocks an IP for five minutes after ten failures
+var DefaultAuthFailurePolicy = AuthFailurePolicy{MaxFailures: 10, Window: 5 * time.Minute}
+
+// ParseAPIKeys parses an API_KEYS value: a comma-separated list of accepted keys
+func ParseAPIKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.Split(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// APIKeyAuth requires RPCs to present one of a set of API keys in the
+// X-API-Key header, rejecting others with Unauthenticated. Every failure is
+// logged at warn with a hash of the presented key, the client IP and the
+// procedure, and counted per client IP; an IP that fails too often is
+// rejected with ResourceExhausted until its block expires. Successes are
+// logged at debug only. HealthCheck and GetVersion are exempt so probes need
+// no key.
+type APIKeyAuth struct {
+	keys   [][sha256.Size]byte // hashes of the accepted keys
+	logger Logger
+	policy AuthFailurePolicy
+
+	mu        sync.Mutex
+	clients   map[string]*authFailures // by client IP
+	lastSweep time.Time
+	failed    atomic.Uint64
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// authFailures tracks recent authentication failures from one client IP
+type authFailures struct {
+	count        int
+	first        time.Time
+	blockedUntil time.Time
+}
+
+// NewAPIKeyAuth returns an authenticator accepting keys
+func NewAPIKeyAuth(keys []string, logger Logger, policy AuthFailurePolicy) *APIKeyAuth {
+	auth := &APIKeyAuth{
+		logger:  logger,
+		policy:  policy,
+		clients: map[string]*authFailures{},
+		now:     time.Now,
+	}
+	for _, key := range keys {
+		auth.keys = append(auth.keys, sha256.Sum256([]byte(key)))
+	}
+	return auth
+}
+
+// Enabled reports whether any keys are accepted; a nil or disabled
+// authenticator lets every request through
+func (a *APIKeyAuth) Enabled() bool {
+	return a != nil && len(a.keys) > 0
+}
+
+// FailedAttempts returns how many authentication attempts have failed since startup
+func (a *APIKeyAuth) FailedAttempts() uint64 {
+	return a.failed.Load()
+}
+
+// Failures returns the recent failures counted against clientIP
+func (a *APIKeyAuth) Failures(clientIP string) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if client, ok := a.clients[clientIP]; ok && a.now().Sub(client.first) < a.policy.Window {
+		return client.count
+	}
+	return 0
+}
+
+// valid reports whether key is one of the accepted keys, comparing hashes in
+// constant time
+func (a *APIKeyAuth) valid(key string) bool {
+	sum := sha256.Sum256([]byte(key))
+	ok := false
+	for _, accepted := range a.keys {
+		if subtle.ConstantTimeCompare(sum[:], accepted[:]) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// authenticate returns ctx attributed to the caller's key, or the error that
+// rejects a call to procedure
+func (a *APIKeyAuth) authenticate(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	if strings.HasSuffix(procedure, "/HealthCheck") || strings.HasSuffix(procedure, "/GetVersion") {
+		return ctx, nil
+	}
+
+	clientIP := GetClientIP(ctx)
+	if retryAfter, blocked := a.blocked(clientIP); blocked {
+		seconds := int((retryAfter + time.Second - 1) / time.Second)
+		err := connect.NewError(connect.CodeResourceExhausted,
+			fmt.Errorf("too many failed authentication attempts; retry in %ds", seconds))
+		err.Meta().Set("Retry-After", strconv.Itoa(seconds))
+		return nil, err
+	}
+
+	key := strings.TrimSpace(header.Get(APIKeyHeader))
+	if key == "" {
+		a.recordFailure(ctx, clientIP, procedure, "missing_key", "")
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("missing "+APIKeyHeader+" header"))
+	}
+	principal := APIKeyPrincipal(key)
+	if !a.valid(key) {
+		a.recordFailure(ctx, clientIP, procedure, "invalid_key", principal.ID)
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("invalid API key"))
+	}
+
+	ctx = WithActor(ctx, principal.String())
+	if debug, ok := a.logger.(interface {
+		Debug(ctx context.Context, msg string, fields map[string]interface{})
+	}); ok {
+		debug.Debug(ctx, "Authentication succeeded", map[string]interface{}{
+			"category":  "auth",
+			"procedure": procedure,
+		})
+	}
+	return ctx, nil
+}
+
+// blocked reports whether clientIP is blocked and for how much longer
+func (a *APIKeyAuth) blocked(clientIP string) (time.Duration, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	client, ok := a.clients[clientIP]
+	if !ok {
+		return 0, false
+	}
+	remaining := client.blockedUntil.Sub(a.now())
+	return remaining, remaining > 0
+}
+
+// recordFailure counts and logs a failed attempt; keyHash is empty when no key
+// was presented
+func (a *APIKeyAuth) recordFailure(ctx context.Context, clientIP, procedure, reason, keyHash string) {
+	a.failed.Add(1)
+
+	a.mu.Lock()
+	now := a.now()
+	a.sweep(now)
+	client, ok := a.clients[clientIP]
+	if !ok || now.Sub(client.first) >= a.policy.Window {
+		client = &authFailures{first: now}
+		a.clients[clientIP] = client
+	}
+	client.count++
+	failures := client.count
+	tripped := a.policy.MaxFailures > 0 && failures == a.policy.MaxFailures
+	if tripped {
+		client.blockedUntil = now.Add(a.policy.Window)
+	}
+	a.mu.Unlock()
+
+	fields := map[string]interface{}{
+		"category":  "auth",
+		"reason":    reason,
+		"client_ip": clientIP,
+		"procedure": procedure,
+		"failures":  failures,
+	}
+	if keyHash != "" {
+		fields["key_hash"] = keyHash
+	}
+	a.logger.Warn(ctx, "Authentication failed", fields)
+
+	if tripped {
+		a.logger.Error(ctx, "Client blocked after repeated authentication failures", nil, map[string]interface{}{
+			"category":      "auth",
+			"alert":         "auth_failures",
+			"client_ip":     clientIP,
+			"failures":      failures,
+			"blocked_until": client.blockedUntil,
+		})
+	}
+}
+
+// sweep forgets clients whose failures and block have both expired, at most
+// once per window. Callers hold a.mu.
+func (a *APIKeyAuth) sweep(now time.Time) {
+	if now.Sub(a.lastSweep) < a.policy.Window {
+		return
+	}
+	a.lastSweep = now
+	for clientIP, client := range a.clients {
+		if now.Sub(client.first) >= a.policy.Window && !now.Before(client.blockedUntil) {
+			delete(a.clients, clientIP)
+		}
+	}
+}
+
+// Interceptor returns a Connect interceptor that authenticates unary and
+// streaming RPCs
+func (a *APIKeyAuth) Interceptor() connect.Interceptor {
+	return &authInterceptor{auth: a}
+}
+
+// authInterceptor implements APIKeyAuth.Interceptor
+type authInterceptor struct {
+	auth *APIKeyAuth
+}
+
+// WrapUnary authenticates unary RPCs
+func (i *authInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := i.auth.authenticate(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *authInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler authenticates streaming RPCs
+func (i *authInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.auth.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
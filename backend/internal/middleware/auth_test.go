@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestAPIKeyAuth_LogsAndCountsFailures(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	logger := &debugMockLogger{}
+	auth := NewAPIKeyAuth([]string{"good-key"}, logger, AuthFailurePolicy{MaxFailures: 3, Window: time.Minute})
+	auth.now = func() time.Time { return now }
+
+	var reached int
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		reached++
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := auth.Interceptor().WrapUnary(next)
+	call := func(ip, key string) error {
+		req := connect.NewRequest(&emptypb.Empty{})
+		if key != "" {
+			req.Header().Set(APIKeyHeader, key)
+		}
+		_, err := handler(WithClientIP(context.Background(), ip), req)
+		return err
+	}
+
+	if err := call("203.0.113.7", "wrong-key"); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("Expected Unauthenticated for a bad key, got %v", err)
+	}
+	if len(logger.warnMessages) != 1 {
+		t.Fatalf("Expected one warning for a failed attempt, got %d", len(logger.warnMessages))
+	}
+	fields := logger.warnMessages[0].Fields
+	if fields["client_ip"] != "203.0.113.7" || fields["procedure"] != "" || fields["reason"] != "invalid_key" || fields["failures"] != 1 {
+		t.Errorf("Unexpected failure fields %v", fields)
+	}
+	if fields["key_hash"] != APIKeyPrincipal("wrong-key").ID {
+		t.Errorf("Expected the hashed key prefix, got %v", fields["key_hash"])
+	}
+	for _, v := range fields {
+		if s, ok := v.(string); ok && strings.Contains(s, "wrong-key") {
+			t.Errorf("Expected the presented key not to be logged, got %v", fields)
+		}
+	}
+
+	if err := call("203.0.113.7", ""); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Fatalf("Expected Unauthenticated for a missing key, got %v", err)
+	}
+	if got := logger.warnMessages[1].Fields; got["reason"] != "missing_key" || got["key_hash"] != nil {
+		t.Errorf("Unexpected fields for a missing key %v", got)
+	}
+	if auth.Failures("203.0.113.7") != 2 || auth.Failures("198.51.100.1") != 0 {
+		t.Errorf("Expected failures counted per IP, got %d", auth.Failures("203.0.113.7"))
+	}
+	if len(logger.errorMessages) != 0 {
+		t.Errorf("Expected no alert below the limit, got %v", logger.errorMessages)
+	}
+
+	// The third failure trips the alert and the block
+	call("203.0.113.7", "wrong-key")
+	if len(logger.errorMessages) != 1 || logger.errorMessages[0].Fields["alert"] != "auth_failures" {
+		t.Fatalf("Expected an alert at the limit, got %v", logger.errorMessages)
+	}
+	err := call("203.0.113.7", "good-key")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("Expected a blocked IP to be rejected even with a good key, got %v", err)
+	}
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Meta().Get("Retry-After") != "60" {
+		t.Errorf("Expected Retry-After 60, got %v", err)
+	}
+	if err := call("198.51.100.1", "good-key"); err != nil {
+		t.Errorf("Expected other IPs to be unaffected, got %v", err)
+	}
+	if auth.FailedAttempts() != 3 {
+		t.Errorf("Expected 3 failed attempts, got %d", auth.FailedAttempts())
+	}
+
+	now = now.Add(time.Minute)
+	if err := call("203.0.113.7", "good-key"); err != nil {
+		t.Errorf("Expected the block to expire, got %v", err)
+	}
+	if reached != 2 {
+		t.Errorf("Expected only authenticated calls to reach the handler, got %d", reached)
+	}
+}
+
+func TestAPIKeyAuth_SuccessLogsAtDebug(t *testing.T) {
+	logger := &debugMockLogger{}
+	auth := NewAPIKeyAuth([]string{"good-key", "other-key"}, logger, DefaultAuthFailurePolicy)
+
+	var actor string
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		actor = getActor(ctx)
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := auth.Interceptor().WrapUnary(next)
+
+	for i := 0; i < 5; i++ {
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set(APIKeyHeader, "other-key")
+		if _, err := handler(WithClientIP(context.Background(), "203.0.113.7"), req); err != nil {
+			t.Fatalf("Expected an accepted key to pass, got %v", err)
+		}
+	}
+
+	if len(logger.infoMessages)+len(logger.warnMessages)+len(logger.errorMessages) != 0 {
+		t.Errorf("Expected successful auths not to log above debug, got info=%v warn=%v error=%v",
+			logger.infoMessages, logger.warnMessages, logger.errorMessages)
+	}
+	if len(logger.debugMessages) != 5 {
+		t.Errorf("Expected one debug line per success, got %d", len(logger.debugMessages))
+	}
+	if actor != APIKeyPrincipal("other-key").String() {
+		t.Errorf("Expected the call to be attributed to the key, got %q", actor)
+	}
+	if auth.FailedAttempts() != 0 {
+		t.Errorf("Expected no failures counted, got %d", auth.FailedAttempts())
+	}
+}
+
+func TestAPIKeyAuth_Exemptions(t *testing.T) {
+	logger := &mockLogger{}
+	auth := NewAPIKeyAuth([]string{"good-key"}, logger, DefaultAuthFailurePolicy)
+
+	for _, procedure := range []string{"/todo.v1.TodoService/HealthCheck", "/todo.v1.TodoService/GetVersion"} {
+		if _, err := auth.authenticate(context.Background(), procedure, nil); err != nil {
+			t.Errorf("Expected %s to be exempt, got %v", procedure, err)
+		}
+	}
+	if len(logger.warnMessages) != 0 {
+		t.Errorf("Expected exempt calls not to be logged, got %v", logger.warnMessages)
+	}
+
+	if NewAPIKeyAuth(ParseAPIKeys(" , "), logger, DefaultAuthFailurePolicy).Enabled() {
+		t.Error("Expected no keys to disable authentication")
+	}
+	if keys := ParseAPIKeys(" alpha, beta ,,"); len(keys) != 2 || keys[0] != "alpha" || keys[1] != "beta" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
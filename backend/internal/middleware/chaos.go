@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// errChaos is returned for requests the chaos interceptor chose to fail
+var errChaos = errors.New("chaos: injected transient failure")
+
+// Chaos injects faults into RPCs so client retry and timeout handling can be
+// exercised: each call is delayed by a random duration up to Latency, and
+// fails with Unavailable with probability ErrorRate. It is meant for
+// non-production environments only.
+type Chaos struct {
+	latency   time.Duration
+	errorRate float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+
+	// sleep waits for d or until ctx is done; tests replace it
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewChaos returns a Chaos drawing from a generator seeded with seed, so a
+// fixed seed injects the same faults in the same order. errorRate is clamped
+// to [0, 1].
+func NewChaos(latency time.Duration, errorRate float64, seed int64) *Chaos {
+	return &Chaos{
+		latency:   max(latency, 0),
+		errorRate: min(max(errorRate, 0), 1),
+		rand:      rand.New(rand.NewSource(seed)),
+		sleep:     sleepContext,
+	}
+}
+
+// Enabled reports whether the chaos settings inject any faults
+func (c *Chaos) Enabled() bool {
+	return c != nil && (c.latency > 0 || c.errorRate > 0)
+}
+
+// draw picks the delay and outcome for one call
+func (c *Chaos) draw() (delay time.Duration, fail bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.latency > 0 {
+		delay = time.Duration(c.rand.Int63n(int64(c.latency) + 1))
+	}
+	fail = c.errorRate > 0 && c.rand.Float64() < c.errorRate
+	return delay, fail
+}
+
+// inject applies a drawn fault to a call to procedure. HealthCheck is exempt
+// so probes don't flap.
+func (c *Chaos) inject(ctx context.Context, procedure string) error {
+	if strings.HasSuffix(procedure, "/HealthCheck") {
+		return nil
+	}
+	delay, fail := c.draw()
+	if delay > 0 {
+		if err := c.sleep(ctx, delay); err != nil {
+			return connect.NewError(connect.CodeDeadlineExceeded, err)
+		}
+	}
+	if fail {
+		return connect.NewError(connect.CodeUnavailable, errChaos)
+	}
+	return nil
+}
+
+// Interceptor returns a Connect interceptor that injects the configured
+// faults into unary and streaming RPCs
+func (c *Chaos) Interceptor() connect.Interceptor {
+	return &chaosInterceptor{chaos: c}
+}
+
+// chaosInterceptor implements Chaos.Interceptor
+type chaosInterceptor struct {
+	chaos *Chaos
+}
+
+// WrapUnary injects faults before unary RPCs run
+func (i *chaosInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.chaos.inject(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *chaosInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler injects faults before streaming RPCs run
+func (i *chaosInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.chaos.inject(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// sleepContext waits for d, returning early with ctx's error if it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
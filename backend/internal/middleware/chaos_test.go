@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestChaos_ErrorRate(t *testing.T) {
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+
+	const calls = 2000
+	countFailures := func(seed int64) int {
+		handler := NewChaos(0, 0.25, seed).Interceptor().WrapUnary(next)
+		failures := 0
+		for i := 0; i < calls; i++ {
+			_, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+			if err != nil {
+				if connect.CodeOf(err) != connect.CodeUnavailable {
+					t.Fatalf("Expected Unavailable, got %v", err)
+				}
+				failures++
+			}
+		}
+		return failures
+	}
+
+	failures := countFailures(42)
+	if rate := float64(failures) / calls; rate < 0.22 || rate > 0.28 {
+		t.Errorf("Expected about 25%% of calls to fail, got %.3f", rate)
+	}
+	if again := countFailures(42); again != failures {
+		t.Errorf("Expected the same seed to inject the same faults, got %d then %d", failures, again)
+	}
+}
+
+func TestChaos_Latency(t *testing.T) {
+	chaos := NewChaos(100*time.Millisecond, 0, 7)
+	var slept []time.Duration
+	chaos.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := chaos.Interceptor().WrapUnary(next)
+	for i := 0; i < 50; i++ {
+		if _, err := handler(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(slept) == 0 {
+		t.Fatal("Expected calls to be delayed")
+	}
+	for _, d := range slept {
+		if d <= 0 || d > 100*time.Millisecond {
+			t.Errorf("Expected delays within (0, 100ms], got %v", d)
+		}
+	}
+}
+
+func TestChaos_Disabled(t *testing.T) {
+	var nilChaos *Chaos
+	if nilChaos.Enabled() || NewChaos(0, 0, 1).Enabled() {
+		t.Error("Expected chaos without latency or errors to be disabled")
+	}
+	if !NewChaos(0, 0.1, 1).Enabled() || !NewChaos(time.Millisecond, 0, 1).Enabled() {
+		t.Error("Expected chaos with latency or errors to be enabled")
+	}
+
+	stack := NewMiddlewareStack(NewStructuredLogger(LevelError))
+	before := len(stack.GetConnectInterceptors())
+	stack.SetChaos(NewChaos(0, 0, 1))
+	if got := len(stack.GetConnectInterceptors()); got != before {
+		t.Errorf("Expected disabled chaos to add no interceptor, got %d interceptors", got)
+	}
+}
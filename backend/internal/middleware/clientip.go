@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientIP resolves the originating client address for a request. Forwarded
+// headers are only honoured when trustedProxies > 0, because any client can set
+// them: the rightmost trustedProxies hops (including the direct peer) are taken
+// to be our own proxies and the hop before them is the client.
+func ClientIP(r *http.Request, trustedProxies int) string {
+	remote := remoteHost(r.RemoteAddr)
+	if trustedProxies <= 0 {
+		return remote
+	}
+
+	var chain []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	if len(chain) == 0 {
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			return realIP
+		}
+		return remote
+	}
+
+	chain = append(chain, remote)
+	index := len(chain) - 1 - trustedProxies
+	if index < 0 {
+		index = 0
+	}
+	return chain[index]
+}
+
+// ClientFingerprint returns a short, stable hash of the client IP and user agent
+// for correlating requests without logging the raw user agent
+func ClientFingerprint(clientIP, userAgent string) string {
+	sum := sha256.Sum256([]byte(clientIP + "|" + userAgent))
+	return hex.EncodeToString(sum[:8])
+}
+
+// remoteHost strips the port from a RemoteAddr value
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// WithClientIP stores the resolved client address in the context
+func WithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, "client_ip", clientIP)
+}
+
+// GetClientIP returns the client address resolved by LoggingMiddleware, or
+// empty when the request did not pass through it
+func GetClientIP(ctx context.Context) string {
+	if ip, ok := ctx.Value("client_ip").(string); ok {
+		return ip
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		remoteAddr     string
+		headers        map[string]string
+		trustedProxies int
+		expected       string
+	}{
+		{
+			name:       "direct request",
+			remoteAddr: "203.0.113.7:52100",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "forwarded headers ignored without trusted proxies",
+			remoteAddr: "203.0.113.7:52100",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.2"},
+			expected:   "203.0.113.7",
+		},
+		{
+			name:           "single trusted proxy",
+			remoteAddr:     "10.0.0.2:8080",
+			headers:        map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			trustedProxies: 1,
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "spoofed entry before trusted proxy is skipped",
+			remoteAddr:     "10.0.0.2:8080",
+			headers:        map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1"},
+			trustedProxies: 1,
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "two trusted proxies",
+			remoteAddr:     "10.0.0.3:8080",
+			headers:        map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.2"},
+			trustedProxies: 2,
+			expected:       "198.51.100.1",
+		},
+		{
+			name:           "real ip header when no forwarded chain",
+			remoteAddr:     "10.0.0.2:8080",
+			headers:        map[string]string{"X-Real-IP": "198.51.100.9"},
+			trustedProxies: 1,
+			expected:       "198.51.100.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			if got := ClientIP(req, tt.trustedProxies); got != tt.expected {
+				t.Errorf("Expected client IP %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClientFingerprint(t *testing.T) {
+	a := ClientFingerprint("198.51.100.1", "curl/8.0")
+	b := ClientFingerprint("198.51.100.1", "curl/8.0")
+	c := ClientFingerprint("198.51.100.1", "Mozilla/5.0")
+
+	if a != b {
+		t.Error("Expected fingerprint to be stable for the same client")
+	}
+	if a == c {
+		t.Error("Expected fingerprint to differ for a different user agent")
+	}
+	if len(a) != 16 {
+		t.Errorf("Expected 16 hex characters, got %d", len(a))
+	}
+}
+
+func TestLoggingMiddleware_ClientFields(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("direct request", func(t *testing.T) {
+		logger := &mockLogger{}
+		errorHandler := NewErrorHandler(logger)
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "203.0.113.7:52100"
+		req.Header.Set("X-Forwarded-For", "1.2.3.4")
+		errorHandler.LoggingMiddleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+		fields := logger.infoMessages[0].Fields
+		if fields["client_ip"] != "203.0.113.7" {
+			t.Errorf("Expected untrusted forwarded header to be ignored, got %v", fields["client_ip"])
+		}
+		if fields["client_fingerprint"] != ClientFingerprint("203.0.113.7", req.UserAgent()) {
+			t.Error("Expected client fingerprint to be logged")
+		}
+	})
+
+	t.Run("proxied request", func(t *testing.T) {
+		logger := &mockLogger{}
+		errorHandler := NewErrorHandler(logger)
+		errorHandler.SetTrustedProxies(1)
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "10.0.0.2:8080"
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		errorHandler.LoggingMiddleware(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+		for _, call := range logger.infoMessages {
+			if call.Fields["client_ip"] != "198.51.100.1" {
+				t.Errorf("Expected proxied client IP in %q log, got %v", call.Message, call.Fields["client_ip"])
+			}
+		}
+	})
+}
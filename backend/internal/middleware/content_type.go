@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// rpcPathPattern matches Connect/gRPC procedure paths such as /todo.v1.TodoService/ListTasks
+var rpcPathPattern = regexp.MustCompile(`^/[A-Za-z_][\w]*(\.[A-Za-z_][\w]*)+/[A-Za-z_][\w]*$`)
+
+// supportedContentType reports whether Connect can negotiate the given media type
+func supportedContentType(mediaType string) bool {
+	switch {
+	case mediaType == "application/json", mediaType == "application/proto":
+		return true
+	case strings.HasPrefix(mediaType, "application/connect+"):
+		return true
+	case strings.HasPrefix(mediaType, "application/grpc"):
+		return true
+	}
+	return false
+}
+
+// ContentTypeMiddleware rejects RPC POSTs whose Content-Type no Connect protocol
+// accepts with a readable invalid_argument error, instead of Connect's bare 415.
+// Supported types pass through untouched so Connect still does the negotiation.
+// GETs (Connect's query-encoded unary calls) and non-RPC paths are not checked.
+func ContentTypeMiddleware(next http.Handler) http.Handler {
+	errorWriter := connect.NewErrorWriter()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !rpcPathPattern.MatchString(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		contentType := r.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err == nil && supportedContentType(strings.ToLower(mediaType)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		errorWriter.Write(w, r, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf(
+			"unsupported Content-Type %q: use application/json or application/proto (Connect), application/connect+json or application/connect+proto (streaming), or application/grpc",
+			contentType,
+		)))
+	})
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentTypeMiddleware(t *testing.T) {
+	called := false
+	handler := ContentTypeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		wantPassed  bool
+	}{
+		{name: "connect json", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/json", wantPassed: true},
+		{name: "json with charset", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/json; charset=utf-8", wantPassed: true},
+		{name: "connect proto", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/proto", wantPassed: true},
+		{name: "connect streaming", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/connect+json", wantPassed: true},
+		{name: "grpc", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/grpc+proto", wantPassed: true},
+		{name: "grpc-web", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "application/grpc-web+proto", wantPassed: true},
+		{name: "plain text", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks", contentType: "text/plain"},
+		{name: "missing", method: http.MethodPost, path: "/todo.v1.TodoService/ListTasks"},
+		{name: "connect GET", method: http.MethodGet, path: "/todo.v1.TodoService/ListTasks", wantPassed: true},
+		{name: "non-RPC path", method: http.MethodPost, path: "/debug/errors", contentType: "text/plain", wantPassed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("{}"))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if called != tt.wantPassed {
+				t.Fatalf("Expected handler called=%v, got %v", tt.wantPassed, called)
+			}
+			if tt.wantPassed {
+				return
+			}
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", w.Code)
+			}
+			var body struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected a Connect JSON error, got %q", w.Body.String())
+			}
+			if body.Code != "invalid_argument" {
+				t.Errorf("Expected invalid_argument, got %q", body.Code)
+			}
+			if !strings.Contains(body.Message, "application/json") {
+				t.Errorf("Expected the message to name supported types, got %q", body.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// EnvelopeMode controls when successful JSON responses are wrapped as
+// {"data": ..., "meta": {...}} for REST-style clients
+type EnvelopeMode int
+
+const (
+	// EnvelopeOnRequest wraps only when the client asks via ?envelope=true
+	// or an Accept header containing application/vnd.envelope+json
+	EnvelopeOnRequest EnvelopeMode = iota
+	// EnvelopeAlways wraps every eligible response
+	EnvelopeAlways
+	// EnvelopeOff never wraps
+	EnvelopeOff
+)
+
+// envelopeMediaType is the Accept value that opts a request into the envelope
+const envelopeMediaType = "application/vnd.envelope+json"
+
+// ParseEnvelopeMode parses "request" (or empty), "always", or "off"
+func ParseEnvelopeMode(value string) (EnvelopeMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "request":
+		return EnvelopeOnRequest, nil
+	case "always":
+		return EnvelopeAlways, nil
+	case "off":
+		return EnvelopeOff, nil
+	default:
+		return EnvelopeOff, fmt.Errorf("unknown envelope mode %q", value)
+	}
+}
+
+// envelope is the wrapped response body
+type envelope struct {
+	Data json.RawMessage `json:"data"`
+	Meta envelopeMeta    `json:"meta"`
+}
+
+type envelopeMeta struct {
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// EnvelopeMiddleware wraps successful application/json responses in an envelope.
+// gRPC, gRPC-Web, and streaming Connect requests are never touched, and error
+// responses pass through unchanged so Connect clients can still decode them.
+func EnvelopeMiddleware(mode EnvelopeMode, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !wantsEnvelope(mode, r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferingResponseWriter{header: make(http.Header), statusCode: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		body := buffered.body.Bytes()
+		if buffered.statusCode == http.StatusOK && isJSONContentType(buffered.header.Get("Content-Type")) && json.Valid(body) {
+			wrapped, err := json.Marshal(envelope{
+				Data: body,
+				Meta: envelopeMeta{RequestID: getRequestID(r.Context())},
+			})
+			if err == nil {
+				body = wrapped
+				buffered.header.Set("Content-Length", strconv.Itoa(len(body)))
+			}
+		}
+
+		for key, values := range buffered.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(buffered.statusCode)
+		w.Write(body)
+	})
+}
+
+// wantsEnvelope reports whether the request is eligible and opted in
+func wantsEnvelope(mode EnvelopeMode, r *http.Request) bool {
+	if mode == EnvelopeOff {
+		return false
+	}
+	// Only unary Connect JSON requests (POST with JSON body or GET) are eligible
+	if r.Method == http.MethodPost && !isJSONContentType(r.Header.Get("Content-Type")) {
+		return false
+	}
+	if mode == EnvelopeAlways {
+		return true
+	}
+	if enabled, _ := strconv.ParseBool(r.URL.Query().Get("envelope")); enabled {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), envelopeMediaType)
+}
+
+// isJSONContentType matches application/json with optional parameters
+func isJSONContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "application/json")
+}
+
+// bufferingResponseWriter holds the full response so it can be rewritten
+type bufferingResponseWriter struct {
+	header     http.Header
+	body       bytes.Buffer
+	statusCode int
+	wroteCode  bool
+}
+
+func (b *bufferingResponseWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferingResponseWriter) WriteHeader(code int) {
+	if b.wroteCode {
+		return
+	}
+	b.statusCode = code
+	b.wroteCode = true
+}
+
+func (b *bufferingResponseWriter) Write(p []byte) (int, error) {
+	b.wroteCode = true
+	return b.body.Write(p)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func jsonHandler(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+func TestEnvelopeMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        EnvelopeMode
+		target      string
+		contentType string
+		accept      string
+		status      int
+		wrapped     bool
+	}{
+		{name: "not requested", mode: EnvelopeOnRequest, target: "/rpc", contentType: "application/json", status: 200},
+		{name: "query param", mode: EnvelopeOnRequest, target: "/rpc?envelope=true", contentType: "application/json", status: 200, wrapped: true},
+		{name: "accept header", mode: EnvelopeOnRequest, target: "/rpc", contentType: "application/json", accept: envelopeMediaType, status: 200, wrapped: true},
+		{name: "always", mode: EnvelopeAlways, target: "/rpc", contentType: "application/json", status: 200, wrapped: true},
+		{name: "off ignores opt-in", mode: EnvelopeOff, target: "/rpc?envelope=true", contentType: "application/json", status: 200},
+		{name: "grpc untouched", mode: EnvelopeAlways, target: "/rpc", contentType: "application/grpc", status: 200},
+		{name: "errors untouched", mode: EnvelopeAlways, target: "/rpc", contentType: "application/json", status: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const body = `{"task":{"id":"task-1"}}`
+			handler := withTestRequestID(EnvelopeMiddleware(tt.mode, jsonHandler(tt.status, body)), "req-123")
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader("{}"))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if !tt.wrapped {
+				if w.Body.String() != body {
+					t.Errorf("Expected unwrapped body, got %s", w.Body.String())
+				}
+				return
+			}
+
+			var got struct {
+				Data map[string]interface{} `json:"data"`
+				Meta map[string]interface{} `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to parse envelope: %v", err)
+			}
+			if _, ok := got.Data["task"]; !ok {
+				t.Errorf("Expected original body under data, got %s", w.Body.String())
+			}
+			if got.Meta["request_id"] != "req-123" {
+				t.Errorf("Expected request_id in meta, got %v", got.Meta)
+			}
+		})
+	}
+}
+
+// withTestRequestID injects a fixed request ID the way RequestIDMiddleware would
+func withTestRequestID(next http.Handler, requestID string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+	})
+}
+
+func TestParseEnvelopeMode(t *testing.T) {
+	for value, expected := range map[string]EnvelopeMode{"": EnvelopeOnRequest, "request": EnvelopeOnRequest, "ALWAYS": EnvelopeAlways, "off": EnvelopeOff} {
+		mode, err := ParseEnvelopeMode(value)
+		if err != nil || mode != expected {
+			t.Errorf("ParseEnvelopeMode(%q) = %v, %v; want %v", value, mode, err, expected)
+		}
+	}
+	if _, err := ParseEnvelopeMode("sometimes"); err == nil {
+		t.Error("Expected error for unknown mode")
+	}
+}
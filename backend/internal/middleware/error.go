@@ -1,14 +1,22 @@
 package middleware
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"regexp"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/go-sql-driver/mysql"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 )
 
 // ErrorResponse represents a standardized error response
@@ -22,9 +30,25 @@ type ErrorResponse struct {
 
 // ErrorHandler provides centralized error handling and logging
 type ErrorHandler struct {
-	logger Logger
+	logger         Logger
+	latencyBudgets LatencyBudgets
+	trustedProxies int
+	logExclusions  map[string]bool
+	recoverMode    RecoverMode
+	requestLogMode RequestLogMode
 }
 
+// RecoverMode selects what RecoveryMiddleware does with a panic after logging it
+type RecoverMode string
+
+const (
+	// RecoverModeRecover answers the request with a 500 (the default)
+	RecoverModeRecover RecoverMode = "recover"
+	// RecoverModePropagate re-panics, so a test exercising the handler fails
+	// loudly instead of seeing a 500
+	RecoverModePropagate RecoverMode = "propagate"
+)
+
 // Logger interface for structured logging
 type Logger interface {
 	Info(ctx context.Context, msg string, fields map[string]interface{})
@@ -52,7 +76,29 @@ func NewErrorHandler(logger Logger) *ErrorHandler {
 	if logger == nil {
 		logger = &DefaultLogger{}
 	}
-	return &ErrorHandler{logger: logger}
+	return &ErrorHandler{
+		logger:         logger,
+		latencyBudgets: DefaultLatencyBudgets(),
+		recoverMode:    RecoverModeRecover,
+		requestLogMode: RequestLogDetailed,
+	}
+}
+
+// SetRecoverMode sets whether RecoveryMiddleware swallows panics or re-panics
+// after logging them
+func (eh *ErrorHandler) SetRecoverMode(mode RecoverMode) {
+	eh.recoverMode = mode
+}
+
+// SetTrustedProxies sets how many reverse proxies in front of the service may be
+// trusted to append X-Forwarded-For; zero ignores forwarded headers entirely
+func (eh *ErrorHandler) SetTrustedProxies(n int) {
+	eh.trustedProxies = n
+}
+
+// SetLatencyBudgets replaces the per-procedure latency budgets used for SLO warnings
+func (eh *ErrorHandler) SetLatencyBudgets(budgets LatencyBudgets) {
+	eh.latencyBudgets = budgets
 }
 
 // RecoveryMiddleware provides panic recovery and error handling
@@ -61,12 +107,22 @@ func (eh *ErrorHandler) RecoveryMiddleware(next http.Handler) http.Handler {
 		defer func() {
 			if err := recover(); err != nil {
 				// Log the panic with stack trace
-				eh.logger.Error(r.Context(), "Panic recovered", fmt.Errorf("%v", err), map[string]interface{}{
-					"method":     r.Method,
-					"path":       r.URL.Path,
-					"user_agent": r.UserAgent(),
-					"stack":      string(debug.Stack()),
+				message := "Panic recovered"
+				if eh.recoverMode == RecoverModePropagate {
+					message = "Panic propagated"
+				}
+				stack := debug.Stack()
+				eh.logger.Error(r.Context(), message, fmt.Errorf("%v", err), map[string]interface{}{
+					"method":      r.Method,
+					"path":        r.URL.Path,
+					"user_agent":  r.UserAgent(),
+					"panic_type":  classifyPanic(err),
+					"panic_frame": panicFrame(stack),
+					"stack":       string(stack),
 				})
+				if eh.recoverMode == RecoverModePropagate {
+					panic(err)
+				}
 
 				// Return internal server error
 				w.Header().Set("Content-Type", "application/json")
@@ -95,89 +151,210 @@ func (eh *ErrorHandler) RecoveryMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// LoggingMiddleware logs all HTTP requests and responses
+// withRequestIDField adds the request ID from ctx to log fields so RPC logs
+// correlate with HTTP logs regardless of the Logger implementation
+func withRequestIDField(ctx context.Context, fields map[string]interface{}) map[string]interface{} {
+	if requestID := getRequestID(ctx); requestID != "" {
+		fields["request_id"] = requestID
+	}
+	return fields
+}
+
+// LoggingMiddleware logs all HTTP requests and responses, or in consolidated
+// mode one completion line per request
 func (eh *ErrorHandler) LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		
-		// Create a response writer wrapper to capture status code
+		// Create a response writer wrapper to capture status code and body size
 		wrapped := &responseWriter{ResponseWriter: w, statusCode: 200}
 		
+		clientIP := ClientIP(r, eh.trustedProxies)
+		fingerprint := ClientFingerprint(clientIP, r.UserAgent())
+		r = r.WithContext(WithClientIP(r.Context(), clientIP))
+		excluded := eh.isLogExcluded(r.URL.Path)
+
+		summary := &requestSummary{}
+		if eh.consolidated() {
+			r = r.WithContext(withRequestSummary(r.Context(), summary))
+		}
+
 		// Log request
-		eh.logger.Info(r.Context(), "HTTP request", map[string]interface{}{
-			"method":      r.Method,
-			"path":        r.URL.Path,
-			"query":       r.URL.RawQuery,
-			"user_agent":  r.UserAgent(),
-			"remote_addr": r.RemoteAddr,
-		})
+		if !excluded {
+			eh.logDetail(r.Context(), "HTTP request", map[string]interface{}{
+				"method":             r.Method,
+				"path":               r.URL.Path,
+				"query":              r.URL.RawQuery,
+				"user_agent":         r.UserAgent(),
+				"remote_addr":        r.RemoteAddr,
+				"client_ip":          clientIP,
+				"client_fingerprint": fingerprint,
+			})
+		}
 
 		next.ServeHTTP(wrapped, r)
 
 		// Log response
 		duration := time.Since(start)
 		fields := map[string]interface{}{
-			"method":       r.Method,
-			"path":         r.URL.Path,
-			"status_code":  wrapped.statusCode,
-			"duration_ms":  duration.Milliseconds(),
-			"content_type": wrapped.Header().Get("Content-Type"),
+			"method":             r.Method,
+			"path":               r.URL.Path,
+			"status_code":        wrapped.statusCode,
+			"duration_ms":        duration.Milliseconds(),
+			"response_bytes":     wrapped.bytesWritten,
+			"content_type":       wrapped.Header().Get("Content-Type"),
+			"client_ip":          clientIP,
+			"client_fingerprint": fingerprint,
+		}
+
+		if eh.consolidated() {
+			if !excluded {
+				eh.logDetail(r.Context(), "HTTP response", fields)
+			}
+			eh.logCompletion(r, wrapped, summary, duration, excluded)
+			return
 		}
 
 		if wrapped.statusCode >= 400 {
 			eh.logger.Error(r.Context(), "HTTP error response", nil, fields)
-		} else {
+		} else if !excluded {
 			eh.logger.Info(r.Context(), "HTTP response", fields)
 		}
 	})
 }
 
-// ConnectErrorInterceptor provides error handling for Connect RPC calls
-func (eh *ErrorHandler) ConnectErrorInterceptor() connect.UnaryInterceptorFunc {
-	return func(next connect.UnaryFunc) connect.UnaryFunc {
-		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			// Log incoming RPC request
-			eh.logger.Info(ctx, "RPC request", map[string]interface{}{
-				"procedure": req.Spec().Procedure,
-				"method":    req.HTTPMethod(),
-			})
+// logCompletion writes the single consolidated log line for a request: at
+// info on success, or at error for a 4xx/5xx status even on excluded paths
+func (eh *ErrorHandler) logCompletion(r *http.Request, wrapped *responseWriter, summary *requestSummary, duration time.Duration, excluded bool) {
+	if wrapped.statusCode < 400 && excluded {
+		return
+	}
 
-			start := time.Now()
-			resp, err := next(ctx, req)
-			duration := time.Since(start)
-
-			if err != nil {
-				// Log RPC error
-				if connect.CodeOf(err) != connect.CodeUnknown {
-					eh.logger.Error(ctx, "RPC error", err, map[string]interface{}{
-						"procedure":   req.Spec().Procedure,
-						"code":        connect.CodeOf(err).String(),
-						"duration_ms": duration.Milliseconds(),
-					})
-				} else {
-					eh.logger.Error(ctx, "RPC unexpected error", err, map[string]interface{}{
-						"procedure":   req.Spec().Procedure,
-						"duration_ms": duration.Milliseconds(),
-					})
-				}
-				return nil, err
-			}
+	fields := withRequestIDField(r.Context(), map[string]interface{}{
+		"method":         r.Method,
+		"path":           r.URL.Path,
+		"status_code":    wrapped.statusCode,
+		"duration_ms":    duration.Milliseconds(),
+		"response_bytes": wrapped.bytesWritten,
+	})
+	if summary.procedure != "" {
+		fields["procedure"] = summary.procedure
+		fields["code"] = summary.code
+	}
+
+	if wrapped.statusCode >= 400 {
+		eh.logger.Error(r.Context(), "Request completed", nil, fields)
+	} else {
+		eh.logger.Info(r.Context(), "Request completed", fields)
+	}
+}
+
+// ConnectErrorInterceptor provides error handling for Connect RPC calls. Unary
+// RPCs are logged when they return, streaming RPCs when the stream closes.
+func (eh *ErrorHandler) ConnectErrorInterceptor() connect.Interceptor {
+	return &rpcLogInterceptor{eh: eh}
+}
+
+// rpcLogInterceptor implements ConnectErrorInterceptor
+type rpcLogInterceptor struct {
+	eh *ErrorHandler
+}
+
+// WrapUnary logs unary RPCs
+func (i *rpcLogInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		finish := i.eh.startRPC(ctx, req.Spec().Procedure, req.HTTPMethod())
+		resp, err := next(ctx, req)
+		finish(err)
+		if err != nil {
+			return nil, err
+		}
 
-			// Log successful RPC response
-			eh.logger.Info(ctx, "RPC response", map[string]interface{}{
-				"procedure":   req.Spec().Procedure,
+		// The HTTP middleware already sets the X-Request-ID header; a trailer
+		// also reaches gRPC clients, which don't see that header
+		if requestID := getRequestID(ctx); requestID != "" {
+			resp.Trailer().Set("X-Request-ID", requestID)
+		}
+
+		return resp, nil
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *rpcLogInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler logs streaming RPCs, finishing when the stream closes
+func (i *rpcLogInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		// Streams are always POSTs
+		finish := i.eh.startRPC(ctx, conn.Spec().Procedure, http.MethodPost)
+
+		// Trailers are written when the handler returns, so the request ID
+		// can be set up front
+		if requestID := getRequestID(ctx); requestID != "" {
+			conn.ResponseTrailer().Set("X-Request-ID", requestID)
+		}
+
+		err := next(ctx, conn)
+		finish(err)
+		return err
+	}
+}
+
+// startRPC logs an incoming RPC and returns the func to call with its result,
+// which logs the outcome, checks the latency budget and records the code for
+// the completion log
+func (eh *ErrorHandler) startRPC(ctx context.Context, procedure, method string) func(err error) {
+	excluded := eh.isLogExcluded(procedure)
+
+	// Log incoming RPC request
+	if !excluded {
+		eh.logDetail(ctx, "RPC request", withRequestIDField(ctx, map[string]interface{}{
+			"procedure": procedure,
+			"method":    method,
+		}))
+	}
+
+	start := time.Now()
+	return func(err error) {
+		duration := time.Since(start)
+		eh.checkLatencyBudget(ctx, procedure, duration)
+		recordRPCOutcome(ctx, procedure, rpcCode(err))
+
+		if err != nil {
+			// Log RPC error
+			message := "RPC error"
+			if connect.CodeOf(err) == connect.CodeUnknown {
+				message = "RPC unexpected error"
+			}
+			eh.logger.Error(ctx, message, err, withRequestIDField(ctx, map[string]interface{}{
+				"procedure":   procedure,
+				"code":        connect.CodeOf(err).String(),
 				"duration_ms": duration.Milliseconds(),
-			})
+			}))
+			return
+		}
 
-			return resp, nil
+		// Log successful RPC response; "ok" matches the code dimension of error logs
+		if !excluded {
+			eh.logDetail(ctx, "RPC response", withRequestIDField(ctx, map[string]interface{}{
+				"procedure":   procedure,
+				"code":        "ok",
+				"duration_ms": duration.Milliseconds(),
+			}))
 		}
 	}
 }
 
-// responseWriter wraps http.ResponseWriter to capture status code
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// count the body bytes written. statusCode starts at 200, which is what the
+// client receives when the handler writes without calling WriteHeader.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
+	statusCode   int
+	bytesWritten int64
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -185,6 +362,34 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytesWritten += int64(n)
+	return n, err
+}
+
+// Flush sends buffered data to the client, which streaming RPCs rely on, when
+// the underlying writer supports it
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection to the handler when the underlying writer
+// supports it; the status code and byte count stop being tracked
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := rw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("hijack: %w", http.ErrNotSupported)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // ValidationErrorHandler converts validation errors to appropriate Connect errors
 func (eh *ErrorHandler) HandleValidationError(err error) error {
 	if err == nil {
@@ -196,7 +401,25 @@ func (eh *ErrorHandler) HandleValidationError(err error) error {
 		"error": err.Error(),
 	})
 
-	return connect.NewError(connect.CodeInvalidArgument, err)
+	connectErr := connect.NewError(connect.CodeInvalidArgument, err)
+
+	// Pass a suggested replacement value on to the client as an error detail
+	var validationErr validator.ValidationError
+	if errors.As(err, &validationErr) && validationErr.Suggestion != "" {
+		detail, detailErr := connect.NewErrorDetail(&errdetails.ErrorInfo{
+			Reason: "VALUE_TOO_LONG",
+			Domain: "todo.v1",
+			Metadata: map[string]string{
+				"field":      validationErr.Field,
+				"suggestion": validationErr.Suggestion,
+			},
+		})
+		if detailErr == nil {
+			connectErr.AddDetail(detail)
+		}
+	}
+
+	return connectErr
 }
 
 // RepositoryErrorHandler converts repository errors to appropriate Connect errors
@@ -205,6 +428,45 @@ func (eh *ErrorHandler) HandleRepositoryError(err error) error {
 		return nil
 	}
 
+	// A missing table fails every request until migrations run, so it gets
+	// its own log message and a code that says retrying will not help
+	if isMissingTable(err) {
+		eh.logger.Error(context.Background(), "Required table is missing; database migrations need to run", err, map[string]interface{}{
+			"error":    err.Error(),
+			"table":    missingTableName(err),
+			"category": "schema",
+		})
+		return connect.NewError(connect.CodeFailedPrecondition, missingTableError(err))
+	}
+
+	// A value longer than its column is the client's to fix, so it stays out
+	// of the 5xx bucket; reaching here means a validator limit exceeds the schema
+	if tooLong := valueTooLong(err); tooLong != nil {
+		eh.logger.Warn(context.Background(), "Value too long for its column; validator limits may exceed the schema", map[string]interface{}{
+			"error":    err.Error(),
+			"column":   tooLong.Column,
+			"category": "schema",
+		})
+		return connect.NewError(connect.CodeInvalidArgument, tooLong)
+	}
+
+	// A duplicate key is a conflict with existing data, not a server fault
+	if isDuplicateEntry(err) {
+		eh.logger.Warn(context.Background(), "Duplicate entry", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	}
+
+	// InnoDB rolled back a transaction to break a deadlock; the whole call can
+	// be retried
+	if isDeadlock(err) {
+		eh.logger.Warn(context.Background(), "Transaction rolled back after a deadlock", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return connect.NewError(connect.CodeAborted, err)
+	}
+
 	// Log repository error
 	eh.logger.Error(context.Background(), "Repository error", err, map[string]interface{}{
 		"error": err.Error(),
@@ -226,6 +488,83 @@ func (eh *ErrorHandler) HandleRepositoryError(err error) error {
 	return connect.NewError(connect.CodeInternal, err)
 }
 
+// missingTableError is returned to clients in place of the driver's error,
+// naming the table when the driver error does
+func missingTableError(err error) error {
+	if table := missingTableName(err); table != "" {
+		return fmt.Errorf("the %s table does not exist; run the database migrations and retry", table)
+	}
+	return errors.New("a required table does not exist; run the database migrations and retry")
+}
+
+// missingTablePattern captures the table in MySQL's "Table 'todos.tasks'
+// doesn't exist" and SQLite's "no such table: tasks", without the database
+var missingTablePattern = regexp.MustCompile(`Table '(?:[^'.]*\.)?([^'.]+)' doesn't exist|no such table: (?:\w+\.)?(\w+)`)
+
+// missingTableName returns the table a missing-table error names, or "" when
+// it names none
+func missingTableName(err error) string {
+	match := missingTablePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2]
+}
+
+// isMissingTable reports whether err is MySQL's "table doesn't exist" (error
+// 1146) or SQLite's "no such table"
+func isMissingTable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1146
+	}
+	return strings.Contains(err.Error(), "no such table")
+}
+
+// isDuplicateEntry reports whether err is MySQL's duplicate-entry error (error
+// 1062) for any unique key. Its "Duplicate entry" message is capitalized, so
+// the case-sensitive message checks below would miss it.
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+// isDeadlock reports whether err is MySQL's deadlock error (error 1213)
+func isDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1213
+}
+
+// ValueTooLongError reports a value that does not fit its database column,
+// as MySQL reports with error 1406 (data too long) in strict mode
+type ValueTooLongError struct {
+	Column string // column named by the database, or empty when unknown
+}
+
+func (e *ValueTooLongError) Error() string {
+	if e.Column == "" {
+		return "value is too long to store"
+	}
+	return e.Column + " is too long to store"
+}
+
+// valueTooLong returns a ValueTooLongError when err is MySQL's error 1406,
+// naming the column from its "Data too long for column 'title'" message, or
+// nil otherwise
+func valueTooLong(err error) *ValueTooLongError {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1406 {
+		return nil
+	}
+	tooLong := &ValueTooLongError{}
+	if _, rest, ok := strings.Cut(mysqlErr.Message, "'"); ok {
+		if column, _, ok := strings.Cut(rest, "'"); ok {
+			tooLong.Column = column
+		}
+	}
+	return tooLong
+}
+
 // contains checks if a string contains a substring (case-insensitive)
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && 
@@ -3,12 +3,17 @@ package middleware
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"connectrpc.com/connect"
+	"github.com/go-sql-driver/mysql"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // mockLogger implements Logger interface for testing
@@ -120,6 +125,35 @@ func TestRecoveryMiddleware(t *testing.T) {
 			t.Error("Expected error response in body")
 		}
 	})
+
+	t.Run("propagate mode", func(t *testing.T) {
+		logger.reset()
+		propagating := NewErrorHandler(logger)
+		propagating.SetRecoverMode(RecoverModePropagate)
+
+		handler := propagating.RecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("test panic")
+		}))
+
+		req := httptest.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+
+		func() {
+			defer func() {
+				if got := recover(); got != "test panic" {
+					t.Errorf("Expected the original panic to propagate, got %v", got)
+				}
+			}()
+			handler.ServeHTTP(w, req)
+		}()
+
+		if len(logger.errorMessages) != 1 || logger.errorMessages[0].Message != "Panic propagated" {
+			t.Errorf("Expected the panic to be logged before propagating, got %v", logger.errorMessages)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected no error response to be written, got %q", w.Body.String())
+		}
+	})
 }
 
 func TestLoggingMiddleware(t *testing.T) {
@@ -167,6 +201,58 @@ func TestLoggingMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("counts response bytes", func(t *testing.T) {
+		logger.reset()
+
+		body := strings.Repeat("x", 1500)
+		handler := errorHandler.LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// No WriteHeader: the implicit 200 must still be logged
+			w.Write([]byte(body[:1000]))
+			w.Write([]byte(body[1000:]))
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+		if w.Body.String() != body {
+			t.Errorf("Expected the body to reach the client unchanged, got %d bytes", w.Body.Len())
+		}
+		responseLog := logger.infoMessages[1]
+		if responseLog.Fields["response_bytes"] != int64(len(body)) {
+			t.Errorf("Expected response_bytes %d, got %v", len(body), responseLog.Fields["response_bytes"])
+		}
+		if responseLog.Fields["status_code"] != 200 {
+			t.Errorf("Expected status code 200 without WriteHeader, got %v", responseLog.Fields["status_code"])
+		}
+	})
+
+	t.Run("flushes streaming responses", func(t *testing.T) {
+		logger.reset()
+
+		flushed := false
+		w := httptest.NewRecorder()
+		handler := errorHandler.LoggingMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			flusher, ok := rw.(http.Flusher)
+			if !ok {
+				t.Fatal("Expected the wrapped writer to implement http.Flusher")
+			}
+			rw.Write([]byte("chunk"))
+			flusher.Flush()
+			flushed = w.Flushed
+
+			// A recorder can't be hijacked, so the wrapper must report that
+			if _, _, err := rw.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+				t.Errorf("Expected hijacking a recorder to be unsupported, got %v", err)
+			}
+		}))
+
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+		if !flushed {
+			t.Error("Expected Flush to reach the underlying writer before the handler returned")
+		}
+	})
+
 	t.Run("error request", func(t *testing.T) {
 		logger.reset()
 		
@@ -232,6 +318,40 @@ func TestValidationErrorHandler(t *testing.T) {
 			t.Error("Expected validation error message")
 		}
 	})
+
+	t.Run("suggestion detail", func(t *testing.T) {
+		result := errorHandler.HandleValidationError(validator.ValidationError{
+			Field:      "title",
+			Message:    "title cannot exceed 255 characters",
+			Suggestion: "Buy milk",
+		})
+
+		var connectErr *connect.Error
+		if !errors.As(result, &connectErr) {
+			t.Fatalf("Expected connect error, got %v", result)
+		}
+		if len(connectErr.Details()) != 1 {
+			t.Fatalf("Expected 1 error detail, got %d", len(connectErr.Details()))
+		}
+		value, err := connectErr.Details()[0].Value()
+		if err != nil {
+			t.Fatalf("Failed to decode error detail: %v", err)
+		}
+		info, ok := value.(*errdetails.ErrorInfo)
+		if !ok {
+			t.Fatalf("Expected ErrorInfo detail, got %T", value)
+		}
+		if info.Metadata["field"] != "title" || info.Metadata["suggestion"] != "Buy milk" {
+			t.Errorf("Unexpected detail metadata: %v", info.Metadata)
+		}
+	})
+
+	t.Run("no suggestion", func(t *testing.T) {
+		result := errorHandler.HandleValidationError(validator.ValidationError{Field: "title", Message: "title is required"})
+		if details := result.(*connect.Error).Details(); len(details) != 0 {
+			t.Errorf("Expected no error details, got %d", len(details))
+		}
+	})
 }
 
 func TestRepositoryErrorHandler(t *testing.T) {
@@ -248,6 +368,7 @@ func TestRepositoryErrorHandler(t *testing.T) {
 		{"duplicate error", "duplicate key constraint", connect.CodeAlreadyExists},
 		{"timeout error", "connection timeout", connect.CodeUnavailable},
 		{"generic error", "some database error", connect.CodeInternal},
+		{"missing table", "no such table: tasks", connect.CodeFailedPrecondition},
 	}
 
 	for _, tc := range testCases {
@@ -284,6 +405,55 @@ func TestRepositoryErrorHandler(t *testing.T) {
 	}
 }
 
+func TestRepositoryErrorHandler_MySQLErrors(t *testing.T) {
+	errorHandler := NewErrorHandler(&mockLogger{})
+
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode connect.Code
+	}{
+		{"duplicate entry", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'default-Buy milk' for key 'tasks.uniq_tenant_title'"}, connect.CodeAlreadyExists},
+		{"deadlock", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"}, connect.CodeAborted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := errorHandler.HandleRepositoryError(fmt.Errorf("failed to update task: %w", tc.err))
+			if code := connect.CodeOf(result); code != tc.expectedCode {
+				t.Errorf("Expected %v code, got %v", tc.expectedCode, code)
+			}
+		})
+	}
+}
+
+func TestRepositoryErrorHandler_MissingTable(t *testing.T) {
+	errorHandler := NewErrorHandler(&mockLogger{})
+
+	testCases := []struct {
+		name    string
+		err     error
+		message string
+	}{
+		{"mysql tasks", &mysql.MySQLError{Number: 1146, Message: "Table 'todos.tasks' doesn't exist"}, "the tasks table does not exist"},
+		{"mysql deleted_tasks", &mysql.MySQLError{Number: 1146, Message: "Table 'todos.deleted_tasks' doesn't exist"}, "the deleted_tasks table does not exist"},
+		{"sqlite list_versions", errors.New("no such table: list_versions"), "the list_versions table does not exist"},
+		{"unnamed table", &mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}, "a required table does not exist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := errorHandler.HandleRepositoryError(fmt.Errorf("failed to get task: %w", tc.err))
+			if code := connect.CodeOf(result); code != connect.CodeFailedPrecondition {
+				t.Errorf("Expected FailedPrecondition, got %v", code)
+			}
+			if !strings.Contains(result.Error(), tc.message) || !strings.Contains(result.Error(), "run the database migrations") {
+				t.Errorf("Expected %q pointing at migrations, got %q", tc.message, result.Error())
+			}
+		})
+	}
+}
+
 func TestContains(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -400,4 +570,154 @@ func TestMiddlewareIntegration(t *testing.T) {
 			t.Error("Expected panic recovery log message")
 		}
 	})
-}
\ No newline at end of file
+}
+func TestConnectInterceptor_RequestIDCorrelation(t *testing.T) {
+	logger := &mockLogger{}
+	stack := NewMiddlewareStack(logger)
+
+	ping := connect.NewUnaryHandler(
+		"/test.v1.TestService/Ping",
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(stack.GetConnectInterceptors()...),
+	)
+	handler := stack.WrapHandler(ping)
+
+	req := httptest.NewRequest(http.MethodPost, "/test.v1.TestService/Ping", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	requestID := w.Header().Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("Expected X-Request-ID header")
+	}
+
+	rpcLogs := 0
+	for _, call := range logger.infoMessages {
+		if call.Message != "RPC request" && call.Message != "RPC response" {
+			continue
+		}
+		rpcLogs++
+		if call.Fields["request_id"] != requestID {
+			t.Errorf("%s: expected request_id %q, got %v", call.Message, requestID, call.Fields["request_id"])
+		}
+	}
+	if rpcLogs != 2 {
+		t.Errorf("Expected RPC request and response logs, got %d", rpcLogs)
+	}
+
+	// Connect unary responses carry trailers as Trailer- prefixed headers
+	if got := w.Header().Get("Trailer-X-Request-Id"); got != requestID {
+		t.Errorf("Expected request ID trailer %q, got %q", requestID, got)
+	}
+}
+
+func TestConnectErrorInterceptor_CodeField(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "success", wantCode: "ok"},
+		{name: "connect error", err: connect.NewError(connect.CodeNotFound, errors.New("task not found")), wantCode: "not_found"},
+		{name: "plain error", err: errors.New("boom"), wantCode: "unknown"},
+	}
+
+	for _, tt := range tests {
+		calls := map[string]func(interceptor connect.Interceptor){
+			"unary": func(interceptor connect.Interceptor) {
+				call := interceptor.WrapUnary(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return connect.NewResponse(&emptypb.Empty{}), nil
+				})
+				call(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+			},
+			"streaming": func(interceptor connect.Interceptor) {
+				call := interceptor.WrapStreamingHandler(func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+					return tt.err
+				})
+				call(context.Background(), &streamingConn{procedure: "/todo.v1.TodoService/ListTasksStream"})
+			},
+		}
+		for kind, call := range calls {
+			t.Run(tt.name+"/"+kind, func(t *testing.T) {
+				logger := &mockLogger{}
+				call(NewErrorHandler(logger).ConnectErrorInterceptor())
+
+				logs := logger.errorMessages
+				if tt.err == nil {
+					logs = logger.infoMessages
+				}
+				if len(logs) == 0 {
+					t.Fatal("Expected the RPC to be logged")
+				}
+				last := logs[len(logs)-1]
+				if last.Fields["code"] != tt.wantCode {
+					t.Errorf("Expected code %q on %q, got %v", tt.wantCode, last.Message, last.Fields["code"])
+				}
+			})
+		}
+	}
+}
+
+func TestConnectInterceptor_Streaming(t *testing.T) {
+	logger := &mockLogger{}
+	stack := NewMiddlewareStack(logger)
+
+	mux := http.NewServeMux()
+	mux.Handle("/test.v1.TestService/Watch", connect.NewServerStreamHandler(
+		"/test.v1.TestService/Watch",
+		func(ctx context.Context, req *connect.Request[emptypb.Empty], stream *connect.ServerStream[emptypb.Empty]) error {
+			return stream.Send(&emptypb.Empty{})
+		},
+		connect.WithInterceptors(stack.GetConnectInterceptors()...),
+	))
+	server := httptest.NewServer(stack.WrapHandler(mux))
+	defer server.Close()
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+"/test.v1.TestService/Watch")
+	stream, err := client.CallServerStream(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	requestID := stream.ResponseHeader().Get("X-Request-ID")
+	if requestID == "" {
+		t.Fatal("Expected X-Request-ID header")
+	}
+	if got := stream.ResponseTrailer().Get("X-Request-ID"); got != requestID {
+		t.Errorf("Expected request ID trailer %q, got %q", requestID, got)
+	}
+	stream.Close()
+
+	var response *LogCall
+	for i, call := range logger.infoMessages {
+		if call.Message == "RPC response" {
+			response = &logger.infoMessages[i]
+		}
+	}
+	if response == nil {
+		t.Fatalf("Expected an RPC response log when the stream closed, got %v", logger.infoMessages)
+	}
+	for key, want := range map[string]interface{}{
+		"procedure":  "/test.v1.TestService/Watch",
+		"code":       "ok",
+		"request_id": requestID,
+	} {
+		if response.Fields[key] != want {
+			t.Errorf("Expected %s %v, got %v", key, want, response.Fields[key])
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import "strings"
+
+// DefaultLogExclusions are the probe endpoints whose successful requests are
+// not logged unless configured otherwise
+var DefaultLogExclusions = []string{"/livez", "/readyz", "/metrics", "HealthCheck", "/grpc.health.v1.Health/Check"}
+
+// ParseLogExclusions parses a comma-separated list such as "/readyz,HealthCheck"
+func ParseLogExclusions(spec string) []string {
+	exclusions := []string{}
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			exclusions = append(exclusions, entry)
+		}
+	}
+	return exclusions
+}
+
+// SetLogExclusions suppresses request/response info logs for the given entries.
+// An entry is an HTTP path or full procedure ("/readyz",
+// "/todo.v1.TodoService/HealthCheck") or a bare method name ("HealthCheck").
+// Errors on excluded paths are still logged.
+func (eh *ErrorHandler) SetLogExclusions(entries []string) {
+	eh.logExclusions = make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		eh.logExclusions[entry] = true
+	}
+}
+
+// isLogExcluded reports whether successful requests to path should not be logged
+func (eh *ErrorHandler) isLogExcluded(path string) bool {
+	if len(eh.logExclusions) == 0 {
+		return false
+	}
+	if eh.logExclusions[path] {
+		return true
+	}
+	method := path[strings.LastIndex(path, "/")+1:]
+	return eh.logExclusions[method]
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestParseLogExclusions(t *testing.T) {
+	got := ParseLogExclusions(" /readyz, HealthCheck,,")
+	want := []string{"/readyz", "HealthCheck"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := ParseLogExclusions(""); len(got) != 0 {
+		t.Errorf("Expected no exclusions, got %v", got)
+	}
+}
+
+func TestLoggingMiddleware_Exclusions(t *testing.T) {
+	logger := &mockLogger{}
+	eh := NewErrorHandler(logger)
+	eh.SetLogExclusions([]string{"/readyz"})
+
+	status := http.StatusOK
+	handler := eh.LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	t.Run("excluded path is not logged", func(t *testing.T) {
+		logger.reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if len(logger.infoMessages) != 0 || len(logger.errorMessages) != 0 {
+			t.Errorf("Expected no logs, got %d info and %d error", len(logger.infoMessages), len(logger.errorMessages))
+		}
+	})
+
+	t.Run("excluded path still logs server errors", func(t *testing.T) {
+		logger.reset()
+		status = http.StatusInternalServerError
+		defer func() { status = http.StatusOK }()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if len(logger.infoMessages) != 0 {
+			t.Errorf("Expected no info logs, got %d", len(logger.infoMessages))
+		}
+		if len(logger.errorMessages) != 1 {
+			t.Errorf("Expected the 500 to be logged, got %d error logs", len(logger.errorMessages))
+		}
+	})
+
+	t.Run("other paths are logged", func(t *testing.T) {
+		logger.reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/todo.v1.TodoService/ListTasks", nil))
+
+		if len(logger.infoMessages) != 2 {
+			t.Errorf("Expected request and response logs, got %d", len(logger.infoMessages))
+		}
+	})
+}
+
+func TestConnectInterceptor_LogExclusions(t *testing.T) {
+	logger := &mockLogger{}
+	eh := NewErrorHandler(logger)
+	eh.SetLogExclusions([]string{"HealthCheck"})
+
+	var healthErr error
+	health := connect.NewUnaryHandler(
+		"/todo.v1.TodoService/HealthCheck",
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			if healthErr != nil {
+				return nil, healthErr
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(eh.ConnectErrorInterceptor()),
+	)
+
+	call := func() {
+		req := httptest.NewRequest(http.MethodPost, "/todo.v1.TodoService/HealthCheck", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		health.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	call()
+	if len(logger.infoMessages) != 0 {
+		t.Errorf("Expected no RPC info logs for an excluded method, got %d", len(logger.infoMessages))
+	}
+
+	logger.reset()
+	healthErr = connect.NewError(connect.CodeUnavailable, errors.New("database down"))
+	call()
+	if len(logger.errorMessages) != 1 {
+		t.Errorf("Expected the RPC error to be logged, got %d error logs", len(logger.errorMessages))
+	}
+}
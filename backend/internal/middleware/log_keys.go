@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// LogKeyStyle selects how the JSON keys of log entries are spelled
+type LogKeyStyle string
+
+const (
+	// LogKeysSnakeCase writes keys such as request_id (the default)
+	LogKeysSnakeCase LogKeyStyle = "snake"
+	// LogKeysCamelCase writes keys such as requestId, for pipelines that
+	// expect camelCase
+	LogKeysCamelCase LogKeyStyle = "camel"
+)
+
+// ParseLogKeyStyle parses a LOG_KEY_STYLE value; empty is snake_case
+func ParseLogKeyStyle(value string) (LogKeyStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "snake", "snake_case":
+		return LogKeysSnakeCase, nil
+	case "camel", "camelcase":
+		return LogKeysCamelCase, nil
+	default:
+		return LogKeysSnakeCase, fmt.Errorf("unknown log key style %q: expected snake or camel", value)
+	}
+}
+
+// SetKeyStyle selects snake_case or camelCase keys for entries, including the
+// keys of their fields
+func (sl *StructuredLogger) SetKeyStyle(style LogKeyStyle) {
+	sl.keyStyle = style
+}
+
+// marshal encodes entry in the logger's key style
+func (sl *StructuredLogger) marshal(entry LogEntry) ([]byte, error) {
+	if sl.keyStyle != LogKeysCamelCase {
+		return json.Marshal(entry)
+	}
+	return marshalCamel(entry)
+}
+
+// marshalCamel encodes entry like json.Marshal, in field order and honoring
+// omitempty, but with its keys and the keys of its fields in camelCase
+func marshalCamel(entry LogEntry) ([]byte, error) {
+	if entry.Fields != nil {
+		fields := make(map[string]interface{}, len(entry.Fields))
+		for k, v := range entry.Fields {
+			fields[camelCase(k)] = v
+		}
+		entry.Fields = fields
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	value := reflect.ValueOf(entry)
+	for i := 0; i < value.NumField(); i++ {
+		name, opts, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		field := value.Field(i)
+		if opts == "omitempty" && (field.IsZero() || (field.Kind() == reflect.Map && field.Len() == 0)) {
+			continue
+		}
+
+		data, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%q:", camelCase(name))
+		buf.Write(data)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// camelCase converts a snake_case key such as request_id to requestId; keys
+// without underscores are returned unchanged
+func camelCase(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r == '_':
+			upper = b.Len() > 0
+		case upper:
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
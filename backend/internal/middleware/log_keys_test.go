@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"testing"
+)
+
+func TestStructuredLogger_KeyStyle(t *testing.T) {
+	logEntry := func(style LogKeyStyle) map[string]interface{} {
+		var buf bytes.Buffer
+		logger := &StructuredLogger{
+			logger:            log.New(&buf, "", 0),
+			service:           "todo-service",
+			instanceID:        "pod-1",
+			includeInstanceID: true,
+		}
+		logger.SetLevel(LevelInfo)
+		logger.SetKeyStyle(style)
+
+		ctx := WithCorrelationID(WithRequestID(context.Background(), "req-123"), "corr-1")
+		logger.Error(ctx, "Update failed", errors.New("boom"), map[string]interface{}{
+			"created_at":  "2025-03-01",
+			"duration_ms": 12,
+			"category":    "database",
+		})
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Expected valid JSON in %s style, got %q: %v", style, buf.String(), err)
+		}
+		return entry
+	}
+
+	tests := []struct {
+		style      LogKeyStyle
+		keys       []string
+		fieldKeys  []string
+		absentKeys []string
+	}{
+		{
+			style:      LogKeysSnakeCase,
+			keys:       []string{"timestamp", "level", "message", "error", "request_id", "correlation_id", "service", "instance_id"},
+			fieldKeys:  []string{"created_at", "duration_ms", "category"},
+			absentKeys: []string{"requestId", "causation_id", "marshal_error"},
+		},
+		{
+			style:      LogKeysCamelCase,
+			keys:       []string{"timestamp", "level", "message", "error", "requestId", "correlationId", "service", "instanceId"},
+			fieldKeys:  []string{"createdAt", "durationMs", "category"},
+			absentKeys: []string{"request_id", "causationId", "marshalError"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			entry := logEntry(tt.style)
+			for _, key := range tt.keys {
+				if _, ok := entry[key]; !ok {
+					t.Errorf("Expected key %q, got %v", key, entry)
+				}
+			}
+			for _, key := range tt.absentKeys {
+				if _, ok := entry[key]; ok {
+					t.Errorf("Expected no key %q, got %v", key, entry)
+				}
+			}
+			fields, _ := entry["fields"].(map[string]interface{})
+			for _, key := range tt.fieldKeys {
+				if _, ok := fields[key]; !ok {
+					t.Errorf("Expected field %q, got %v", key, fields)
+				}
+			}
+			if entry["message"] != "Update failed" || entry["error"] != "boom" {
+				t.Errorf("Expected values to be unchanged, got %v", entry)
+			}
+		})
+	}
+}
+
+func TestParseLogKeyStyle(t *testing.T) {
+	for value, want := range map[string]LogKeyStyle{
+		"":      LogKeysSnakeCase,
+		"snake": LogKeysSnakeCase,
+		"Camel": LogKeysCamelCase,
+	} {
+		if got, err := ParseLogKeyStyle(value); err != nil || got != want {
+			t.Errorf("ParseLogKeyStyle(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseLogKeyStyle("kebab"); err == nil {
+		t.Error("Expected an unknown style to be rejected")
+	}
+
+	for key, want := range map[string]string{
+		"request_id":    "requestId",
+		"rows_affected": "rowsAffected",
+		"category":      "category",
+		"_private":      "private",
+	} {
+		if got := camelCase(key); got != want {
+			t.Errorf("camelCase(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
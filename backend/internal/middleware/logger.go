@@ -4,10 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // LogLevel represents the logging level
@@ -37,77 +43,144 @@ func (l LogLevel) String() string {
 
 // StructuredLogger provides structured logging with JSON output
 type StructuredLogger struct {
-	level       LogLevel
+	level       atomic.Int32 // LogLevel; read on every call and changed by SetLevel
 	logger      *log.Logger
 	service     string
 	version     string
 	environment string
+	instanceID  string
+	keyStyle    LogKeyStyle
+
+	// includeInstanceID attaches instanceID to every entry; off by default
+	includeInstanceID bool
 }
 
 // LogEntry represents a structured log entry
 type LogEntry struct {
-	Timestamp   time.Time              `json:"timestamp"`
-	Level       string                 `json:"level"`
-	Message     string                 `json:"message"`
-	Error       string                 `json:"error,omitempty"`
-	Fields      map[string]interface{} `json:"fields,omitempty"`
-	RequestID   string                 `json:"request_id,omitempty"`
-	Service     string                 `json:"service,omitempty"`
-	Version     string                 `json:"version,omitempty"`
-	Environment string                 `json:"environment,omitempty"`
-	Source      string                 `json:"source,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Level         string                 `json:"level"`
+	Message       string                 `json:"message"`
+	Error         string                 `json:"error,omitempty"`
+	Fields        map[string]interface{} `json:"fields,omitempty"`
+	RequestID     string                 `json:"request_id,omitempty"`
+	CorrelationID string                 `json:"correlation_id,omitempty"`
+	CausationID   string                 `json:"causation_id,omitempty"`
+	Actor         string                 `json:"actor,omitempty"`
+	Service       string                 `json:"service,omitempty"`
+	Version       string                 `json:"version,omitempty"`
+	Environment   string                 `json:"environment,omitempty"`
+	InstanceID    string                 `json:"instance_id,omitempty"`
+	Source        string                 `json:"source,omitempty"`
+	MarshalError  string                 `json:"marshal_error,omitempty"`
 }
 
 // NewStructuredLogger creates a new structured logger
 func NewStructuredLogger(level LogLevel) *StructuredLogger {
-	return &StructuredLogger{
-		level:       level,
+	sl := &StructuredLogger{
 		logger:      log.New(os.Stdout, "", 0), // No prefix/flags, we'll format ourselves
 		service:     getEnvOrDefault("SERVICE_NAME", "todo-service"),
 		version:     getEnvOrDefault("SERVICE_VERSION", "dev"),
 		environment: getEnvOrDefault("ENVIRONMENT", "development"),
+		instanceID:  processInstanceID(),
 	}
+	sl.SetLevel(level)
+	return sl
 }
 
 // NewStructuredLoggerWithMetadata creates a logger with custom metadata
 func NewStructuredLoggerWithMetadata(level LogLevel, service, version, environment string) *StructuredLogger {
-	return &StructuredLogger{
-		level:       level,
+	sl := &StructuredLogger{
 		logger:      log.New(os.Stdout, "", 0),
 		service:     service,
 		version:     version,
 		environment: environment,
+		instanceID:  processInstanceID(),
+	}
+	sl.SetLevel(level)
+	return sl
+}
+
+// processInstanceID identifies this replica in logs, resolved once so every
+// logger in the process reports the same id
+var processInstanceID = sync.OnceValue(instanceIDFromEnv)
+
+// instanceIDFromEnv returns POD_NAME, else HOSTNAME, else a generated id
+func instanceIDFromEnv() string {
+	if id := os.Getenv("POD_NAME"); id != "" {
+		return id
+	}
+	if id := os.Getenv("HOSTNAME"); id != "" {
+		return id
 	}
+	return uuid.NewString()
+}
+
+// InstanceID returns the replica identifier attached to entries when
+// SetIncludeInstanceID is on
+func (sl *StructuredLogger) InstanceID() string {
+	return sl.instanceID
+}
+
+// SetIncludeInstanceID controls whether every entry carries the instance_id
+// field, which tells apart the replicas of a multi-replica deployment
+func (sl *StructuredLogger) SetIncludeInstanceID(include bool) {
+	sl.includeInstanceID = include
+}
+
+// Metadata returns the service name, version and environment attached to every entry
+func (sl *StructuredLogger) Metadata() (service, version, environment string) {
+	return sl.service, sl.version, sl.environment
 }
 
 // Debug logs a debug message
 func (sl *StructuredLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
-	if sl.level <= LevelDebug {
+	if sl.enabled(LevelDebug) {
 		sl.log(ctx, LevelDebug, msg, nil, fields)
 	}
 }
 
 // Info logs an info message
 func (sl *StructuredLogger) Info(ctx context.Context, msg string, fields map[string]interface{}) {
-	if sl.level <= LevelInfo {
+	if sl.enabled(LevelInfo) {
 		sl.log(ctx, LevelInfo, msg, nil, fields)
 	}
 }
 
 // Warn logs a warning message
 func (sl *StructuredLogger) Warn(ctx context.Context, msg string, fields map[string]interface{}) {
-	if sl.level <= LevelWarn {
+	if sl.enabled(LevelWarn) {
 		sl.log(ctx, LevelWarn, msg, nil, fields)
 	}
 }
 
 // Error logs an error message
 func (sl *StructuredLogger) Error(ctx context.Context, msg string, err error, fields map[string]interface{}) {
-	if sl.level <= LevelError {
+	if sl.enabled(LevelError) {
 		sl.log(ctx, LevelError, msg, err, fields)
 	}
 }
 
+// SetLevel changes the minimum level logged; it is safe to call while other
+// goroutines are logging
+func (sl *StructuredLogger) SetLevel(level LogLevel) {
+	sl.level.Store(int32(level))
+}
+
+// Level returns the minimum level logged
+func (sl *StructuredLogger) Level() LogLevel {
+	return LogLevel(sl.level.Load())
+}
+
+// SetOutput redirects log entries to w, e.g. to capture them in tests
+func (sl *StructuredLogger) SetOutput(w io.Writer) {
+	sl.logger.SetOutput(w)
+}
+
+// enabled reports whether entries at level are logged
+func (sl *StructuredLogger) enabled(level LogLevel) bool {
+	return sl.Level() <= level
+}
+
 // log outputs a structured log entry
 func (sl *StructuredLogger) log(ctx context.Context, level LogLevel, msg string, err error, fields map[string]interface{}) {
 	entry := LogEntry{
@@ -119,6 +192,9 @@ func (sl *StructuredLogger) log(ctx context.Context, level LogLevel, msg string,
 		Version:     sl.version,
 		Environment: sl.environment,
 	}
+	if sl.includeInstanceID {
+		entry.InstanceID = sl.instanceID
+	}
 
 	if err != nil {
 		entry.Error = err.Error()
@@ -129,15 +205,30 @@ func (sl *StructuredLogger) log(ctx context.Context, level LogLevel, msg string,
 		entry.RequestID = requestID
 	}
 
+	// Tie the entry to the workflow that led to this request
+	entry.CorrelationID = getCorrelationID(ctx)
+	entry.CausationID = getCausationID(ctx)
+
+	// Attribute the entry to the authenticated caller if known
+	if actor := getActor(ctx); actor != "" {
+		entry.Actor = actor
+	}
+
 	// Add source information from context if available
 	if source := getSource(ctx); source != "" {
 		entry.Source = source
 	}
 
 	// Marshal to JSON
-	jsonData, jsonErr := json.Marshal(entry)
+	jsonData, jsonErr := sl.marshal(entry)
+	if jsonErr != nil {
+		// Keep the entry structured: only fields can fail to marshal, so
+		// replace the ones that do with their string form and note why
+		entry.Fields = sanitizeFields(fields)
+		entry.MarshalError = jsonErr.Error()
+		jsonData, jsonErr = sl.marshal(entry)
+	}
 	if jsonErr != nil {
-		// Fallback to simple logging if JSON marshaling fails
 		sl.logger.Printf("[%s] %s (JSON marshal error: %v)", level.String(), msg, jsonErr)
 		return
 	}
@@ -145,6 +236,19 @@ func (sl *StructuredLogger) log(ctx context.Context, level LogLevel, msg string,
 	sl.logger.Println(string(jsonData))
 }
 
+// sanitizeFields returns a copy of fields with every value that cannot be
+// marshaled to JSON, such as a channel or a NaN, replaced by its %v form
+func sanitizeFields(fields map[string]interface{}) map[string]interface{} {
+	sanitized := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		if _, err := json.Marshal(v); err != nil {
+			v = fmt.Sprintf("%v", v)
+		}
+		sanitized[k] = v
+	}
+	return sanitized
+}
+
 // getRequestID extracts request ID from context
 func getRequestID(ctx context.Context) string {
 	if ctx == nil {
@@ -156,23 +260,85 @@ func getRequestID(ctx context.Context) string {
 	return ""
 }
 
-// RequestIDMiddleware adds a unique request ID to each request context
+// GetRequestID returns the request ID assigned by RequestIDMiddleware
+func GetRequestID(ctx context.Context) string {
+	return getRequestID(ctx)
+}
+
+// Headers carrying the workflow a request belongs to: the correlation ID names
+// the originating request and the causation ID the immediate parent
+const (
+	CorrelationIDHeader = "X-Correlation-ID"
+	CausationIDHeader   = "X-Causation-ID"
+)
+
+// traceIDPattern bounds client-supplied correlation and causation IDs to short
+// tokens that are safe to log; anything else is ignored
+var traceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware adds a unique request ID to each request context, along
+// with the correlation and causation IDs from the request headers. A request
+// without a correlation ID starts a new workflow, so its own ID is used.
 func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Generate a simple request ID (in production, use a proper UUID library)
 		requestID := fmt.Sprintf("%d", time.Now().UnixNano())
+
+		correlationID := r.Header.Get(CorrelationIDHeader)
+		if !traceIDPattern.MatchString(correlationID) {
+			correlationID = requestID
+		}
+		causationID := r.Header.Get(CausationIDHeader)
+		if !traceIDPattern.MatchString(causationID) {
+			causationID = ""
+		}
 		
 		// Add request ID to context
 		ctx := context.WithValue(r.Context(), "request_id", requestID)
+		ctx = WithCorrelationID(ctx, correlationID)
+		ctx = WithCausationID(ctx, causationID)
 		r = r.WithContext(ctx)
 		
 		// Add request ID to response headers for debugging
 		w.Header().Set("X-Request-ID", requestID)
+		w.Header().Set(CorrelationIDHeader, correlationID)
 		
 		next.ServeHTTP(w, r)
 	})
 }
 
+// WithCorrelationID adds a correlation ID to the context
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, "correlation_id", correlationID)
+}
+
+// WithCausationID adds a causation ID to the context
+func WithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, "causation_id", causationID)
+}
+
+// getCorrelationID extracts the correlation ID from context
+func getCorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value("correlation_id").(string); ok {
+		return id
+	}
+	return ""
+}
+
+// getCausationID extracts the causation ID from context
+func getCausationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value("causation_id").(string); ok {
+		return id
+	}
+	return ""
+}
+
 // WithRequestID adds a request ID to the context
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, "request_id", requestID)
@@ -269,4 +435,22 @@ func (sl *StructuredLogger) LogMetrics(ctx context.Context, metrics map[string]i
 	}
 	
 	sl.Info(ctx, "Performance metrics", enrichedFields)
-}
\ No newline at end of file
+}
+
+// LogEvent logs a business event such as task_created, separately from
+// infrastructure logs, so analytics can select on category "event"
+func (sl *StructuredLogger) LogEvent(ctx context.Context, eventName string, attrs map[string]interface{}) {
+	fields := map[string]interface{}{
+		"category": "event",
+		"event":    eventName,
+	}
+
+	for k, v := range attrs {
+		if k == "category" || k == "event" {
+			continue
+		}
+		fields[k] = v
+	}
+
+	sl.Info(ctx, "Event "+eventName, fields)
+}
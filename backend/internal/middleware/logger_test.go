@@ -8,6 +8,8 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -64,9 +66,9 @@ func TestGetLogLevel(t *testing.T) {
 func TestStructuredLogger(t *testing.T) {
 	var buf bytes.Buffer
 	logger := &StructuredLogger{
-		level:  LevelDebug,
 		logger: log.New(&buf, "", 0),
 	}
+	logger.SetLevel(LevelDebug)
 
 	ctx := context.Background()
 	fields := map[string]interface{}{
@@ -152,9 +154,9 @@ func TestStructuredLogger(t *testing.T) {
 	t.Run("log level filtering", func(t *testing.T) {
 		// Create logger with WARN level
 		warnLogger := &StructuredLogger{
-			level:  LevelWarn,
 			logger: log.New(&buf, "", 0),
 		}
+		warnLogger.SetLevel(LevelWarn)
 		
 		buf.Reset()
 		warnLogger.Debug(ctx, "debug message", nil)
@@ -185,6 +187,65 @@ func TestStructuredLogger(t *testing.T) {
 			t.Errorf("Expected request ID 'test-request-id', got %s", entry.RequestID)
 		}
 	})
+
+	t.Run("with correlation and causation IDs", func(t *testing.T) {
+		buf.Reset()
+		ctxWithIDs := WithCausationID(WithCorrelationID(ctx, "order-42"), "req-7")
+		logger.Info(ctxWithIDs, "message with correlation", nil)
+
+		var entry LogEntry
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse log JSON: %v", err)
+		}
+
+		if entry.CorrelationID != "order-42" {
+			t.Errorf("Expected correlation ID 'order-42', got %s", entry.CorrelationID)
+		}
+		if entry.CausationID != "req-7" {
+			t.Errorf("Expected causation ID 'req-7', got %s", entry.CausationID)
+		}
+	})
+}
+
+func TestStructuredLogger_SetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &StructuredLogger{
+		logger: log.New(&buf, "", 0),
+	}
+	logger.SetLevel(LevelError)
+	ctx := context.Background()
+
+	logger.Info(ctx, "hidden", nil)
+	logger.SetLevel(LevelDebug)
+	if logger.Level() != LevelDebug {
+		t.Errorf("Expected level DEBUG, got %v", logger.Level())
+	}
+	logger.Debug(ctx, "shown", nil)
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("Expected only entries at the new level to be logged, got %q", buf.String())
+	}
+
+	// Run with -race: reading and changing the level must not race with logging
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Debug(ctx, "concurrent", nil)
+				if level := logger.Level(); level < LevelDebug || level > LevelError {
+					t.Errorf("Read a level that was never set: %v", level)
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.SetLevel(LogLevel((i + j) % 4))
+			}
+		}(i)
+	}
+	wg.Wait()
 }
 
 func TestRequestIDMiddleware(t *testing.T) {
@@ -222,6 +283,55 @@ func TestRequestIDMiddleware(t *testing.T) {
 	}
 }
 
+func TestRequestIDMiddleware_CorrelationIDs(t *testing.T) {
+	tests := []struct {
+		name            string
+		correlation     string
+		causation       string
+		wantCorrelation string // empty means the request's own ID
+		wantCausation   string
+	}{
+		{name: "defaults correlation to request ID"},
+		{name: "propagates incoming IDs", correlation: "order-42", causation: "req-7", wantCorrelation: "order-42", wantCausation: "req-7"},
+		{name: "ignores unsafe IDs", correlation: "bad id\nforged", causation: "<script>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestID, correlationID, causationID string
+			handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestID = getRequestID(r.Context())
+				correlationID = getCorrelationID(r.Context())
+				causationID = getCausationID(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			if tt.correlation != "" {
+				req.Header.Set(CorrelationIDHeader, tt.correlation)
+			}
+			if tt.causation != "" {
+				req.Header.Set(CausationIDHeader, tt.causation)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			want := tt.wantCorrelation
+			if want == "" {
+				want = requestID
+			}
+			if correlationID != want {
+				t.Errorf("Expected correlation ID %q, got %q", want, correlationID)
+			}
+			if causationID != tt.wantCausation {
+				t.Errorf("Expected causation ID %q, got %q", tt.wantCausation, causationID)
+			}
+			if got := w.Header().Get(CorrelationIDHeader); got != want {
+				t.Errorf("Expected %s response header %q, got %q", CorrelationIDHeader, want, got)
+			}
+		})
+	}
+}
+
 func TestGetRequestID(t *testing.T) {
 	t.Run("nil context", func(t *testing.T) {
 		id := getRequestID(nil)
@@ -276,8 +386,8 @@ func TestNewStructuredLogger(t *testing.T) {
 		t.Fatal("Expected logger to be created")
 	}
 	
-	if logger.level != LevelInfo {
-		t.Errorf("Expected level INFO, got %v", logger.level)
+	if logger.Level() != LevelInfo {
+		t.Errorf("Expected level INFO, got %v", logger.Level())
 	}
 	
 	if logger.logger == nil {
@@ -321,12 +431,12 @@ func TestNewStructuredLoggerWithMetadata(t *testing.T) {
 func TestLogDatabaseOperation(t *testing.T) {
 	var buf bytes.Buffer
 	logger := &StructuredLogger{
-		level:       LevelInfo,
 		logger:      log.New(&buf, "", 0),
 		service:     "test-service",
 		version:     "v1.0.0",
 		environment: "test",
 	}
+	logger.SetLevel(LevelInfo)
 
 	ctx := context.Background()
 	duration := 50 * time.Millisecond
@@ -379,12 +489,12 @@ func TestLogDatabaseOperation(t *testing.T) {
 func TestLogServiceCall(t *testing.T) {
 	var buf bytes.Buffer
 	logger := &StructuredLogger{
-		level:       LevelInfo,
 		logger:      log.New(&buf, "", 0),
 		service:     "test-service",
 		version:     "v1.0.0",
 		environment: "test",
 	}
+	logger.SetLevel(LevelInfo)
 
 	ctx := context.Background()
 	duration := 100 * time.Millisecond
@@ -437,12 +547,12 @@ func TestLogServiceCall(t *testing.T) {
 func TestLogMetrics(t *testing.T) {
 	var buf bytes.Buffer
 	logger := &StructuredLogger{
-		level:       LevelInfo,
 		logger:      log.New(&buf, "", 0),
 		service:     "test-service",
 		version:     "v1.0.0",
 		environment: "test",
 	}
+	logger.SetLevel(LevelInfo)
 
 	ctx := context.Background()
 	metrics := map[string]interface{}{
@@ -484,4 +594,121 @@ func TestWithSource(t *testing.T) {
 	if source != "test.function" {
 		t.Errorf("Expected 'test.function', got %s", source)
 	}
-}
\ No newline at end of file
+}
+func TestStructuredLogger_LogEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &StructuredLogger{
+		logger: log.New(&buf, "", 0),
+	}
+	logger.SetLevel(LevelInfo)
+
+	logger.LogEvent(context.Background(), "task_completed", map[string]interface{}{
+		"task_id":  "task-1",
+		"category": "overridden",
+	})
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse log JSON: %v", err)
+	}
+	if entry.Level != "INFO" {
+		t.Errorf("Expected level INFO, got %s", entry.Level)
+	}
+	if entry.Fields["category"] != "event" || entry.Fields["event"] != "task_completed" {
+		t.Errorf("Expected category event and event task_completed, got %v", entry.Fields)
+	}
+	if entry.Fields["task_id"] != "task-1" {
+		t.Errorf("Expected task_id to be kept, got %v", entry.Fields["task_id"])
+	}
+}
+
+func TestStructuredLogger_MarshalFallback(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &StructuredLogger{
+		logger: log.New(&buf, "", 0),
+	}
+	logger.SetLevel(LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.Info(ctx, "odd fields", map[string]interface{}{
+		"channel": make(chan int),
+		"count":   3,
+	})
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected valid JSON despite an unmarshalable field, got %q: %v", buf.String(), err)
+	}
+	if entry.Message != "odd fields" || entry.Level != "INFO" || entry.RequestID != "req-123" {
+		t.Errorf("Expected message, level and request ID to survive, got %+v", entry)
+	}
+	if entry.MarshalError == "" {
+		t.Error("Expected a marshal_error note")
+	}
+	if s, ok := entry.Fields["channel"].(string); !ok || !strings.HasPrefix(s, "0x") {
+		t.Errorf("Expected the channel to be logged as its %%v string, got %v", entry.Fields["channel"])
+	}
+	if entry.Fields["count"] != float64(3) {
+		t.Errorf("Expected marshalable fields to be kept as is, got %v", entry.Fields["count"])
+	}
+}
+
+func TestStructuredLogger_InstanceID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStructuredLoggerWithMetadata(LevelInfo, "test-service", "v1.0.0", "test")
+	logger.SetOutput(&buf)
+
+	ctx := context.Background()
+	logIDs := func() []string {
+		buf.Reset()
+		logger.Info(ctx, "first", nil)
+		logger.Warn(ctx, "second", nil)
+		logger.Error(ctx, "third", errors.New("boom"), nil)
+
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("Failed to parse log JSON: %v", err)
+			}
+			id, _ := entry["instance_id"].(string)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// Off by default
+	if ids := logIDs(); strings.Join(ids, "") != "" {
+		t.Errorf("Expected no instance_id by default, got %q", ids)
+	}
+
+	logger.SetIncludeInstanceID(true)
+	ids := logIDs()
+	if len(ids) != 3 || ids[0] == "" || ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Errorf("Expected the same non-empty instance_id on every entry, got %q", ids)
+	}
+	if ids[0] != logger.InstanceID() {
+		t.Errorf("Expected entries to carry the logger's instance_id %q, got %q", logger.InstanceID(), ids[0])
+	}
+	if other := NewStructuredLogger(LevelInfo); other.InstanceID() != ids[0] {
+		t.Errorf("Expected loggers in one process to share an instance_id, got %q and %q", other.InstanceID(), ids[0])
+	}
+}
+
+func TestInstanceIDFromEnv(t *testing.T) {
+	t.Setenv("POD_NAME", "todo-7f9c-abcde")
+	t.Setenv("HOSTNAME", "node-1")
+	if id := instanceIDFromEnv(); id != "todo-7f9c-abcde" {
+		t.Errorf("Expected POD_NAME to win, got %q", id)
+	}
+
+	t.Setenv("POD_NAME", "")
+	if id := instanceIDFromEnv(); id != "node-1" {
+		t.Errorf("Expected HOSTNAME without POD_NAME, got %q", id)
+	}
+
+	t.Setenv("HOSTNAME", "")
+	if first, second := instanceIDFromEnv(), instanceIDFromEnv(); first == "" || first == second {
+		t.Errorf("Expected distinct generated ids, got %q and %q", first, second)
+	}
+}
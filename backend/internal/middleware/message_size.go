@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+)
+
+// MessageSizeLimit caps the size of request messages. Connect enforces the cap
+// while reading, before anything is decoded, and answers with
+// resource_exhausted; message nesting is already bounded by protobuf's own
+// recursion limit. Because the rejection happens before any interceptor runs,
+// it is logged by wrapping the service's HTTP handler.
+type MessageSizeLimit struct {
+	maxBytes int
+	logger   Logger
+}
+
+// NewMessageSizeLimit limits request messages to maxBytes; zero disables the limit
+func NewMessageSizeLimit(maxBytes int, logger Logger) *MessageSizeLimit {
+	return &MessageSizeLimit{maxBytes: maxBytes, logger: logger}
+}
+
+// HandlerOption returns the Connect option that enforces the limit
+func (l *MessageSizeLimit) HandlerOption() connect.HandlerOption {
+	return connect.WithReadMaxBytes(l.maxBytes)
+}
+
+// Middleware logs requests that Connect rejected for exceeding the limit.
+// Sizes are measured on the wire, so a compressed message that only exceeds
+// the limit once decompressed is rejected but not logged.
+func (l *MessageSizeLimit) Middleware(next http.Handler) http.Handler {
+	if l.maxBytes <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := &countingReader{ReadCloser: r.Body}
+		r.Body = body
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(wrapped, r)
+
+		size := body.n
+		if r.Method == http.MethodGet {
+			// Connect GETs carry the message in the query string
+			size = int64(len(r.URL.Query().Get("message")))
+		}
+		if size <= int64(l.maxBytes) || !isResourceExhausted(wrapped) {
+			return
+		}
+		l.logger.Error(r.Context(), "Request message exceeds MAX_MESSAGE_BYTES", nil, map[string]interface{}{
+			"procedure":     r.URL.Path,
+			"message_bytes": size,
+			"max_bytes":     l.maxBytes,
+		})
+	})
+}
+
+// isResourceExhausted reports whether the response carries a resource_exhausted
+// error: HTTP 429 for the Connect protocol, grpc-status 8 for gRPC and gRPC-Web
+func isResourceExhausted(w *responseWriter) bool {
+	if w.statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	code := strconv.Itoa(int(connect.CodeResourceExhausted))
+	header := w.Header()
+	return header.Get("Grpc-Status") == code || header.Get(http.TrailerPrefix+"Grpc-Status") == code
+}
+
+// countingReader counts the bytes read from a request body
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
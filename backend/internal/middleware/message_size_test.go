@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestMessageSizeLimit(t *testing.T) {
+	logger := &mockLogger{}
+	limit := NewMessageSizeLimit(64, logger)
+
+	mux := http.NewServeMux()
+	echo := connect.NewUnaryHandler("/test.v1.EchoService/Echo",
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			return connect.NewResponse(req.Msg), nil
+		},
+		limit.HandlerOption(),
+	)
+	mux.Handle("/test.v1.EchoService/Echo", limit.Middleware(echo))
+
+	// gRPC needs HTTP/2
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	protocols := []struct {
+		name string
+		opts []connect.ClientOption
+	}{
+		{name: "connect"},
+		{name: "connect json", opts: []connect.ClientOption{connect.WithProtoJSON()}},
+		{name: "grpc", opts: []connect.ClientOption{connect.WithGRPC()}},
+		{name: "grpc-web", opts: []connect.ClientOption{connect.WithGRPCWeb()}},
+	}
+
+	for _, protocol := range protocols {
+		t.Run(protocol.name, func(t *testing.T) {
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+				server.Client(), server.URL+"/test.v1.EchoService/Echo", protocol.opts...)
+
+			logger.reset()
+			if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("small"))); err != nil {
+				t.Fatalf("Expected a small message to pass, got %v", err)
+			}
+			if len(logger.errorMessages) != 0 {
+				t.Errorf("Expected no error logs, got %d", len(logger.errorMessages))
+			}
+
+			_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(strings.Repeat("x", 1024))))
+			if connect.CodeOf(err) != connect.CodeResourceExhausted {
+				t.Fatalf("Expected resource_exhausted, got %v", err)
+			}
+			if len(logger.errorMessages) != 1 {
+				t.Fatalf("Expected 1 error log, got %d", len(logger.errorMessages))
+			}
+			if logger.errorMessages[0].Fields["procedure"] != "/test.v1.EchoService/Echo" {
+				t.Errorf("Expected the procedure to be logged, got %v", logger.errorMessages[0].Fields)
+			}
+		})
+	}
+}
+
+func TestMessageSizeLimit_Disabled(t *testing.T) {
+	wrapped := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, wrapped = r.Body.(*countingReader)
+	})
+
+	NewMessageSizeLimit(0, &mockLogger{}).Middleware(next).ServeHTTP(
+		httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/test.v1.EchoService/Echo", strings.NewReader("{}")))
+	if wrapped {
+		t.Error("Expected requests to pass through untouched without a limit")
+	}
+}
@@ -8,16 +8,24 @@ import (
 
 // MiddlewareStack combines multiple middlewares into a single handler
 type MiddlewareStack struct {
-	errorHandler *ErrorHandler
-	logger       Logger
+	errorHandler      *ErrorHandler
+	logger            Logger
+	principalResolver PrincipalResolver
+	tenancy           *tenancyConfig
+	securityHeaders   SecurityHeaders
+	envelopeMode      EnvelopeMode
+	chaos             *Chaos
+	quotas            *KeyQuotas
+	auth              *APIKeyAuth
 }
 
 // NewMiddlewareStack creates a new middleware stack
 func NewMiddlewareStack(logger Logger) *MiddlewareStack {
 	errorHandler := NewErrorHandler(logger)
 	return &MiddlewareStack{
-		errorHandler: errorHandler,
-		logger:       logger,
+		errorHandler:    errorHandler,
+		logger:          logger,
+		securityHeaders: DefaultSecurityHeaders(),
 	}
 }
 
@@ -25,17 +33,85 @@ func NewMiddlewareStack(logger Logger) *MiddlewareStack {
 func (ms *MiddlewareStack) WrapHandler(h http.Handler) http.Handler {
 	// Apply middlewares in reverse order (last applied is executed first)
 	handler := h
+	handler = EnvelopeMiddleware(ms.envelopeMode, handler)
+	handler = ContentTypeMiddleware(handler)
 	handler = ms.errorHandler.LoggingMiddleware(handler)
 	handler = ms.errorHandler.RecoveryMiddleware(handler)
 	handler = RequestIDMiddleware(handler)
+	handler = SecurityHeadersMiddleware(ms.securityHeaders, handler)
 	return handler
 }
 
+// SetEnvelopeMode controls when JSON responses are wrapped in an envelope
+func (ms *MiddlewareStack) SetEnvelopeMode(mode EnvelopeMode) {
+	ms.envelopeMode = mode
+}
+
+// SetSecurityHeaders replaces the security headers added to every response
+func (ms *MiddlewareStack) SetSecurityHeaders(headers SecurityHeaders) {
+	ms.securityHeaders = headers
+}
+
+// SetPrincipalResolver enables actor attribution in logs using the given resolver
+func (ms *MiddlewareStack) SetPrincipalResolver(resolve PrincipalResolver) {
+	ms.principalResolver = resolve
+}
+
+// tenancyConfig enables tenant scoping of RPCs
+type tenancyConfig struct {
+	defaultTenant string
+}
+
+// EnableTenancy requires RPCs to carry a tenant in the X-Tenant-ID header.
+// defaultTenant is used when the header is absent; leave it empty to reject
+// such requests.
+func (ms *MiddlewareStack) EnableTenancy(defaultTenant string) {
+	ms.tenancy = &tenancyConfig{defaultTenant: defaultTenant}
+}
+
+// SetChaos injects the faults configured by chaos into RPCs; a nil or
+// disabled Chaos leaves requests untouched
+func (ms *MiddlewareStack) SetChaos(chaos *Chaos) {
+	ms.chaos = chaos
+}
+
+// SetKeyQuotas limits the request rate of each API key; nil or empty quotas
+// leave requests unlimited
+func (ms *MiddlewareStack) SetKeyQuotas(quotas *KeyQuotas) {
+	ms.quotas = quotas
+}
+
+// SetAPIKeyAuth requires RPCs to present an API key accepted by auth; a nil or
+// disabled auth leaves requests unauthenticated
+func (ms *MiddlewareStack) SetAPIKeyAuth(auth *APIKeyAuth) {
+	ms.auth = auth
+}
+
 // GetConnectInterceptors returns Connect RPC interceptors
 func (ms *MiddlewareStack) GetConnectInterceptors() []connect.Interceptor {
-	return []connect.Interceptor{
-		connect.UnaryInterceptorFunc(ms.errorHandler.ConnectErrorInterceptor()),
+	interceptors := []connect.Interceptor{}
+	// The actor interceptor runs first so error logs are attributed too
+	if ms.principalResolver != nil {
+		interceptors = append(interceptors, ActorInterceptor(ms.principalResolver))
+	}
+	interceptors = append(interceptors, ms.errorHandler.ConnectErrorInterceptor())
+	// Authentication failures are logged, and rejected calls go no further
+	if ms.auth.Enabled() {
+		interceptors = append(interceptors, ms.auth.Interceptor())
+	}
+	// The tenant interceptor runs inside error handling so rejections are logged
+	if ms.tenancy != nil {
+		interceptors = append(interceptors, TenantInterceptor(ms.tenancy.defaultTenant))
+	}
+	// Quota rejections are logged, and rejected calls skip injected faults
+	if ms.quotas.Enabled() {
+		interceptors = append(interceptors, ms.quotas.Interceptor())
+	}
+	// Injected faults are logged like real ones
+	if ms.chaos.Enabled() {
+		interceptors = append(interceptors, ms.chaos.Interceptor())
 	}
+	return interceptors
 }
 
 // ErrorHandler returns the error handler for manual use
@@ -4,8 +4,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-
-	"connectrpc.com/connect"
 )
 
 func TestNewMiddlewareStack(t *testing.T) {
@@ -233,8 +231,8 @@ func TestConnectInterceptor_Basic(t *testing.T) {
 	
 	// Verify interceptor type
 	interceptor := interceptors[0]
-	if _, ok := interceptor.(connect.UnaryInterceptorFunc); !ok {
-		t.Error("Expected UnaryInterceptorFunc")
+	if _, ok := interceptor.(*rpcLogInterceptor); !ok {
+		t.Error("Expected the RPC logging interceptor")
 	}
 	
 	// Note: Full integration testing of Connect interceptors requires
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Panic classes reported in the panic_type log field
+const (
+	PanicTypeRuntime = "runtime_error" // nil dereference, index out of range, nil map write...
+	PanicTypeError   = "error"         // panic(err) with an application error
+	PanicTypeString  = "string"        // panic("message")
+	PanicTypeOther   = "other"         // any other value
+)
+
+// classifyPanic returns the panic class of a recovered value
+func classifyPanic(value interface{}) string {
+	switch value.(type) {
+	case runtime.Error:
+		return PanicTypeRuntime
+	case error:
+		return PanicTypeError
+	case string:
+		return PanicTypeString
+	default:
+		return PanicTypeOther
+	}
+}
+
+// panicFrame returns the frame that panicked, as "function file:line", from a
+// stack captured by debug.Stack in a deferred recover. Frames above the last
+// panic call, which include deferred functions that re-panicked, and frames
+// inside the runtime, such as runtime.sigpanic for a nil dereference, are
+// skipped. It returns "" when the stack has no such frame.
+func panicFrame(stack []byte) string {
+	lines := strings.Split(string(stack), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+
+	// Frames are a function line followed by a tab-indented location line
+	for i := start; i+1 < len(lines); i++ {
+		function := lines[i]
+		if function == "" || strings.HasPrefix(function, "\t") || strings.HasPrefix(function, "runtime.") {
+			continue
+		}
+
+		if open := strings.LastIndex(function, "("); open > 0 {
+			function = function[:open]
+		}
+		location := strings.TrimSpace(lines[i+1])
+		if offset := strings.LastIndex(location, " +0x"); offset > 0 {
+			location = location[:offset]
+		}
+		return function + " " + location
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// panicWithString, panicWithError and writeNilMap are named so the frame
+// reported for their panics can be checked
+func panicWithString() {
+	panic("something went wrong")
+}
+
+func panicWithError() {
+	panic(errors.New("invariant violated"))
+}
+
+func writeNilMap() {
+	var counts map[string]int
+	counts["boom"]++
+}
+
+func TestRecoveryMiddleware_ClassifiesPanics(t *testing.T) {
+	tests := []struct {
+		name      string
+		panics    func()
+		wantType  string
+		wantFrame string
+	}{
+		{"string", panicWithString, PanicTypeString, "middleware.panicWithString"},
+		{"error", panicWithError, PanicTypeError, "middleware.panicWithError"},
+		{"nil map write", writeNilMap, PanicTypeRuntime, "middleware.writeNilMap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &mockLogger{}
+			handler := NewErrorHandler(logger).RecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.panics()
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+			if len(logger.errorMessages) != 1 {
+				t.Fatalf("Expected 1 error message, got %d", len(logger.errorMessages))
+			}
+			fields := logger.errorMessages[0].Fields
+			if fields["panic_type"] != tt.wantType {
+				t.Errorf("Expected panic_type %q, got %v", tt.wantType, fields["panic_type"])
+			}
+			frame, _ := fields["panic_frame"].(string)
+			if !strings.Contains(frame, tt.wantFrame+" ") || !strings.Contains(frame, "panic_test.go:") {
+				t.Errorf("Expected panic_frame in %s, got %q", tt.wantFrame, frame)
+			}
+		})
+	}
+}
+
+func TestClassifyPanic(t *testing.T) {
+	var nilMap map[string]int
+	var runtimeErr interface{}
+	func() {
+		defer func() { runtimeErr = recover() }()
+		nilMap["boom"] = 1
+	}()
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{"message", PanicTypeString},
+		{errors.New("custom"), PanicTypeError},
+		{runtimeErr, PanicTypeRuntime},
+		{42, PanicTypeOther},
+	}
+	for _, tt := range tests {
+		if got := classifyPanic(tt.value); got != tt.want {
+			t.Errorf("classifyPanic(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPanicFrame(t *testing.T) {
+	stack := []byte(`goroutine 7 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+example.com/app.recoverer.func1()
+	/app/recover.go:12 +0x45
+panic({0x6c2a40?, 0x8a1b30?})
+	/usr/local/go/src/runtime/panic.go:785 +0x132
+runtime.panicmem(...)
+	/usr/local/go/src/runtime/panic.go:262
+runtime.sigpanic()
+	/usr/local/go/src/runtime/signal_unix.go:917 +0x359
+example.com/app.(*Store).Load(0x0)
+	/app/store.go:40 +0x17
+example.com/app.handler()
+	/app/handler.go:9 +0x25
+`)
+
+	if got, want := panicFrame(stack), "example.com/app.(*Store).Load /app/store.go:40"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := panicFrame([]byte("goroutine 1 [running]:\nmain.main()\n\t/app/main.go:3 +0x1\n")); got != "" {
+		t.Errorf("Expected no frame without a panic, got %q", got)
+	}
+}
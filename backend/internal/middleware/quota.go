@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// APIKeyHeader carries the caller's API key
+const APIKeyHeader = "X-API-Key"
+
+// defaultQuotaKey sets the quota of keys without one of their own
+const defaultQuotaKey = "*"
+
+// maxQuotaCallers bounds how many callers KeyQuotas tracks at once, so a flood
+// of distinct client IPs cannot grow it without limit
+const maxQuotaCallers = 10000
+
+// ParseAPIKeyQuotas parses an API_KEY_QUOTAS value: a comma-separated list of
+// key=limit pairs, where limit is the key's requests per window and the key
+// "*" sets the limit for keys not listed. Empty means no quotas.
+func ParseAPIKeyQuotas(value string) (map[string]int, error) {
+	quotas := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, limit, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid quota entry %q: expected key=limit", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid quota limit %q: must be a positive integer", limit)
+		}
+		quotas[key] = n
+	}
+	return quotas, nil
+}
+
+// KeyQuotas limits how many requests each caller may make in a sliding window.
+// A caller is the principal the request was authenticated as, such as an API
+// key accepted by APIKeyAuth, so an X-API-Key header that was never verified
+// counts for nothing; anonymous callers are told apart by client IP and share
+// the "*" quota. HealthCheck is not limited.
+type KeyQuotas struct {
+	limits     map[string]int // by principal, plus "*"
+	window     time.Duration
+	maxCallers int
+
+	mu        sync.Mutex
+	keys      map[string][]time.Time // request times within the window, oldest first
+	lastSweep time.Time
+
+	// now returns the current time; tests replace it
+	now func() time.Time
+}
+
+// NewKeyQuotas returns quotas allowing each API key limits[key] requests, or
+// limits["*"] for other callers, in any window-long span
+func NewKeyQuotas(limits map[string]int, window time.Duration) *KeyQuotas {
+	byPrincipal := make(map[string]int, len(limits))
+	for key, limit := range limits {
+		if key != defaultQuotaKey {
+			key = APIKeyPrincipal(key).String()
+		}
+		byPrincipal[key] = limit
+	}
+	return &KeyQuotas{
+		limits:     byPrincipal,
+		window:     window,
+		maxCallers: maxQuotaCallers,
+		keys:       map[string][]time.Time{},
+		now:        time.Now,
+	}
+}
+
+// Enabled reports whether any quota is configured
+func (q *KeyQuotas) Enabled() bool {
+	return q != nil && len(q.limits) > 0 && q.window > 0
+}
+
+// limit returns key's quota, or false when the key is unlimited
+func (q *KeyQuotas) limit(key string) (int, bool) {
+	if n, ok := q.limits[key]; ok {
+		return n, true
+	}
+	n, ok := q.limits[defaultQuotaKey]
+	return n, ok
+}
+
+// allow records a request by key, or returns how long until the key may make
+// another when it is over its quota
+func (q *KeyQuotas) allow(key string) (retryAfter time.Duration, ok bool) {
+	limit, limited := q.limit(key)
+	if !limited {
+		return 0, true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	q.sweep(now)
+
+	requests, tracked := q.keys[key]
+	if !tracked && len(q.keys) >= q.maxCallers {
+		q.evictIdlest()
+	}
+	cutoff := now.Add(-q.window)
+	expired := 0
+	for expired < len(requests) && !requests[expired].After(cutoff) {
+		expired++
+	}
+	requests = requests[expired:]
+
+	if len(requests) >= limit {
+		q.keys[key] = requests
+		return requests[len(requests)-limit].Sub(cutoff), false
+	}
+	q.keys[key] = append(requests, now)
+	return 0, true
+}
+
+// sweep drops keys idle for a whole window, at most once per window, so keys
+// seen once do not accumulate. Callers hold q.mu.
+func (q *KeyQuotas) sweep(now time.Time) {
+	if now.Sub(q.lastSweep) < q.window {
+		return
+	}
+	q.lastSweep = now
+	cutoff := now.Add(-q.window)
+	for key, requests := range q.keys {
+		if len(requests) == 0 || !requests[len(requests)-1].After(cutoff) {
+			delete(q.keys, key)
+		}
+	}
+}
+
+// evictIdlest forgets the caller whose latest request is oldest, making room
+// for a new one. Callers hold q.mu.
+func (q *KeyQuotas) evictIdlest() {
+	idlest, idlestAt := "", time.Time{}
+	for key, requests := range q.keys {
+		var latest time.Time
+		if len(requests) > 0 {
+			latest = requests[len(requests)-1]
+		}
+		if idlest == "" || latest.Before(idlestAt) {
+			idlest, idlestAt = key, latest
+		}
+	}
+	delete(q.keys, idlest)
+}
+
+// caller returns the quota key for a request: the authenticated principal,
+// or the client IP for anonymous requests. It is empty when neither is known.
+func caller(ctx context.Context) string {
+	if actor := getActor(ctx); actor != "" {
+		return actor
+	}
+	if ip := GetClientIP(ctx); ip != "" {
+		return "ip:" + ip
+	}
+	return ""
+}
+
+// check rejects a call to procedure with ResourceExhausted when its caller is
+// over their quota, setting Retry-After to the seconds to wait
+func (q *KeyQuotas) check(ctx context.Context, procedure string) error {
+	key := caller(ctx)
+	if key == "" || strings.HasSuffix(procedure, "/HealthCheck") {
+		return nil
+	}
+
+	retryAfter, ok := q.allow(key)
+	if ok {
+		return nil
+	}
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	err := connect.NewError(connect.CodeResourceExhausted,
+		fmt.Errorf("%s is over its quota; retry in %ds", key, seconds))
+	err.Meta().Set("Retry-After", strconv.Itoa(seconds))
+	return err
+}
+
+// Interceptor returns a Connect interceptor that enforces the quotas on unary
+// and streaming RPCs. It must run after the interceptor that authenticates
+// callers.
+func (q *KeyQuotas) Interceptor() connect.Interceptor {
+	return &quotaInterceptor{quotas: q}
+}
+
+// quotaInterceptor implements KeyQuotas.Interceptor
+type quotaInterceptor struct {
+	quotas *KeyQuotas
+}
+
+// WrapUnary enforces quotas before unary RPCs run
+func (i *quotaInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.quotas.check(ctx, req.Spec().Procedure); err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *quotaInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler enforces quotas before streaming RPCs run
+func (i *quotaInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.quotas.check(ctx, conn.Spec().Procedure); err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
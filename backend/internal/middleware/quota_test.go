@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// asKey returns ctx authenticated as the holder of an API key, as APIKeyAuth does
+func asKey(ctx context.Context, key string) context.Context {
+	return WithActor(ctx, APIKeyPrincipal(key).String())
+}
+
+func TestKeyQuotas_OneKeyOverQuota(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	quotas := NewKeyQuotas(map[string]int{"busy-key": 3, "*": 10}, time.Minute)
+	quotas.now = func() time.Time { return now }
+
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := quotas.Interceptor().WrapUnary(next)
+	call := func(key string) error {
+		_, err := handler(asKey(context.Background(), key), connect.NewRequest(&emptypb.Empty{}))
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(10 * time.Second)
+		if err := call("busy-key"); err != nil {
+			t.Fatalf("Expected call %d within quota to succeed, got %v", i+1, err)
+		}
+		if err := call("quiet-key"); err != nil {
+			t.Fatalf("Expected quiet key to stay under its quota, got %v", err)
+		}
+	}
+
+	err := call("busy-key")
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted over quota, got %v", err)
+	}
+	// The first call, 20s ago, leaves the window in 40s
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) || connectErr.Meta().Get("Retry-After") != "40" {
+		t.Errorf("Expected Retry-After 40, got %v", err)
+	}
+	if strings.Contains(err.Error(), "busy-key") {
+		t.Errorf("Expected the error not to reveal the key, got %v", err)
+	}
+	if err := call("quiet-key"); err != nil {
+		t.Errorf("Expected quiet key to be unaffected, got %v", err)
+	}
+
+	now = now.Add(40 * time.Second)
+	if err := call("busy-key"); err != nil {
+		t.Errorf("Expected a call once the oldest left the window to succeed, got %v", err)
+	}
+}
+
+func TestKeyQuotas_Exemptions(t *testing.T) {
+	quotas := NewKeyQuotas(map[string]int{"*": 1}, time.Minute)
+	ctx := asKey(context.Background(), "key")
+
+	if err := quotas.check(ctx, "/todo.v1.TodoService/ListTasks"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := quotas.check(ctx, "/todo.v1.TodoService/HealthCheck"); err != nil {
+		t.Errorf("Expected HealthCheck to be exempt, got %v", err)
+	}
+	if err := quotas.check(ctx, "/todo.v1.TodoService/ListTasks"); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("Expected the second call to be limited, got %v", err)
+	}
+
+	unlisted := NewKeyQuotas(map[string]int{"listed": 1}, time.Minute)
+	for i := 0; i < 3; i++ {
+		if err := unlisted.check(asKey(context.Background(), "unlisted"), "/todo.v1.TodoService/ListTasks"); err != nil {
+			t.Fatalf("Expected keys without a quota to be unlimited, got %v", err)
+		}
+	}
+}
+
+func TestKeyQuotas_UnauthenticatedCallers(t *testing.T) {
+	quotas := NewKeyQuotas(map[string]int{"vip-key": 100, "*": 2}, time.Minute)
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+	handler := quotas.Interceptor().WrapUnary(next)
+	call := func(ip, key string) error {
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set(APIKeyHeader, key)
+		_, err := handler(WithClientIP(context.Background(), ip), req)
+		return err
+	}
+
+	// An unverified X-API-Key header neither earns a key's quota nor, by
+	// changing it on every call, a fresh one
+	for i, key := range []string{"vip-key", "random-1"} {
+		if err := call("203.0.113.7", key); err != nil {
+			t.Fatalf("Expected call %d within the anonymous quota to succeed, got %v", i+1, err)
+		}
+	}
+	if err := call("203.0.113.7", "random-2"); connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("Expected the client IP to be limited by the * quota, got %v", err)
+	}
+	if err := call("198.51.100.1", ""); err != nil {
+		t.Errorf("Expected another client IP to have its own quota, got %v", err)
+	}
+}
+
+func TestKeyQuotas_EvictsIdleKeys(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	quotas := NewKeyQuotas(map[string]int{"*": 5}, time.Minute)
+	quotas.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "c"} {
+		quotas.allow(key)
+	}
+	now = now.Add(30 * time.Second)
+	quotas.allow("a")
+
+	now = now.Add(45 * time.Second)
+	quotas.allow("d")
+
+	if len(quotas.keys) != 2 || quotas.keys["a"] == nil || quotas.keys["d"] == nil {
+		t.Errorf("Expected only the recently used keys a and d to be tracked, got %v", quotas.keys)
+	}
+}
+
+func TestKeyQuotas_CapsTrackedCallers(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	quotas := NewKeyQuotas(map[string]int{"*": 5}, time.Minute)
+	quotas.now = func() time.Time { return now }
+	quotas.maxCallers = 3
+
+	for _, key := range []string{"a", "b", "c"} {
+		now = now.Add(time.Second)
+		quotas.allow(key)
+	}
+	now = now.Add(time.Second)
+	quotas.allow("a")
+	now = now.Add(time.Second)
+	quotas.allow("d")
+
+	// b was the idlest when d arrived
+	if len(quotas.keys) != 3 || quotas.keys["b"] != nil || quotas.keys["d"] == nil {
+		t.Errorf("Expected b to make room for d, got %v", quotas.keys)
+	}
+}
+
+func TestParseAPIKeyQuotas(t *testing.T) {
+	quotas, err := ParseAPIKeyQuotas(" alpha=60, beta = 5 ,*=100,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(quotas) != 3 || quotas["alpha"] != 60 || quotas["beta"] != 5 || quotas["*"] != 100 {
+		t.Errorf("Unexpected quotas %v", quotas)
+	}
+
+	if quotas, err := ParseAPIKeyQuotas(""); err != nil || len(quotas) != 0 {
+		t.Errorf("Expected no quotas for an empty value, got %v, %v", quotas, err)
+	}
+	if NewKeyQuotas(nil, time.Minute).Enabled() {
+		t.Error("Expected empty quotas to be disabled")
+	}
+
+	for _, value := range []string{"alpha", "=5", "alpha=0", "alpha=-1", "alpha=many"} {
+		if _, err := ParseAPIKeyQuotas(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
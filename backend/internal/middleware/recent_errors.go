@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// RecentError is a captured error-level log entry
+type RecentError struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error,omitempty"`
+	Code      string    `json:"code,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+}
+
+// RecentErrors is a fixed-size ring buffer of the most recent errors
+type RecentErrors struct {
+	mu      sync.Mutex
+	entries []RecentError
+	next    int
+	full    bool
+}
+
+// NewRecentErrors creates a buffer holding the last capacity errors
+func NewRecentErrors(capacity int) *RecentErrors {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &RecentErrors{entries: make([]RecentError, capacity)}
+}
+
+// Add records an error, overwriting the oldest once the buffer is full
+func (re *RecentErrors) Add(entry RecentError) {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	re.entries[re.next] = entry
+	re.next = (re.next + 1) % len(re.entries)
+	if re.next == 0 {
+		re.full = true
+	}
+}
+
+// Snapshot returns the recorded errors, newest first
+func (re *RecentErrors) Snapshot() []RecentError {
+	re.mu.Lock()
+	defer re.mu.Unlock()
+
+	count := re.next
+	if re.full {
+		count = len(re.entries)
+	}
+
+	snapshot := make([]RecentError, 0, count)
+	for i := 1; i <= count; i++ {
+		snapshot = append(snapshot, re.entries[(re.next-i+len(re.entries))%len(re.entries)])
+	}
+	return snapshot
+}
+
+// Handler serves the recorded errors as JSON
+func (re *RecentErrors) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors": re.Snapshot(),
+		})
+	})
+}
+
+// recordingLogger passes every call through and captures Error calls
+type recordingLogger struct {
+	Logger
+	recent *RecentErrors
+}
+
+// NewRecordingLogger wraps logger so its error-level entries are also kept in recent
+func NewRecordingLogger(logger Logger, recent *RecentErrors) Logger {
+	return &recordingLogger{Logger: logger, recent: recent}
+}
+
+// Debug passes through when the wrapped logger has a debug level, so
+// consolidated request logs keep their detail
+func (rl *recordingLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	if debug, ok := rl.Logger.(interface {
+		Debug(ctx context.Context, msg string, fields map[string]interface{})
+	}); ok {
+		debug.Debug(ctx, msg, fields)
+	}
+}
+
+// Error logs through the wrapped logger and records the entry
+func (rl *recordingLogger) Error(ctx context.Context, msg string, err error, fields map[string]interface{}) {
+	rl.Logger.Error(ctx, msg, err, fields)
+
+	entry := RecentError{
+		Timestamp: time.Now().UTC(),
+		Message:   msg,
+		RequestID: getRequestID(ctx),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		if code := connect.CodeOf(err); code != connect.CodeUnknown {
+			entry.Code = code.String()
+		}
+	}
+	if code, ok := fields["code"]; ok && entry.Code == "" {
+		entry.Code = fmt.Sprint(code)
+	}
+	rl.recent.Add(entry)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+)
+
+func TestRecentErrors_RingBuffer(t *testing.T) {
+	recent := NewRecentErrors(3)
+	for i := 1; i <= 5; i++ {
+		recent.Add(RecentError{Message: fmt.Sprintf("error %d", i)})
+	}
+
+	snapshot := recent.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(snapshot))
+	}
+	for i, expected := range []string{"error 5", "error 4", "error 3"} {
+		if snapshot[i].Message != expected {
+			t.Errorf("Entry %d: expected %q, got %q", i, expected, snapshot[i].Message)
+		}
+	}
+}
+
+func TestRecordingLogger_DebugEndpoint(t *testing.T) {
+	recent := NewRecentErrors(10)
+	logger := &mockLogger{}
+	handler := NewErrorHandler(NewRecordingLogger(logger, recent))
+
+	// Trigger errors through the error handler paths
+	handler.HandleRepositoryError(errors.New("task not found: 123"))
+	ctx := WithRequestID(context.Background(), "req-42")
+	handler.logger.Error(ctx, "RPC error", connect.NewError(connect.CodeInternal, errors.New("boom")), nil)
+	handler.logger.Info(ctx, "RPC response", nil)
+
+	if len(logger.errorMessages) != 2 {
+		t.Errorf("Expected errors to reach the wrapped logger, got %d", len(logger.errorMessages))
+	}
+
+	w := httptest.NewRecorder()
+	recent.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var body struct {
+		Errors []RecentError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(body.Errors) != 2 {
+		t.Fatalf("Expected 2 recorded errors, got %d", len(body.Errors))
+	}
+
+	latest := body.Errors[0]
+	if latest.Message != "RPC error" || latest.Code != "internal" || latest.RequestID != "req-42" {
+		t.Errorf("Unexpected latest entry: %+v", latest)
+	}
+	if body.Errors[1].Message != "Repository error" {
+		t.Errorf("Expected repository error second, got %+v", body.Errors[1])
+	}
+
+	w = httptest.NewRecorder()
+	recent.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/errors", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 for POST, got %d", w.Code)
+	}
+}
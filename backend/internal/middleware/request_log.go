@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// RequestLogMode selects how many log lines a request produces
+type RequestLogMode string
+
+const (
+	// RequestLogDetailed logs the HTTP request and response and the RPC
+	// request and response at info, up to four lines per call (the default)
+	RequestLogDetailed RequestLogMode = "detailed"
+	// RequestLogConsolidated logs one "Request completed" line per request at
+	// info and moves the detailed lines to debug
+	RequestLogConsolidated RequestLogMode = "consolidated"
+)
+
+// ParseRequestLogMode parses a REQUEST_LOG_MODE value; empty is detailed
+func ParseRequestLogMode(value string) (RequestLogMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "detailed":
+		return RequestLogDetailed, nil
+	case "consolidated":
+		return RequestLogConsolidated, nil
+	default:
+		return RequestLogDetailed, fmt.Errorf("unknown request log mode %q", value)
+	}
+}
+
+// SetRequestLogMode selects detailed or consolidated request logs
+func (eh *ErrorHandler) SetRequestLogMode(mode RequestLogMode) {
+	eh.requestLogMode = mode
+}
+
+// consolidated reports whether requests get a single completion log
+func (eh *ErrorHandler) consolidated() bool {
+	return eh.requestLogMode == RequestLogConsolidated
+}
+
+// logDetail logs a per-stage request log line: at info in detailed mode, and
+// at debug in consolidated mode when the logger has a debug level
+func (eh *ErrorHandler) logDetail(ctx context.Context, msg string, fields map[string]interface{}) {
+	if !eh.consolidated() {
+		eh.logger.Info(ctx, msg, fields)
+		return
+	}
+	if debug, ok := eh.logger.(interface {
+		Debug(ctx context.Context, msg string, fields map[string]interface{})
+	}); ok {
+		debug.Debug(ctx, msg, fields)
+	}
+}
+
+// rpcCode names the outcome of an RPC: "ok" or its Connect code
+func rpcCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return connect.CodeOf(err).String()
+}
+
+// requestSummary carries what the RPC interceptor learns back out to the
+// logging middleware for the completion log
+type requestSummary struct {
+	procedure string
+	code      string
+}
+
+type requestSummaryKey struct{}
+
+// withRequestSummary adds summary to ctx for the interceptor to fill in
+func withRequestSummary(ctx context.Context, summary *requestSummary) context.Context {
+	return context.WithValue(ctx, requestSummaryKey{}, summary)
+}
+
+// recordRPCOutcome notes the procedure and code of the RPC serving ctx's
+// request, when the logging middleware is collecting a summary
+func recordRPCOutcome(ctx context.Context, procedure, code string) {
+	if summary, ok := ctx.Value(requestSummaryKey{}).(*requestSummary); ok {
+		summary.procedure = procedure
+		summary.code = code
+	}
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// debugMockLogger is a mockLogger that also records debug calls
+type debugMockLogger struct {
+	mockLogger
+	debugMessages []LogCall
+}
+
+func (m *debugMockLogger) Debug(ctx context.Context, msg string, fields map[string]interface{}) {
+	m.debugMessages = append(m.debugMessages, LogCall{Message: msg, Fields: fields})
+}
+
+func TestParseRequestLogMode(t *testing.T) {
+	for value, want := range map[string]RequestLogMode{"": RequestLogDetailed, "detailed": RequestLogDetailed, " Consolidated ": RequestLogConsolidated} {
+		if got, err := ParseRequestLogMode(value); err != nil || got != want {
+			t.Errorf("ParseRequestLogMode(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseRequestLogMode("verbose"); err == nil {
+		t.Error("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestLoggingMiddleware_Consolidated(t *testing.T) {
+	logger := &debugMockLogger{}
+	eh := NewErrorHandler(logger)
+
+	var rpcErr error
+	ping := connect.NewUnaryHandler(
+		"/test.v1.PingService/Ping",
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(eh.ConnectErrorInterceptor()),
+	)
+	handler := RequestIDMiddleware(eh.LoggingMiddleware(ping))
+
+	call := func() {
+		logger.reset()
+		logger.debugMessages = nil
+		req := httptest.NewRequest(http.MethodPost, "/test.v1.PingService/Ping", strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("detailed mode logs each stage at info", func(t *testing.T) {
+		call()
+		if len(logger.infoMessages) != 4 {
+			t.Errorf("Expected 4 info logs, got %d", len(logger.infoMessages))
+		}
+	})
+
+	eh.SetRequestLogMode(RequestLogConsolidated)
+
+	t.Run("one completion log at info", func(t *testing.T) {
+		call()
+		if len(logger.infoMessages) != 1 {
+			t.Fatalf("Expected exactly 1 info log, got %d: %v", len(logger.infoMessages), logger.infoMessages)
+		}
+		entry := logger.infoMessages[0]
+		if entry.Message != "Request completed" {
+			t.Errorf("Expected 'Request completed', got %q", entry.Message)
+		}
+		for key, want := range map[string]interface{}{
+			"method":      http.MethodPost,
+			"path":        "/test.v1.PingService/Ping",
+			"procedure":   "/test.v1.PingService/Ping",
+			"code":        "ok",
+			"status_code": http.StatusOK,
+		} {
+			if entry.Fields[key] != want {
+				t.Errorf("Expected %s %v, got %v", key, want, entry.Fields[key])
+			}
+		}
+		for _, key := range []string{"duration_ms", "response_bytes", "request_id"} {
+			if _, ok := entry.Fields[key]; !ok {
+				t.Errorf("Expected field %s", key)
+			}
+		}
+		if len(logger.debugMessages) != 4 {
+			t.Errorf("Expected the 4 detailed logs at debug, got %d", len(logger.debugMessages))
+		}
+	})
+
+	t.Run("failed RPC completes at error level", func(t *testing.T) {
+		rpcErr = connect.NewError(connect.CodeNotFound, errors.New("no such task"))
+		defer func() { rpcErr = nil }()
+
+		call()
+		if len(logger.infoMessages) != 0 {
+			t.Errorf("Expected no info logs, got %d", len(logger.infoMessages))
+		}
+		var completion *LogCall
+		for i, entry := range logger.errorMessages {
+			if entry.Message == "Request completed" {
+				completion = &logger.errorMessages[i]
+			}
+		}
+		if completion == nil || completion.Fields["code"] != "not_found" {
+			t.Errorf("Expected an error-level completion log with code not_found, got %v", logger.errorMessages)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	expected := map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"Referrer-Policy":         "no-referrer",
+		"Content-Security-Policy": "default-src 'none'; frame-ancestors 'none'",
+	}
+
+	handlers := map[string]http.HandlerFunc{
+		"success": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		},
+		"error": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+		},
+		"panic": func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		},
+	}
+
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			stack := NewMiddlewareStack(&mockLogger{})
+			w := httptest.NewRecorder()
+
+			stack.WrapHandler(h).ServeHTTP(w, httptest.NewRequest("POST", "/test", nil))
+
+			for header, value := range expected {
+				if got := w.Header().Get(header); got != value {
+					t.Errorf("Expected %s %q, got %q", header, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDisabledSecurityHeaders(t *testing.T) {
+	headers, err := ParseDisabledSecurityHeaders(DefaultSecurityHeaders(), "X-Frame-Options, content-security-policy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if headers.FrameDeny || headers.ContentSecurityPolicy != "" {
+		t.Errorf("Expected frame and CSP headers to be disabled: %+v", headers)
+	}
+	if !headers.NoSniff || !headers.NoReferrer {
+		t.Errorf("Expected other headers to stay enabled: %+v", headers)
+	}
+
+	w := httptest.NewRecorder()
+	SecurityHeadersMiddleware(headers, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Header().Get("X-Frame-Options") != "" || w.Header().Get("Content-Security-Policy") != "" {
+		t.Error("Expected disabled headers to be omitted")
+	}
+	if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Error("Expected enabled header to be set")
+	}
+
+	if _, err := ParseDisabledSecurityHeaders(DefaultSecurityHeaders(), "x-powered-by"); err == nil {
+		t.Error("Expected error for unknown header")
+	}
+}
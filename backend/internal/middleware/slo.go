@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LatencyBudgets defines how long each RPC is expected to take. Calls that run
+// longer are logged as SLO violations.
+type LatencyBudgets struct {
+	// Default applies to procedures without an explicit budget (zero disables it)
+	Default time.Duration
+	// Procedures maps a full procedure ("/todo.v1.TodoService/ListTasks") or a
+	// bare method name ("ListTasks") to its budget
+	Procedures map[string]time.Duration
+}
+
+// DefaultLatencyBudgets returns the budgets used when none are configured
+func DefaultLatencyBudgets() LatencyBudgets {
+	return LatencyBudgets{
+		Default: 500 * time.Millisecond,
+		Procedures: map[string]time.Duration{
+			"CreateTask":  100 * time.Millisecond,
+			"GetTask":     50 * time.Millisecond,
+			"ListTasks":   300 * time.Millisecond,
+			"UpdateTask":  100 * time.Millisecond,
+			"DeleteTask":  100 * time.Millisecond,
+			"DeleteTasks": 300 * time.Millisecond,
+			"HealthCheck": 50 * time.Millisecond,
+		},
+	}
+}
+
+// BudgetFor returns the latency budget for a procedure
+func (b LatencyBudgets) BudgetFor(procedure string) time.Duration {
+	if budget, ok := b.Procedures[procedure]; ok {
+		return budget
+	}
+	method := procedure[strings.LastIndex(procedure, "/")+1:]
+	if budget, ok := b.Procedures[method]; ok {
+		return budget
+	}
+	return b.Default
+}
+
+// ParseLatencyBudgets parses overrides in the form "CreateTask=100ms,ListTasks=300ms"
+// on top of the default budgets
+func ParseLatencyBudgets(spec string) (LatencyBudgets, error) {
+	budgets := DefaultLatencyBudgets()
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return LatencyBudgets{}, fmt.Errorf("invalid latency budget %q: expected name=duration", pair)
+		}
+		budget, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return LatencyBudgets{}, fmt.Errorf("invalid latency budget %q: %w", pair, err)
+		}
+		budgets.Procedures[strings.TrimSpace(name)] = budget
+	}
+	return budgets, nil
+}
+
+// checkLatencyBudget logs a warning when an RPC exceeded its latency budget
+func (eh *ErrorHandler) checkLatencyBudget(ctx context.Context, procedure string, duration time.Duration) {
+	budget := eh.latencyBudgets.BudgetFor(procedure)
+	if budget <= 0 || duration <= budget {
+		return
+	}
+
+	eh.logger.Warn(ctx, "RPC exceeded latency budget", withRequestIDField(ctx, map[string]interface{}{
+		"procedure":     procedure,
+		"duration_ms":   duration.Milliseconds(),
+		"budget_ms":     budget.Milliseconds(),
+		"slo_violation": true,
+	}))
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestLatencyBudgets_BudgetFor(t *testing.T) {
+	budgets := LatencyBudgets{
+		Default: time.Second,
+		Procedures: map[string]time.Duration{
+			"ListTasks":                       300 * time.Millisecond,
+			"/todo.v1.TodoService/CreateTask": 100 * time.Millisecond,
+		},
+	}
+
+	tests := []struct {
+		procedure string
+		expected  time.Duration
+	}{
+		{"/todo.v1.TodoService/ListTasks", 300 * time.Millisecond},
+		{"/todo.v1.TodoService/CreateTask", 100 * time.Millisecond},
+		{"/todo.v1.TodoService/GetTask", time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := budgets.BudgetFor(tt.procedure); got != tt.expected {
+			t.Errorf("BudgetFor(%s) = %v, expected %v", tt.procedure, got, tt.expected)
+		}
+	}
+}
+
+func TestParseLatencyBudgets(t *testing.T) {
+	budgets, err := ParseLatencyBudgets("CreateTask=250ms, Slow=2s")
+	if err != nil {
+		t.Fatalf("Expected valid spec to parse, got %v", err)
+	}
+	if budgets.BudgetFor("/todo.v1.TodoService/CreateTask") != 250*time.Millisecond {
+		t.Error("Expected CreateTask override to apply")
+	}
+	if budgets.BudgetFor("/todo.v1.TodoService/ListTasks") != 300*time.Millisecond {
+		t.Error("Expected default ListTasks budget to be kept")
+	}
+
+	if _, err := ParseLatencyBudgets("CreateTask"); err == nil {
+		t.Error("Expected missing duration to fail")
+	}
+	if _, err := ParseLatencyBudgets("CreateTask=fast"); err == nil {
+		t.Error("Expected malformed duration to fail")
+	}
+}
+
+func TestConnectErrorInterceptor_SLOViolation(t *testing.T) {
+	logger := &mockLogger{}
+	errorHandler := NewErrorHandler(logger)
+	errorHandler.SetLatencyBudgets(LatencyBudgets{
+		Procedures: map[string]time.Duration{"Slow": 10 * time.Millisecond},
+	})
+
+	serve := func(procedure string, delay time.Duration) {
+		handler := connect.NewUnaryHandler(procedure,
+			func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+				time.Sleep(delay)
+				return connect.NewResponse(&emptypb.Empty{}), nil
+			},
+			connect.WithInterceptors(errorHandler.ConnectErrorInterceptor()),
+		)
+
+		req := httptest.NewRequest(http.MethodPost, procedure, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	t.Run("call within budget", func(t *testing.T) {
+		logger.reset()
+		serve("/test.v1.TestService/Fast", 20*time.Millisecond)
+
+		if len(logger.warnMessages) != 0 {
+			t.Errorf("Expected no SLO warning for unbudgeted procedure, got %d", len(logger.warnMessages))
+		}
+	})
+
+	t.Run("call exceeding budget", func(t *testing.T) {
+		logger.reset()
+		serve("/test.v1.TestService/Slow", 30*time.Millisecond)
+
+		if len(logger.warnMessages) != 1 {
+			t.Fatalf("Expected 1 SLO warning, got %d", len(logger.warnMessages))
+		}
+		fields := logger.warnMessages[0].Fields
+		if fields["slo_violation"] != true {
+			t.Error("Expected slo_violation field to be true")
+		}
+		if fields["budget_ms"] != int64(10) {
+			t.Errorf("Expected budget_ms 10, got %v", fields["budget_ms"])
+		}
+		if fields["procedure"] != "/test.v1.TestService/Slow" {
+			t.Errorf("Expected procedure to be logged, got %v", fields["procedure"])
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Names Connect registers its JSON codec under; both must be replaced for
+// strict decoding to apply to every JSON request
+const (
+	jsonCodecName            = "json"
+	jsonCharsetUTF8CodecName = "json; charset=utf-8"
+)
+
+// StrictJSON replaces Connect's JSON codecs with ones that reject unknown
+// fields instead of discarding them, so a misspelled field name fails the
+// request with invalid_argument rather than being silently ignored. Binary
+// protobuf requests are unaffected.
+func StrictJSON() connect.Option {
+	return connect.WithOptions(
+		connect.WithCodec(strictJSONCodec{name: jsonCodecName}),
+		connect.WithCodec(strictJSONCodec{name: jsonCharsetUTF8CodecName}),
+	)
+}
+
+// strictJSONCodec is Connect's protojson codec without DiscardUnknown
+type strictJSONCodec struct {
+	name string
+}
+
+// Name returns the codec name Connect negotiates on
+func (c strictJSONCodec) Name() string {
+	return c.name
+}
+
+// Marshal encodes a message exactly as Connect's default JSON codec does
+func (c strictJSONCodec) Marshal(message any) ([]byte, error) {
+	protoMessage, ok := message.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T doesn't implement proto.Message", message)
+	}
+	return protojson.MarshalOptions{}.Marshal(protoMessage)
+}
+
+// Unmarshal decodes a message, failing on fields the schema doesn't define
+func (c strictJSONCodec) Unmarshal(data []byte, message any) error {
+	protoMessage, ok := message.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T doesn't implement proto.Message", message)
+	}
+	if len(data) == 0 {
+		return errors.New("zero-length payload is not a valid JSON object")
+	}
+	return protojson.UnmarshalOptions{}.Unmarshal(data, protoMessage)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestStrictJSONCodec(t *testing.T) {
+	codec := strictJSONCodec{name: jsonCodecName}
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		var req todov1.CreateTaskRequest
+		err := codec.Unmarshal([]byte(`{"title":"Buy milk","priorty":"high"}`), &req)
+		if err == nil || !strings.Contains(err.Error(), "priorty") {
+			t.Errorf("Expected an error naming the unknown field, got %v", err)
+		}
+	})
+
+	t.Run("accepts known fields", func(t *testing.T) {
+		var req todov1.CreateTaskRequest
+		if err := codec.Unmarshal([]byte(`{"title":"Buy milk"}`), &req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if req.Title != "Buy milk" {
+			t.Errorf("Expected title 'Buy milk', got %q", req.Title)
+		}
+	})
+
+	t.Run("round trips", func(t *testing.T) {
+		data, err := codec.Marshal(&todov1.CreateTaskRequest{Title: "Buy milk"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var req todov1.CreateTaskRequest
+		if err := codec.Unmarshal(data, &req); err != nil || req.Title != "Buy milk" {
+			t.Errorf("Expected round trip to keep the title, got %q (%v)", req.Title, err)
+		}
+	})
+
+	t.Run("rejects empty payload", func(t *testing.T) {
+		var req todov1.CreateTaskRequest
+		if err := codec.Unmarshal(nil, &req); err == nil {
+			t.Error("Expected an empty payload to be rejected")
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// TenantHeader carries the tenant a request acts on
+const TenantHeader = "X-Tenant-ID"
+
+// tenantIDPattern restricts tenant IDs to short slugs that are safe to log and store
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// ValidateTenantID reports whether id is a well-formed tenant ID
+func ValidateTenantID(id string) error {
+	if !tenantIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid tenant id %q: expected 1-64 letters, digits, '-' or '_'", id)
+	}
+	return nil
+}
+
+// TenantInterceptor requires every RPC to name its tenant in the X-Tenant-ID
+// header and stores it in the context, where the repository uses it to scope
+// queries. Requests without the header fall back to defaultTenant, or are
+// rejected with Unauthenticated when it is empty; malformed IDs are rejected
+// with InvalidArgument. HealthCheck, GetVersion, DebugEcho and DiagnoseStorage
+// are exempt so probes and support tooling need no tenant. Streaming RPCs are
+// scoped the same way as unary ones.
+func TenantInterceptor(defaultTenant string) connect.Interceptor {
+	return &tenantInterceptor{defaultTenant: defaultTenant}
+}
+
+// tenantInterceptor implements TenantInterceptor
+type tenantInterceptor struct {
+	defaultTenant string
+}
+
+// WrapUnary scopes unary RPCs to the request's tenant
+func (i *tenantInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		ctx, err := i.scope(ctx, req.Spec().Procedure, req.Header())
+		if err != nil {
+			return nil, err
+		}
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (i *tenantInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler scopes streaming RPCs to the request's tenant
+func (i *tenantInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		ctx, err := i.scope(ctx, conn.Spec().Procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		return next(ctx, conn)
+	}
+}
+
+// scope returns ctx carrying the tenant named by header, or the error that
+// rejects the request
+func (i *tenantInterceptor) scope(ctx context.Context, procedure string, header http.Header) (context.Context, error) {
+	if strings.HasSuffix(procedure, "/HealthCheck") ||
+		strings.HasSuffix(procedure, "/GetVersion") || strings.HasSuffix(procedure, "/DebugEcho") ||
+		strings.HasSuffix(procedure, "/DiagnoseStorage") {
+		return ctx, nil
+	}
+
+	tenant := strings.TrimSpace(header.Get(TenantHeader))
+	if tenant == "" {
+		tenant = i.defaultTenant
+	}
+	if tenant == "" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, errors.New("missing "+TenantHeader+" header"))
+	}
+	if err := ValidateTenantID(tenant); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	return WithTenant(ctx, tenant), nil
+}
+
+// WithTenant adds the tenant to the context
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, "tenant_id", tenant)
+}
+
+// GetTenant extracts the tenant from context, or "" when the request is not tenant scoped
+func GetTenant(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if tenant, ok := ctx.Value("tenant_id").(string); ok {
+		return tenant
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestTenantInterceptor(t *testing.T) {
+	var gotTenant string
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		gotTenant = GetTenant(ctx)
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}
+
+	tests := []struct {
+		name          string
+		defaultTenant string
+		header        string
+		wantTenant    string
+		wantCode      connect.Code
+	}{
+		{name: "header sets tenant", header: "acme", wantTenant: "acme"},
+		{name: "header overrides default", defaultTenant: "default", header: "acme", wantTenant: "acme"},
+		{name: "default tenant when header missing", defaultTenant: "default", wantTenant: "default"},
+		{name: "missing tenant without default", wantCode: connect.CodeUnauthenticated},
+		{name: "malformed tenant", header: "acme/../other", wantCode: connect.CodeInvalidArgument},
+		{name: "overlong tenant", header: string(make([]byte, 65)), wantCode: connect.CodeInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTenant = ""
+			req := connect.NewRequest(&emptypb.Empty{})
+			if tt.header != "" {
+				req.Header().Set(TenantHeader, tt.header)
+			}
+
+			_, err := TenantInterceptor(tt.defaultTenant).WrapUnary(next)(context.Background(), req)
+
+			if tt.wantCode != 0 {
+				if connect.CodeOf(err) != tt.wantCode {
+					t.Errorf("Expected code %v, got %v", tt.wantCode, err)
+				}
+				if gotTenant != "" {
+					t.Error("Expected rejected request not to reach the handler")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("Expected tenant %q, got %q", tt.wantTenant, gotTenant)
+			}
+		})
+	}
+}
+
+// streamingConn is a StreamingHandlerConn carrying only a procedure, headers
+// and trailers
+type streamingConn struct {
+	connect.StreamingHandlerConn
+	procedure string
+	header    http.Header
+	trailer   http.Header
+}
+
+func (c *streamingConn) Spec() connect.Spec         { return connect.Spec{Procedure: c.procedure} }
+func (c *streamingConn) RequestHeader() http.Header { return c.header }
+
+func (c *streamingConn) ResponseTrailer() http.Header {
+	if c.trailer == nil {
+		c.trailer = http.Header{}
+	}
+	return c.trailer
+}
+
+func TestTenantInterceptor_Streaming(t *testing.T) {
+	var gotTenant string
+	next := func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		gotTenant = GetTenant(ctx)
+		return nil
+	}
+	handler := TenantInterceptor("").WrapStreamingHandler(next)
+
+	conn := &streamingConn{procedure: "/todo.v1.TodoService/ListTasksStream", header: http.Header{}}
+	conn.header.Set(TenantHeader, "acme")
+	if err := handler(context.Background(), conn); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if gotTenant != "acme" {
+		t.Errorf("Expected stream scoped to tenant 'acme', got %q", gotTenant)
+	}
+
+	gotTenant = ""
+	conn.header.Del(TenantHeader)
+	if err := handler(context.Background(), conn); connect.CodeOf(err) != connect.CodeUnauthenticated {
+		t.Errorf("Expected a stream without a tenant to be rejected, got %v", err)
+	}
+	if gotTenant != "" {
+		t.Error("Expected rejected stream not to reach the handler")
+	}
+}
+
+func TestMiddlewareStack_EnableTenancy(t *testing.T) {
+	stack := NewMiddlewareStack(NewStructuredLogger(LevelInfo))
+	stack.EnableTenancy("default")
+	if got := len(stack.GetConnectInterceptors()); got != 2 {
+		t.Errorf("Expected 2 interceptors with tenancy enabled, got %d", got)
+	}
+}
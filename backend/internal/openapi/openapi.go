@@ -0,0 +1,257 @@
+// Package openapi describes the Connect JSON surface of a service as an
+// OpenAPI 3 document, generated from the compiled proto descriptors so it
+// cannot drift from the service definition.
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// errorSchemaName is the component describing Connect's JSON error body
+const errorSchemaName = "connect.Error"
+
+// Document is the subset of OpenAPI 3.0 emitted for a Connect service
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem holds the operations on one path; Connect unary RPCs are POSTs
+type PathItem struct {
+	Post *Operation `json:"post,omitempty"`
+}
+
+// Operation describes one RPC
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// RequestBody describes an RPC request message
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes an RPC response message or error
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType wraps a schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the shared message schemas
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the subset of JSON Schema used for proto messages
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Build generates the document for a service's unary RPCs. Schemas follow the
+// protojson mapping that Connect uses for application/json.
+func Build(service protoreflect.ServiceDescriptor, version string) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: string(service.FullName()), Version: version},
+		Paths:   map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{
+			errorSchemaName: errorSchema(),
+		}},
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			continue
+		}
+
+		doc.Paths["/"+string(service.FullName())+"/"+string(method.Name())] = PathItem{Post: &Operation{
+			OperationID: string(method.Name()),
+			Summary:     comment(method),
+			Tags:        []string{string(service.Name())},
+			RequestBody: &RequestBody{
+				Required: true,
+				Content:  jsonContent(doc.messageRef(method.Input())),
+			},
+			Responses: map[string]Response{
+				"200": {Description: "Success", Content: jsonContent(doc.messageRef(method.Output()))},
+				"default": {
+					Description: "Connect error",
+					Content:     jsonContent(&Schema{Ref: "#/components/schemas/" + errorSchemaName}),
+				},
+			},
+		}}
+	}
+
+	return doc
+}
+
+// Handler serves the document as JSON. It is marshalled once up front.
+func Handler(doc *Document) (http.Handler, error) {
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}), nil
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// messageRef registers the message (and everything it references) as a
+// component and returns a reference to it
+func (doc *Document) messageRef(message protoreflect.MessageDescriptor) *Schema {
+	if schema := wellKnownSchema(message); schema != nil {
+		return schema
+	}
+
+	name := string(message.FullName())
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := doc.Components.Schemas[name]; ok {
+		return ref
+	}
+
+	schema := &Schema{Type: "object", Description: comment(message), Properties: map[string]*Schema{}}
+	// Register before walking fields so recursive messages terminate
+	doc.Components.Schemas[name] = schema
+
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldSchema := doc.fieldSchema(field)
+		if fieldSchema.Ref == "" {
+			fieldSchema.Description = comment(field)
+		}
+		schema.Properties[field.JSONName()] = fieldSchema
+	}
+
+	return ref
+}
+
+// fieldSchema maps a field to its protojson representation
+func (doc *Document) fieldSchema(field protoreflect.FieldDescriptor) *Schema {
+	if field.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: doc.singularSchema(field.MapValue())}
+	}
+	if field.IsList() {
+		return &Schema{Type: "array", Items: doc.singularSchema(field)}
+	}
+	return doc.singularSchema(field)
+}
+
+func (doc *Document) singularSchema(field protoreflect.FieldDescriptor) *Schema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson encodes 64-bit integers as strings
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return &Schema{Type: "string", Enum: names}
+	default:
+		return doc.messageRef(field.Message())
+	}
+}
+
+// wellKnownSchema returns the inline schema for well-known types with a special
+// JSON form, or nil for ordinary messages
+func wellKnownSchema(message protoreflect.MessageDescriptor) *Schema {
+	switch message.FullName() {
+	case "google.protobuf.Timestamp":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &Schema{Type: "string", Description: "Duration in seconds with an s suffix, e.g. 1.5s"}
+	case "google.protobuf.Empty":
+		return &Schema{Type: "object"}
+	}
+	return nil
+}
+
+// errorSchema describes the JSON body Connect returns for errors
+func errorSchema() *Schema {
+	return &Schema{
+		Type:        "object",
+		Description: "Connect error response",
+		Properties: map[string]*Schema{
+			"code": {
+				Type:        "string",
+				Description: "Connect error code, e.g. invalid_argument or not_found",
+			},
+			"message": {Type: "string"},
+			"details": {
+				Type: "array",
+				Items: &Schema{Type: "object", Properties: map[string]*Schema{
+					"type":  {Type: "string"},
+					"value": {Type: "string", Format: "byte"},
+				}},
+			},
+		},
+	}
+}
+
+// comment returns the leading (or trailing) proto comment for a descriptor
+func comment(desc protoreflect.Descriptor) string {
+	loc := desc.ParentFile().SourceLocations().ByDescriptor(desc)
+	text := loc.LeadingComments
+	if text == "" {
+		text = loc.TrailingComments
+	}
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestHandler_ServesTodoService(t *testing.T) {
+	service := todov1.File_todo_v1_todo_proto.Services().ByName("TodoService")
+	handler, err := Handler(Build(service, "test"))
+	if err != nil {
+		t.Fatalf("Failed to build handler: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected application/json, got %q", got)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Served document is not valid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" || doc.Info.Version != "test" {
+		t.Errorf("Unexpected document header: %+v %+v", doc.OpenAPI, doc.Info)
+	}
+
+	for _, method := range []string{"CreateTask", "GetTask", "ListTasks", "UpdateTask", "DeleteTask", "HealthCheck"} {
+		item, ok := doc.Paths["/todo.v1.TodoService/"+method]
+		if !ok || item.Post == nil {
+			t.Errorf("Missing operation %s", method)
+			continue
+		}
+		if _, ok := item.Post.Responses["default"]; !ok {
+			t.Errorf("%s: missing error response", method)
+		}
+	}
+	// Streaming methods have no JSON request/response form to document
+	unary := 0
+	for i := 0; i < service.Methods().Len(); i++ {
+		if method := service.Methods().Get(i); !method.IsStreamingClient() && !method.IsStreamingServer() {
+			unary++
+		}
+	}
+	if got := len(doc.Paths); got != unary {
+		t.Errorf("Expected %d operations, got %d", unary, got)
+	}
+	if _, ok := doc.Paths["/todo.v1.TodoService/ListTasksStream"]; ok {
+		t.Error("Expected the streaming ListTasksStream to be left out")
+	}
+
+	// Every reference must resolve to a component
+	for name, schema := range doc.Components.Schemas {
+		for field, property := range schema.Properties {
+			if property.Items != nil {
+				property = property.Items
+			}
+			if property.Ref == "" {
+				continue
+			}
+			if _, ok := doc.Components.Schemas[property.Ref[len("#/components/schemas/"):]]; !ok {
+				t.Errorf("%s.%s: unresolved reference %s", name, field, property.Ref)
+			}
+		}
+	}
+
+	task := doc.Components.Schemas["todo.v1.Task"]
+	if task == nil {
+		t.Fatal("Missing todo.v1.Task schema")
+	}
+	if got := task.Properties["createdAt"]; got == nil || got.Format != "date-time" {
+		t.Errorf("Expected createdAt as a date-time string, got %+v", got)
+	}
+	if _, ok := doc.Components.Schemas[errorSchemaName]; !ok {
+		t.Error("Missing Connect error schema")
+	}
+}
+
+func TestHandler_RejectsOtherMethods(t *testing.T) {
+	service := todov1.File_todo_v1_todo_proto.Services().ByName("TodoService")
+	handler, err := Handler(Build(service, "test"))
+	if err != nil {
+		t.Fatalf("Failed to build handler: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/openapi.json", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,141 @@
+// Package reminder emits an event when a task with a due date becomes due while
+// still incomplete.
+package reminder
+
+import (
+	"context"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+)
+
+// defaultBatchSize is how many due tasks are claimed per repository call
+const defaultBatchSize = 100
+
+// Sink receives a reminder for each task that has become due
+type Sink interface {
+	Remind(ctx context.Context, task *todov1.Task) error
+}
+
+// SinkFunc adapts a function to Sink
+type SinkFunc func(ctx context.Context, task *todov1.Task) error
+
+// Remind calls f
+func (f SinkFunc) Remind(ctx context.Context, task *todov1.Task) error {
+	return f(ctx, task)
+}
+
+// LogSink writes each reminder as a structured log line (the default sink)
+type LogSink struct {
+	Logger *middleware.StructuredLogger
+}
+
+// Remind logs the due task
+func (s LogSink) Remind(ctx context.Context, task *todov1.Task) error {
+	s.Logger.Info(ctx, "Task is due", map[string]interface{}{
+		"event":    "task_due",
+		"task_id":  task.Id,
+		"title":    task.Title,
+		"due_date": task.DueDate.AsTime().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// Scheduler periodically claims due, incomplete tasks and hands them to a Sink.
+// A task is marked reminded before its sink is called, so each task is
+// reminded at most once even across instances; a failing sink is logged and
+// not retried.
+type Scheduler struct {
+	repo      repository.TodoRepository
+	sink      Sink
+	logger    *middleware.StructuredLogger
+	clock     repository.Clock
+	batchSize int
+}
+
+// Option configures optional behavior of the Scheduler
+type Option func(*Scheduler)
+
+// WithClock sets the time source deciding which tasks are due (default system clock)
+func WithClock(clock repository.Clock) Option {
+	return func(s *Scheduler) {
+		s.clock = clock
+	}
+}
+
+// WithBatchSize sets how many due tasks are claimed per repository call
+func WithBatchSize(size int) Option {
+	return func(s *Scheduler) {
+		s.batchSize = size
+	}
+}
+
+// NewScheduler creates a scheduler; a nil sink logs reminders through logger
+func NewScheduler(repo repository.TodoRepository, sink Sink, logger *middleware.StructuredLogger, opts ...Option) *Scheduler {
+	if sink == nil {
+		sink = LogSink{Logger: logger}
+	}
+	s := &Scheduler{
+		repo:      repo,
+		sink:      sink,
+		logger:    logger,
+		batchSize: defaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run sends reminders every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RunOnce(ctx); err != nil {
+				s.logger.Error(ctx, "Failed to send due-date reminders", err, nil)
+			}
+		}
+	}
+}
+
+// RunOnce sends reminders for every task that is currently due and returns
+// how many were sent
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	ctx = middleware.WithSource(ctx, "reminder.Scheduler")
+	sent := 0
+	for {
+		tasks, err := s.repo.ClaimDueReminders(ctx, s.now(), s.batchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, task := range tasks {
+			if err := s.sink.Remind(ctx, task); err != nil {
+				s.logger.Error(ctx, "Reminder sink failed", err, map[string]interface{}{
+					"task_id": task.Id,
+				})
+				continue
+			}
+			sent++
+		}
+
+		if len(tasks) < s.batchSize {
+			return sent, nil
+		}
+	}
+}
+
+func (s *Scheduler) now() time.Time {
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,108 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+)
+
+// recordingSink remembers every task it was asked to remind about
+type recordingSink struct {
+	mu    sync.Mutex
+	tasks []string
+}
+
+func (s *recordingSink) Remind(ctx context.Context, task *todov1.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks = append(s.tasks, task.Title)
+	return nil
+}
+
+func TestScheduler_FiresOnce(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	clock := &repository.FixedClock{T: start}
+	repo := repository.NewMockTodoRepository()
+	sink := &recordingSink{}
+	scheduler := NewScheduler(repo, sink, middleware.NewStructuredLogger(middleware.LevelError), WithClock(clock), WithBatchSize(1))
+
+	due := start.Add(time.Hour)
+	task, _ := repo.Create(ctx, &repository.CreateTaskRequest{Title: "Due soon", DueDate: &due})
+	done, _ := repo.Create(ctx, &repository.CreateTaskRequest{Title: "Already done", DueDate: &due})
+	repo.Update(ctx, &repository.UpdateTaskRequest{ID: done.Id, Completed: true})
+	repo.Create(ctx, &repository.CreateTaskRequest{Title: "Unscheduled"})
+
+	if sent, err := scheduler.RunOnce(ctx); err != nil || sent != 0 {
+		t.Fatalf("Expected nothing due yet, got %d (%v)", sent, err)
+	}
+
+	clock.T = due
+	if sent, err := scheduler.RunOnce(ctx); err != nil || sent != 1 {
+		t.Fatalf("Expected one reminder, got %d (%v)", sent, err)
+	}
+
+	clock.T = due.Add(time.Hour)
+	if sent, err := scheduler.RunOnce(ctx); err != nil || sent != 0 {
+		t.Fatalf("Expected the reminder not to repeat, got %d (%v)", sent, err)
+	}
+	if len(sink.tasks) != 1 || sink.tasks[0] != "Due soon" {
+		t.Errorf("Expected exactly one reminder for the due task, got %v", sink.tasks)
+	}
+
+	// Rescheduling re-arms the reminder
+	later := clock.T.Add(time.Hour)
+	repo.Update(ctx, &repository.UpdateTaskRequest{ID: task.Id, DueDate: &later})
+	clock.T = later
+	if sent, _ := scheduler.RunOnce(ctx); sent != 1 {
+		t.Errorf("Expected a new reminder after rescheduling, got %d", sent)
+	}
+}
+
+func TestScheduler_SinkErrorIsNotRetried(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := repository.NewMockTodoRepository()
+	due := now.Add(-time.Minute)
+	repo.Create(ctx, &repository.CreateTaskRequest{Title: "Due", DueDate: &due})
+
+	calls := 0
+	sink := SinkFunc(func(ctx context.Context, task *todov1.Task) error {
+		calls++
+		return errors.New("webhook down")
+	})
+	scheduler := NewScheduler(repo, sink, middleware.NewStructuredLogger(middleware.LevelError))
+
+	for i := 0; i < 2; i++ {
+		if sent, err := scheduler.RunOnce(ctx); err != nil || sent != 0 {
+			t.Fatalf("Expected no successful reminders, got %d (%v)", sent, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected the sink to be called once, got %d", calls)
+	}
+}
+
+func TestScheduler_RunStopsOnCancel(t *testing.T) {
+	scheduler := NewScheduler(repository.NewMockTodoRepository(), nil, middleware.NewStructuredLogger(middleware.LevelError))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stopped := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx, time.Millisecond)
+		close(stopped)
+	}()
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after cancellation")
+	}
+}
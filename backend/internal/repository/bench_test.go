@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"google.golang.org/protobuf/proto"
+)
+
+// benchmarkListRows is how many tasks the List benchmarks run against
+const benchmarkListRows = 10000
+
+// newBenchmarkRepo returns a repository over a fresh in-memory SQLite database.
+// Error level keeps per-query logs out of the measurement.
+func newBenchmarkRepo(b *testing.B) (*sql.DB, TodoRepository) {
+	db := setupTestDB(b)
+	return db, NewMySQLTodoRepositoryWithLogger(db, middleware.NewStructuredLogger(middleware.LevelError))
+}
+
+// seedTasks inserts n tasks in one transaction, every third one completed and
+// with spread-out timestamps so sorting has real work to do
+func seedTasks(b *testing.B, db *sql.DB, n int) {
+	b.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		b.Fatalf("Failed to begin seed transaction: %v", err)
+	}
+	stmt, err := tx.Prepare("INSERT INTO tasks (id, title, completed, created_at, updated_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		b.Fatalf("Failed to prepare seed insert: %v", err)
+	}
+	defer stmt.Close()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		title := fmt.Sprintf("Task %05d", i)
+		if i%10 == 0 {
+			title = fmt.Sprintf("Buy groceries %05d", i)
+		}
+		ts := base.Add(time.Duration(i) * time.Minute)
+		if _, err := stmt.Exec(fmt.Sprintf("seed-%05d", i), title, i%3 == 0, ts, ts); err != nil {
+			b.Fatalf("Failed to seed task: %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("Failed to commit seed transaction: %v", err)
+	}
+}
+
+func BenchmarkCreate(b *testing.B) {
+	_, repo := newBenchmarkRepo(b)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Benchmark task"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCreateRoundTrips compares Create with and without the re-read over
+// a mock MySQL connection that charges every statement a simulated round trip
+func BenchmarkCreateRoundTrips(b *testing.B) {
+	const roundTrip = 200 * time.Microsecond
+	now := time.Date(2025, 5, 1, 9, 30, 0, 0, time.UTC)
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+
+	for _, reread := range []bool{true, false} {
+		name := "reread"
+		var opts []Option
+		if !reread {
+			name = "without_reread"
+			opts = append(opts, WithoutCreateReread())
+		}
+
+		b.Run(name, func(b *testing.B) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				b.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+			repo := NewMySQLTodoRepositoryWithLogger(db, middleware.NewStructuredLogger(middleware.LevelError), opts...)
+
+			statements := 0
+			for i := 0; i < b.N; i++ {
+				mock.ExpectExec("INSERT INTO tasks").WillDelayFor(roundTrip).WillReturnResult(sqlmock.NewResult(1024, 1))
+				statements++
+				if reread {
+					mock.ExpectQuery("SELECT .* FROM tasks").WillDelayFor(roundTrip).
+						WillReturnRows(sqlmock.NewRows(columns).AddRow("task", "Benchmark task", false, now, now, nil, 1024.0, nil))
+					statements++
+				}
+			}
+
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.Create(ctx, &CreateTaskRequest{ID: "task", Title: "Benchmark task"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(statements)/float64(b.N), "statements/op")
+		})
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	db, repo := newBenchmarkRepo(b)
+	seedTasks(b, db, benchmarkListRows)
+	ctx := context.Background()
+
+	benchmarks := []struct {
+		name    string
+		filters ListTasksRequest
+	}{
+		{name: "first page", filters: ListTasksRequest{}},
+		{name: "deep page", filters: ListTasksRequest{Page: 400, PageSize: 20}},
+		{name: "search", filters: ListTasksRequest{Query: "groceries"}},
+		{name: "pending by title", filters: ListTasksRequest{
+			Status:    todov1.StatusFilter_STATUS_FILTER_PENDING,
+			SortBy:    todov1.SortField_SORT_FIELD_TITLE,
+			SortOrder: todov1.SortOrder_SORT_ORDER_ASC,
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				filters := bm.filters
+				if _, _, err := repo.List(ctx, &filters); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkListIDs compares a full page with an ID-only page, reporting the
+// encoded size of each as payload-bytes
+func BenchmarkListIDs(b *testing.B) {
+	db, repo := newBenchmarkRepo(b)
+	seedTasks(b, db, benchmarkListRows)
+	ctx := context.Background()
+
+	b.Run("full", func(b *testing.B) {
+		b.ReportAllocs()
+		var size int
+		for i := 0; i < b.N; i++ {
+			tasks, _, err := repo.List(ctx, &ListTasksRequest{PageSize: 100})
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = proto.Size(&todov1.ListTasksResponse{Tasks: tasks})
+		}
+		b.ReportMetric(float64(size), "payload-bytes")
+	})
+
+	b.Run("ids only", func(b *testing.B) {
+		b.ReportAllocs()
+		var size int
+		for i := 0; i < b.N; i++ {
+			ids, _, err := repo.ListIDs(ctx, &ListTasksRequest{PageSize: 100})
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = proto.Size(&todov1.ListTasksResponse{TaskIds: ids})
+		}
+		b.ReportMetric(float64(size), "payload-bytes")
+	})
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	_, repo := newBenchmarkRepo(b)
+	ctx := context.Background()
+
+	task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Benchmark"})
+	if err != nil {
+		b.Fatalf("Failed to create task: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := &UpdateTaskRequest{ID: task.Id, Title: "Benchmark", Completed: i%2 == 0}
+		if _, _, err := repo.Update(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
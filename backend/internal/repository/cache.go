@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"google.golang.org/protobuf/proto"
+)
+
+// CachingTodoRepository wraps a TodoRepository with an in-memory LRU cache of
+// tasks by ID for GetByID. Writes made through it update or evict the affected
+// entries; writes made by other instances are only seen once an entry's ttl
+// expires. All other methods pass straight through.
+type CachingTodoRepository struct {
+	TodoRepository
+
+	capacity int
+	ttl      time.Duration
+	clock    Clock
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// cacheEntry is one cached task. tenant is the tenant it was loaded under, or
+// empty when it was loaded without tenant scoping.
+type cacheEntry struct {
+	task    *todov1.Task
+	tenant  string
+	expires time.Time
+}
+
+// CacheStats is a snapshot of cache effectiveness. Counters are cumulative
+// since the cache was created; Size is the current number of entries.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // entries dropped to stay within capacity
+	Size      int
+}
+
+// HitRatio returns hits / (hits + misses), or zero before any lookups
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Sub returns the counter deltas since an earlier snapshot, keeping the
+// current Size, so periodic reports can cover a window instead of all time
+func (s CacheStats) Sub(earlier CacheStats) CacheStats {
+	return CacheStats{
+		Hits:      s.Hits - earlier.Hits,
+		Misses:    s.Misses - earlier.Misses,
+		Evictions: s.Evictions - earlier.Evictions,
+		Size:      s.Size,
+	}
+}
+
+// NewCachingTodoRepository caches up to capacity tasks from repo for ttl each
+func NewCachingTodoRepository(repo TodoRepository, capacity int, ttl time.Duration) *CachingTodoRepository {
+	return &CachingTodoRepository{
+		TodoRepository: repo,
+		capacity:       capacity,
+		ttl:            ttl,
+		clock:          realClock{},
+		order:          list.New(),
+		entries:        make(map[string]*list.Element),
+	}
+}
+
+// Stats returns the current hit, miss and eviction counts and cache size
+func (c *CachingTodoRepository) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      len(c.entries),
+	}
+}
+
+// GetByID serves the task from the cache when present and fresh
+func (c *CachingTodoRepository) GetByID(ctx context.Context, id string) (*todov1.Task, error) {
+	if task, ok := c.lookup(ctx, id); ok {
+		return task, nil
+	}
+
+	task, err := c.TodoRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, task)
+	return task, nil
+}
+
+// Create stores the new task so an immediate GetByID is a hit
+func (c *CachingTodoRepository) Create(ctx context.Context, req *CreateTaskRequest) (*todov1.Task, error) {
+	task, err := c.TodoRepository.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, task)
+	return task, nil
+}
+
+// Duplicate stores the copy like Create
+func (c *CachingTodoRepository) Duplicate(ctx context.Context, id string, titleSuffix string) (*todov1.Task, error) {
+	task, err := c.TodoRepository.Duplicate(ctx, id, titleSuffix)
+	if err != nil {
+		return nil, err
+	}
+	c.store(ctx, task)
+	return task, nil
+}
+
+// Update replaces the cached task with the updated one
+func (c *CachingTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, *todov1.Task, error) {
+	task, previous, err := c.TodoRepository.Update(ctx, req)
+	if err != nil {
+		c.evict(req.ID)
+		return nil, nil, err
+	}
+	c.store(ctx, task)
+	return task, previous, nil
+}
+
+// Delete evicts the task whether or not the delete succeeded
+func (c *CachingTodoRepository) Delete(ctx context.Context, id string) error {
+	defer c.evict(id)
+	return c.TodoRepository.Delete(ctx, id)
+}
+
+// DeleteMany evicts every requested task
+func (c *CachingTodoRepository) DeleteMany(ctx context.Context, ids []string) ([]*todov1.DeleteTaskResult, error) {
+	defer c.evict(ids...)
+	return c.TodoRepository.DeleteMany(ctx, ids)
+}
+
+// Reorder clears the cache, since moving one task can renumber every position
+func (c *CachingTodoRepository) Reorder(ctx context.Context, id, afterID string) (*todov1.Task, error) {
+	defer c.purge()
+	return c.TodoRepository.Reorder(ctx, id, afterID)
+}
+
+// ArchiveCompleted clears the cache, since any number of tasks may be archived
+func (c *CachingTodoRepository) ArchiveCompleted(ctx context.Context, before time.Time) (int64, error) {
+	defer c.purge()
+	return c.TodoRepository.ArchiveCompleted(ctx, before)
+}
+
+// WithTx clears the cache when the transaction fails, since entries stored by
+// its writes describe changes that were rolled back
+func (c *CachingTodoRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := c.TodoRepository.WithTx(ctx, fn)
+	if err != nil {
+		c.purge()
+	}
+	return err
+}
+
+// lookup returns a copy of the cached task if it is fresh and visible to the
+// tenant in ctx, counting the hit or miss
+func (c *CachingTodoRepository) lookup(ctx context.Context, id string) (*todov1.Task, bool) {
+	tenant := middleware.GetTenant(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[id]
+	if ok {
+		entry := elem.Value.(*cacheEntry)
+		switch {
+		case !c.clock.Now().Before(entry.expires):
+			c.order.Remove(elem)
+			delete(c.entries, id)
+		case tenant == "" || entry.tenant == tenant:
+			c.order.MoveToFront(elem)
+			c.hits++
+			return proto.Clone(entry.task).(*todov1.Task), true
+		}
+	}
+
+	c.misses++
+	return nil, false
+}
+
+// store caches a copy of task, evicting the least recently used entry when full
+func (c *CachingTodoRepository) store(ctx context.Context, task *todov1.Task) {
+	if c.capacity <= 0 {
+		return
+	}
+	entry := &cacheEntry{
+		task:    proto.Clone(task).(*todov1.Task),
+		tenant:  middleware.GetTenant(ctx),
+		expires: c.clock.Now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[task.Id]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[task.Id] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).task.Id)
+		c.evictions++
+	}
+}
+
+// evict drops the given tasks from the cache
+func (c *CachingTodoRepository) evict(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range ids {
+		if elem, ok := c.entries[id]; ok {
+			c.order.Remove(elem)
+			delete(c.entries, id)
+		}
+	}
+}
+
+// purge empties the cache
+func (c *CachingTodoRepository) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
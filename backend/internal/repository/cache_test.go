@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestCachingTodoRepository_Stats(t *testing.T) {
+	mock := NewMockTodoRepository()
+	cache := NewCachingTodoRepository(mock, 2, time.Minute)
+	ctx := context.Background()
+
+	a, _ := mock.Create(ctx, &CreateTaskRequest{Title: "a"})
+	b, _ := mock.Create(ctx, &CreateTaskRequest{Title: "b"})
+	c, _ := mock.Create(ctx, &CreateTaskRequest{Title: "c"})
+
+	for _, id := range []string{a.Id, a.Id, b.Id, a.Id, c.Id, b.Id} {
+		if _, err := cache.GetByID(ctx, id); err != nil {
+			t.Fatalf("GetByID(%s): %v", id, err)
+		}
+	}
+
+	// a miss, a hit, b miss, a hit, c miss (evicts b), b miss (evicts a)
+	stats := cache.Stats()
+	want := CacheStats{Hits: 2, Misses: 4, Evictions: 2, Size: 2}
+	if stats != want {
+		t.Errorf("Expected %+v, got %+v", want, stats)
+	}
+	if got := stats.HitRatio(); got != 2.0/6.0 {
+		t.Errorf("Expected hit ratio 1/3, got %v", got)
+	}
+
+	window := cache.Stats()
+	cache.GetByID(ctx, c.Id)
+	if delta := cache.Stats().Sub(window); delta.Hits != 1 || delta.Misses != 0 {
+		t.Errorf("Expected one hit in the window, got %+v", delta)
+	}
+}
+
+func TestCachingTodoRepository_Invalidation(t *testing.T) {
+	mock := NewMockTodoRepository()
+	clock := &FixedClock{T: time.Now()}
+	cache := NewCachingTodoRepository(mock, 10, time.Minute)
+	cache.clock = clock
+	ctx := context.Background()
+
+	task, err := cache.Create(ctx, &CreateTaskRequest{Title: "Cached"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	// Updates through the cache are visible immediately
+	if _, _, err := cache.Update(ctx, &UpdateTaskRequest{ID: task.Id, Title: "Renamed"}); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	got, _ := cache.GetByID(ctx, task.Id)
+	if got.Title != "Renamed" {
+		t.Errorf("Expected updated title, got %q", got.Title)
+	}
+
+	// Callers cannot mutate the cached copy
+	got.Title = "Mutated"
+	if again, _ := cache.GetByID(ctx, task.Id); again.Title != "Renamed" {
+		t.Errorf("Expected cached task to be unaffected, got %q", again.Title)
+	}
+
+	// Another tenant never sees the entry
+	other := middleware.WithTenant(ctx, "acme")
+	if _, ok := cache.lookup(other, task.Id); ok {
+		t.Error("Expected a tenant-scoped lookup to miss an entry loaded for another tenant")
+	}
+
+	// Writes behind the cache's back show up once the entry expires
+	mock.Update(ctx, &UpdateTaskRequest{ID: task.Id, Title: "Elsewhere"})
+	clock.T = clock.T.Add(2 * time.Minute)
+	if got, _ := cache.GetByID(ctx, task.Id); got.Title != "Elsewhere" {
+		t.Errorf("Expected expired entry to be reloaded, got %q", got.Title)
+	}
+
+	if err := cache.Delete(ctx, task.Id); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	if _, err := cache.GetByID(ctx, task.Id); err == nil {
+		t.Error("Expected deleted task to be gone")
+	}
+}
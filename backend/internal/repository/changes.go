@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// ListChangedSince returns up to limit tasks, archived ones included, that
+// come after the (since, sinceID) cursor in (updated_at, id) order, oldest
+// change first. An empty sinceID skips every task updated at since. Without a
+// tenant in ctx every tenant's tasks are returned.
+func (r *mysqlTodoRepository) ListChangedSince(ctx context.Context, since time.Time, sinceID string, limit int) ([]*todov1.Task, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.ListChangedSince")
+
+	var where string
+	var args []interface{}
+	if sinceID == "" {
+		where, args = scopedWhere(ctx, "updated_at > ?", since.UTC())
+	} else {
+		where, args = scopedWhere(ctx, "(updated_at > ? OR (updated_at = ? AND id > ?))", since.UTC(), since.UTC(), sinceID)
+	}
+	rows, err := r.reader(ctx).QueryContext(ctx, `
+		SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date
+		FROM tasks
+		WHERE `+where+`
+		ORDER BY updated_at, id
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*todov1.Task{}
+	for rows.Next() {
+		task, err := r.scanListRow(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list changed tasks: %w", err)
+	}
+
+	r.logger.LogDatabaseOperation(ctx, "SELECT tasks changed", time.Since(start), true, int64(len(tasks)))
+	return tasks, nil
+}
+
+// ListChangedSince returns up to limit tasks after the (since, sinceID) cursor,
+// oldest change first
+func (m *MockTodoRepository) ListChangedSince(ctx context.Context, since time.Time, sinceID string, limit int) ([]*todov1.Task, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return nil, m.listError
+	}
+
+	changed := []*todov1.Task{}
+	for _, task := range m.tasks {
+		if task.UpdatedAt == nil {
+			continue
+		}
+		updatedAt := task.UpdatedAt.AsTime()
+		if updatedAt.After(since) || (sinceID != "" && updatedAt.Equal(since) && task.Id > sinceID) {
+			changed = append(changed, task)
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if !changed[i].UpdatedAt.AsTime().Equal(changed[j].UpdatedAt.AsTime()) {
+			return changed[i].UpdatedAt.AsTime().Before(changed[j].UpdatedAt.AsTime())
+		}
+		return changed[i].Id < changed[j].Id
+	})
+	if len(changed) > limit {
+		changed = changed[:limit]
+	}
+	return changed, nil
+}
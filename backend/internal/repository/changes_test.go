@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestListChangedSince(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repos := map[string]func(clock Clock) TodoRepository{
+		"mysql": func(clock Clock) TodoRepository { return NewMySQLTodoRepository(setupTestDB(t), WithClock(clock)) },
+		"mock": func(clock Clock) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetClock(clock)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := &FixedClock{T: base}
+			repo := newRepo(clock)
+
+			var ids []string
+			for i, title := range []string{"First", "Second", "Third"} {
+				clock.T = base.Add(time.Duration(i) * time.Minute)
+				task, err := repo.Create(ctx, &CreateTaskRequest{Title: title})
+				if err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+				ids = append(ids, task.Id)
+			}
+			// Updating the first task makes it the latest change
+			clock.T = base.Add(time.Hour)
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: ids[0], Title: "First", Completed: true}); err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+
+			changed, err := repo.ListChangedSince(ctx, base, "", 10)
+			if err != nil {
+				t.Fatalf("Failed to list changes: %v", err)
+			}
+			if len(changed) != 3 || changed[0].Id != ids[1] || changed[1].Id != ids[2] || changed[2].Id != ids[0] {
+				t.Errorf("Expected changes after the first create, oldest first, got %v", changed)
+			}
+
+			limited, err := repo.ListChangedSince(ctx, time.Time{}, "", 2)
+			if err != nil {
+				t.Fatalf("Failed to list changes: %v", err)
+			}
+			if len(limited) != 2 || limited[0].Id != ids[1] {
+				t.Errorf("Expected the 2 oldest changes, got %v", limited)
+			}
+
+			none, err := repo.ListChangedSince(ctx, base.Add(time.Hour), "", 10)
+			if err != nil {
+				t.Fatalf("Failed to list changes: %v", err)
+			}
+			if len(none) != 0 {
+				t.Errorf("Expected since to be exclusive, got %v", none)
+			}
+		})
+	}
+}
+
+func TestListChangedSince_SharedTimestamp(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repos := map[string]func(clock Clock) TodoRepository{
+		"mysql": func(clock Clock) TodoRepository {
+			return NewMySQLTodoRepository(setupTestDB(t), WithClock(clock), WithIDGenerator(NewSequentialGenerator(0)))
+		},
+		"mock": func(clock Clock) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetClock(clock)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(&FixedClock{T: base})
+
+			// More tasks than one page, all updated in the same second
+			const total, limit = 7, 3
+			for i := 0; i < total; i++ {
+				if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Task"}); err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+			}
+
+			seen := map[string]bool{}
+			since, sinceID := base.Add(-time.Second), ""
+			for pages := 0; pages <= total/limit; pages++ {
+				changed, err := repo.ListChangedSince(ctx, since, sinceID, limit)
+				if err != nil {
+					t.Fatalf("Failed to list changes: %v", err)
+				}
+				for _, task := range changed {
+					if seen[task.Id] {
+						t.Errorf("Task %s returned twice", task.Id)
+					}
+					seen[task.Id] = true
+				}
+				if len(changed) < limit {
+					break
+				}
+				last := changed[len(changed)-1]
+				since, sinceID = last.UpdatedAt.AsTime(), last.Id
+			}
+			if len(seen) != total {
+				t.Errorf("Expected all %d tasks across pages, got %d", total, len(seen))
+			}
+
+			// Without an id the timestamp alone stays exclusive
+			none, err := repo.ListChangedSince(ctx, base, "", limit)
+			if err != nil {
+				t.Fatalf("Failed to list changes: %v", err)
+			}
+			if len(none) != 0 {
+				t.Errorf("Expected no changes after the shared timestamp, got %d", len(none))
+			}
+		})
+	}
+}
+
+func TestCountCreatedSince(t *testing.T) {
+	base := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repos := map[string]func(clock Clock) TodoRepository{
+		"mysql": func(clock Clock) TodoRepository { return NewMySQLTodoRepository(setupTestDB(t), WithClock(clock)) },
+		"mock": func(clock Clock) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetClock(clock)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := &FixedClock{T: base}
+			repo := newRepo(clock)
+
+			// One task a second before the window, one exactly at its start
+			// and one inside it
+			for _, offset := range []time.Duration{-time.Second, 0, time.Hour} {
+				clock.T = base.Add(offset)
+				if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Task"}); err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+			}
+
+			tests := []struct {
+				since time.Time
+				want  uint32
+			}{
+				{since: base.Add(-time.Minute), want: 3},
+				{since: base, want: 2},
+				{since: base.Add(time.Second), want: 1},
+				{since: base.Add(2 * time.Hour), want: 0},
+			}
+			for _, tt := range tests {
+				count, err := repo.CountCreatedSince(ctx, tt.since)
+				if err != nil {
+					t.Fatalf("Failed to count tasks: %v", err)
+				}
+				if count != tt.want {
+					t.Errorf("Expected %d tasks created since %v, got %d", tt.want, tt.since, count)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "time"
+
+// Clock is the time source for timestamps the repository assigns itself
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads the system clock (the default)
+type realClock struct{}
+
+// Now returns the current time
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// utcClock reads the system clock in UTC, matching SQLite's CURRENT_TIMESTAMP
+// so stored timestamps compare correctly as text
+type utcClock struct{}
+
+// Now returns the current time in UTC
+func (utcClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// FixedClock always returns the same instant, for tests that assert exact timestamps
+type FixedClock struct {
+	T time.Time
+}
+
+// Now returns the fixed instant
+func (c FixedClock) Now() time.Time {
+	return c.T
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WithoutCreateReread has Create build the returned task from the values it
+// inserted instead of reading the row back, saving a round trip on the hot
+// create path. created_at and updated_at are stamped by the repository rather
+// than the column defaults, and the position comes back from the INSERT itself:
+// through RETURNING on SQLite, and on MySQL through LAST_INSERT_ID(expr), which
+// limits new positions to whole numbers and replaces the session's last insert
+// ID. It is off by default; leave it off when callers need the timestamps
+// exactly as the database normalized them, such as a MySQL session whose time
+// zone is not UTC.
+func WithoutCreateReread() Option {
+	return func(r *mysqlTodoRepository) {
+		r.skipCreateReread = true
+	}
+}
+
+// createQuery returns the INSERT used by Create, the timestamp arguments
+// that follow its id, title, tenant and due date placeholders and the tenant
+// scope arguments after those. With unique titles the tenant and title follow
+// again for the guard. Without the re-read it also returns the time written
+// to created_at and updated_at.
+func (r *mysqlTodoRepository) createQuery(ctx context.Context) (query string, stamps, scope []interface{}, createdAt time.Time) {
+	// New tasks go to the end of the tenant's manual ordering
+	position := appendPosition
+	from, scope := appendScope(ctx)
+	stampColumns, stampValues, stamps := r.insertStamps()
+	returning := ""
+
+	if r.skipCreateReread {
+		createdAt = r.createStamp()
+		stampColumns, stampValues, stamps = ", created_at, updated_at", ", ?, ?", []interface{}{createdAt, createdAt}
+		if r.sqlite {
+			returning = " RETURNING position"
+		} else {
+			// The OK packet carries LAST_INSERT_ID(expr) as the insert ID, which
+			// is an integer, so the position is rounded down past the last one
+			position = fmt.Sprintf("LAST_INSERT_ID(FLOOR(GREATEST(COALESCE(MAX(position), 0), 0)) + %g)", positionStep)
+		}
+	}
+
+	// With unique titles the row is only inserted while the title is free. The
+	// aggregate always yields one row, so HAVING rather than WHERE drops it.
+	guard := ""
+	if r.uniqueTitles {
+		guard = `
+		HAVING NOT EXISTS (SELECT 1 FROM tasks WHERE tenant_id = ? AND title = ?)`
+	}
+
+	query = `
+		INSERT INTO tasks (id, title, completed, tenant_id, due_date, position` + stampColumns + `)
+		SELECT ?, ?, FALSE, ?, ?, ` + position + stampValues + `
+		` + from + guard + returning
+	return query, stamps, scope, createdAt
+}
+
+// createStamp returns the created_at for a task that will not be read back,
+// at the precision the column stores so the returned task matches the row
+func (r *mysqlTodoRepository) createStamp() time.Time {
+	clock := r.clock
+	if clock == nil {
+		clock = utcClock{}
+	}
+	now := clock.Now()
+	if !r.sqlite {
+		// MySQL TIMESTAMP columns keep whole seconds
+		now = now.Truncate(time.Second)
+	}
+	return now
+}
+
+// createResult is what Create's INSERT reports about the new row
+type createResult struct {
+	rowsAffected int64   // zero when the unique title guard dropped the row
+	position     float64 // only known without the re-read
+}
+
+// insertTask runs Create's INSERT
+func (r *mysqlTodoRepository) insertTask(ctx context.Context, query string, args []interface{}) (createResult, error) {
+	if r.skipCreateReread && r.sqlite {
+		var res createResult
+		err := r.queryMutation(ctx, query, args, &res.position)
+		if err == sql.ErrNoRows {
+			// The unique title guard dropped the row
+			return createResult{}, nil
+		}
+		if err != nil {
+			return createResult{}, err
+		}
+		res.rowsAffected = 1
+		return res, nil
+	}
+
+	result, err := r.execMutation(ctx, query, args...)
+	if err != nil {
+		return createResult{}, err
+	}
+	res := createResult{}
+	res.rowsAffected, _ = result.RowsAffected()
+	if !r.skipCreateReread {
+		return res, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return res, fmt.Errorf("failed to read task position: %w", err)
+	}
+	res.position = float64(id)
+	return res, nil
+}
+
+// createdTask builds the task Create inserted from its known values
+func (r *mysqlTodoRepository) createdTask(id string, req *CreateTaskRequest, createdAt time.Time, res createResult) *todov1.Task {
+	task := &todov1.Task{
+		Id:        id,
+		Title:     req.Title,
+		CreatedAt: timestamppb.New(createdAt),
+		UpdatedAt: timestamppb.New(createdAt),
+		Position:  res.position,
+	}
+	if req.DueDate != nil {
+		dueDate := *req.DueDate
+		if !r.sqlite {
+			// MySQL rounds fractional seconds on TIMESTAMP columns
+			dueDate = dueDate.Round(time.Second)
+		}
+		task.DueDate = timestamppb.New(dueDate)
+	}
+	return task
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreate_WithoutReread(t *testing.T) {
+	createdAt := time.Date(2025, 5, 1, 9, 30, 0, 123456789, time.UTC)
+	dueDate := time.Date(2025, 5, 8, 17, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"re-read", nil},
+		{"without re-read", []Option{WithoutCreateReread()}},
+		{"without re-read, list version", []Option{WithoutCreateReread(), WithListVersion()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithClock(FixedClock{T: createdAt})}, tt.opts...)
+			repo := NewSQLiteTodoRepository(setupTestDB(t), opts...)
+			ctx := context.Background()
+
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "First"}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			created, err := repo.Create(ctx, &CreateTaskRequest{Title: "Second", DueDate: &dueDate})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+
+			if created.Position != 2048 {
+				t.Errorf("Expected position 2048, got %v", created.Position)
+			}
+			if !created.CreatedAt.AsTime().Equal(createdAt) || !created.DueDate.AsTime().Equal(dueDate) {
+				t.Errorf("Unexpected timestamps: created %v, due %v", created.CreatedAt.AsTime(), created.DueDate.AsTime())
+			}
+
+			stored, err := repo.GetByID(ctx, created.Id)
+			if err != nil {
+				t.Fatalf("Failed to get task: %v", err)
+			}
+			if !proto.Equal(created, stored) {
+				t.Errorf("Expected the returned task to match the stored row\nreturned: %v\nstored:   %v", created, stored)
+			}
+		})
+	}
+}
+
+func TestMySQLTodoRepository_CreateWithoutReread(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	// Only the INSERT runs; the position comes back as the insert ID
+	now := time.Date(2025, 5, 1, 9, 30, 0, 900000000, time.UTC)
+	mock.ExpectExec(`INSERT INTO tasks .* LAST_INSERT_ID\(FLOOR\(GREATEST\(COALESCE\(MAX\(position\), 0\), 0\)\) \+ 1024\)`).
+		WithArgs("task-1", "Write report", "default", nil, now.Truncate(time.Second), now.Truncate(time.Second)).
+		WillReturnResult(sqlmock.NewResult(3072, 1))
+
+	repo := NewMySQLTodoRepository(db, WithClock(FixedClock{T: now}), WithoutCreateReread())
+	task, err := repo.Create(context.Background(), &CreateTaskRequest{ID: "task-1", Title: "Write report"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if task.Id != "task-1" || task.Title != "Write report" || task.Completed || task.Position != 3072 {
+		t.Errorf("Unexpected task: %v", task)
+	}
+	if !task.CreatedAt.AsTime().Equal(now.Truncate(time.Second)) || !task.UpdatedAt.AsTime().Equal(task.CreatedAt.AsTime()) {
+		t.Errorf("Expected timestamps at whole seconds, got %v and %v", task.CreatedAt.AsTime(), task.UpdatedAt.AsTime())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+)
+
+// Names of the integrity checks Diagnose runs, in report order
+const (
+	CheckNullTimestamps = "null_timestamps"
+	CheckInvalidIDs     = "invalid_ids"
+	CheckOverlongTitles = "overlong_titles"
+	CheckDuplicateIDs   = "duplicate_ids"
+)
+
+// storageSampleLimit caps the offending IDs reported per check
+const storageSampleLimit = 20
+
+// StorageReport is the result of Diagnose
+type StorageReport struct {
+	TotalRows int64
+	Checks    []StorageCheck
+}
+
+// StorageCheck is one integrity check and the rows it flagged
+type StorageCheck struct {
+	Name      string
+	Count     int64    // Offending rows
+	SampleIDs []string // Up to storageSampleLimit offending IDs
+}
+
+// newStorageReport returns a report with every check present and passing
+func newStorageReport() *StorageReport {
+	report := &StorageReport{}
+	for _, name := range []string{CheckNullTimestamps, CheckInvalidIDs, CheckOverlongTitles, CheckDuplicateIDs} {
+		report.Checks = append(report.Checks, StorageCheck{Name: name, SampleIDs: []string{}})
+	}
+	return report
+}
+
+// Healthy reports whether every check passed
+func (r *StorageReport) Healthy() bool {
+	for _, check := range r.Checks {
+		if check.Count > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// flag counts rows offending rows against the named check and samples id
+func (r *StorageReport) flag(name, id string, rows int64) {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			r.Checks[i].Count += rows
+			if len(r.Checks[i].SampleIDs) < storageSampleLimit {
+				r.Checks[i].SampleIDs = append(r.Checks[i].SampleIDs, id)
+			}
+			return
+		}
+	}
+}
+
+// inspect applies the per-row checks to one task row
+func (r *StorageReport) inspect(id, title string, nullTimestamp bool) {
+	r.TotalRows++
+	if nullTimestamp {
+		r.flag(CheckNullTimestamps, id, 1)
+	}
+	if !validTaskID(id) {
+		r.flag(CheckInvalidIDs, id, 1)
+	}
+	if len(title) > validator.MaxTitleLength {
+		r.flag(CheckOverlongTitles, id, 1)
+	}
+}
+
+// validTaskID reports whether id has a form some ID_STRATEGY generates: a
+// canonical lowercase UUID, a ULID or a sequential number
+func validTaskID(id string) bool {
+	if parsed, err := uuid.Parse(id); err == nil && parsed.String() == id {
+		return true
+	}
+	if _, err := ulid.ParseStrict(id); err == nil {
+		return true
+	}
+	if n, err := strconv.ParseUint(id, 10, 64); err == nil && strconv.FormatUint(n, 10) == id {
+		return true
+	}
+	return false
+}
+
+// Diagnose runs read-only integrity checks over every tenant's tasks on the
+// primary: NULL timestamps, IDs in no generated format, titles longer than
+// validator.MaxTitleLength and duplicate IDs. The schema rules most of these
+// out, so findings point to manual edits or drift from an older schema.
+func (r *mysqlTodoRepository) Diagnose(ctx context.Context) (*StorageReport, error) {
+	ctx = middleware.WithSource(ctx, "repository.Diagnose")
+	report := newStorageReport()
+
+	rows, err := r.primary(ctx).QueryContext(ctx, `
+		SELECT id, title, created_at IS NULL OR updated_at IS NULL
+		FROM tasks
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, title string
+		var nullTimestamp bool
+		if err := rows.Scan(&id, &title, &nullTimestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		report.inspect(id, title, nullTimestamp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+
+	duplicates, err := r.primary(ctx).QueryContext(ctx, `
+		SELECT id, COUNT(*)
+		FROM tasks
+		GROUP BY id
+		HAVING COUNT(*) > 1
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate ids: %w", err)
+	}
+	defer duplicates.Close()
+	for duplicates.Next() {
+		var id string
+		var count int64
+		if err := duplicates.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate id: %w", err)
+		}
+		report.flag(CheckDuplicateIDs, id, count)
+	}
+	if err := duplicates.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find duplicate ids: %w", err)
+	}
+
+	return report, nil
+}
+
+// Diagnose runs the same checks over the in-memory tasks. IDs are map keys,
+// so duplicate_ids always passes.
+func (m *MockTodoRepository) Diagnose(ctx context.Context) (*StorageReport, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.tasks))
+	for id := range m.tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	report := newStorageReport()
+	for _, id := range ids {
+		task := m.tasks[id]
+		report.inspect(task.Id, task.Title, task.CreatedAt == nil || task.UpdatedAt == nil)
+	}
+	return report, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// checkByName returns the named check from a report, failing the test if absent
+func checkByName(t *testing.T, report *StorageReport, name string) StorageCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("Report has no %s check", name)
+	return StorageCheck{}
+}
+
+func TestMySQLTodoRepository_Diagnose(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("healthy table", func(t *testing.T) {
+		repo := NewMySQLTodoRepository(setupTestDB(t))
+		for _, gen := range []IDGenerator{UUIDGenerator{}, NewULIDGenerator(), NewSequentialGenerator(0)} {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{ID: gen.NewID(), Title: "Buy milk " + gen.NewID()}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+		}
+
+		report, err := repo.Diagnose(ctx)
+		if err != nil {
+			t.Fatalf("Failed to diagnose: %v", err)
+		}
+		if report.TotalRows != 3 || !report.Healthy() {
+			t.Errorf("Expected 3 healthy rows, got %+v", report)
+		}
+		if len(report.Checks) != 4 {
+			t.Errorf("Expected every check to be reported, got %d", len(report.Checks))
+		}
+	})
+
+	t.Run("drifted table", func(t *testing.T) {
+		// A table edited by hand, without the constraints the schema adds
+		db, err := sql.Open("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatalf("Failed to open database: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		defer db.Close()
+
+		statements := []string{
+			"CREATE TABLE tasks (id TEXT, title TEXT NOT NULL, created_at DATETIME, updated_at DATETIME)",
+			"INSERT INTO tasks VALUES ('0b6f2a1e-3c4d-4e5f-8a9b-0c1d2e3f4a5b', 'Fine', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('0B6F2A1E-3C4D-4E5F-8A9B-0C1D2E3F4A5C', 'Uppercase UUID', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('task one', 'Bad id', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('42', 'No created_at', NULL, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('43', '" + strings.Repeat("x", validator.MaxTitleLength+1) + "', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('44', 'Copy', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+			"INSERT INTO tasks VALUES ('44', 'Copy', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)",
+		}
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatalf("Failed to seed table: %v", err)
+			}
+		}
+
+		report, err := NewMySQLTodoRepository(db).Diagnose(ctx)
+		if err != nil {
+			t.Fatalf("Failed to diagnose: %v", err)
+		}
+		if report.TotalRows != 7 || report.Healthy() {
+			t.Errorf("Expected 7 unhealthy rows, got %+v", report)
+		}
+
+		expected := map[string][]string{
+			CheckNullTimestamps: {"42"},
+			CheckInvalidIDs:     {"0B6F2A1E-3C4D-4E5F-8A9B-0C1D2E3F4A5C", "task one"},
+			CheckOverlongTitles: {"43"},
+			CheckDuplicateIDs:   {"44"},
+		}
+		counts := map[string]int64{CheckNullTimestamps: 1, CheckInvalidIDs: 2, CheckOverlongTitles: 1, CheckDuplicateIDs: 2}
+		for name, ids := range expected {
+			check := checkByName(t, report, name)
+			if check.Count != counts[name] || strings.Join(check.SampleIDs, ",") != strings.Join(ids, ",") {
+				t.Errorf("Expected %s to flag %d rows %v, got %d %v", name, counts[name], ids, check.Count, check.SampleIDs)
+			}
+		}
+	})
+}
+
+func TestMockTodoRepository_Diagnose(t *testing.T) {
+	repo := NewMockTodoRepository()
+	now := timestamppb.Now()
+	repo.AddTask(&todov1.Task{Id: "1", Title: "Fine", CreatedAt: now, UpdatedAt: now})
+	repo.AddTask(&todov1.Task{Id: "2", Title: "Never stamped"})
+	repo.AddTask(&todov1.Task{Id: "not an id", Title: "Bad id", CreatedAt: now, UpdatedAt: now})
+
+	report, err := repo.Diagnose(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to diagnose: %v", err)
+	}
+	if report.TotalRows != 3 || report.Healthy() {
+		t.Errorf("Expected 3 unhealthy rows, got %+v", report)
+	}
+	if check := checkByName(t, report, CheckNullTimestamps); check.Count != 1 || check.SampleIDs[0] != "2" {
+		t.Errorf("Expected task 2 to have NULL timestamps, got %+v", check)
+	}
+	if check := checkByName(t, report, CheckInvalidIDs); check.Count != 1 || check.SampleIDs[0] != "not an id" {
+		t.Errorf("Expected the malformed id to be flagged, got %+v", check)
+	}
+}
+
+func TestStorageReport_SampleLimit(t *testing.T) {
+	report := newStorageReport()
+	for i := 0; i < storageSampleLimit+5; i++ {
+		report.inspect("bad id", "Task", false)
+	}
+	check := checkByName(t, report, CheckInvalidIDs)
+	if check.Count != storageSampleLimit+5 || len(check.SampleIDs) != storageSampleLimit {
+		t.Errorf("Expected %d rows with %d samples, got %d with %d", storageSampleLimit+5, storageSampleLimit, check.Count, len(check.SampleIDs))
+	}
+}
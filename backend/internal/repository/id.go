@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+// IDGenerator produces identifiers for newly created tasks
+type IDGenerator interface {
+	NewID() string
+}
+
+// UUIDGenerator generates random UUID v4 identifiers (the default)
+type UUIDGenerator struct{}
+
+// NewID returns a new UUID v4 string
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+// ULIDGenerator generates ULIDs, which sort by creation time and keep
+// inserts clustered at the end of the primary key index
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	entropy *ulid.MonotonicEntropy
+}
+
+// NewULIDGenerator creates a ULID generator that is monotonic within a millisecond
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{
+		entropy: ulid.Monotonic(rand.Reader, 0),
+	}
+}
+
+// NewID returns a new ULID string, strictly greater than any previously returned
+func (g *ULIDGenerator) NewID() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return ulid.MustNew(ulid.Timestamp(time.Now()), g.entropy).String()
+}
+
+// SequentialGenerator generates increasing numeric identifiers for systems that
+// expect numeric IDs. The counter lives in memory, so it must be seeded past the
+// highest existing ID, as NewIDGenerator does, and is only safe with a single
+// writer instance.
+type SequentialGenerator struct {
+	last atomic.Uint64
+}
+
+// NewSequentialGenerator creates a generator whose first ID is start+1
+func NewSequentialGenerator(start uint64) *SequentialGenerator {
+	g := &SequentialGenerator{}
+	g.last.Store(start)
+	return g
+}
+
+// NewID returns the next number in the sequence
+func (g *SequentialGenerator) NewID() string {
+	return strconv.FormatUint(g.last.Add(1), 10)
+}
+
+// NewIDGenerator returns the generator for a named strategy ("uuid", "ulid" or
+// "sequential"). A sequential generator continues from the highest numeric task
+// ID in db, which must already have its schema.
+func NewIDGenerator(ctx context.Context, db *sql.DB, strategy string) (IDGenerator, error) {
+	switch strategy {
+	case "", "uuid":
+		return UUIDGenerator{}, nil
+	case "ulid":
+		return NewULIDGenerator(), nil
+	case "sequential":
+		last, err := maxNumericID(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		return NewSequentialGenerator(last), nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy: %s", strategy)
+	}
+}
+
+// maxNumericID returns the highest all-digit task ID in db across every
+// tenant, or 0 when there is none. IDs of other strategies are skipped rather
+// than cast, since a UUID such as 123e4567-... would cast to a number.
+func maxNumericID(ctx context.Context, db *sql.DB) (uint64, error) {
+	numeric := "id REGEXP '^[0-9]+$'"
+	if isSQLiteDriver(db) {
+		numeric = "id <> '' AND id NOT GLOB '*[^0-9]*'"
+	}
+
+	var id string
+	err := db.QueryRowContext(ctx, "SELECT id FROM tasks WHERE "+numeric+" ORDER BY LENGTH(id) DESC, id DESC LIMIT 1").Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the highest task ID: %w", err)
+	}
+	last, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse task ID %q: %w", id, err)
+	}
+	return last, nil
+}
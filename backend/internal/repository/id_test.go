@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIDGenerators_Unique(t *testing.T) {
+	generators := map[string]IDGenerator{
+		"uuid":       UUIDGenerator{},
+		"ulid":       NewULIDGenerator(),
+		"sequential": NewSequentialGenerator(0),
+	}
+
+	for name, gen := range generators {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			for i := 0; i < 10000; i++ {
+				id := gen.NewID()
+				if seen[id] {
+					t.Fatalf("Duplicate ID generated: %s", id)
+				}
+				seen[id] = true
+			}
+		})
+	}
+}
+
+func TestULIDGenerator_Monotonic(t *testing.T) {
+	gen := NewULIDGenerator()
+
+	prev := gen.NewID()
+	for i := 0; i < 10000; i++ {
+		id := gen.NewID()
+		if id <= prev {
+			t.Fatalf("Expected ULIDs to increase, got %s after %s", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestSequentialGenerator_StartsAfterSeed(t *testing.T) {
+	gen := NewSequentialGenerator(41)
+
+	if id := gen.NewID(); id != "42" {
+		t.Errorf("Expected first ID 42, got %s", id)
+	}
+	if id := gen.NewID(); id != "43" {
+		t.Errorf("Expected second ID 43, got %s", id)
+	}
+}
+
+func TestNewIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+
+	if _, err := NewIDGenerator(ctx, db, "uuid"); err != nil {
+		t.Errorf("Expected uuid strategy to be supported, got %v", err)
+	}
+	if _, err := NewIDGenerator(ctx, db, "ulid"); err != nil {
+		t.Errorf("Expected ulid strategy to be supported, got %v", err)
+	}
+	if _, err := NewIDGenerator(ctx, db, "snowflake"); err == nil {
+		t.Error("Expected unknown strategy to return an error")
+	}
+
+	gen, err := NewIDGenerator(ctx, db, "sequential")
+	if err != nil {
+		t.Fatalf("Expected sequential strategy to be supported, got %v", err)
+	}
+	if id := gen.NewID(); id != "1" {
+		t.Errorf("Expected an empty table to start at 1, got %s", id)
+	}
+}
+
+func TestNewIDGenerator_SequentialContinuesFromDatabase(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	// 100 sorts before 99 as text, and UUIDs or ULIDs are not numbers at all
+	for _, id := range []string{"99", "100", "7", "123e4567-e89b-12d3-a456-426614174000", "01ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := db.Exec("INSERT INTO tasks (id, title) VALUES (?, 'Seeded')", id); err != nil {
+			t.Fatalf("Failed to insert task: %v", err)
+		}
+	}
+
+	gen, err := NewIDGenerator(ctx, db, "sequential")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	task, err := NewMySQLTodoRepository(db, WithIDGenerator(gen)).Create(ctx, &CreateTaskRequest{Title: "Next"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if task.Id != "101" {
+		t.Errorf("Expected the sequence to continue at 101, got %s", task.Id)
+	}
+}
+
+func TestMySQLTodoRepository_CreateUsesIDGenerator(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db, WithIDGenerator(NewSequentialGenerator(99)))
+
+	task, err := repo.Create(context.Background(), &CreateTaskRequest{Title: "Numbered"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if task.Id != "100" {
+		t.Errorf("Expected ID from injected generator, got %s", task.Id)
+	}
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// CallRecorder receives one timing sample per repository call
+type CallRecorder interface {
+	RecordCall(ctx context.Context, method string, duration time.Duration, err error)
+}
+
+// LogCallRecorder records calls as database operation log entries named
+// "repository.<Method>"
+type LogCallRecorder struct {
+	Logger *middleware.StructuredLogger
+}
+
+// RecordCall logs the sample through LogDatabaseOperation
+func (l LogCallRecorder) RecordCall(ctx context.Context, method string, duration time.Duration, err error) {
+	l.Logger.LogDatabaseOperation(ctx, "repository."+method, duration, err == nil, 0)
+}
+
+// instrumentedTodoRepository times every call to the wrapped repository
+type instrumentedTodoRepository struct {
+	next     TodoRepository
+	recorder CallRecorder
+}
+
+// NewInstrumentedTodoRepository wraps repo so every method call is timed and
+// reported to recorder, tagged with the method name. It can wrap any other
+// decorator, e.g. the caching repository, to measure what callers observe.
+func NewInstrumentedTodoRepository(repo TodoRepository, recorder CallRecorder) TodoRepository {
+	return &instrumentedTodoRepository{next: repo, recorder: recorder}
+}
+
+// observe records the call that started at start; use with defer
+func (r *instrumentedTodoRepository) observe(ctx context.Context, method string, start time.Time, err *error) {
+	r.recorder.RecordCall(ctx, method, time.Since(start), *err)
+}
+
+func (r *instrumentedTodoRepository) Create(ctx context.Context, req *CreateTaskRequest) (task *todov1.Task, err error) {
+	defer r.observe(ctx, "Create", time.Now(), &err)
+	return r.next.Create(ctx, req)
+}
+
+func (r *instrumentedTodoRepository) GetByID(ctx context.Context, id string) (task *todov1.Task, err error) {
+	defer r.observe(ctx, "GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *instrumentedTodoRepository) List(ctx context.Context, filters *ListTasksRequest) (tasks []*todov1.Task, pagination *PaginationResult, err error) {
+	defer r.observe(ctx, "List", time.Now(), &err)
+	return r.next.List(ctx, filters)
+}
+
+func (r *instrumentedTodoRepository) ListIDs(ctx context.Context, filters *ListTasksRequest) (ids []string, pagination *PaginationResult, err error) {
+	defer r.observe(ctx, "ListIDs", time.Now(), &err)
+	return r.next.ListIDs(ctx, filters)
+}
+
+func (r *instrumentedTodoRepository) CountByState(ctx context.Context) (stats *TaskStats, err error) {
+	defer r.observe(ctx, "CountByState", time.Now(), &err)
+	return r.next.CountByState(ctx)
+}
+
+func (r *instrumentedTodoRepository) ListGrouped(ctx context.Context, filters *ListTasksRequest) (grouped *GroupedTasks, err error) {
+	defer r.observe(ctx, "ListGrouped", time.Now(), &err)
+	return r.next.ListGrouped(ctx, filters)
+}
+
+func (r *instrumentedTodoRepository) ListStream(ctx context.Context, filters *ListTasksRequest, chunkSize int, emit func([]*todov1.Task) error) (pagination *PaginationResult, err error) {
+	defer r.observe(ctx, "ListStream", time.Now(), &err)
+	return r.next.ListStream(ctx, filters, chunkSize, emit)
+}
+
+func (r *instrumentedTodoRepository) ListChangedSince(ctx context.Context, since time.Time, sinceID string, limit int) (tasks []*todov1.Task, err error) {
+	defer r.observe(ctx, "ListChangedSince", time.Now(), &err)
+	return r.next.ListChangedSince(ctx, since, sinceID, limit)
+}
+
+func (r *instrumentedTodoRepository) Count(ctx context.Context) (count uint32, err error) {
+	defer r.observe(ctx, "Count", time.Now(), &err)
+	return r.next.Count(ctx)
+}
+
+func (r *instrumentedTodoRepository) CountCreatedSince(ctx context.Context, since time.Time) (count uint32, err error) {
+	defer r.observe(ctx, "CountCreatedSince", time.Now(), &err)
+	return r.next.CountCreatedSince(ctx, since)
+}
+
+func (r *instrumentedTodoRepository) ListVersion(ctx context.Context) (version uint64, err error) {
+	defer r.observe(ctx, "ListVersion", time.Now(), &err)
+	return r.next.ListVersion(ctx)
+}
+
+func (r *instrumentedTodoRepository) ExistsByTitle(ctx context.Context, title string) (exists bool, err error) {
+	defer r.observe(ctx, "ExistsByTitle", time.Now(), &err)
+	return r.next.ExistsByTitle(ctx, title)
+}
+
+func (r *instrumentedTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (task, previous *todov1.Task, err error) {
+	defer r.observe(ctx, "Update", time.Now(), &err)
+	return r.next.Update(ctx, req)
+}
+
+func (r *instrumentedTodoRepository) Delete(ctx context.Context, id string) (err error) {
+	defer r.observe(ctx, "Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *instrumentedTodoRepository) Duplicate(ctx context.Context, id string, titleSuffix string) (task *todov1.Task, err error) {
+	defer r.observe(ctx, "Duplicate", time.Now(), &err)
+	return r.next.Duplicate(ctx, id, titleSuffix)
+}
+
+func (r *instrumentedTodoRepository) DeleteMany(ctx context.Context, ids []string) (results []*todov1.DeleteTaskResult, err error) {
+	defer r.observe(ctx, "DeleteMany", time.Now(), &err)
+	return r.next.DeleteMany(ctx, ids)
+}
+
+func (r *instrumentedTodoRepository) Reorder(ctx context.Context, id, afterID string) (task *todov1.Task, err error) {
+	defer r.observe(ctx, "Reorder", time.Now(), &err)
+	return r.next.Reorder(ctx, id, afterID)
+}
+
+func (r *instrumentedTodoRepository) ArchiveCompleted(ctx context.Context, before time.Time) (archived int64, err error) {
+	defer r.observe(ctx, "ArchiveCompleted", time.Now(), &err)
+	return r.next.ArchiveCompleted(ctx, before)
+}
+
+func (r *instrumentedTodoRepository) PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (purged int64, err error) {
+	defer r.observe(ctx, "PurgeDeleted", time.Now(), &err)
+	return r.next.PurgeDeleted(ctx, before, batchSize)
+}
+
+func (r *instrumentedTodoRepository) ClaimDueReminders(ctx context.Context, now time.Time, limit int) (tasks []*todov1.Task, err error) {
+	defer r.observe(ctx, "ClaimDueReminders", time.Now(), &err)
+	return r.next.ClaimDueReminders(ctx, now, limit)
+}
+
+func (r *instrumentedTodoRepository) Diagnose(ctx context.Context) (report *StorageReport, err error) {
+	defer r.observe(ctx, "Diagnose", time.Now(), &err)
+	return r.next.Diagnose(ctx)
+}
+
+func (r *instrumentedTodoRepository) HealthCheck(ctx context.Context) (err error) {
+	defer r.observe(ctx, "HealthCheck", time.Now(), &err)
+	return r.next.HealthCheck(ctx)
+}
+
+func (r *instrumentedTodoRepository) ReplicaHealthCheck(ctx context.Context) (err error) {
+	defer r.observe(ctx, "ReplicaHealthCheck", time.Now(), &err)
+	return r.next.ReplicaHealthCheck(ctx)
+}
+
+func (r *instrumentedTodoRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer r.observe(ctx, "WithTx", time.Now(), &err)
+	return r.next.WithTx(ctx, fn)
+}
+
+// StreamAll forwards the stream and records one sample covering the whole
+// stream once it ends
+func (r *instrumentedTodoRepository) StreamAll(ctx context.Context) (<-chan *todov1.Task, <-chan error) {
+	start := time.Now()
+	tasks, errs := r.next.StreamAll(ctx)
+
+	out := make(chan *todov1.Task)
+	outErrs := make(chan error, 1)
+	go func() {
+		defer close(outErrs)
+		defer close(out)
+
+		for task := range tasks {
+			select {
+			case out <- task:
+			case <-ctx.Done():
+				// The wrapped stream stops on the same cancellation; drain it so it can exit
+				for range tasks {
+				}
+			}
+		}
+
+		err := <-errs
+		r.recorder.RecordCall(ctx, "StreamAll", time.Since(start), err)
+		if err != nil {
+			outErrs <- err
+		}
+	}()
+
+	return out, outErrs
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+// sampleRecorder collects the method names of recorded calls
+type sampleRecorder struct {
+	mu      sync.Mutex
+	methods map[string]int
+	failed  map[string]int
+}
+
+func (r *sampleRecorder) RecordCall(ctx context.Context, method string, duration time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.methods[method]++
+	if err != nil {
+		r.failed[method]++
+	}
+}
+
+func TestInstrumentedTodoRepository_RecordsEveryMethod(t *testing.T) {
+	recorder := &sampleRecorder{methods: map[string]int{}, failed: map[string]int{}}
+	repo := NewInstrumentedTodoRepository(NewMockTodoRepository(), recorder)
+	ctx := context.Background()
+
+	task, _ := repo.Create(ctx, &CreateTaskRequest{Title: "Timed"})
+	repo.GetByID(ctx, task.Id)
+	repo.GetByID(ctx, "missing")
+	repo.List(ctx, &ListTasksRequest{})
+	repo.ListIDs(ctx, &ListTasksRequest{})
+	repo.ListGrouped(ctx, &ListTasksRequest{})
+	repo.ListStream(ctx, &ListTasksRequest{}, 10, func([]*todov1.Task) error { return nil })
+	repo.ListChangedSince(ctx, time.Time{}, "", 10)
+	repo.Count(ctx)
+	repo.CountCreatedSince(ctx, time.Time{})
+	repo.ListVersion(ctx)
+	repo.CountByState(ctx)
+	repo.ExistsByTitle(ctx, "Timed")
+	repo.Update(ctx, &UpdateTaskRequest{ID: task.Id, Completed: true})
+	copied, _ := repo.Duplicate(ctx, task.Id, " (copy)")
+	repo.Reorder(ctx, copied.Id, "")
+	repo.ArchiveCompleted(ctx, time.Now())
+	repo.ClaimDueReminders(ctx, time.Now(), 10)
+	repo.Diagnose(ctx)
+	repo.HealthCheck(ctx)
+	repo.ReplicaHealthCheck(ctx)
+	if _, err := collectStream(repo.StreamAll(ctx)); err != nil {
+		t.Fatalf("Failed to stream tasks: %v", err)
+	}
+	repo.Delete(ctx, copied.Id)
+	repo.DeleteMany(ctx, []string{task.Id})
+	repo.PurgeDeleted(ctx, time.Now(), 10)
+	repo.WithTx(ctx, func(ctx context.Context) error { return nil })
+
+	iface := reflect.TypeOf((*TodoRepository)(nil)).Elem()
+	var missing []string
+	for i := 0; i < iface.NumMethod(); i++ {
+		if recorder.methods[iface.Method(i).Name] == 0 {
+			missing = append(missing, iface.Method(i).Name)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("Expected a timing sample for every method, missing %v", missing)
+	}
+
+	if recorder.methods["GetByID"] != 2 || recorder.failed["GetByID"] != 1 {
+		t.Errorf("Expected two GetByID samples with one failure, got %d/%d", recorder.methods["GetByID"], recorder.failed["GetByID"])
+	}
+}
+
+func TestInstrumentedTodoRepository_StreamCancel(t *testing.T) {
+	recorder := &sampleRecorder{methods: map[string]int{}, failed: map[string]int{}}
+	mock := NewMockTodoRepository()
+	for _, title := range []string{"a", "b", "c"} {
+		mock.Create(context.Background(), &CreateTaskRequest{Title: title})
+	}
+	repo := NewInstrumentedTodoRepository(mock, recorder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tasks, errs := repo.StreamAll(ctx)
+	<-tasks
+	cancel()
+	for range tasks {
+	}
+	<-errs
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if recorder.methods["StreamAll"] != 1 {
+		t.Errorf("Expected one StreamAll sample, got %d", recorder.methods["StreamAll"])
+	}
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListIDs pages through tasks like List with the same filters, sorting,
+// pagination and counting, but selects only the id column, so sync clients can
+// cheaply fetch the full ID set and load details on demand.
+func (r *mysqlTodoRepository) ListIDs(ctx context.Context, filters *ListTasksRequest) ([]string, *PaginationResult, error) {
+	page, pageSize := pageBounds(filters, maxPageSize)
+	q := r.buildListQuery(ctx, filters)
+	db := r.reader(ctx)
+	pageArgs := q.pageArgs(pageSize, (page-1)*pageSize)
+
+	var ids []string
+	totalItems, totalUnfiltered, approximate, err := r.fetchCountedPage(ctx, db, q, func(extraColumns string, total *uint32) (int, error) {
+		var err error
+		ids, err = queryIDs(ctx, db, q.selectPage("id"+extraColumns), pageArgs, total)
+		return len(ids), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ids, newPagination(page, pageSize, totalItems, totalUnfiltered, approximate), nil
+}
+
+// queryIDs runs an ID page query, scanning the windowed total from the extra
+// last column into total when it is not nil
+func queryIDs(ctx context.Context, db queryer, query string, args []interface{}, total *uint32) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task IDs: %w", err)
+	}
+	defer rows.Close()
+
+	dest := []interface{}{nil}
+	if total != nil {
+		dest = append(dest, total)
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		dest[0] = &id
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan task ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read task IDs: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestListIDs_MatchesList(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		for _, title := range []string{"Buy milk", "Walk dog", "Buy eggs", "Buy bread", "Read book"} {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+		}
+
+		for _, filters := range []ListTasksRequest{
+			{PageSize: 2},
+			{Page: 2, PageSize: 2, Query: "buy", SortBy: todov1.SortField_SORT_FIELD_TITLE, SortOrder: todov1.SortOrder_SORT_ORDER_ASC},
+		} {
+			listFilters, idFilters := filters, filters
+			tasks, listPagination, err := repo.List(ctx, &listFilters)
+			if err != nil {
+				t.Fatalf("Failed to list tasks: %v", err)
+			}
+			ids, pagination, err := repo.ListIDs(ctx, &idFilters)
+			if err != nil {
+				t.Fatalf("Failed to list task IDs: %v", err)
+			}
+
+			if len(ids) != len(tasks) {
+				t.Fatalf("Expected %d IDs, got %v", len(tasks), ids)
+			}
+			for i, task := range tasks {
+				if ids[i] != task.Id {
+					t.Errorf("Expected ID %d to be %s, got %s", i, task.Id, ids[i])
+				}
+			}
+			if *pagination != *listPagination {
+				t.Errorf("Expected pagination %+v, got %+v", *listPagination, *pagination)
+			}
+		}
+	})
+}
+
+func TestMySQLTodoRepository_ListIDsWindowedCount(t *testing.T) {
+	repo := NewMySQLTodoRepository(setupTestDB(t), WithWindowedCount())
+	ctx := context.Background()
+	for _, title := range []string{"a", "b", "c"} {
+		if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	// The last page is counted from its rows, a page past the end by COUNT
+	for _, page := range []uint32{2, 3} {
+		ids, pagination, err := repo.ListIDs(ctx, &ListTasksRequest{Page: page, PageSize: 2})
+		if err != nil {
+			t.Fatalf("Failed to list task IDs: %v", err)
+		}
+		if pagination.TotalItems != 3 || pagination.TotalPages != 2 {
+			t.Errorf("Expected 3 tasks over 2 pages on page %d, got %+v", page, *pagination)
+		}
+		if want := 3 - int(page); len(ids) != max(want, 0) {
+			t.Errorf("Expected %d IDs on page %d, got %v", max(want, 0), page, ids)
+		}
+	}
+}
+
+func TestMySQLTodoRepository_ListIDsApproximateCount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT TABLE_ROWS FROM information_schema.TABLES").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_ROWS"}).AddRow(5000000))
+	mock.ExpectQuery("SELECT id FROM tasks").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("task-1"))
+
+	repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1000000))
+	ids, pagination, err := repo.ListIDs(context.Background(), &ListTasksRequest{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("Failed to list task IDs: %v", err)
+	}
+	if len(ids) != 1 || !pagination.Approximate || pagination.TotalItems != 5000000 {
+		t.Errorf("Expected the estimated total like List, got %d IDs and %+v", len(ids), *pagination)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
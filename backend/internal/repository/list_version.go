@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// ErrListVersionDisabled is returned by ListVersion when the repository does
+// not track list versions
+var ErrListVersionDisabled = errors.New("list versions are not tracked")
+
+// WithListVersion keeps a per-tenant counter in the list_versions table that
+// every mutation advances inside its own transaction, so clients can cheaply
+// tell whether anything changed since their last fetch. The table must exist;
+// see db.InitDB.
+func WithListVersion() Option {
+	return func(r *mysqlTodoRepository) {
+		r.listVersion = true
+	}
+}
+
+// ListVersion returns the list version for ctx's tenant, or the sum over all
+// tenants when ctx is unscoped. It is 0 until the first mutation.
+func (r *mysqlTodoRepository) ListVersion(ctx context.Context) (uint64, error) {
+	if !r.listVersion {
+		return 0, ErrListVersionDisabled
+	}
+
+	query := "SELECT COALESCE(SUM(version), 0) FROM list_versions"
+	cond, args := tenantScope(ctx)
+	if cond != "" {
+		query += " WHERE " + cond
+	}
+
+	var version int64
+	if err := r.reader(ctx).QueryRowContext(ctx, query, args...).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read list version: %w", err)
+	}
+	return uint64(version), nil
+}
+
+// execMutation runs a write statement, in a transaction that also advances the
+// list version when versions are tracked
+func (r *mysqlTodoRepository) execMutation(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !r.listVersion {
+		return r.exec(ctx, query, args...)
+	}
+
+	var result sql.Result
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if result, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		return r.bumpListVersion(ctx, tx, rowsAffected)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// queryMutation runs a write that returns one row, such as INSERT ... RETURNING,
+// scanning it into dest, and advances the list version in the same transaction
+func (r *mysqlTodoRepository) queryMutation(ctx context.Context, query string, args []interface{}, dest ...interface{}) error {
+	if !r.listVersion {
+		return r.queryRow(ctx, r.primary(ctx), query, args, dest...)
+	}
+
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
+			return err
+		}
+		return r.bumpListVersion(ctx, tx, 1)
+	})
+}
+
+// bumpListVersion advances the list version within tx after a write that
+// affected rowsAffected rows. The row for ctx's tenant is created on first
+// use; an unscoped write may have touched any tenant, so it advances them all.
+// It does nothing when versions are not tracked or no rows changed.
+func (r *mysqlTodoRepository) bumpListVersion(ctx context.Context, tx *sql.Tx, rowsAffected int64) error {
+	if !r.listVersion || rowsAffected == 0 {
+		return nil
+	}
+
+	const bump = "UPDATE list_versions SET version = version + 1 WHERE tenant_id = ?"
+	tenant := tenantOf(ctx)
+	result, err := tx.ExecContext(ctx, bump, tenant)
+	if err == nil {
+		var n int64
+		if n, err = result.RowsAffected(); err == nil && n == 0 {
+			_, err = tx.ExecContext(ctx, "INSERT INTO list_versions (tenant_id, version) VALUES (?, 1)", tenant)
+			// A concurrent first write for the tenant inserted the row first
+			if isDuplicateKey(err) {
+				_, err = tx.ExecContext(ctx, bump, tenant)
+			}
+		}
+	}
+	if err == nil && middleware.GetTenant(ctx) == "" {
+		_, err = tx.ExecContext(ctx, "UPDATE list_versions SET version = version + 1 WHERE tenant_id <> ?", tenant)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to advance list version: %w", err)
+	}
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique-key violation from MySQL or SQLite
+func isDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
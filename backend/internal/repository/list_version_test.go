@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestListVersion_AdvancesOnMutation(t *testing.T) {
+	repos := map[string]func() TodoRepository{
+		"mysql": func() TodoRepository { return NewSQLiteTodoRepository(setupTestDB(t), WithListVersion()) },
+		"mock":  func() TodoRepository { return NewMockTodoRepository() },
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo()
+
+			last, err := repo.ListVersion(ctx)
+			if err != nil {
+				t.Fatalf("Failed to read list version: %v", err)
+			}
+			if last != 0 {
+				t.Errorf("Expected version 0 before any mutation, got %d", last)
+			}
+			expectAdvanced := func(step string) {
+				t.Helper()
+				version, err := repo.ListVersion(ctx)
+				if err != nil {
+					t.Fatalf("Failed to read list version: %v", err)
+				}
+				if version <= last {
+					t.Errorf("Expected %s to advance the version past %d, got %d", step, last, version)
+				}
+				last = version
+			}
+
+			task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Versioned"})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			expectAdvanced("create")
+
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: task.Id, Title: "Versioned", Completed: true}); err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			expectAdvanced("update")
+
+			if _, err := repo.ArchiveCompleted(ctx, time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("Failed to archive tasks: %v", err)
+			}
+			expectAdvanced("archive")
+
+			if err := repo.Delete(ctx, task.Id); err != nil {
+				t.Fatalf("Failed to delete task: %v", err)
+			}
+			expectAdvanced("delete")
+
+			if err := repo.Delete(ctx, task.Id); err == nil {
+				t.Fatal("Expected deleting a missing task to fail")
+			}
+			if version, _ := repo.ListVersion(ctx); version != last {
+				t.Errorf("Expected a failed delete to keep version %d, got %d", last, version)
+			}
+		})
+	}
+}
+
+func TestListVersion_TenantScoped(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t), WithListVersion())
+	acme := middleware.WithTenant(context.Background(), "acme")
+	globex := middleware.WithTenant(context.Background(), "globex")
+
+	if _, err := repo.Create(acme, &CreateTaskRequest{Title: "Acme task"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := repo.Create(acme, &CreateTaskRequest{Title: "Another acme task"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if version, err := repo.ListVersion(acme); err != nil || version != 2 {
+		t.Errorf("Expected acme at version 2, got %d (%v)", version, err)
+	}
+	if version, err := repo.ListVersion(globex); err != nil || version != 0 {
+		t.Errorf("Expected globex untouched at version 0, got %d (%v)", version, err)
+	}
+
+	// An unscoped write may touch any tenant, so every tenant moves on
+	if _, err := repo.Create(context.Background(), &CreateTaskRequest{Title: "Default task"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if version, _ := repo.ListVersion(acme); version != 3 {
+		t.Errorf("Expected an unscoped write to advance acme to 3, got %d", version)
+	}
+}
+
+func TestListVersion_Disabled(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	if _, err := repo.Create(context.Background(), &CreateTaskRequest{Title: "Untracked"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	if _, err := repo.ListVersion(context.Background()); !errors.Is(err, ErrListVersionDisabled) {
+		t.Errorf("Expected ErrListVersionDisabled, got %v", err)
+	}
+}
@@ -3,33 +3,108 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/google/uuid"
-	todov1 "buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go/todo/v1"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // MockTodoRepository is an in-memory implementation for testing
 type MockTodoRepository struct {
-	mu           sync.RWMutex
-	tasks        map[string]*todov1.Task
-	healthError  error
-	createError  error
-	getError     error
-	listError    error
-	updateError  error
-	deleteError  error
+	mu               sync.RWMutex
+	tasks            map[string]*todov1.Task
+	remindedAt       map[string]time.Time
+	deletedAt        map[string]time.Time // deleted task IDs, like deleted_tasks
+	softDelete       bool
+	idGen            IDGenerator
+	clock            Clock
+	defaultSortField todov1.SortField
+	defaultSortOrder todov1.SortOrder
+	listVersion      uint64
+	uniqueTitles     bool
+	healthError      error
+	replicaError     error
+	createError      error
+	getError         error
+	listError        error
+	updateError      error
+	deleteError      error
 }
 
 // NewMockTodoRepository creates a new mock repository
 func NewMockTodoRepository() *MockTodoRepository {
 	return &MockTodoRepository{
-		tasks: make(map[string]*todov1.Task),
+		tasks:            make(map[string]*todov1.Task),
+		remindedAt:       make(map[string]time.Time),
+		deletedAt:        make(map[string]time.Time),
+		idGen:            UUIDGenerator{},
+		clock:            realClock{},
+		defaultSortField: defaultSortField,
+		defaultSortOrder: defaultSortOrder,
+		replicaError:     ErrNoReplica,
 	}
 }
 
+// SetDefaultSort sets the ordering List uses when a request leaves the sort
+// field unspecified, like WithDefaultSort
+func (m *MockTodoRepository) SetDefaultSort(field todov1.SortField, order todov1.SortOrder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := sortColumns[field]; ok {
+		m.defaultSortField = field
+		m.defaultSortOrder = order
+	}
+}
+
+// SetClock sets the time source used to stamp tasks
+func (m *MockTodoRepository) SetClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+}
+
+// SetIDGenerator sets the generator used for new task IDs
+func (m *MockTodoRepository) SetIDGenerator(gen IDGenerator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.idGen = gen
+}
+
+// SetSoftDelete makes deletes keep tasks in the trash, counted as deleted and
+// removed by PurgeDeleted, like WithSoftDelete
+func (m *MockTodoRepository) SetSoftDelete(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.softDelete = enabled
+}
+
+// SetUniqueTitles makes Create, Update and Duplicate reject titles already in
+// use, like WithUniqueTitles
+func (m *MockTodoRepository) SetUniqueTitles(unique bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.uniqueTitles = unique
+}
+
+// checkTitleFree returns a duplicate title error when unique titles are on and
+// a task other than excludeID, archived or not, has title, ignoring case.
+// Callers hold m.mu.
+func (m *MockTodoRepository) checkTitleFree(title, excludeID string) error {
+	if !m.uniqueTitles {
+		return nil
+	}
+	for _, task := range m.tasks {
+		if task.Id != excludeID && strings.EqualFold(task.Title, title) {
+			return duplicateTitleError(title)
+		}
+	}
+	return nil
+}
+
 // SetHealthError makes health check return the specified error
 func (m *MockTodoRepository) SetHealthError(err error) {
 	m.mu.Lock()
@@ -37,6 +112,14 @@ func (m *MockTodoRepository) SetHealthError(err error) {
 	m.healthError = err
 }
 
+// SetReplicaHealthError makes the replica health check return the specified
+// error; nil simulates a healthy replica. A new mock has no replica.
+func (m *MockTodoRepository) SetReplicaHealthError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.replicaError = err
+}
+
 // SetCreateError makes create operations return the specified error
 func (m *MockTodoRepository) SetCreateError(err error) {
 	m.mu.Lock()
@@ -81,8 +164,16 @@ func (m *MockTodoRepository) Create(ctx context.Context, req *CreateTaskRequest)
 		return nil, m.createError
 	}
 
-	id := uuid.New().String()
-	now := timestamppb.Now()
+	id := req.ID
+	if id == "" {
+		id = m.idGen.NewID()
+	} else if _, exists := m.tasks[id]; exists {
+		return nil, fmt.Errorf("duplicate task id: %s", id)
+	}
+	if err := m.checkTitleFree(req.Title, ""); err != nil {
+		return nil, err
+	}
+	now := timestamppb.New(m.clock.Now())
 	
 	task := &todov1.Task{
 		Id:        id,
@@ -90,12 +181,23 @@ func (m *MockTodoRepository) Create(ctx context.Context, req *CreateTaskRequest)
 		Completed: false,
 		CreatedAt: now,
 		UpdatedAt: now,
+		Position:  m.nextPosition(),
+		DueDate:   toDueDate(req.DueDate),
 	}
 
 	m.tasks[id] = task
+	m.listVersion++
 	return task, nil
 }
 
+// toDueDate converts an optional due date to a protobuf timestamp
+func toDueDate(dueDate *time.Time) *timestamppb.Timestamp {
+	if dueDate == nil {
+		return nil
+	}
+	return timestamppb.New(*dueDate)
+}
+
 // GetByID retrieves a task by ID
 func (m *MockTodoRepository) GetByID(ctx context.Context, id string) (*todov1.Task, error) {
 	m.mu.RLock()
@@ -122,9 +224,62 @@ func (m *MockTodoRepository) List(ctx context.Context, filters *ListTasksRequest
 		return nil, nil, m.listError
 	}
 
-	// Convert map to slice
+	tasks, pagination := m.list(filters, maxPageSize)
+	return tasks, pagination, nil
+}
+
+// ListIDs returns the IDs of the page List would return
+func (m *MockTodoRepository) ListIDs(ctx context.Context, filters *ListTasksRequest) ([]string, *PaginationResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return nil, nil, m.listError
+	}
+
+	tasks, pagination := m.list(filters, maxPageSize)
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.Id
+	}
+	return ids, pagination, nil
+}
+
+// ListStream emits the page List would return, with up to maxStreamPageSize
+// tasks, in chunks of up to chunkSize
+func (m *MockTodoRepository) ListStream(ctx context.Context, filters *ListTasksRequest, chunkSize int, emit func([]*todov1.Task) error) (*PaginationResult, error) {
+	m.mu.RLock()
+	if m.listError != nil {
+		m.mu.RUnlock()
+		return nil, m.listError
+	}
+	tasks, pagination := m.list(filters, maxStreamPageSize)
+	m.mu.RUnlock()
+
+	if chunkSize <= 0 {
+		chunkSize = 100
+	}
+	for start := 0; start < len(tasks); start += chunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(start+chunkSize, len(tasks))
+		if err := emit(tasks[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return pagination, nil
+}
+
+// list filters, sorts and pages the tasks, capping the page size at limit.
+// The caller holds m.mu.
+func (m *MockTodoRepository) list(filters *ListTasksRequest, limit uint32) ([]*todov1.Task, *PaginationResult) {
+	// Convert map to slice, leaving out archived tasks unless requested
 	allTasks := make([]*todov1.Task, 0, len(m.tasks))
 	for _, task := range m.tasks {
+		if task.ArchivedAt != nil && !filters.IncludeArchived {
+			continue
+		}
 		allTasks = append(allTasks, task)
 	}
 
@@ -148,24 +303,30 @@ func (m *MockTodoRepository) List(ctx context.Context, filters *ListTasksRequest
 			}
 		}
 
+		switch filters.DueDate {
+		case todov1.DueDateFilter_DUE_DATE_FILTER_SCHEDULED:
+			if task.DueDate == nil {
+				continue
+			}
+		case todov1.DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED:
+			if task.DueDate != nil {
+				continue
+			}
+		}
+
 		filteredTasks = append(filteredTasks, task)
 	}
 
-	// Apply pagination
-	page := filters.Page
-	if page == 0 {
-		page = 1
-	}
-	pageSize := filters.PageSize
-	if pageSize == 0 {
-		pageSize = 20
-	}
-	if pageSize > 100 {
-		pageSize = 100
+	if filters.SortBy == todov1.SortField_SORT_FIELD_RELEVANCE && filters.Query != "" {
+		sortByRelevance(filteredTasks, filters.Query)
+	} else {
+		sortBy, sortOrder := resolveSort(filters.SortBy, filters.SortOrder, m.defaultSortField, m.defaultSortOrder)
+		sortTasks(filteredTasks, sortBy, sortOrder)
 	}
 
+	// Apply pagination
+	page, pageSize := pageBounds(filters, limit)
 	totalItems := uint32(len(filteredTasks))
-	totalPages := (totalItems + pageSize - 1) / pageSize
 	offset := (page - 1) * pageSize
 
 	// Get page slice
@@ -178,40 +339,185 @@ func (m *MockTodoRepository) List(ctx context.Context, filters *ListTasksRequest
 		pageTasks = filteredTasks[offset:end]
 	}
 
-	pagination := &PaginationResult{
-		Page:        page,
-		PageSize:    pageSize,
-		TotalPages:  totalPages,
-		TotalItems:  totalItems,
-		HasPrevious: page > 1,
-		HasNext:     page < totalPages,
+	return pageTasks, newPagination(page, pageSize, totalItems, uint32(len(allTasks)), false)
+}
+
+// sortTasks orders tasks like the MySQL repository: by the resolved field,
+// in sortAscending's direction, with id as a tie-breaker in the same direction
+func sortTasks(tasks []*todov1.Task, sortBy todov1.SortField, sortOrder todov1.SortOrder) {
+	compare := func(a, b *todov1.Task) int {
+		switch sortBy {
+		case todov1.SortField_SORT_FIELD_UPDATED_AT:
+			return a.UpdatedAt.AsTime().Compare(b.UpdatedAt.AsTime())
+		case todov1.SortField_SORT_FIELD_TITLE:
+			// Case-insensitive like the utf8mb4_unicode_ci column collation
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		case todov1.SortField_SORT_FIELD_POSITION:
+			switch {
+			case a.Position < b.Position:
+				return -1
+			case a.Position > b.Position:
+				return 1
+			}
+			return 0
+		default:
+			return a.CreatedAt.AsTime().Compare(b.CreatedAt.AsTime())
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		c := compare(tasks[i], tasks[j])
+		if c == 0 {
+			c = strings.Compare(tasks[i].Id, tasks[j].Id)
+		}
+		if sortAscending(sortBy, sortOrder) {
+			return c < 0
+		}
+		return c > 0
+	})
+}
+
+// Duplicate copies an existing task into a new pending task
+func (m *MockTodoRepository) Duplicate(ctx context.Context, id string, titleSuffix string) (*todov1.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.createError != nil {
+		return nil, m.createError
+	}
+
+	source, exists := m.tasks[id]
+	if !exists {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+
+	title := duplicateTitle(source.Title, titleSuffix)
+	if err := m.checkTitleFree(title, ""); err != nil {
+		return nil, err
+	}
+
+	now := timestamppb.New(m.clock.Now())
+	task := &todov1.Task{
+		Id:        m.idGen.NewID(),
+		Title:     title,
+		Completed: false,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Position:  m.nextPosition(),
+		DueDate:   source.DueDate,
+	}
+
+	m.tasks[task.Id] = task
+	m.listVersion++
+	return task, nil
+}
+
+// ListGrouped lists pending and completed tasks as separately paginated groups
+func (m *MockTodoRepository) ListGrouped(ctx context.Context, filters *ListTasksRequest) (*GroupedTasks, error) {
+	return listGrouped(ctx, m, filters)
+}
+
+// Count returns the total number of tasks
+func (m *MockTodoRepository) Count(ctx context.Context) (uint32, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return 0, m.listError
+	}
+
+	return uint32(len(m.tasks)), nil
+}
+
+// CountCreatedSince returns the number of tasks created at or after since
+func (m *MockTodoRepository) CountCreatedSince(ctx context.Context, since time.Time) (uint32, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return 0, m.listError
+	}
+
+	var count uint32
+	for _, task := range m.tasks {
+		if task.CreatedAt != nil && !task.CreatedAt.AsTime().Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListVersion returns how many mutations the mock has applied
+func (m *MockTodoRepository) ListVersion(ctx context.Context) (uint64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return 0, m.listError
+	}
+	return m.listVersion, nil
+}
+
+// ExistsByTitle reports whether a task with the given title exists, ignoring case
+func (m *MockTodoRepository) ExistsByTitle(ctx context.Context, title string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.getError != nil {
+		return false, m.getError
 	}
 
-	return pageTasks, pagination, nil
+	for _, task := range m.tasks {
+		if strings.EqualFold(task.Title, title) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-// Update modifies an existing task
-func (m *MockTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, error) {
+// Update modifies an existing task, returning it and a copy of it as it was before
+func (m *MockTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, *todov1.Task, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if m.updateError != nil {
-		return nil, m.updateError
+		return nil, nil, m.updateError
+	}
+	if req.Fields().Title && req.Title == "" {
+		return nil, nil, errEmptyTitleUpdate
 	}
 
 	task, exists := m.tasks[req.ID]
 	if !exists {
-		return nil, fmt.Errorf("task not found: %s", req.ID)
+		return nil, nil, fmt.Errorf("task not found: %s", req.ID)
+	}
+	previous := proto.Clone(task).(*todov1.Task)
+	changed := req.Changes(previous)
+	if req.Fields().Title && req.Title != task.Title {
+		if err := m.checkTitleFree(req.Title, req.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if !changed && req.SkipNoOp {
+		return task, previous, nil
 	}
 
-	// Update fields
-	if req.Title != "" {
+	// Update the selected fields
+	fields := req.Fields()
+	if fields.Title {
 		task.Title = req.Title
 	}
-	task.Completed = req.Completed
-	task.UpdatedAt = timestamppb.Now()
+	if fields.Completed {
+		task.Completed = req.Completed
+	}
+	if fields.DueDate {
+		task.DueDate = toDueDate(req.DueDate)
+		delete(m.remindedAt, task.Id)
+	}
+	task.UpdatedAt = timestamppb.New(m.clock.Now())
+	m.listVersion++
 
-	return task, nil
+	return task, previous, nil
 }
 
 // Delete removes a task
@@ -229,9 +535,183 @@ func (m *MockTodoRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	delete(m.tasks, id)
+	if m.softDelete {
+		m.deletedAt[id] = m.clock.Now()
+	}
+	m.listVersion++
 	return nil
 }
 
+// DeleteMany removes several tasks, reporting a result per id
+func (m *MockTodoRepository) DeleteMany(ctx context.Context, ids []string) ([]*todov1.DeleteTaskResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	results := make([]*todov1.DeleteTaskResult, 0, len(ids))
+	for _, id := range ids {
+		result := &todov1.DeleteTaskResult{Id: id}
+
+		if m.deleteError != nil {
+			result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_ERROR
+			result.Error = m.deleteError.Error()
+		} else if _, exists := m.tasks[id]; exists {
+			delete(m.tasks, id)
+			if m.softDelete {
+				m.deletedAt[id] = m.clock.Now()
+			}
+			m.listVersion++
+			result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_DELETED
+		} else {
+			result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// CountByState counts tasks by state; every deleted task counts as in the trash
+func (m *MockTodoRepository) CountByState(ctx context.Context) (*TaskStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.listError != nil {
+		return nil, m.listError
+	}
+
+	stats := &TaskStats{Deleted: uint32(len(m.deletedAt))}
+	for _, task := range m.tasks {
+		switch {
+		case task.ArchivedAt != nil:
+			stats.Archived++
+		case task.Completed:
+			stats.Completed++
+		default:
+			stats.Active++
+		}
+	}
+	return stats, nil
+}
+
+// ArchiveCompleted flags completed tasks last updated before the cutoff as archived
+func (m *MockTodoRepository) ArchiveCompleted(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.updateError != nil {
+		return 0, m.updateError
+	}
+
+	var archived int64
+	now := timestamppb.New(m.clock.Now())
+	for _, task := range m.tasks {
+		if !task.Completed || task.ArchivedAt != nil || task.UpdatedAt == nil || !task.UpdatedAt.AsTime().Before(before) {
+			continue
+		}
+		task.ArchivedAt = now
+		archived++
+	}
+	if archived > 0 {
+		m.listVersion++
+	}
+
+	return archived, nil
+}
+
+// PurgeDeleted forgets deleted tasks whose deletion time is before the cutoff
+func (m *MockTodoRepository) PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.softDelete {
+		return 0, ErrSoftDeleteDisabled
+	}
+	if m.deleteError != nil {
+		return 0, m.deleteError
+	}
+
+	var purged int64
+	for id, deletedAt := range m.deletedAt {
+		if deletedAt.Before(before) {
+			delete(m.deletedAt, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// ClaimDueReminders marks and returns up to limit due, incomplete tasks that
+// have not been reminded yet, earliest due date first
+func (m *MockTodoRepository) ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]*todov1.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.updateError != nil {
+		return nil, m.updateError
+	}
+
+	due := []*todov1.Task{}
+	for _, task := range m.tasks {
+		if task.Completed || task.ArchivedAt != nil || task.DueDate == nil || task.DueDate.AsTime().After(now) {
+			continue
+		}
+		if _, reminded := m.remindedAt[task.Id]; reminded {
+			continue
+		}
+		due = append(due, task)
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].DueDate.AsTime().Equal(due[j].DueDate.AsTime()) {
+			return due[i].DueDate.AsTime().Before(due[j].DueDate.AsTime())
+		}
+		return due[i].Id < due[j].Id
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for _, task := range due {
+		m.remindedAt[task.Id] = now
+	}
+	return due, nil
+}
+
+// StreamAll sends a snapshot of every task in id order on the returned channel
+func (m *MockTodoRepository) StreamAll(ctx context.Context) (<-chan *todov1.Task, <-chan error) {
+	tasks := make(chan *todov1.Task)
+	errs := make(chan error, 1)
+
+	m.mu.RLock()
+	snapshot := make([]*todov1.Task, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		snapshot = append(snapshot, task)
+	}
+	listError := m.listError
+	m.mu.RUnlock()
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Id < snapshot[j].Id })
+
+	go func() {
+		defer close(errs)
+		defer close(tasks)
+
+		if listError != nil {
+			errs <- listError
+			return
+		}
+		for _, task := range snapshot {
+			select {
+			case tasks <- task:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return tasks, errs
+}
+
 // HealthCheck verifies the repository is healthy
 func (m *MockTodoRepository) HealthCheck(ctx context.Context) error {
 	m.mu.RLock()
@@ -239,12 +719,20 @@ func (m *MockTodoRepository) HealthCheck(ctx context.Context) error {
 	return m.healthError
 }
 
+// ReplicaHealthCheck returns the error set by SetReplicaHealthError
+func (m *MockTodoRepository) ReplicaHealthCheck(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.replicaError
+}
+
 // Reset clears all tasks and errors
 func (m *MockTodoRepository) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.tasks = make(map[string]*todov1.Task)
 	m.healthError = nil
+	m.replicaError = ErrNoReplica
 	m.createError = nil
 	m.getError = nil
 	m.listError = nil
@@ -252,6 +740,80 @@ func (m *MockTodoRepository) Reset() {
 	m.deleteError = nil
 }
 
+// Reorder moves a task directly after afterID, or to the top when afterID is empty
+func (m *MockTodoRepository) Reorder(ctx context.Context, id, afterID string) (*todov1.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	task, exists := m.tasks[id]
+	if !exists {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	if _, exists := m.tasks[afterID]; afterID != "" && !exists {
+		return nil, fmt.Errorf("task not found: %s", afterID)
+	}
+
+	for attempt := 0; ; attempt++ {
+		ordered := m.tasksByPosition()
+
+		var prev, next float64
+		var hasPrev, hasNext bool
+		i := 0
+		if afterID != "" {
+			for ordered[i].Id != afterID {
+				i++
+			}
+			prev, hasPrev = ordered[i].Position, true
+			i++
+		}
+		for ; i < len(ordered); i++ {
+			if ordered[i].Id != id {
+				next, hasNext = ordered[i].Position, true
+				break
+			}
+		}
+
+		if position, ok := positionBetween(prev, next, hasPrev, hasNext); ok {
+			task.Position = position
+			task.UpdatedAt = timestamppb.New(m.clock.Now())
+			m.listVersion++
+			return task, nil
+		}
+		if attempt > 0 {
+			return nil, fmt.Errorf("failed to move task: no room after rebalancing")
+		}
+		for i, t := range ordered {
+			t.Position = float64(i+1) * positionStep
+		}
+	}
+}
+
+// tasksByPosition returns all tasks in (position, id) order. Callers hold m.mu.
+func (m *MockTodoRepository) tasksByPosition() []*todov1.Task {
+	ordered := make([]*todov1.Task, 0, len(m.tasks))
+	for _, task := range m.tasks {
+		ordered = append(ordered, task)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].Position != ordered[j].Position {
+			return ordered[i].Position < ordered[j].Position
+		}
+		return ordered[i].Id < ordered[j].Id
+	})
+	return ordered
+}
+
+// nextPosition returns the position for a task appended to the end. Callers hold m.mu.
+func (m *MockTodoRepository) nextPosition() float64 {
+	highest := 0.0
+	for _, task := range m.tasks {
+		if task.Position > highest {
+			highest = task.Position
+		}
+	}
+	return highest + positionStep
+}
+
 // AddTask adds a task directly (for testing setup)
 func (m *MockTodoRepository) AddTask(task *todov1.Task) {
 	m.mu.Lock()
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// Tasks are ordered manually by a fractional position. Moving a task only
+// rewrites its own position, halfway between its new neighbours; when repeated
+// moves leave neighbours too close to split, positions are rebalanced.
+const (
+	// positionStep spaces new and rebalanced tasks apart
+	positionStep = 1024.0
+
+	// minPositionGap is the smallest gap that is still split rather than rebalanced
+	minPositionGap = 1e-6
+)
+
+// appendPosition is the position of a task added positionStep past the last
+// one, as an aggregate over the rows appendScope selects
+var appendPosition = fmt.Sprintf("COALESCE(MAX(position), 0) + %g", positionStep)
+
+// appendScope returns the FROM clause, limited to ctx's tenant, that an
+// INSERT ... SELECT aggregates appendPosition over
+func appendScope(ctx context.Context) (string, []interface{}) {
+	cond, args := tenantScope(ctx)
+	if cond == "" {
+		return "FROM tasks", nil
+	}
+	return "FROM tasks WHERE " + cond, args
+}
+
+// positionBetween returns a position strictly between prev and next, where
+// hasPrev/hasNext are false at the ends of the list. ok is false when the
+// neighbours are too close together and positions need rebalancing.
+func positionBetween(prev, next float64, hasPrev, hasNext bool) (position float64, ok bool) {
+	switch {
+	case hasPrev && hasNext:
+		if next-prev < minPositionGap {
+			return 0, false
+		}
+		return prev + (next-prev)/2, true
+	case hasPrev:
+		return prev + positionStep, true
+	case hasNext:
+		return next - positionStep, true
+	default:
+		return positionStep, true
+	}
+}
+
+// Reorder moves a task directly after afterID in the manual ordering, or to the
+// top when afterID is empty
+func (r *mysqlTodoRepository) Reorder(ctx context.Context, id, afterID string) (*todov1.Task, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.Reorder")
+
+	var task *todov1.Task
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := r.getByID(ctx, tx, id); err != nil {
+			return err
+		}
+
+		// At most one rebalance is needed to open up a gap
+		for attempt := 0; ; attempt++ {
+			prev, next, hasPrev, hasNext, err := r.positionNeighbours(ctx, tx, id, afterID)
+			if err != nil {
+				return err
+			}
+
+			position, ok := positionBetween(prev, next, hasPrev, hasNext)
+			if ok {
+				// A move counts as an edit; MySQL stamps it unless the clock does
+				set, setArgs := "position = ?", []interface{}{position}
+				if r.clock != nil {
+					set += ", updated_at = ?"
+					setArgs = append(setArgs, r.clock.Now())
+				}
+				where, args := scopedWhere(ctx, "id = ?", id)
+				if _, err := tx.ExecContext(ctx, "UPDATE tasks SET "+set+" WHERE "+where, append(setArgs, args...)...); err != nil {
+					return fmt.Errorf("failed to move task: %w", err)
+				}
+				break
+			}
+			if attempt > 0 {
+				return fmt.Errorf("failed to move task: no room after rebalancing")
+			}
+			if err := r.rebalancePositions(ctx, tx); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if task, err = r.getByID(ctx, tx, id); err != nil {
+			return err
+		}
+		return r.bumpListVersion(ctx, tx, 1)
+	})
+	r.logger.LogDatabaseOperation(ctx, "UPDATE tasks position", time.Since(start), err == nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	r.markWrite()
+
+	return task, nil
+}
+
+// positionNeighbours returns the positions the moved task will sit between:
+// afterID's and that of the task following it in (position, id) order
+func (r *mysqlTodoRepository) positionNeighbours(ctx context.Context, tx *sql.Tx, id, afterID string) (prev, next float64, hasPrev, hasNext bool, err error) {
+	var nextPosition sql.NullFloat64
+
+	if afterID == "" {
+		where, args := scopedWhere(ctx, "id <> ?", id)
+		err = tx.QueryRowContext(ctx, "SELECT position FROM tasks WHERE "+where+" ORDER BY position, id LIMIT 1", args...).Scan(&nextPosition)
+	} else {
+		where, args := scopedWhere(ctx, "id = ?", afterID)
+		err = tx.QueryRowContext(ctx, "SELECT position FROM tasks WHERE "+where, args...).Scan(&prev)
+		if err == sql.ErrNoRows {
+			return 0, 0, false, false, fmt.Errorf("task not found: %s", afterID)
+		}
+		if err != nil {
+			return 0, 0, false, false, fmt.Errorf("failed to get task position: %w", err)
+		}
+		hasPrev = true
+
+		where, args = scopedWhere(ctx, "(position > ? OR (position = ? AND id > ?)) AND id <> ?", prev, prev, afterID, id)
+		err = tx.QueryRowContext(ctx, "SELECT position FROM tasks WHERE "+where+" ORDER BY position, id LIMIT 1", args...).Scan(&nextPosition)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return 0, 0, false, false, fmt.Errorf("failed to get task position: %w", err)
+	}
+
+	return prev, nextPosition.Float64, hasPrev, nextPosition.Valid, nil
+}
+
+// rebalancePositions respaces every task in scope positionStep apart, keeping
+// the current order. updated_at is left untouched since nothing visibly changed.
+func (r *mysqlTodoRepository) rebalancePositions(ctx context.Context, tx *sql.Tx) error {
+	query := "SELECT id FROM tasks"
+	cond, args := tenantScope(ctx)
+	if cond != "" {
+		query += " WHERE " + cond
+	}
+
+	rows, err := tx.QueryContext(ctx, query+" ORDER BY position, id", args...)
+	if err != nil {
+		return fmt.Errorf("failed to rebalance positions: %w", err)
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to rebalance positions: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to rebalance positions: %w", err)
+	}
+
+	for i, id := range ids {
+		_, err := tx.ExecContext(ctx, "UPDATE tasks SET position = ?, updated_at = updated_at WHERE id = ?", float64(i+1)*positionStep, id)
+		if err != nil {
+			return fmt.Errorf("failed to rebalance positions: %w", err)
+		}
+	}
+
+	r.logger.Info(ctx, "Rebalanced task positions", map[string]interface{}{
+		"tasks": len(ids),
+	})
+	return nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestPositionBetween(t *testing.T) {
+	tests := []struct {
+		name             string
+		prev, next       float64
+		hasPrev, hasNext bool
+		want             float64
+		wantOK           bool
+	}{
+		{name: "between neighbours", prev: 1024, next: 2048, hasPrev: true, hasNext: true, want: 1536, wantOK: true},
+		{name: "after last", prev: 2048, hasPrev: true, want: 3072, wantOK: true},
+		{name: "before first", next: 1024, hasNext: true, want: 0, wantOK: true},
+		{name: "empty list", want: positionStep, wantOK: true},
+		{name: "neighbours too close", prev: 1, next: 1 + minPositionGap/2, hasPrev: true, hasNext: true},
+		{name: "tied neighbours", prev: 0, next: 0, hasPrev: true, hasNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := positionBetween(tt.prev, tt.next, tt.hasPrev, tt.hasNext)
+			if ok != tt.wantOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if ok && got != tt.want {
+				t.Errorf("Expected position %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// manualOrder lists task titles in manual order
+func manualOrder(t *testing.T, repo TodoRepository) string {
+	t.Helper()
+	tasks, _, err := repo.List(context.Background(), &ListTasksRequest{SortBy: todov1.SortField_SORT_FIELD_POSITION})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return strings.Join(titles, ",")
+}
+
+func TestTodoRepository_Reorder(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		ids := map[string]string{}
+		for _, title := range []string{"a", "b", "c", "d"} {
+			task, err := repo.Create(ctx, &CreateTaskRequest{Title: title})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			ids[title] = task.Id
+		}
+
+		if got := manualOrder(t, repo); got != "a,b,c,d" {
+			t.Fatalf("Expected creation order, got %s", got)
+		}
+
+		steps := []struct {
+			move, after string
+			want        string
+		}{
+			{move: "d", after: "a", want: "a,d,b,c"},
+			{move: "a", after: "c", want: "d,b,c,a"},
+			{move: "c", after: "", want: "c,d,b,a"},
+			{move: "c", after: "b", want: "d,b,c,a"},
+		}
+		for _, step := range steps {
+			afterID := ""
+			if step.after != "" {
+				afterID = ids[step.after]
+			}
+			task, err := repo.Reorder(ctx, ids[step.move], afterID)
+			if err != nil {
+				t.Fatalf("Failed to move %s after %q: %v", step.move, step.after, err)
+			}
+			if task.Id != ids[step.move] {
+				t.Errorf("Expected moved task %s, got %s", ids[step.move], task.Id)
+			}
+			if got := manualOrder(t, repo); got != step.want {
+				t.Errorf("Moving %s after %q: expected %s, got %s", step.move, step.after, step.want, got)
+			}
+		}
+
+		// Repeatedly splitting the same gap eventually forces a rebalance
+		for i := 0; i < 60; i++ {
+			move, after := "b", "d"
+			if i%2 == 1 {
+				move, after = "c", "d"
+			}
+			if _, err := repo.Reorder(ctx, ids[move], ids[after]); err != nil {
+				t.Fatalf("Failed to move task on iteration %d: %v", i, err)
+			}
+		}
+		if got := manualOrder(t, repo); got != "d,c,b,a" {
+			t.Errorf("Expected order to survive rebalancing, got %s", got)
+		}
+
+		if _, err := repo.Reorder(ctx, ids["a"], "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("Expected not found for a missing anchor, got %v", err)
+		}
+		if _, err := repo.Reorder(ctx, "missing", ids["a"]); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("Expected not found for a missing task, got %v", err)
+		}
+	})
+}
+
+func TestMySQLTodoRepository_ReorderTiedPositions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	// Rows from before manual ordering all sit at position 0
+	for _, id := range []string{"1", "2", "3"} {
+		if _, err := db.Exec("INSERT INTO tasks (id, title) VALUES (?, ?)", id, "task "+id); err != nil {
+			t.Fatalf("Failed to seed task: %v", err)
+		}
+	}
+
+	if _, err := repo.Reorder(ctx, "3", "1"); err != nil {
+		t.Fatalf("Failed to move task: %v", err)
+	}
+	if got := manualOrder(t, repo); got != "task 1,task 3,task 2" {
+		t.Errorf("Expected task 3 directly after task 1, got %s", got)
+	}
+}
+
+func TestMySQLTodoRepository_AppendPositionPerTenant(t *testing.T) {
+	repos := map[string]TodoRepository{
+		"reread":         NewMySQLTodoRepository(setupTestDB(t)),
+		"without reread": NewSQLiteTodoRepository(setupTestDB(t), WithoutCreateReread()),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			acme := middleware.WithTenant(context.Background(), "acme")
+			globex := middleware.WithTenant(context.Background(), "globex")
+
+			for _, title := range []string{"a", "b", "c"} {
+				if _, err := repo.Create(acme, &CreateTaskRequest{Title: title}); err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+			}
+
+			// Another tenant's tasks do not push new tasks down the ordering
+			created, err := repo.Create(globex, &CreateTaskRequest{Title: "first"})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			if created.Position != positionStep {
+				t.Errorf("Expected the tenant's first task at %v, got %v", positionStep, created.Position)
+			}
+			copied, err := repo.Duplicate(globex, created.Id, " (copy)")
+			if err != nil {
+				t.Fatalf("Failed to duplicate task: %v", err)
+			}
+			if copied.Position != 2*positionStep {
+				t.Errorf("Expected the copy at %v, got %v", 2*positionStep, copied.Position)
+			}
+		})
+	}
+}
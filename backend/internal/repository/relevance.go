@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+// Relevance ranks for SORT_FIELD_RELEVANCE; lower ranks sort first
+const (
+	rankExact    = 0 // the title is the query
+	rankPrefix   = 1 // the title starts with the query
+	rankContains = 2 // the query appears elsewhere in the title
+)
+
+// relevanceOrder returns an ORDER BY clause ranking exact matches of query
+// above prefix matches above other matches, newest first within a rank, and
+// its arguments. searchColumn is a lowercased title expression; matching is
+// case-insensitive like the search filter.
+func relevanceOrder(searchColumn, query string) (string, []interface{}) {
+	query = strings.ToLower(query)
+	orderBy := fmt.Sprintf(
+		"ORDER BY CASE WHEN %s = ? THEN %d WHEN %s LIKE ? THEN %d ELSE %d END, %s DESC, %s DESC",
+		searchColumn, rankExact, searchColumn, rankPrefix, rankContains, quoteIdentifier("created_at"), quoteIdentifier("id"),
+	)
+	return orderBy, []interface{}{query, query + "%"}
+}
+
+// relevanceRank scores title against query the way relevanceOrder does
+func relevanceRank(title, query string) int {
+	title, query = strings.ToLower(title), strings.ToLower(query)
+	switch {
+	case title == query:
+		return rankExact
+	case strings.HasPrefix(title, query):
+		return rankPrefix
+	default:
+		return rankContains
+	}
+}
+
+// sortByRelevance orders tasks like relevanceOrder
+func sortByRelevance(tasks []*todov1.Task, query string) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		ri, rj := relevanceRank(tasks[i].Title, query), relevanceRank(tasks[j].Title, query)
+		if ri != rj {
+			return ri < rj
+		}
+		if c := tasks[i].CreatedAt.AsTime().Compare(tasks[j].CreatedAt.AsTime()); c != 0 {
+			return c > 0
+		}
+		return tasks[i].Id > tasks[j].Id
+	})
+}
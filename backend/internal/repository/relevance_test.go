@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestList_RelevanceOrdering(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		// Created best match first, so newest-first order would reverse them
+		for _, title := range []string{"Milk", "milk chocolate", "Buy milk", "Walk dog"} {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+		}
+
+		tasks, pagination, err := repo.List(ctx, &ListTasksRequest{
+			Query:  "MILK",
+			SortBy: todov1.SortField_SORT_FIELD_RELEVANCE,
+		})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if pagination.TotalItems != 3 {
+			t.Fatalf("Expected 3 matches, got %d", pagination.TotalItems)
+		}
+		want := []string{"Milk", "milk chocolate", "Buy milk"}
+		for i, task := range tasks {
+			if task.Title != want[i] {
+				t.Errorf("Expected %q at position %d, got %q", want[i], i, task.Title)
+			}
+		}
+
+		// The same ordering applies to the ID-only variant
+		ids, _, err := repo.ListIDs(ctx, &ListTasksRequest{Query: "milk", SortBy: todov1.SortField_SORT_FIELD_RELEVANCE, PageSize: 1, Page: 2})
+		if err != nil {
+			t.Fatalf("Failed to list task IDs: %v", err)
+		}
+		if len(ids) != 1 || ids[0] != tasks[1].Id {
+			t.Errorf("Expected the prefix match on page 2, got %v", ids)
+		}
+
+		// Without a query there is nothing to rank, so the default order applies
+		all, _, err := repo.List(ctx, &ListTasksRequest{SortBy: todov1.SortField_SORT_FIELD_RELEVANCE})
+		if err != nil {
+			t.Fatalf("Failed to list tasks without a query: %v", err)
+		}
+		if len(all) != 4 {
+			t.Errorf("Expected all 4 tasks, got %d", len(all))
+		}
+	})
+}
+
+func TestRelevanceRank(t *testing.T) {
+	tests := []struct {
+		title string
+		want  int
+	}{
+		{"Groceries", rankExact},
+		{"groceries for the week", rankPrefix},
+		{"Buy groceries", rankContains},
+	}
+	for _, tt := range tests {
+		if got := relevanceRank(tt.title, "GROCERIES"); got != tt.want {
+			t.Errorf("relevanceRank(%q) = %d, want %d", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// ClaimDueReminders marks up to limit incomplete, unarchived tasks that are due
+// at or before now and have not been reminded yet, and returns them. Each task
+// is claimed with a conditional update, so when several instances run the
+// reminder job a task is returned to exactly one of them. Changing a task's due
+// date makes it eligible again. Without a tenant in ctx every tenant is scanned.
+func (r *mysqlTodoRepository) ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]*todov1.Task, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.ClaimDueReminders")
+
+	where, args := scopedWhere(ctx,
+		"completed = FALSE AND archived_at IS NULL AND reminded_at IS NULL AND due_date IS NOT NULL AND due_date <= ?", now)
+	rows, err := r.primary(ctx).QueryContext(ctx, `
+		SELECT id
+		FROM tasks
+		WHERE `+where+`
+		ORDER BY due_date, id
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due tasks: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan due task: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query due tasks: %w", err)
+	}
+
+	claimed := []*todov1.Task{}
+	for _, id := range ids {
+		result, err := r.primary(ctx).ExecContext(ctx,
+			"UPDATE tasks SET reminded_at = ?, updated_at = updated_at WHERE id = ? AND reminded_at IS NULL", now, id)
+		if err != nil {
+			return claimed, fmt.Errorf("failed to mark task reminded: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			continue // claimed by another instance
+		}
+
+		task, err := r.getByID(ctx, r.primary(ctx), id)
+		if err != nil {
+			return claimed, err
+		}
+		claimed = append(claimed, task)
+	}
+
+	r.logger.LogDatabaseOperation(ctx, "UPDATE tasks reminded", time.Since(start), true, int64(len(claimed)))
+	if len(claimed) > 0 {
+		r.markWrite()
+	}
+
+	return claimed, nil
+}
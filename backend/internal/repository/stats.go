@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// TaskStats counts tasks by state
+type TaskStats struct {
+	Active    uint32 // pending, not archived
+	Completed uint32 // completed, not archived
+	Archived  uint32
+	Deleted   uint32 // in the trash; always 0 without soft delete
+}
+
+// CountByState counts ctx's tenant's tasks by state in one grouped query. The
+// tasks branch reads only the covering idx_tenant_state index and the trash
+// branch only idx_tenant_deleted_at, never the rows themselves.
+func (r *mysqlTodoRepository) CountByState(ctx context.Context) (*TaskStats, error) {
+	cond, args := tenantScope(ctx)
+	where := ""
+	if cond != "" {
+		where = " WHERE " + cond
+	}
+
+	query := `
+		SELECT CASE
+			WHEN archived_at IS NOT NULL THEN 'archived'
+			WHEN completed THEN 'completed'
+			ELSE 'active'
+		END AS state
+		FROM tasks` + where
+	queryArgs := append([]interface{}{}, args...)
+	if r.softDelete {
+		query += " UNION ALL SELECT 'deleted' AS state FROM deleted_tasks" + where
+		queryArgs = append(queryArgs, args...)
+	}
+	query = "SELECT state, COUNT(*) FROM (" + query + ") AS states GROUP BY state"
+
+	rows, err := r.reader(ctx).QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by state: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &TaskStats{}
+	for rows.Next() {
+		var state string
+		var count uint32
+		if err := rows.Scan(&state, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task counts: %w", err)
+		}
+		switch state {
+		case "active":
+			stats.Active = count
+		case "completed":
+			stats.Completed = count
+		case "archived":
+			stats.Archived = count
+		case "deleted":
+			stats.Deleted = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read task counts: %w", err)
+	}
+	return stats, nil
+}
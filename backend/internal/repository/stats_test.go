@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCountByState(t *testing.T) {
+	repos := map[string]func() TodoRepository{
+		"mysql": func() TodoRepository { return NewSQLiteTodoRepository(setupTestDB(t), WithSoftDelete()) },
+		"mock": func() TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetSoftDelete(true)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo()
+
+			ids := map[string]string{}
+			for _, title := range []string{"active", "archived", "completed", "deleted", "batch deleted"} {
+				task, err := repo.Create(ctx, &CreateTaskRequest{Title: title})
+				if err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+				ids[title] = task.Id
+			}
+
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: ids["archived"], Completed: true}); err != nil {
+				t.Fatalf("Failed to complete task: %v", err)
+			}
+			if _, err := repo.ArchiveCompleted(ctx, time.Now().Add(time.Hour)); err != nil {
+				t.Fatalf("Failed to archive tasks: %v", err)
+			}
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: ids["completed"], Completed: true}); err != nil {
+				t.Fatalf("Failed to complete task: %v", err)
+			}
+			if err := repo.Delete(ctx, ids["deleted"]); err != nil {
+				t.Fatalf("Failed to delete task: %v", err)
+			}
+			if _, err := repo.DeleteMany(ctx, []string{ids["batch deleted"]}); err != nil {
+				t.Fatalf("Failed to delete tasks: %v", err)
+			}
+
+			stats, err := repo.CountByState(ctx)
+			if err != nil {
+				t.Fatalf("Failed to count tasks: %v", err)
+			}
+			want := TaskStats{Active: 1, Completed: 1, Archived: 1, Deleted: 2}
+			if *stats != want {
+				t.Errorf("Expected %+v, got %+v", want, *stats)
+			}
+		})
+	}
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := setupTestDB(t)
+	deletedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := NewSQLiteTodoRepository(db, WithSoftDelete(), WithClock(FixedClock{T: deletedAt}))
+	ctx := context.Background()
+
+	task, err := repo.Create(ctx, &CreateTaskRequest{ID: "reused-id", Title: "Trash me"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := repo.Delete(ctx, task.Id); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	if _, err := repo.GetByID(ctx, task.Id); err == nil {
+		t.Error("Expected a deleted task to be hidden")
+	}
+	if err := repo.Delete(ctx, task.Id); err == nil {
+		t.Error("Expected deleting a trashed task to report it missing")
+	}
+
+	var title string
+	var trashedAt time.Time
+	if err := db.QueryRow("SELECT title, deleted_at FROM deleted_tasks WHERE id = ?", task.Id).Scan(&title, &trashedAt); err != nil {
+		t.Fatalf("Expected the task in the trash: %v", err)
+	}
+	if title != "Trash me" || !trashedAt.Equal(deletedAt) {
+		t.Errorf("Unexpected trashed copy: %q deleted at %v", title, trashedAt)
+	}
+
+	// Reusing the ID and deleting again replaces the trashed copy
+	if _, err := repo.Create(ctx, &CreateTaskRequest{ID: "reused-id", Title: "Trash me again"}); err != nil {
+		t.Fatalf("Failed to recreate task: %v", err)
+	}
+	if err := repo.Delete(ctx, "reused-id"); err != nil {
+		t.Fatalf("Failed to delete recreated task: %v", err)
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM deleted_tasks WHERE title = 'Trash me again'").Scan(&count); err != nil || count != 1 {
+		t.Errorf("Expected the newer copy in the trash, got %d (%v)", count, err)
+	}
+
+	// Without soft delete, deletes discard tasks and nothing counts as deleted
+	for name, plain := range map[string]TodoRepository{"mysql": NewSQLiteTodoRepository(db), "mock": NewMockTodoRepository()} {
+		doomed, _ := plain.Create(ctx, &CreateTaskRequest{Title: "Gone"})
+		if err := plain.Delete(ctx, doomed.Id); err != nil {
+			t.Fatalf("%s: failed to delete task: %v", name, err)
+		}
+		stats, err := plain.CountByState(ctx)
+		if err != nil {
+			t.Fatalf("%s: failed to count tasks: %v", name, err)
+		}
+		if stats.Deleted != 0 {
+			t.Errorf("%s: expected no deleted count without soft delete, got %d", name, stats.Deleted)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// stmtCache lazily prepares statements once per connection pool and reuses
+// them across calls. *sql.Stmt is safe for concurrent use and re-prepares
+// itself on new connections; a statement that still fails with ErrBadConn is
+// dropped and prepared again.
+type stmtCache struct {
+	mu     sync.Mutex
+	stmts  map[stmtKey]*sql.Stmt
+	closed bool
+}
+
+// errStmtCacheClosed is returned for statements requested after close
+var errStmtCacheClosed = errors.New("statement cache is closed")
+
+type stmtKey struct {
+	db    *sql.DB
+	query string
+}
+
+// newStmtCache creates an empty statement cache
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: make(map[stmtKey]*sql.Stmt)}
+}
+
+// get returns the cached statement for query on db, preparing it if needed.
+// The prepare round trip runs without the lock, so a slow prepare does not
+// hold up calls for other statements; when two calls race to prepare the same
+// query, the first to finish is kept and the other closes its copy.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, error) {
+	key := stmtKey{db: db, query: query}
+
+	c.mu.Lock()
+	stmt, ok := c.stmts[key]
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return nil, errStmtCacheClosed
+	}
+	if ok {
+		return stmt, nil
+	}
+
+	prepared, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		prepared.Close()
+		return nil, errStmtCacheClosed
+	}
+	if stmt, ok := c.stmts[key]; ok {
+		prepared.Close()
+		return stmt, nil
+	}
+	c.stmts[key] = prepared
+	return prepared, nil
+}
+
+// close closes every cached statement and refuses new ones
+func (c *stmtCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for key, stmt := range c.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(c.stmts, key)
+	}
+	c.closed = true
+	return errors.Join(errs...)
+}
+
+// invalidate closes and forgets the statement for query on db
+func (c *stmtCache) invalidate(db *sql.DB, query string) {
+	key := stmtKey{db: db, query: query}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if stmt, ok := c.stmts[key]; ok {
+		stmt.Close()
+		delete(c.stmts, key)
+	}
+}
+
+// do runs fn with the prepared statement, re-preparing and retrying once on ErrBadConn
+func (c *stmtCache) do(ctx context.Context, db *sql.DB, query string, fn func(*sql.Stmt) error) error {
+	for attempt := 0; ; attempt++ {
+		stmt, err := c.get(ctx, db, query)
+		if err != nil {
+			return err
+		}
+		err = fn(stmt)
+		if attempt == 0 && errors.Is(err, driver.ErrBadConn) {
+			c.invalidate(db, query)
+			continue
+		}
+		return err
+	}
+}
+
+// Close releases the prepared statements the repository holds. The database
+// pools stay open; they belong to the caller, which should close them after
+// the repository.
+func (r *mysqlTodoRepository) Close() error {
+	if r.stmts == nil {
+		return nil
+	}
+	return r.stmts.close()
+}
+
+// exec runs a write statement, prepared when the cache is enabled, in the
+// transaction ctx carries if any
+func (r *mysqlTodoRepository) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx := r.txFrom(ctx); tx != nil {
+		return tx.ExecContext(ctx, query, args...)
+	}
+	if r.stmts == nil {
+		return r.db.ExecContext(ctx, query, args...)
+	}
+
+	var result sql.Result
+	err := r.stmts.do(ctx, r.db, query, func(stmt *sql.Stmt) error {
+		var err error
+		result, err = stmt.ExecContext(ctx, args...)
+		return err
+	})
+	return result, err
+}
+
+// queryRow runs a single-row query and scans it into dest. Statements are
+// prepared when the cache is enabled and q is a pool rather than a transaction.
+func (r *mysqlTodoRepository) queryRow(ctx context.Context, q queryer, query string, args []interface{}, dest ...interface{}) error {
+	db, isPool := q.(*sql.DB)
+	if r.stmts == nil || !isPool {
+		return q.QueryRowContext(ctx, query, args...).Scan(dest...)
+	}
+
+	return r.stmts.do(ctx, db, query, func(stmt *sql.Stmt) error {
+		return stmt.QueryRowContext(ctx, args...).Scan(dest...)
+	})
+}
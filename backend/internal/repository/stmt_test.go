@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestMySQLTodoRepository_PreparedStatements(t *testing.T) {
+	t.Run("crud works with prepared statements", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewMySQLTodoRepository(db, WithPreparedStatements())
+		ctx := context.Background()
+
+		task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Prepared"})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+
+		got, err := repo.GetByID(ctx, task.Id)
+		if err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		if got.Title != "Prepared" {
+			t.Errorf("Expected title Prepared, got %q", got.Title)
+		}
+
+		if err := repo.Delete(ctx, task.Id); err != nil {
+			t.Fatalf("Failed to delete task: %v", err)
+		}
+		if _, err := repo.GetByID(ctx, task.Id); err == nil {
+			t.Error("Expected deleted task to be gone")
+		}
+	})
+
+	t.Run("statement is prepared once and reused", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithPreparedStatements())
+		columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+		now := time.Now()
+
+		prepared := mock.ExpectPrepare("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id")
+		prepared.ExpectQuery().WithArgs("task-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("task-1", "First", false, now, now, nil, 1024.0, nil))
+		prepared.ExpectQuery().WithArgs("task-2").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("task-2", "Second", false, now, now, nil, 1024.0, nil))
+
+		for _, id := range []string{"task-1", "task-2"} {
+			if _, err := repo.GetByID(context.Background(), id); err != nil {
+				t.Fatalf("Failed to get %s: %v", id, err)
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestStmtCache(t *testing.T) {
+	t.Run("a slow prepare does not block other statements", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+		mock.MatchExpectationsInOrder(false)
+		mock.ExpectPrepare("SELECT slow").WillDelayFor(500 * time.Millisecond)
+		mock.ExpectPrepare("SELECT fast")
+
+		cache := newStmtCache()
+		slowDone := make(chan struct{})
+		go func() {
+			defer close(slowDone)
+			cache.get(context.Background(), db, "SELECT slow")
+		}()
+		time.Sleep(50 * time.Millisecond)
+
+		if _, err := cache.get(context.Background(), db, "SELECT fast"); err != nil {
+			t.Fatalf("Failed to prepare statement: %v", err)
+		}
+		select {
+		case <-slowDone:
+			t.Error("Expected the fast statement to be prepared while the slow one was pending")
+		default:
+		}
+		<-slowDone
+	})
+
+	t.Run("close releases statements", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+		mock.ExpectPrepare("SELECT 1").WillBeClosed()
+
+		cache := newStmtCache()
+		if _, err := cache.get(context.Background(), db, "SELECT 1"); err != nil {
+			t.Fatalf("Failed to prepare statement: %v", err)
+		}
+		if err := cache.close(); err != nil {
+			t.Fatalf("Failed to close cache: %v", err)
+		}
+		if _, err := cache.get(context.Background(), db, "SELECT 1"); !errors.Is(err, errStmtCacheClosed) {
+			t.Errorf("Expected errStmtCacheClosed after close, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func BenchmarkGetByID(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "adhoc"},
+		{name: "prepared", opts: []Option{WithPreparedStatements()}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			db := setupTestDB(b)
+			// Error level keeps per-query logs out of the measurement
+			repo := NewMySQLTodoRepositoryWithLogger(db, middleware.NewStructuredLogger(middleware.LevelError), bm.opts...)
+			ctx := context.Background()
+
+			task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Benchmark"})
+			if err != nil {
+				b.Fatalf("Failed to create task: %v", err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetByID(ctx, task.Id); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// DefaultTenant is the tenant_id of tasks created without a tenant in context;
+// it matches the column default in the schema
+const DefaultTenant = "default"
+
+// tenantScope returns a condition restricting a query to the tenant in ctx.
+// A context without a tenant (single-tenant deployments, maintenance jobs such
+// as the archiver) yields no condition and sees every tenant's tasks.
+func tenantScope(ctx context.Context) (string, []interface{}) {
+	tenant := middleware.GetTenant(ctx)
+	if tenant == "" {
+		return "", nil
+	}
+	return "tenant_id = ?", []interface{}{tenant}
+}
+
+// scopedWhere appends the tenant condition for ctx to a WHERE clause body
+func scopedWhere(ctx context.Context, where string, args ...interface{}) (string, []interface{}) {
+	cond, tenantArgs := tenantScope(ctx)
+	if cond == "" {
+		return where, args
+	}
+	return where + " AND " + cond, append(args, tenantArgs...)
+}
+
+// tenantOf returns the tenant new tasks are stored under
+func tenantOf(ctx context.Context) string {
+	if tenant := middleware.GetTenant(ctx); tenant != "" {
+		return tenant
+	}
+	return DefaultTenant
+}
@@ -3,13 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/go-sql-driver/mysql"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
 	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
-	todov1 "buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -18,14 +21,35 @@ type TodoRepository interface {
 	Create(ctx context.Context, task *CreateTaskRequest) (*todov1.Task, error)
 	GetByID(ctx context.Context, id string) (*todov1.Task, error)
 	List(ctx context.Context, filters *ListTasksRequest) ([]*todov1.Task, *PaginationResult, error)
-	Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, error)
+	ListIDs(ctx context.Context, filters *ListTasksRequest) ([]string, *PaginationResult, error)
+	ListGrouped(ctx context.Context, filters *ListTasksRequest) (*GroupedTasks, error)
+	ListStream(ctx context.Context, filters *ListTasksRequest, chunkSize int, emit func([]*todov1.Task) error) (*PaginationResult, error)
+	ListChangedSince(ctx context.Context, since time.Time, sinceID string, limit int) ([]*todov1.Task, error)
+	Count(ctx context.Context) (uint32, error)
+	CountCreatedSince(ctx context.Context, since time.Time) (uint32, error)
+	ListVersion(ctx context.Context) (uint64, error)
+	CountByState(ctx context.Context) (*TaskStats, error)
+	ExistsByTitle(ctx context.Context, title string) (bool, error)
+	Update(ctx context.Context, req *UpdateTaskRequest) (task, previous *todov1.Task, err error)
 	Delete(ctx context.Context, id string) error
+	Duplicate(ctx context.Context, id string, titleSuffix string) (*todov1.Task, error)
+	DeleteMany(ctx context.Context, ids []string) ([]*todov1.DeleteTaskResult, error)
+	Reorder(ctx context.Context, id, afterID string) (*todov1.Task, error)
+	ArchiveCompleted(ctx context.Context, before time.Time) (int64, error)
+	PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	ClaimDueReminders(ctx context.Context, now time.Time, limit int) ([]*todov1.Task, error)
+	StreamAll(ctx context.Context) (<-chan *todov1.Task, <-chan error)
+	Diagnose(ctx context.Context) (*StorageReport, error)
 	HealthCheck(ctx context.Context) error
+	ReplicaHealthCheck(ctx context.Context) error
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // CreateTaskRequest represents the data needed to create a new task
 type CreateTaskRequest struct {
-	Title string
+	ID      string // Optional caller-supplied ID; generated when empty
+	Title   string
+	DueDate *time.Time // Optional; nil leaves the task unscheduled
 }
 
 // UpdateTaskRequest represents the data needed to update a task
@@ -33,6 +57,55 @@ type UpdateTaskRequest struct {
 	ID        string
 	Title     string
 	Completed bool
+
+	DueDate      *time.Time // New due date; nil keeps the current one
+	ClearDueDate bool       // Remove the due date
+
+	// Mask, when set, limits the update to the fields it selects; nil writes
+	// completed always, and title and the due date when given
+	Mask *UpdateMask
+
+	// SkipNoOp leaves the row, updated_at included, untouched when the update
+	// would not change any field it writes; the current task is returned
+	SkipNoOp bool
+}
+
+// UpdateMask selects the fields an update writes
+type UpdateMask struct {
+	Title     bool
+	Completed bool
+	DueDate   bool // Writes DueDate, clearing the due date when it is nil
+}
+
+// Fields returns the fields the update writes
+func (req *UpdateTaskRequest) Fields() UpdateMask {
+	if req.Mask != nil {
+		return *req.Mask
+	}
+	return UpdateMask{
+		Title:     req.Title != "",
+		Completed: true,
+		DueDate:   req.ClearDueDate || req.DueDate != nil,
+	}
+}
+
+// Changes reports whether applying the update to current would change any of
+// the fields it writes
+func (req *UpdateTaskRequest) Changes(current *todov1.Task) bool {
+	fields := req.Fields()
+	if fields.Title && req.Title != current.Title {
+		return true
+	}
+	if fields.Completed && req.Completed != current.Completed {
+		return true
+	}
+	if fields.DueDate {
+		if req.DueDate == nil {
+			return current.DueDate != nil
+		}
+		return current.DueDate == nil || !req.DueDate.Equal(current.DueDate.AsTime())
+	}
+	return false
 }
 
 // ListTasksRequest represents filters for listing tasks
@@ -41,40 +114,278 @@ type ListTasksRequest struct {
 	PageSize  uint32
 	Query     string
 	Status    todov1.StatusFilter
+	DueDate   todov1.DueDateFilter
 	SortBy    todov1.SortField
 	SortOrder todov1.SortOrder
+
+	IncludeArchived bool
 }
 
 // PaginationResult contains pagination metadata
 type PaginationResult struct {
-	Page        uint32
-	PageSize    uint32
-	TotalPages  uint32
-	TotalItems  uint32
-	HasPrevious bool
-	HasNext     bool
+	Page            uint32
+	PageSize        uint32
+	TotalPages      uint32
+	TotalItems      uint32
+	HasPrevious     bool
+	HasNext         bool
+	TotalUnfiltered uint32 // All tasks, ignoring query and status filters
+	Approximate     bool   // TotalItems is an estimate rather than an exact count
+}
+
+// TaskPage is one page of tasks with its pagination metadata
+type TaskPage struct {
+	Tasks      []*todov1.Task
+	Pagination *PaginationResult
+}
+
+// GroupedTasks holds separately paginated pending and completed tasks
+type GroupedTasks struct {
+	Pending   TaskPage
+	Completed TaskPage
+}
+
+// listGrouped runs one status-scoped List per group so search and pagination
+// apply within each group
+func listGrouped(ctx context.Context, repo TodoRepository, filters *ListTasksRequest) (*GroupedTasks, error) {
+	grouped := &GroupedTasks{}
+	groups := []struct {
+		status todov1.StatusFilter
+		page   *TaskPage
+	}{
+		{todov1.StatusFilter_STATUS_FILTER_PENDING, &grouped.Pending},
+		{todov1.StatusFilter_STATUS_FILTER_COMPLETED, &grouped.Completed},
+	}
+
+	for _, group := range groups {
+		scoped := *filters
+		scoped.Status = group.status
+		tasks, pagination, err := repo.List(ctx, &scoped)
+		if err != nil {
+			return nil, err
+		}
+		*group.page = TaskPage{Tasks: tasks, Pagination: pagination}
+	}
+
+	return grouped, nil
 }
 
 // mysqlTodoRepository implements TodoRepository using MySQL
 type mysqlTodoRepository struct {
 	db     *sql.DB
 	logger *middleware.StructuredLogger
+	idGen  IDGenerator
+	clock  Clock // nil leaves timestamps to the database defaults
+
+	approxCountThreshold uint32
+	streamBatchSize      int
+	stmts                *stmtCache // nil unless prepared statements are enabled
+	caseFoldedSearch     bool
+	windowedCount        bool
+	defaultSortField     todov1.SortField
+	defaultSortOrder     todov1.SortOrder
+	windowUnsupported    atomic.Bool // set once windowed counts have failed to parse
+	listVersion          bool        // mutations advance list_versions
+	softDelete           bool        // deletes move tasks to deleted_tasks
+	skipCreateReread     bool        // Create returns the inserted values without a SELECT
+	uniqueTitles         bool        // writes reject titles already used in the tenant
+	sqlite               bool        // the database is SQLite rather than MySQL
+	rowLocks             bool        // reads ahead of a write can lock rows with FOR UPDATE
+
+	// Read replica routing; replica is nil when reads go to the primary
+	replica           *sql.DB
+	primaryReadWindow time.Duration
+	lastWrite         atomic.Int64 // UnixNano of the most recent write
 }
 
-// NewMySQLTodoRepository creates a new MySQL-based todo repository
-func NewMySQLTodoRepository(db *sql.DB) TodoRepository {
-	return &mysqlTodoRepository{
-		db:     db,
-		logger: middleware.NewStructuredLogger(middleware.LevelInfo),
+// queryer is the read subset shared by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Option configures optional behavior of the MySQL repository
+type Option func(*mysqlTodoRepository)
+
+// WithIDGenerator sets the generator used for new task IDs (default UUID v4)
+func WithIDGenerator(gen IDGenerator) Option {
+	return func(r *mysqlTodoRepository) {
+		r.idGen = gen
 	}
 }
 
+// WithClock stamps created_at, updated_at and archived_at from clock instead of
+// relying on the database's CURRENT_TIMESTAMP defaults
+func WithClock(clock Clock) Option {
+	return func(r *mysqlTodoRepository) {
+		r.clock = clock
+	}
+}
+
+// WithAppTimestamps has the repository stamp created_at, updated_at and
+// archived_at from the system clock in UTC instead of leaving them to MySQL's
+// column defaults and ON UPDATE CURRENT_TIMESTAMP, so timestamps behave the
+// same on MySQL and SQLite. It is WithClock with the clock SQLite repositories
+// use; without either, MySQL manages the timestamps.
+func WithAppTimestamps() Option {
+	return WithClock(utcClock{})
+}
+
+// WithApproximateCountThreshold lets unfiltered List calls use the InnoDB row
+// estimate from information_schema instead of COUNT(*) once the table holds at
+// least threshold rows. COUNT(*) on InnoDB scans an index, so this keeps paging
+// cheap on very large tables at the cost of a total that can drift by a few
+// percent; such responses are flagged as approximate. When archived tasks are
+// excluded, as they are by default, the exact archived count is subtracted from
+// the estimate. Filtered and tenant-scoped queries always use exact counts since
+// the estimate covers the whole table. Zero (the default) disables estimation.
+func WithApproximateCountThreshold(threshold uint32) Option {
+	return func(r *mysqlTodoRepository) {
+		r.approxCountThreshold = threshold
+	}
+}
+
+// ErrNoReplica is returned by ReplicaHealthCheck when no read replica is configured
+var ErrNoReplica = errors.New("no read replica configured")
+
+// WithReadReplica routes read-only operations (GetByID, List) to a replica pool
+// while writes stay on the primary. Reads that immediately follow a write within
+// the same repository call always use the primary.
+func WithReadReplica(replica *sql.DB) Option {
+	return func(r *mysqlTodoRepository) {
+		r.replica = replica
+	}
+}
+
+// WithPrimaryReadWindow forces reads to the primary for the given duration after
+// any write, hiding replication lag from clients that read their own writes
+func WithPrimaryReadWindow(window time.Duration) Option {
+	return func(r *mysqlTodoRepository) {
+		r.primaryReadWindow = window
+	}
+}
+
+// WithPreparedStatements prepares the hot-path queries (GetByID, Create, Delete)
+// once per pool and reuses them instead of sending the SQL text on every call
+func WithPreparedStatements() Option {
+	return func(r *mysqlTodoRepository) {
+		r.stmts = newStmtCache()
+	}
+}
+
+// WithCaseFoldedSearch matches search queries against the lowercase title_lower
+// column instead of title, so search is case-insensitive whatever the table
+// collation. The column must exist; see db.EnsureTitleLowerColumn.
+func WithCaseFoldedSearch() Option {
+	return func(r *mysqlTodoRepository) {
+		r.caseFoldedSearch = true
+	}
+}
+
+// WithWindowedCount has List read the total from COUNT(*) OVER () alongside the
+// page rows instead of a separate COUNT query, saving a round trip. Databases
+// without window functions (MySQL before 8.0) fall back to two queries after
+// the first failed attempt.
+func WithWindowedCount() Option {
+	return func(r *mysqlTodoRepository) {
+		r.windowedCount = true
+	}
+}
+
+// WithDefaultSort sets the ordering List uses when a request leaves the sort
+// field unspecified (default created_at, newest first). An unspecified order
+// keeps the field's natural direction; unknown fields are ignored.
+func WithDefaultSort(field todov1.SortField, order todov1.SortOrder) Option {
+	return func(r *mysqlTodoRepository) {
+		if _, ok := sortColumns[field]; ok {
+			r.defaultSortField = field
+			r.defaultSortOrder = order
+		}
+	}
+}
+
+// NewMySQLTodoRepository creates a new MySQL-based todo repository
+func NewMySQLTodoRepository(db *sql.DB, opts ...Option) TodoRepository {
+	return NewMySQLTodoRepositoryWithLogger(db, middleware.NewStructuredLogger(middleware.LevelInfo), opts...)
+}
+
 // NewMySQLTodoRepositoryWithLogger creates a new MySQL repository with custom logger
-func NewMySQLTodoRepositoryWithLogger(db *sql.DB, logger *middleware.StructuredLogger) TodoRepository {
-	return &mysqlTodoRepository{
+func NewMySQLTodoRepositoryWithLogger(db *sql.DB, logger *middleware.StructuredLogger, opts ...Option) TodoRepository {
+	r := &mysqlTodoRepository{
 		db:     db,
 		logger: logger,
+		idGen:  UUIDGenerator{},
+
+		streamBatchSize:  defaultStreamBatchSize,
+		defaultSortField: defaultSortField,
+		defaultSortOrder: defaultSortOrder,
+		rowLocks:         !isSQLiteDriver(db),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// NewSQLiteTodoRepository creates a repository for a SQLite database set up by
+// db.InitSQLite. The queries are shared with MySQL; only updated_at differs, as
+// SQLite cannot refresh it on update, so timestamps come from the application
+// clock unless WithClock supplies another.
+func NewSQLiteTodoRepository(db *sql.DB, opts ...Option) TodoRepository {
+	return NewSQLiteTodoRepositoryWithLogger(db, middleware.NewStructuredLogger(middleware.LevelInfo), opts...)
+}
+
+// NewSQLiteTodoRepositoryWithLogger creates a new SQLite repository with custom logger
+func NewSQLiteTodoRepositoryWithLogger(db *sql.DB, logger *middleware.StructuredLogger, opts ...Option) TodoRepository {
+	r := NewMySQLTodoRepositoryWithLogger(db, logger, append([]Option{WithClock(utcClock{})}, opts...)...).(*mysqlTodoRepository)
+	r.sqlite = true
+	return r
+}
+
+// isSQLiteDriver reports whether db is backed by the SQLite driver, which has
+// no row locks: a SQLite write transaction locks the whole database instead
+func isSQLiteDriver(db *sql.DB) bool {
+	return strings.HasPrefix(fmt.Sprintf("%T", db.Driver()), "*sqlite3.")
+}
+
+// forUpdate returns the clause that locks the rows a transaction reads before
+// writing them, or nothing where the database has no row locks
+func (r *mysqlTodoRepository) forUpdate() string {
+	if !r.rowLocks {
+		return ""
 	}
+	return " FOR UPDATE"
+}
+
+// reader returns the transaction ctx carries, or the pool that read-only
+// queries should use
+func (r *mysqlTodoRepository) reader(ctx context.Context) queryer {
+	if tx := r.txFrom(ctx); tx != nil {
+		return tx
+	}
+	if r.replica == nil {
+		return r.db
+	}
+	if r.primaryReadWindow > 0 && time.Since(time.Unix(0, r.lastWrite.Load())) < r.primaryReadWindow {
+		return r.db
+	}
+	return r.replica
+}
+
+// insertStamps returns the extra INSERT columns, placeholders and arguments that
+// set created_at and updated_at from the configured clock, or nothing when the
+// database defaults apply
+func (r *mysqlTodoRepository) insertStamps() (columns, placeholders string, args []interface{}) {
+	if r.clock == nil {
+		return "", "", nil
+	}
+	now := r.clock.Now()
+	return ", created_at, updated_at", ", ?, ?", []interface{}{now, now}
+}
+
+// markWrite records a write so the primary read window can take effect
+func (r *mysqlTodoRepository) markWrite() {
+	r.lastWrite.Store(time.Now().UnixNano())
 }
 
 // Create creates a new task in the database
@@ -82,47 +393,98 @@ func (r *mysqlTodoRepository) Create(ctx context.Context, req *CreateTaskRequest
 	start := time.Now()
 	ctx = middleware.WithSource(ctx, "repository.Create")
 	
-	id := uuid.New().String()
+	id := req.ID
+	if id == "" {
+		id = r.idGen.NewID()
+	}
 
-	query := `
-		INSERT INTO tasks (id, title, completed)
-		VALUES (?, ?, FALSE)
-	`
-	
-	result, err := r.db.ExecContext(ctx, query, id, req.Title)
-	duration := time.Since(start)
-	
-	var rowsAffected int64
-	if result != nil {
-		rowsAffected, _ = result.RowsAffected()
+	query, stamps, scope, createdAt := r.createQuery(ctx)
+	args := func(id string) []interface{} {
+		args := append([]interface{}{id, req.Title, tenantOf(ctx), req.DueDate}, stamps...)
+		args = append(args, scope...)
+		if r.uniqueTitles {
+			args = append(args, tenantOf(ctx), req.Title)
+		}
+		return args
+	}
+
+	res, err := r.insertTask(ctx, query, args(id))
+	// A generated ID that collides is retried with a fresh one; a caller-supplied
+	// ID is reported as a duplicate
+	for attempt := 1; err != nil && req.ID == "" && attempt < maxCreateAttempts && isDuplicatePrimaryKey(err); attempt++ {
+		r.logger.Warn(ctx, "Generated task ID already exists, retrying", map[string]interface{}{
+			"id":      id,
+			"attempt": attempt,
+		})
+		id = r.idGen.NewID()
+		res, err = r.insertTask(ctx, query, args(id))
 	}
+	duration := time.Since(start)
 	
 	// Log database operation
-	r.logger.LogDatabaseOperation(ctx, "INSERT tasks", duration, err == nil, rowsAffected)
+	r.logger.LogDatabaseOperation(ctx, "INSERT tasks", duration, err == nil, res.rowsAffected)
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
+	if r.uniqueTitles && res.rowsAffected == 0 {
+		return nil, duplicateTitleError(req.Title)
+	}
+	r.markWrite()
+
+	if r.skipCreateReread {
+		return r.createdTask(id, req, createdAt, res), nil
+	}
+	return r.getByID(ctx, r.primary(ctx), id)
+}
 
-	return r.GetByID(ctx, id)
+// errEmptyTitleUpdate rejects an update that would blank a task's title
+var errEmptyTitleUpdate = errors.New("title cannot be updated to empty")
+
+// maxCreateAttempts bounds how many generated IDs Create tries before giving up
+const maxCreateAttempts = 3
+
+// isDuplicatePrimaryKey reports whether err is MySQL's duplicate-entry error for
+// the primary key, as opposed to another unique index such as uniq_tenant_title
+func isDuplicatePrimaryKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 && strings.Contains(mysqlErr.Message, "PRIMARY")
 }
 
 // GetByID retrieves a task by its ID
 func (r *mysqlTodoRepository) GetByID(ctx context.Context, id string) (*todov1.Task, error) {
+	return r.getByID(ctx, r.reader(ctx), id)
+}
+
+// getByID retrieves a task by its ID using the given connection pool or transaction
+func (r *mysqlTodoRepository) getByID(ctx context.Context, q queryer, id string) (*todov1.Task, error) {
+	return r.selectByID(ctx, q, id, "")
+}
+
+// getByIDForUpdate retrieves a task by its ID within tx, locking its row until
+// the transaction ends so a concurrent write cannot slip in between the read
+// and the caller's write
+func (r *mysqlTodoRepository) getByIDForUpdate(ctx context.Context, tx *sql.Tx, id string) (*todov1.Task, error) {
+	return r.selectByID(ctx, tx, id, r.forUpdate())
+}
+
+// selectByID runs the task lookup shared by getByID and getByIDForUpdate,
+// appending suffix to the query
+func (r *mysqlTodoRepository) selectByID(ctx context.Context, q queryer, id, suffix string) (*todov1.Task, error) {
 	start := time.Now()
 	ctx = middleware.WithSource(ctx, "repository.GetByID")
 	
 	var task todov1.Task
-	var createdAt, updatedAt sql.NullTime
+	var createdAt, updatedAt, archivedAt, dueDate sql.NullTime
 
+	where, args := scopedWhere(ctx, "id = ?", id)
 	query := `
-		SELECT id, title, completed, created_at, updated_at
+		SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date
 		FROM tasks
-		WHERE id = ?
-	`
+		WHERE ` + where + suffix
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.Id, &task.Title, &task.Completed, &createdAt, &updatedAt,
+	err := r.queryRow(ctx, q, query, args,
+		&task.Id, &task.Title, &task.Completed, &createdAt, &updatedAt, &archivedAt, &task.Position, &dueDate,
 	)
 	duration := time.Since(start)
 	
@@ -140,48 +502,279 @@ func (r *mysqlTodoRepository) GetByID(ctx context.Context, id string) (*todov1.T
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
-	if createdAt.Valid {
-		task.CreatedAt = timestamppb.New(createdAt.Time)
-	}
-	if updatedAt.Valid {
-		task.UpdatedAt = timestamppb.New(updatedAt.Time)
-	}
+	task.CreatedAt = r.toTimestamp(ctx, task.Id, "created_at", createdAt)
+	task.UpdatedAt = r.toTimestamp(ctx, task.Id, "updated_at", updatedAt)
+	task.ArchivedAt = r.toTimestamp(ctx, task.Id, "archived_at", archivedAt)
+	task.DueDate = r.toTimestamp(ctx, task.Id, "due_date", dueDate)
 
 	return &task, nil
 }
 
-// List retrieves tasks with pagination and filtering
-func (r *mysqlTodoRepository) List(ctx context.Context, filters *ListTasksRequest) ([]*todov1.Task, *PaginationResult, error) {
-	// Set defaults
-	page := filters.Page
+// toTimestamp converts a nullable column to a protobuf timestamp. Zero or
+// out-of-range values (e.g. MySQL zero dates) would make the Task fail to
+// marshal, so they are logged and replaced with the Unix epoch.
+func (r *mysqlTodoRepository) toTimestamp(ctx context.Context, id, column string, value sql.NullTime) *timestamppb.Timestamp {
+	if !value.Valid {
+		return nil
+	}
+
+	ts := timestamppb.New(value.Time)
+	if value.Time.IsZero() || ts.CheckValid() != nil {
+		r.logger.Warn(ctx, "Invalid timestamp in tasks row", map[string]interface{}{
+			"id":     id,
+			"column": column,
+			"value":  value.Time.String(),
+		})
+		return timestamppb.New(time.Unix(0, 0))
+	}
+	return ts
+}
+
+// Page sizes List and ListIDs fall back to and are capped at
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageBounds returns the page filters ask for, defaulting to the first, and
+// its size, defaulting to defaultPageSize and capped at limit
+func pageBounds(filters *ListTasksRequest, limit uint32) (page, pageSize uint32) {
+	page = filters.Page
 	if page == 0 {
 		page = 1
 	}
-	
-	pageSize := filters.PageSize
+	pageSize = filters.PageSize
 	if pageSize == 0 {
-		pageSize = 20
+		pageSize = defaultPageSize
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	if pageSize > limit {
+		pageSize = limit
+	}
+	return page, pageSize
+}
+
+// newPagination describes one page of a result holding totalItems tasks
+func newPagination(page, pageSize, totalItems, totalUnfiltered uint32, approximate bool) *PaginationResult {
+	totalPages := (totalItems + pageSize - 1) / pageSize
+	return &PaginationResult{
+		Page:            page,
+		PageSize:        pageSize,
+		TotalPages:      totalPages,
+		TotalItems:      totalItems,
+		HasPrevious:     page > 1,
+		HasNext:         page < totalPages,
+		TotalUnfiltered: totalUnfiltered,
+		Approximate:     approximate,
+	}
+}
+
+// List retrieves tasks with pagination and filtering
+func (r *mysqlTodoRepository) List(ctx context.Context, filters *ListTasksRequest) ([]*todov1.Task, *PaginationResult, error) {
+	page, pageSize := pageBounds(filters, maxPageSize)
+	q := r.buildListQuery(ctx, filters)
+	db := r.reader(ctx)
+	pageArgs := q.pageArgs(pageSize, (page-1)*pageSize)
+
+	var tasks []*todov1.Task
+	totalItems, totalUnfiltered, approximate, err := r.fetchCountedPage(ctx, db, q, func(extraColumns string, total *uint32) (int, error) {
+		var err error
+		tasks, err = r.queryTasks(ctx, db, q.pageQuery(extraColumns), pageArgs, total)
+		return len(tasks), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tasks, newPagination(page, pageSize, totalItems, totalUnfiltered, approximate), nil
+}
+
+// fetchCountedPage fetches one page of q and counts its totals. The total
+// comes from the table estimate when enabled, from COUNT(*) OVER () on the
+// page query itself with windowed counts, or else from a COUNT query.
+// fetchPage runs the page query with any extra trailing columns, scanning the
+// last one into total when it is not nil, and returns the rows it read.
+func (r *mysqlTodoRepository) fetchCountedPage(ctx context.Context, db queryer, q listQuery, fetchPage func(extraColumns string, total *uint32) (int, error)) (totalItems, totalUnfiltered uint32, approximate bool, err error) {
+	// Estimate the total for large unfiltered tables when enabled
+	totalItems, approximate = r.estimateListTotal(ctx, db, q)
+
+	// Fetch the page with its total in one round trip when windowed counts are
+	// enabled. A page past the end has no row to carry the total, so it still
+	// needs the COUNT query below.
+	fetched := false
+	counted := approximate
+	if !approximate && r.windowedCount && !r.windowUnsupported.Load() {
+		n, err := fetchPage(", COUNT(*) OVER ()", &totalItems)
+		switch {
+		case err == nil:
+			fetched, counted = true, n > 0
+		case isSyntaxError(err):
+			r.windowUnsupported.Store(true)
+			r.logger.Warn(ctx, "Window functions unsupported, falling back to COUNT queries", map[string]interface{}{
+				"error": err.Error(),
+			})
+		default:
+			return 0, 0, false, err
+		}
+	}
+	if !counted {
+		if totalItems, err = q.count(ctx, db); err != nil {
+			return 0, 0, false, err
+		}
+	}
+
+	// Count all tasks in scope when a filter narrows the result
+	if totalUnfiltered, err = q.countUnfiltered(ctx, db, totalItems); err != nil {
+		return 0, 0, false, err
+	}
+
+	// Query the page unless the windowed query already fetched it
+	if !fetched {
+		if _, err := fetchPage("", nil); err != nil {
+			return 0, 0, false, err
+		}
+	}
+	return totalItems, totalUnfiltered, approximate, nil
+}
+
+// maxStreamPageSize bounds ListStream pages, which may be far larger than List's
+const maxStreamPageSize = 10000
+
+// ListStream runs List's query but hands the page to emit in chunks of up to
+// chunkSize tasks as rows are scanned, instead of buffering it. Pages default to
+// 20 tasks like List but may hold up to maxStreamPageSize. The pagination is
+// counted once the last chunk has been emitted. Scanning stops when ctx is
+// cancelled or emit returns an error, which ListStream then returns. The
+// connection stays checked out while emit runs, so emit should not block for
+// long.
+func (r *mysqlTodoRepository) ListStream(ctx context.Context, filters *ListTasksRequest, chunkSize int, emit func([]*todov1.Task) error) (*PaginationResult, error) {
+	page, pageSize := pageBounds(filters, maxStreamPageSize)
+	if chunkSize <= 0 {
+		chunkSize = 100
+	}
+
+	q := r.buildListQuery(ctx, filters)
+	db := r.reader(ctx)
+
+	if err := r.streamPageChunks(ctx, db, q, pageSize, (page-1)*pageSize, chunkSize, emit); err != nil {
+		return nil, err
+	}
+
+	totalItems, approximate := r.estimateListTotal(ctx, db, q)
+	if !approximate {
+		var err error
+		if totalItems, err = q.count(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+	totalUnfiltered, err := q.countUnfiltered(ctx, db, totalItems)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPagination(page, pageSize, totalItems, totalUnfiltered, approximate), nil
+}
+
+// streamPageChunks scans one page of q and emits it chunkSize tasks at a time
+func (r *mysqlTodoRepository) streamPageChunks(ctx context.Context, db queryer, q listQuery, pageSize, offset uint32, chunkSize int, emit func([]*todov1.Task) error) error {
+	rows, err := db.QueryContext(ctx, q.pageQuery(""), q.pageArgs(pageSize, offset)...)
+	if err != nil {
+		return fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	chunk := make([]*todov1.Task, 0, chunkSize)
+	for rows.Next() {
+		task, err := r.scanListRow(ctx, rows)
+		if err != nil {
+			return err
+		}
+		chunk = append(chunk, task)
+		if len(chunk) < chunkSize {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+		chunk = make([]*todov1.Task, 0, chunkSize)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	if len(chunk) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return emit(chunk)
+	}
+	return nil
+}
+
+// listQuery is the filtered, sorted set of tasks that List pages through
+type listQuery struct {
+	scope        []string // archive and tenant conditions every count respects
+	scopeArgs    []interface{}
+	where        string // WHERE clause for scope and filters; empty when unrestricted
+	args         []interface{}
+	filtered     bool // a filter narrows the result beyond scope
+	activeOnly   bool // archived tasks are out of scope
+	tenantScoped bool // other tenants' tasks are out of scope
+	orderBy      string
+	orderArgs    []interface{} // placeholders in orderBy, bound after args
+}
+
+// buildListQuery turns List filters into a listQuery
+func (r *mysqlTodoRepository) buildListQuery(ctx context.Context, filters *ListTasksRequest) listQuery {
+	// Archived tasks and other tenants' tasks are out of scope for every count
+	scope := []string{}
+	scopeArgs := []interface{}{}
+	if !filters.IncludeArchived {
+		scope = append(scope, "archived_at IS NULL")
+	}
+	cond, tenantArgs := tenantScope(ctx)
+	if cond != "" {
+		scope = append(scope, cond)
+		scopeArgs = append(scopeArgs, tenantArgs...)
 	}
 
 	// Build query conditions
-	conditions := []string{}
-	args := []interface{}{}
+	conditions := append([]string{}, scope...)
+	filtered := false
+	args := append([]interface{}{}, scopeArgs...)
 
 	// Search query
 	if filters.Query != "" {
-		conditions = append(conditions, "title LIKE ?")
-		args = append(args, "%"+filters.Query+"%")
+		if r.caseFoldedSearch {
+			conditions = append(conditions, "title_lower LIKE ?")
+			args = append(args, "%"+strings.ToLower(filters.Query)+"%")
+		} else {
+			conditions = append(conditions, "title LIKE ?")
+			args = append(args, "%"+filters.Query+"%")
+		}
+		filtered = true
 	}
 
 	// Status filter
 	switch filters.Status {
 	case todov1.StatusFilter_STATUS_FILTER_COMPLETED:
 		conditions = append(conditions, "completed = TRUE")
+		filtered = true
 	case todov1.StatusFilter_STATUS_FILTER_PENDING:
 		conditions = append(conditions, "completed = FALSE")
+		filtered = true
+	}
+
+	// Due date filter
+	switch filters.DueDate {
+	case todov1.DueDateFilter_DUE_DATE_FILTER_SCHEDULED:
+		conditions = append(conditions, "due_date IS NOT NULL")
+		filtered = true
+	case todov1.DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED:
+		conditions = append(conditions, "due_date IS NULL")
+		filtered = true
 	}
 
 	// Build WHERE clause
@@ -190,125 +783,465 @@ func (r *mysqlTodoRepository) List(ctx context.Context, filters *ListTasksReques
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Count total items
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks %s", whereClause)
-	var totalItems uint32
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalItems)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to count tasks: %w", err)
+	// Determine sort field and order; both come from closed sets, never the request
+	sortBy, sortDirection := resolveSort(filters.SortBy, filters.SortOrder, r.defaultSortField, r.defaultSortOrder)
+	sortField := sortColumn(sortBy)
+	sortOrder := "DESC"
+	if sortAscending(sortBy, sortDirection) {
+		sortOrder = "ASC"
 	}
 
-	// Calculate pagination
-	totalPages := (totalItems + pageSize - 1) / pageSize
-	offset := (page - 1) * pageSize
-
-	// Determine sort field and order
-	sortField := "created_at"
-	switch filters.SortBy {
-	case todov1.SortField_SORT_FIELD_UPDATED_AT:
-		sortField = "updated_at"
-	case todov1.SortField_SORT_FIELD_TITLE:
-		sortField = "title"
+	orderBy := fmt.Sprintf("ORDER BY %s %s, %s %s", sortField, sortOrder, quoteIdentifier("id"), sortOrder)
+	var orderArgs []interface{}
+	if filters.SortBy == todov1.SortField_SORT_FIELD_RELEVANCE && filters.Query != "" {
+		searchColumn := "LOWER(title)"
+		if r.caseFoldedSearch {
+			searchColumn = "title_lower"
+		}
+		orderBy, orderArgs = relevanceOrder(searchColumn, filters.Query)
 	}
 
-	sortOrder := "DESC"
-	if filters.SortOrder == todov1.SortOrder_SORT_ORDER_ASC {
-		sortOrder = "ASC"
+	return listQuery{
+		scope:        scope,
+		scopeArgs:    scopeArgs,
+		where:        whereClause,
+		args:         args,
+		filtered:     filtered,
+		activeOnly:   !filters.IncludeArchived,
+		tenantScoped: cond != "",
+		orderBy:      orderBy,
+		orderArgs:    orderArgs,
 	}
+}
 
-	// Query tasks
-	query := fmt.Sprintf(`
-		SELECT id, title, completed, created_at, updated_at
+// pageArgs returns the arguments for pageQuery: the filter and ordering
+// arguments, then the limit and offset
+func (q listQuery) pageArgs(pageSize, offset uint32) []interface{} {
+	args := append(append([]interface{}{}, q.args...), q.orderArgs...)
+	return append(args, pageSize, offset)
+}
+
+// pageQuery selects one LIMIT ? OFFSET ? page, with any extra trailing columns
+func (q listQuery) pageQuery(extraColumns string) string {
+	return q.selectPage("id, title, completed, created_at, updated_at, archived_at, position, due_date" + extraColumns)
+}
+
+// selectPage selects columns for one LIMIT ? OFFSET ? page
+func (q listQuery) selectPage(columns string) string {
+	return fmt.Sprintf(`
+		SELECT %s
 		FROM tasks
 		%s
-		ORDER BY %s %s
+		%s
 		LIMIT ? OFFSET ?
-	`, whereClause, sortField, sortOrder)
+	`, columns, q.where, q.orderBy)
+}
 
-	args = append(args, pageSize, offset)
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// count returns the number of tasks matching the query
+func (q listQuery) count(ctx context.Context, db queryer) (uint32, error) {
+	var total uint32
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks %s", q.where)
+	if err := db.QueryRowContext(ctx, countQuery, q.args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return total, nil
+}
+
+// countUnfiltered counts all tasks in scope when a filter narrows the result;
+// otherwise the totals are equal and total is returned
+func (q listQuery) countUnfiltered(ctx context.Context, db queryer, total uint32) (uint32, error) {
+	if !q.filtered {
+		return total, nil
+	}
+	unfilteredQuery := "SELECT COUNT(*) FROM tasks"
+	if len(q.scope) > 0 {
+		unfilteredQuery += " WHERE " + strings.Join(q.scope, " AND ")
+	}
+	var totalUnfiltered uint32
+	if err := db.QueryRowContext(ctx, unfilteredQuery, q.scopeArgs...).Scan(&totalUnfiltered); err != nil {
+		return 0, fmt.Errorf("failed to count all tasks: %w", err)
+	}
+	return totalUnfiltered, nil
+}
+
+// queryTasks runs a page query and scans its tasks. When total is not nil the
+// query carries the total row count as an extra last column on every row; the
+// total is left at zero when the page is empty.
+func (r *mysqlTodoRepository) queryTasks(ctx context.Context, db queryer, query string, args []interface{}, total *uint32) ([]*todov1.Task, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query tasks: %w", err)
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
 	defer rows.Close()
 
-	// Collect tasks
+	var extra []interface{}
+	if total != nil {
+		extra = append(extra, total)
+	}
 	tasks := []*todov1.Task{}
 	for rows.Next() {
-		var task todov1.Task
-		var createdAt, updatedAt sql.NullTime
-
-		err := rows.Scan(&task.Id, &task.Title, &task.Completed, &createdAt, &updatedAt)
+		task, err := r.scanListRow(ctx, rows, extra...)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to scan task: %w", err)
+			return nil, err
 		}
+		tasks = append(tasks, task)
+	}
+	// A connection dropped mid-scan ends the loop early; don't return
+	// the partial page as if it were complete
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+	return tasks, nil
+}
 
-		if createdAt.Valid {
-			task.CreatedAt = timestamppb.New(createdAt.Time)
-		}
-		if updatedAt.Valid {
-			task.UpdatedAt = timestamppb.New(updatedAt.Time)
+// scanListRow scans one List row, plus any extra trailing columns into extra
+func (r *mysqlTodoRepository) scanListRow(ctx context.Context, rows *sql.Rows, extra ...interface{}) (*todov1.Task, error) {
+	var task todov1.Task
+	var createdAt, updatedAt, archivedAt, dueDate sql.NullTime
+
+	dest := []interface{}{&task.Id, &task.Title, &task.Completed, &createdAt, &updatedAt, &archivedAt, &task.Position, &dueDate}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan task: %w", err)
+	}
+
+	task.CreatedAt = r.toTimestamp(ctx, task.Id, "created_at", createdAt)
+	task.UpdatedAt = r.toTimestamp(ctx, task.Id, "updated_at", updatedAt)
+	task.ArchivedAt = r.toTimestamp(ctx, task.Id, "archived_at", archivedAt)
+	task.DueDate = r.toTimestamp(ctx, task.Id, "due_date", dueDate)
+	return &task, nil
+}
+
+// isSyntaxError reports whether the database rejected a query as unparseable,
+// as MySQL before 8.0 (error 1064) and old SQLite versions do for OVER ()
+func isSyntaxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1064
+	}
+	return strings.Contains(err.Error(), "syntax error")
+}
+
+// sortColumns is the closed allowlist of columns List may order by. Only
+// entries in this map ever reach the ORDER BY clause.
+var sortColumns = map[todov1.SortField]string{
+	todov1.SortField_SORT_FIELD_CREATED_AT: "created_at",
+	todov1.SortField_SORT_FIELD_UPDATED_AT: "updated_at",
+	todov1.SortField_SORT_FIELD_TITLE:      "title",
+	todov1.SortField_SORT_FIELD_POSITION:   "position",
+}
+
+// The shipped List ordering when neither the request nor WithDefaultSort
+// chooses one: newest first
+const (
+	defaultSortField = todov1.SortField_SORT_FIELD_CREATED_AT
+	defaultSortOrder = todov1.SortOrder_SORT_ORDER_DESC
+)
+
+// resolveSort applies the configured default to a request that leaves the sort
+// field unspecified (or names an unknown one). The default order only applies
+// along with the default field; an explicit field with no order keeps
+// sortAscending's per-field direction.
+func resolveSort(field todov1.SortField, order todov1.SortOrder, defaultField todov1.SortField, defaultOrder todov1.SortOrder) (todov1.SortField, todov1.SortOrder) {
+	if _, ok := sortColumns[field]; !ok {
+		field = defaultField
+		if order == todov1.SortOrder_SORT_ORDER_UNSPECIFIED {
+			order = defaultOrder
 		}
+	}
+	return field, order
+}
+
+// sortColumn returns the quoted column for a sort field, defaulting to
+// created_at for unspecified or unknown values
+func sortColumn(field todov1.SortField) string {
+	column, ok := sortColumns[field]
+	if !ok {
+		column = "created_at"
+	}
+	return quoteIdentifier(column)
+}
+
+// sortAscending reports the list direction. Lists default to newest first, but
+// the manual ordering defaults to top to bottom.
+func sortAscending(field todov1.SortField, order todov1.SortOrder) bool {
+	if order == todov1.SortOrder_SORT_ORDER_UNSPECIFIED {
+		return field == todov1.SortField_SORT_FIELD_POSITION
+	}
+	return order == todov1.SortOrder_SORT_ORDER_ASC
+}
+
+// quoteIdentifier quotes a column name with backticks, escaping embedded backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// ListGrouped lists pending and completed tasks as separately paginated groups
+func (r *mysqlTodoRepository) ListGrouped(ctx context.Context, filters *ListTasksRequest) (*GroupedTasks, error) {
+	return listGrouped(ctx, r, filters)
+}
+
+// Count returns the total number of tasks. It always reads the primary, as
+// the MAX_TASKS limit must see writes the replica has not caught up with.
+func (r *mysqlTodoRepository) Count(ctx context.Context) (uint32, error) {
+	query := "SELECT COUNT(*) FROM tasks"
+	cond, args := tenantScope(ctx)
+	if cond != "" {
+		query += " WHERE " + cond
+	}
+
+	var count uint32
+	if err := r.primary(ctx).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return count, nil
+}
+
+// CountCreatedSince returns the number of tasks, archived ones included,
+// created at or after since
+func (r *mysqlTodoRepository) CountCreatedSince(ctx context.Context, since time.Time) (uint32, error) {
+	where, args := scopedWhere(ctx, "created_at >= ?", since.UTC())
 
-		tasks = append(tasks, &task)
+	var count uint32
+	if err := r.reader(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count created tasks: %w", err)
+	}
+	return count, nil
+}
+
+// ExistsByTitle reports whether a task in ctx's tenant, archived ones
+// included, has the given title. Matching follows the column collation, which
+// is case-insensitive in MySQL.
+func (r *mysqlTodoRepository) ExistsByTitle(ctx context.Context, title string) (bool, error) {
+	return r.titleTaken(ctx, r.primary(ctx), title, "")
+}
+
+// estimateListTotal returns an approximate total for an unfiltered query that
+// spans every tenant: the table's row estimate, less the archived tasks when
+// those are out of scope. Archived tasks are counted exactly through
+// idx_archived_at. ok is false when the total has to be counted instead.
+func (r *mysqlTodoRepository) estimateListTotal(ctx context.Context, db queryer, q listQuery) (total uint32, ok bool) {
+	if q.filtered || q.tenantScoped {
+		return 0, false
+	}
+	estimate, ok := r.estimateRowCount(ctx, db)
+	if !ok || !q.activeOnly {
+		return estimate, ok
+	}
+
+	var archived uint32
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE archived_at IS NOT NULL").Scan(&archived); err != nil {
+		return 0, false
+	}
+	if archived >= estimate {
+		// The estimate lags too far behind to subtract from
+		return 0, false
+	}
+	return estimate - archived, true
+}
+
+// estimateRowCount returns the storage engine's row estimate for the tasks table
+// when approximate counts are enabled and the estimate meets the threshold.
+// Any failure (including non-MySQL databases) falls back to an exact count.
+func (r *mysqlTodoRepository) estimateRowCount(ctx context.Context, q queryer) (uint32, bool) {
+	if r.approxCountThreshold == 0 {
+		return 0, false
 	}
 
-	pagination := &PaginationResult{
-		Page:        page,
-		PageSize:    pageSize,
-		TotalPages:  totalPages,
-		TotalItems:  totalItems,
-		HasPrevious: page > 1,
-		HasNext:     page < totalPages,
+	var estimate sql.NullInt64
+	err := q.QueryRowContext(ctx, `
+		SELECT TABLE_ROWS
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'tasks'
+	`).Scan(&estimate)
+	if err != nil || !estimate.Valid || estimate.Int64 < int64(r.approxCountThreshold) {
+		return 0, false
 	}
 
-	return tasks, pagination, nil
+	return uint32(estimate.Int64), true
 }
 
-// Update modifies an existing task
-func (r *mysqlTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, error) {
-	// Check if task exists
-	_, err := r.GetByID(ctx, req.ID)
+// Duplicate copies an existing task into a new pending task, appending titleSuffix
+// to the title. The read and insert share a transaction and the source row stays
+// locked until it commits, so a concurrent edit or delete of the source waits
+// rather than leaving a copy of a row that no longer exists in that form.
+func (r *mysqlTodoRepository) Duplicate(ctx context.Context, id string, titleSuffix string) (*todov1.Task, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.Duplicate")
+
+	var task *todov1.Task
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		source, err := r.getByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		title := duplicateTitle(source.Title, titleSuffix)
+		if err := r.checkTitleFree(ctx, tx, title, ""); err != nil {
+			return err
+		}
+
+		newID := r.idGen.NewID()
+		stampColumns, stampValues, stamps := r.insertStamps()
+		from, scope := appendScope(ctx)
+		args := append([]interface{}{newID, title, tenantOf(ctx), dueDateOf(source)}, stamps...)
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tasks (id, title, completed, tenant_id, due_date, position`+stampColumns+`)
+			SELECT ?, ?, FALSE, ?, ?, `+appendPosition+stampValues+`
+			`+from, append(args, scope...)...)
+		if err != nil {
+			return fmt.Errorf("failed to duplicate task: %w", err)
+		}
+
+		if task, err = r.getByID(ctx, tx, newID); err != nil {
+			return err
+		}
+		return r.bumpListVersion(ctx, tx, 1)
+	})
+	r.logger.LogDatabaseOperation(ctx, "INSERT tasks copy", time.Since(start), err == nil, 1)
 	if err != nil {
 		return nil, err
 	}
+	r.markWrite()
 
-	// Update task
+	return task, nil
+}
+
+// dueDateOf returns the task's due date as a nullable column value
+func dueDateOf(task *todov1.Task) *time.Time {
+	if task.DueDate == nil {
+		return nil
+	}
+	dueDate := task.DueDate.AsTime()
+	return &dueDate
+}
+
+// duplicateTitle appends suffix to title, shortening title on a rune boundary
+// so the result still fits in validator.MaxTitleLength bytes
+func duplicateTitle(title, suffix string) string {
+	limit := validator.MaxTitleLength - len(suffix)
+	if len(title) > limit {
+		cut := 0
+		for i := range title {
+			if i > limit {
+				break
+			}
+			cut = i
+		}
+		title = strings.TrimSpace(title[:cut])
+	}
+	return title + suffix
+}
+
+// Update modifies an existing task, returning it along with the row as read,
+// locked, before the write. Callers tell whether any written field took a new
+// value with req.Changes(previous) rather than from RowsAffected: MySQL counts
+// only changed rows (matched ones with clientFoundRows) and counts the row
+// whenever updated_at is set explicitly, while SQLite counts matched rows.
+//
+// title is NOT NULL and never blank, so the generated SQL never contains
+// title = '': without a mask an empty Title means "keep the title" and is left
+// out of the SET clause, and a mask selecting the title with an empty Title is
+// rejected before anything is written.
+func (r *mysqlTodoRepository) Update(ctx context.Context, req *UpdateTaskRequest) (*todov1.Task, *todov1.Task, error) {
+	fields := req.Fields()
+	if fields.Title && req.Title == "" {
+		return nil, nil, errEmptyTitleUpdate
+	}
+
+	// Update only the selected fields
 	updates := []string{}
 	args := []interface{}{}
 
-	if req.Title != "" {
+	if fields.Title {
 		updates = append(updates, "title = ?")
 		args = append(args, req.Title)
 	}
 
-	// Always update completed status
-	updates = append(updates, "completed = ?")
-	args = append(args, req.Completed)
+	if fields.Completed {
+		updates = append(updates, "completed = ?")
+		args = append(args, req.Completed)
+	}
 
-	// Add ID for WHERE clause
-	args = append(args, req.ID)
+	// A new due date deserves a new reminder
+	if fields.DueDate {
+		if req.DueDate == nil {
+			updates = append(updates, "due_date = NULL", "reminded_at = NULL")
+		} else {
+			updates = append(updates, "due_date = ?", "reminded_at = NULL")
+			args = append(args, *req.DueDate)
+		}
+	}
+
+	// An explicit value also stops MySQL's ON UPDATE CURRENT_TIMESTAMP
+	if r.clock != nil {
+		updates = append(updates, "updated_at = ?")
+		args = append(args, r.clock.Now())
+	}
+
+	// Add ID (and tenant) for WHERE clause
+	where, args := scopedWhere(ctx, "id = ?", append(args, req.ID)...)
 
 	query := fmt.Sprintf(`
 		UPDATE tasks
 		SET %s
-		WHERE id = ?
-	`, strings.Join(updates, ", "))
+		WHERE %s
+	`, strings.Join(updates, ", "), where)
+
+	// Read, write and re-read in one transaction so the comparison and the
+	// returned task describe the same write; the read locks the row on MySQL
+	// so a concurrent update waits rather than being overwritten unseen
+	var task, previous *todov1.Task
+	var changed bool
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		previous, err = r.getByIDForUpdate(ctx, tx, req.ID)
+		if err != nil {
+			return err
+		}
+		changed = req.Changes(previous)
+		if fields.Title && req.Title != previous.Title {
+			if err := r.checkTitleFree(ctx, tx, req.Title, req.ID); err != nil {
+				return err
+			}
+		}
+		if !changed && req.SkipNoOp {
+			task = previous
+			return nil
+		}
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		if err := r.bumpListVersion(ctx, tx, rowsAffected); err != nil {
+			return err
+		}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
+		task, err = r.getByID(ctx, tx, req.ID)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update task: %w", err)
+		return nil, nil, err
+	}
+	if changed || !req.SkipNoOp {
+		r.markWrite()
 	}
 
-	return r.GetByID(ctx, req.ID)
+	return task, previous, nil
 }
 
-// Delete removes a task from the database
+// Delete removes a task from the database, or moves it to the trash when soft
+// delete is on
 func (r *mysqlTodoRepository) Delete(ctx context.Context, id string) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id)
+	where, args := scopedWhere(ctx, "id = ?", id)
+	result, err := r.deleteTasks(ctx, where, args...)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
+	r.markWrite()
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
@@ -322,7 +1255,194 @@ func (r *mysqlTodoRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// HealthCheck verifies the database connection
+// DeleteMany removes several tasks in a single transaction, reporting a result per id.
+// A missing id or a failed delete does not abort the batch; only a failure to begin
+// or commit the transaction fails the whole call.
+func (r *mysqlTodoRepository) DeleteMany(ctx context.Context, ids []string) ([]*todov1.DeleteTaskResult, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.DeleteMany")
+
+	var results []*todov1.DeleteTaskResult
+	var rowsAffected int64
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		results = make([]*todov1.DeleteTaskResult, 0, len(ids))
+		rowsAffected = 0
+		for _, id := range ids {
+			result := &todov1.DeleteTaskResult{Id: id}
+
+			where, args := scopedWhere(ctx, "id = ?", id)
+			res, err := r.trashAndDelete(ctx, tx, where, args...)
+			if err == nil {
+				var n int64
+				n, err = res.RowsAffected()
+				if err == nil && n == 0 {
+					result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND
+				}
+				rowsAffected += n
+			}
+
+			switch {
+			case err != nil:
+				result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_ERROR
+				result.Error = err.Error()
+			case result.Status == todov1.DeleteResultStatus_DELETE_RESULT_STATUS_UNSPECIFIED:
+				result.Status = todov1.DeleteResultStatus_DELETE_RESULT_STATUS_DELETED
+			}
+
+			results = append(results, result)
+		}
+		return r.bumpListVersion(ctx, tx, rowsAffected)
+	})
+	r.logger.LogDatabaseOperation(ctx, "DELETE tasks batch", time.Since(start), err == nil, rowsAffected)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete batch: %w", err)
+	}
+	r.markWrite()
+
+	return results, nil
+}
+
+// defaultStreamBatchSize is how many rows StreamAll fetches per query
+const defaultStreamBatchSize = 500
+
+// StreamAll sends every task in scope, including archived ones, on the returned channel
+// in id order, paging through the table so memory use stays bounded. The task
+// channel is closed when streaming ends; at most one error, including context
+// cancellation, is sent on the error channel, which is then closed.
+func (r *mysqlTodoRepository) StreamAll(ctx context.Context) (<-chan *todov1.Task, <-chan error) {
+	tasks := make(chan *todov1.Task)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(tasks)
+
+		lastID := ""
+		for {
+			page, err := r.streamPage(ctx, lastID)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for _, task := range page {
+				// Check first so a cancelled consumer that keeps receiving still stops
+				if err := ctx.Err(); err != nil {
+					errs <- err
+					return
+				}
+				select {
+				case tasks <- task:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if len(page) < r.streamBatchSize {
+				return
+			}
+			lastID = page[len(page)-1].Id
+		}
+	}()
+
+	return tasks, errs
+}
+
+// streamPage fetches the next batch of tasks with ids greater than afterID.
+// Rows are fully read and closed before any task is handed to a consumer.
+func (r *mysqlTodoRepository) streamPage(ctx context.Context, afterID string) ([]*todov1.Task, error) {
+	where, args := scopedWhere(ctx, "id > ?", afterID)
+	query := `
+		SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date
+		FROM tasks
+		WHERE ` + where + `
+		ORDER BY id
+		LIMIT ?
+	`
+
+	rows, err := r.reader(ctx).QueryContext(ctx, query, append(args, r.streamBatchSize)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream tasks: %w", err)
+	}
+	defer rows.Close()
+
+	page := make([]*todov1.Task, 0, r.streamBatchSize)
+	for rows.Next() {
+		var task todov1.Task
+		var createdAt, updatedAt, archivedAt, dueDate sql.NullTime
+
+		if err := rows.Scan(&task.Id, &task.Title, &task.Completed, &createdAt, &updatedAt, &archivedAt, &task.Position, &dueDate); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+
+		task.CreatedAt = r.toTimestamp(ctx, task.Id, "created_at", createdAt)
+		task.UpdatedAt = r.toTimestamp(ctx, task.Id, "updated_at", updatedAt)
+		task.ArchivedAt = r.toTimestamp(ctx, task.Id, "archived_at", archivedAt)
+		task.DueDate = r.toTimestamp(ctx, task.Id, "due_date", dueDate)
+
+		page = append(page, &task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to stream tasks: %w", err)
+	}
+
+	return page, nil
+}
+
+// ArchiveCompleted flags completed tasks last updated before the cutoff as archived
+// and returns how many were archived. updated_at is left untouched so the archive
+// does not look like a user edit. Without a tenant in ctx every tenant is archived.
+func (r *mysqlTodoRepository) ArchiveCompleted(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.ArchiveCompleted")
+
+	archivedAt := "CURRENT_TIMESTAMP"
+	var stamps []interface{}
+	if r.clock != nil {
+		archivedAt = "?"
+		stamps = append(stamps, r.clock.Now())
+	}
+
+	where, args := scopedWhere(ctx, "completed = TRUE AND archived_at IS NULL AND updated_at < ?", before)
+	args = append(stamps, args...)
+	query := `
+		UPDATE tasks
+		SET archived_at = ` + archivedAt + `, updated_at = updated_at
+		WHERE ` + where
+
+	result, err := r.execMutation(ctx, query, args...)
+	duration := time.Since(start)
+
+	var rowsAffected int64
+	if result != nil {
+		rowsAffected, _ = result.RowsAffected()
+	}
+	r.logger.LogDatabaseOperation(ctx, "UPDATE tasks archive", duration, err == nil, rowsAffected)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+	r.markWrite()
+
+	return rowsAffected, nil
+}
+
+// HealthCheck verifies the primary database connection. The read replica is
+// checked separately by ReplicaHealthCheck so that a lagging or unreachable
+// replica does not take the service out of rotation.
 func (r *mysqlTodoRepository) HealthCheck(ctx context.Context) error {
 	return r.db.PingContext(ctx)
+}
+
+// ReplicaHealthCheck verifies the read replica connection, returning
+// ErrNoReplica when reads go to the primary
+func (r *mysqlTodoRepository) ReplicaHealthCheck(ctx context.Context) error {
+	if r.replica == nil {
+		return ErrNoReplica
+	}
+	if err := r.replica.PingContext(ctx); err != nil {
+		return fmt.Errorf("read replica connection failed: %w", err)
+	}
+	return nil
 }
\ No newline at end of file
@@ -3,10 +3,23 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
-	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"connectrpc.com/connect"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	_ "github.com/mattn/go-sqlite3"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestMySQLTodoRepository_WithLogging(t *testing.T) {
@@ -24,7 +37,12 @@ func TestMySQLTodoRepository_WithLogging(t *testing.T) {
 			title TEXT NOT NULL,
 			completed BOOLEAN DEFAULT FALSE,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			archived_at DATETIME,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			position REAL NOT NULL DEFAULT 0,
+			due_date DATETIME,
+			reminded_at DATETIME
 		)
 	`)
 	if err != nil {
@@ -109,37 +127,1968 @@ func TestMySQLTodoRepository_WithLogging(t *testing.T) {
 	})
 }
 
-// TestRepositoryInterface ensures our repository implements the interface correctly
-func TestRepositoryInterface(t *testing.T) {
-	db, err := sql.Open("sqlite3", ":memory:")
+func TestMySQLTodoRepository_DeleteMany(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, &CreateTaskRequest{Title: "First"})
 	if err != nil {
-		t.Fatalf("Failed to open database: %v", err)
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	second, err := repo.Create(ctx, &CreateTaskRequest{Title: "Second"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	results, err := repo.DeleteMany(ctx, []string{first.Id, "missing-id", second.Id})
+	if err != nil {
+		t.Fatalf("Expected batch delete to succeed, got error: %v", err)
+	}
+
+	expected := []todov1.DeleteResultStatus{
+		todov1.DeleteResultStatus_DELETE_RESULT_STATUS_DELETED,
+		todov1.DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND,
+		todov1.DeleteResultStatus_DELETE_RESULT_STATUS_DELETED,
+	}
+	if len(results) != len(expected) {
+		t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+	}
+	for i, result := range results {
+		if result.Status != expected[i] {
+			t.Errorf("Result %d: expected status %v, got %v", i, expected[i], result.Status)
+		}
+	}
+
+	if _, err := repo.GetByID(ctx, first.Id); err == nil {
+		t.Error("Expected first task to be deleted")
+	}
+	if _, err := repo.GetByID(ctx, second.Id); err == nil {
+		t.Error("Expected second task to be deleted")
+	}
+}
+
+func TestMySQLTodoRepository_Count(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 tasks, got %d", count)
+	}
+
+	for _, title := range []string{"One", "Two"} {
+		if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	count, err = repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 tasks, got %d", count)
+	}
+}
+
+func TestMySQLTodoRepository_ExistsByTitle(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	exists, err := repo.ExistsByTitle(ctx, "Buy milk")
+	if err != nil {
+		t.Fatalf("Failed to look up title: %v", err)
+	}
+	if !exists {
+		t.Error("Expected existing title to be found")
+	}
+
+	exists, err = repo.ExistsByTitle(ctx, "Buy eggs")
+	if err != nil {
+		t.Fatalf("Failed to look up title: %v", err)
+	}
+	if exists {
+		t.Error("Expected unknown title not to be found")
+	}
+}
+
+func TestMySQLTodoRepository_CreateWithClientID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+	const clientID = "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+	task, err := repo.Create(ctx, &CreateTaskRequest{ID: clientID, Title: "Offline task"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if task.Id != clientID {
+		t.Errorf("Expected client ID %s, got %s", clientID, task.Id)
+	}
+
+	// The primary key rejects a second create with the same ID
+	if _, err := repo.Create(ctx, &CreateTaskRequest{ID: clientID, Title: "Retry"}); err == nil {
+		t.Error("Expected duplicate ID to fail")
+	}
+}
+
+func TestMySQLTodoRepository_ListTotalUnfiltered(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	for _, title := range []string{"Buy milk", "Buy eggs", "Walk dog", "Read book", "Buy bread"} {
+		if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	_, pagination, err := repo.List(ctx, &ListTasksRequest{Query: "Buy"})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if pagination.TotalItems != 3 {
+		t.Errorf("Expected 3 filtered items, got %d", pagination.TotalItems)
+	}
+	if pagination.TotalUnfiltered != 5 {
+		t.Errorf("Expected 5 unfiltered items, got %d", pagination.TotalUnfiltered)
+	}
+
+	_, pagination, err = repo.List(ctx, &ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if pagination.TotalItems != 5 || pagination.TotalUnfiltered != 5 {
+		t.Errorf("Expected totals to match without filters, got %d and %d", pagination.TotalItems, pagination.TotalUnfiltered)
+	}
+}
+
+func TestMySQLTodoRepository_CaseFoldedSearch(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	// Make LIKE case-sensitive, as a binary collation would be
+	if _, err := db.Exec("PRAGMA case_sensitive_like = ON"); err != nil {
+		t.Fatalf("Failed to set pragma: %v", err)
+	}
+
+	for _, title := range []string{"Buy MILK", "buy milk", "Walk dog"} {
+		if _, err := NewMySQLTodoRepository(db).Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+	}
+
+	tasks, _, err := NewMySQLTodoRepository(db).List(ctx, &ListTasksRequest{Query: "Milk"})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("Expected collation-dependent search to miss, got %d tasks", len(tasks))
+	}
+
+	repo := NewMySQLTodoRepository(db, WithCaseFoldedSearch())
+	for _, query := range []string{"Milk", "MILK", "milk", "bUy M"} {
+		tasks, pagination, err := repo.List(ctx, &ListTasksRequest{Query: query})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if len(tasks) != 2 || pagination.TotalItems != 2 {
+			t.Errorf("Query %q: expected 2 tasks, got %d", query, len(tasks))
+		}
+		for _, task := range tasks {
+			if task.Title != "Buy MILK" && task.Title != "buy milk" {
+				t.Errorf("Query %q: expected original title casing, got %q", query, task.Title)
+			}
+		}
+	}
+}
+
+func TestMySQLTodoRepository_WindowedCount(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	twoQueries := NewMySQLTodoRepository(db)
+	windowed := NewMySQLTodoRepository(db, WithWindowedCount())
+
+	for i := 0; i < 25; i++ {
+		task, err := twoQueries.Create(ctx, &CreateTaskRequest{Title: fmt.Sprintf("Task %d", i)})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		if i%3 == 0 {
+			if _, _, err := twoQueries.Update(ctx, &UpdateTaskRequest{ID: task.Id, Completed: true}); err != nil {
+				t.Fatalf("Failed to complete task: %v", err)
+			}
+		}
+	}
+
+	requests := []*ListTasksRequest{
+		{},
+		{Page: 2, PageSize: 10},
+		{Page: 3, PageSize: 10},
+		{Page: 9, PageSize: 10}, // past the end
+		{Query: "Task 1"},
+		{Status: todov1.StatusFilter_STATUS_FILTER_COMPLETED, PageSize: 5},
+		{Query: "nothing matches"},
+	}
+	for _, req := range requests {
+		wantTasks, want, err := twoQueries.List(ctx, req)
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		gotTasks, got, err := windowed.List(ctx, req)
+		if err != nil {
+			t.Fatalf("Failed to list tasks with windowed count: %v", err)
+		}
+		if *got != *want {
+			t.Errorf("%+v: expected pagination %+v, got %+v", req, *want, *got)
+		}
+		if len(gotTasks) != len(wantTasks) {
+			t.Errorf("%+v: expected %d tasks, got %d", req, len(wantTasks), len(gotTasks))
+		}
+	}
+	if windowed.(*mysqlTodoRepository).windowUnsupported.Load() {
+		t.Error("Expected SQLite to support window functions")
+	}
+}
+
+func TestMySQLTodoRepository_MissingTable(t *testing.T) {
+	errorHandler := middleware.NewErrorHandler(middleware.NewStructuredLogger(middleware.LevelError))
+	assertMissingTable := func(t *testing.T, err error) {
+		t.Helper()
+		mapped := errorHandler.HandleRepositoryError(err)
+		if connect.CodeOf(mapped) != connect.CodeFailedPrecondition {
+			t.Fatalf("Expected FailedPrecondition, got %v", mapped)
+		}
+		if !strings.Contains(mapped.Error(), "run the database migrations") {
+			t.Errorf("Expected the message to point at migrations, got %q", mapped.Error())
+		}
+	}
+
+	t.Run("mysql error 1146", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT .* FROM tasks").
+			WillReturnError(&mysql.MySQLError{Number: 1146, Message: "Table 'todos.tasks' doesn't exist"})
+
+		_, err = NewMySQLTodoRepository(db).GetByID(context.Background(), "550e8400-e29b-41d4-a716-446655440000")
+		if err == nil {
+			t.Fatal("Expected an error for a missing table")
+		}
+		assertMissingTable(t, err)
+	})
+
+	t.Run("sqlite no such table", func(t *testing.T) {
+		db := setupTestDB(t)
+		if _, err := db.Exec("DROP TABLE tasks"); err != nil {
+			t.Fatalf("Failed to drop table: %v", err)
+		}
+
+		_, _, err := NewSQLiteTodoRepository(db).List(context.Background(), &ListTasksRequest{})
+		if err == nil {
+			t.Fatal("Expected an error for a missing table")
+		}
+		assertMissingTable(t, err)
+	})
+}
+
+func TestMySQLTodoRepository_DataTooLong(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
 	}
 	defer db.Close()
 
-	// This should compile if the interface is implemented correctly
-	var repo TodoRepository = NewMySQLTodoRepository(db)
-	
-	if repo == nil {
-		t.Fatal("Expected repository to be created")
+	mock.ExpectExec("INSERT INTO tasks").
+		WillReturnError(&mysql.MySQLError{Number: 1406, Message: "Data too long for column 'title' at row 1"})
+
+	_, err = NewMySQLTodoRepository(db).Create(context.Background(), &CreateTaskRequest{Title: strings.Repeat("x", 300)})
+	if err == nil {
+		t.Fatal("Expected an error for an overlong title")
 	}
 
-	// Test with custom logger
-	logger := middleware.NewStructuredLogger(middleware.LevelDebug)
-	var repoWithLogger TodoRepository = NewMySQLTodoRepositoryWithLogger(db, logger)
-	
-	if repoWithLogger == nil {
-		t.Fatal("Expected repository with logger to be created")
+	mapped := middleware.NewErrorHandler(middleware.NewStructuredLogger(middleware.LevelError)).HandleRepositoryError(err)
+	if connect.CodeOf(mapped) != connect.CodeInvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", mapped)
+	}
+	var tooLong *middleware.ValueTooLongError
+	if !errors.As(mapped, &tooLong) || tooLong.Column != "title" {
+		t.Errorf("Expected a ValueTooLongError for title, got %v", mapped)
+	}
+	if !strings.Contains(mapped.Error(), "title is too long to store") {
+		t.Errorf("Expected the message to explain the value is too long, got %q", mapped.Error())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
 	}
 }
 
-// Helper function to extract source from context for testing
-func getSourceFromContext(ctx context.Context) string {
-	if ctx == nil {
-		return ""
+func TestMySQLTodoRepository_DuplicateEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
 	}
-	if source, ok := ctx.Value("source").(string); ok {
-		return source
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO tasks").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'default-Buy milk' for key 'tasks.uniq_tenant_title'"})
+
+	_, err = NewMySQLTodoRepository(db).Create(context.Background(), &CreateTaskRequest{Title: "Buy milk"})
+	if err == nil {
+		t.Fatal("Expected an error for a duplicate entry")
 	}
-	return ""
-}
\ No newline at end of file
+
+	mapped := middleware.NewErrorHandler(middleware.NewStructuredLogger(middleware.LevelError)).HandleRepositoryError(err)
+	if connect.CodeOf(mapped) != connect.CodeAlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", mapped)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQLTodoRepository_WindowedCountFallback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMySQLTodoRepository(db, WithWindowedCount())
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	// MySQL 5.7 cannot parse OVER (); later calls skip straight to two queries
+	mock.ExpectQuery(`COUNT\(\*\) OVER \(\)`).
+		WillReturnError(&mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"})
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks`).
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("1", "Task", false, now, now, nil, 1024.0, nil))
+	}
+
+	for i := 0; i < 2; i++ {
+		tasks, pagination, err := repo.List(context.Background(), &ListTasksRequest{})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if len(tasks) != 1 || pagination.TotalItems != 1 {
+			t.Errorf("Expected 1 task, got %d (total %d)", len(tasks), pagination.TotalItems)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQLTodoRepository_ListRowError(t *testing.T) {
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	// The connection drops after the first row, as with a reset mid-scan
+	pageRows := func(extra ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows(append(columns, extra...))
+		for i, id := range []string{"1", "2", "3"} {
+			values := []driver.Value{id, "Task " + id, false, now, now, nil, 1024.0, nil}
+			if len(extra) > 0 {
+				values = append(values, 3)
+			}
+			rows.AddRow(values...)
+			if i == 1 {
+				rows.RowError(i, mysql.ErrInvalidConn)
+			}
+		}
+		return rows
+	}
+
+	t.Run("two queries", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM tasks`).
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(pageRows())
+
+		tasks, _, err := NewMySQLTodoRepository(db).List(context.Background(), &ListTasksRequest{})
+		if !errors.Is(err, mysql.ErrInvalidConn) {
+			t.Errorf("Expected the row error, got %v", err)
+		}
+		if tasks != nil {
+			t.Errorf("Expected no partial results, got %d tasks", len(tasks))
+		}
+	})
+
+	t.Run("windowed count", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		mock.ExpectQuery(`COUNT\(\*\) OVER \(\)`).
+			WillReturnRows(pageRows("total"))
+
+		tasks, _, err := NewMySQLTodoRepository(db, WithWindowedCount()).List(context.Background(), &ListTasksRequest{})
+		if !errors.Is(err, mysql.ErrInvalidConn) {
+			t.Errorf("Expected the row error, got %v", err)
+		}
+		if tasks != nil {
+			t.Errorf("Expected no partial results, got %d tasks", len(tasks))
+		}
+	})
+}
+
+func TestMySQLTodoRepository_ListGrouped(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	for _, seed := range []struct {
+		title     string
+		completed bool
+	}{
+		{"Buy milk", false},
+		{"Buy eggs", false},
+		{"Buy bread", false},
+		{"Walk dog", false},
+		{"Buy stamps", true},
+		{"Read book", true},
+	} {
+		task, err := repo.Create(ctx, &CreateTaskRequest{Title: seed.title})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		if seed.completed {
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: task.Id, Title: seed.title, Completed: true}); err != nil {
+				t.Fatalf("Failed to complete task: %v", err)
+			}
+		}
+	}
+
+	grouped, err := repo.ListGrouped(ctx, &ListTasksRequest{Query: "Buy", PageSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to list grouped tasks: %v", err)
+	}
+
+	if grouped.Pending.Pagination.TotalItems != 3 {
+		t.Errorf("Expected 3 pending matches, got %d", grouped.Pending.Pagination.TotalItems)
+	}
+	if len(grouped.Pending.Tasks) != 2 || !grouped.Pending.Pagination.HasNext {
+		t.Errorf("Expected a full first pending page with more to follow, got %d tasks", len(grouped.Pending.Tasks))
+	}
+	if grouped.Completed.Pagination.TotalItems != 1 || len(grouped.Completed.Tasks) != 1 {
+		t.Errorf("Expected 1 completed match, got %d", grouped.Completed.Pagination.TotalItems)
+	}
+	if grouped.Completed.Pagination.HasNext {
+		t.Error("Expected completed group to fit on one page")
+	}
+	for _, task := range grouped.Pending.Tasks {
+		if task.Completed {
+			t.Errorf("Completed task %q returned in pending group", task.Title)
+		}
+	}
+
+	grouped, err = repo.ListGrouped(ctx, &ListTasksRequest{Query: "Buy", PageSize: 2, Page: 2})
+	if err != nil {
+		t.Fatalf("Failed to list grouped tasks: %v", err)
+	}
+	if len(grouped.Pending.Tasks) != 1 || grouped.Pending.Pagination.HasNext {
+		t.Errorf("Expected last pending page with 1 task, got %d", len(grouped.Pending.Tasks))
+	}
+	if len(grouped.Completed.Tasks) != 0 {
+		t.Errorf("Expected completed group to be exhausted on page 2, got %d tasks", len(grouped.Completed.Tasks))
+	}
+}
+
+func TestMySQLTodoRepository_TenantIsolation(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	acme := middleware.WithTenant(context.Background(), "acme")
+	globex := middleware.WithTenant(context.Background(), "globex")
+
+	acmeTask, err := repo.Create(acme, &CreateTaskRequest{Title: "Acme plan"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := repo.Create(globex, &CreateTaskRequest{Title: "Globex plan"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	tasks, pagination, err := repo.List(acme, &ListTasksRequest{Query: "plan"})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Id != acmeTask.Id {
+		t.Errorf("Expected only acme's task, got %v", tasks)
+	}
+	if pagination.TotalItems != 1 || pagination.TotalUnfiltered != 1 {
+		t.Errorf("Expected tenant-scoped totals of 1, got %d and %d", pagination.TotalItems, pagination.TotalUnfiltered)
+	}
+
+	if count, err := repo.Count(globex); err != nil || count != 1 {
+		t.Errorf("Expected globex count 1, got %d (%v)", count, err)
+	}
+	if count, err := repo.CountCreatedSince(globex, time.Time{}); err != nil || count != 1 {
+		t.Errorf("Expected globex created count 1, got %d (%v)", count, err)
+	}
+	if exists, err := repo.ExistsByTitle(globex, "Acme plan"); err != nil || exists {
+		t.Errorf("Expected acme's title to be invisible to globex, got %v (%v)", exists, err)
+	}
+
+	// Other tenants' tasks behave as if they do not exist
+	if _, err := repo.GetByID(globex, acmeTask.Id); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found reading across tenants, got %v", err)
+	}
+	if _, _, err := repo.Update(globex, &UpdateTaskRequest{ID: acmeTask.Id, Completed: true}); err == nil {
+		t.Error("Expected update across tenants to fail")
+	}
+	if err := repo.Delete(globex, acmeTask.Id); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found deleting across tenants, got %v", err)
+	}
+
+	task, err := repo.GetByID(acme, acmeTask.Id)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.Completed {
+		t.Error("Expected acme's task to be untouched by globex")
+	}
+
+	// An unscoped context spans every tenant
+	if count, err := repo.Count(context.Background()); err != nil || count != 2 {
+		t.Errorf("Expected unscoped count 2, got %d (%v)", count, err)
+	}
+}
+
+func TestSortColumn(t *testing.T) {
+	tests := []struct {
+		field todov1.SortField
+		want  string
+	}{
+		{todov1.SortField_SORT_FIELD_UNSPECIFIED, "`created_at`"},
+		{todov1.SortField_SORT_FIELD_CREATED_AT, "`created_at`"},
+		{todov1.SortField_SORT_FIELD_UPDATED_AT, "`updated_at`"},
+		{todov1.SortField_SORT_FIELD_TITLE, "`title`"},
+		{todov1.SortField(999), "`created_at`"},
+		{todov1.SortField(-1), "`created_at`"},
+	}
+
+	for _, tt := range tests {
+		if got := sortColumn(tt.field); got != tt.want {
+			t.Errorf("sortColumn(%d) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+
+	if got := quoteIdentifier("title`; DROP TABLE tasks; --"); got != "`title``; DROP TABLE tasks; --`" {
+		t.Errorf("Expected embedded backticks to be escaped, got %q", got)
+	}
+}
+
+func TestMySQLTodoRepository_ListUnknownSortField(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Only task"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	tasks, _, err := repo.List(ctx, &ListTasksRequest{
+		SortBy:    todov1.SortField(999),
+		SortOrder: todov1.SortOrder(999),
+	})
+	if err != nil {
+		t.Fatalf("Expected unknown sort values to fall back to defaults, got %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("Expected 1 task, got %d", len(tasks))
+	}
+}
+
+func TestDefaultSort(t *testing.T) {
+	repos := map[string]func(configure bool) TodoRepository{
+		"mysql": func(configure bool) TodoRepository {
+			opts := []Option{WithIDGenerator(NewSequentialGenerator(0))}
+			if configure {
+				opts = append(opts, WithDefaultSort(todov1.SortField_SORT_FIELD_TITLE, todov1.SortOrder_SORT_ORDER_ASC))
+			}
+			return NewMySQLTodoRepository(setupTestDB(t), opts...)
+		},
+		"mock": func(configure bool) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetIDGenerator(NewSequentialGenerator(0))
+			if configure {
+				repo.SetDefaultSort(todov1.SortField_SORT_FIELD_TITLE, todov1.SortOrder_SORT_ORDER_ASC)
+			}
+			return repo
+		},
+	}
+
+	tests := []struct {
+		name      string
+		configure bool
+		req       ListTasksRequest
+		want      []string
+	}{
+		{name: "shipped default is newest first", want: []string{"banana", "apple", "cherry"}},
+		{name: "configured default applies", configure: true, want: []string{"apple", "banana", "cherry"}},
+		{name: "explicit order overrides default order", configure: true, req: ListTasksRequest{SortOrder: todov1.SortOrder_SORT_ORDER_DESC}, want: []string{"cherry", "banana", "apple"}},
+		{name: "explicit field keeps its own direction", configure: true, req: ListTasksRequest{SortBy: todov1.SortField_SORT_FIELD_CREATED_AT}, want: []string{"banana", "apple", "cherry"}},
+	}
+
+	for name, newRepo := range repos {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				repo := newRepo(tt.configure)
+				ctx := context.Background()
+				for _, title := range []string{"cherry", "apple", "banana"} {
+					if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+						t.Fatalf("Failed to create task: %v", err)
+					}
+				}
+
+				tasks, _, err := repo.List(ctx, &tt.req)
+				if err != nil {
+					t.Fatalf("Failed to list tasks: %v", err)
+				}
+				var got []string
+				for _, task := range tasks {
+					got = append(got, task.Title)
+				}
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			})
+		}
+	}
+}
+
+func TestMySQLTodoRepository_CreateRetriesIDCollision(t *testing.T) {
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	collision := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'tasks.PRIMARY'"}
+	errorHandler := middleware.NewErrorHandler(middleware.NewStructuredLogger(middleware.LevelError))
+
+	t.Run("retries with a fresh id", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithIDGenerator(NewSequentialGenerator(0)))
+
+		mock.ExpectExec("INSERT INTO tasks").
+			WithArgs("1", "New task", DefaultTenant, nil).
+			WillReturnError(collision)
+		mock.ExpectExec("INSERT INTO tasks").
+			WithArgs("2", "New task", DefaultTenant, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+			WithArgs("2").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("2", "New task", false, time.Now(), time.Now(), nil, 1024.0, nil))
+
+		task, err := repo.Create(context.Background(), &CreateTaskRequest{Title: "New task"})
+		if err != nil {
+			t.Fatalf("Expected collision to be retried, got %v", err)
+		}
+		if task.Id != "2" {
+			t.Errorf("Expected regenerated id 2, got %s", task.Id)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("gives up after repeated collisions", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithIDGenerator(NewSequentialGenerator(0)))
+		for i := 0; i < maxCreateAttempts; i++ {
+			mock.ExpectExec("INSERT INTO tasks").WillReturnError(collision)
+		}
+
+		_, err = repo.Create(context.Background(), &CreateTaskRequest{Title: "New task"})
+		if err == nil {
+			t.Fatal("Expected an error after repeated collisions")
+		}
+		if code := connect.CodeOf(errorHandler.HandleRepositoryError(err)); code != connect.CodeAlreadyExists {
+			t.Errorf("Expected AlreadyExists, got %v", code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("client-supplied id is not retried", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db)
+		mock.ExpectExec("INSERT INTO tasks").WillReturnError(collision)
+
+		_, err = repo.Create(context.Background(), &CreateTaskRequest{ID: "1", Title: "New task"})
+		if err == nil || !strings.Contains(err.Error(), "Duplicate") {
+			t.Fatalf("Expected duplicate error for client id, got %v", err)
+		}
+		if code := connect.CodeOf(errorHandler.HandleRepositoryError(err)); code != connect.CodeAlreadyExists {
+			t.Errorf("Expected AlreadyExists, got %v", code)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestMySQLTodoRepository_ArchiveCompleted(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	old := time.Now().Add(-60 * 24 * time.Hour).UTC()
+	for _, seed := range []struct {
+		id        string
+		completed bool
+		updatedAt time.Time
+	}{
+		{"old-done", true, old},
+		{"old-pending", false, old},
+		{"new-done", true, time.Now().UTC()},
+	} {
+		_, err := db.Exec("INSERT INTO tasks (id, title, completed, updated_at) VALUES (?, ?, ?, ?)",
+			seed.id, seed.id, seed.completed, seed.updatedAt)
+		if err != nil {
+			t.Fatalf("Failed to seed task: %v", err)
+		}
+	}
+
+	archived, err := repo.ArchiveCompleted(ctx, time.Now().Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to archive tasks: %v", err)
+	}
+	if archived != 1 {
+		t.Errorf("Expected 1 archived task, got %d", archived)
+	}
+
+	task, err := repo.GetByID(ctx, "old-done")
+	if err != nil {
+		t.Fatalf("Archived task should still be retrievable: %v", err)
+	}
+	if task.ArchivedAt == nil {
+		t.Error("Expected archived_at to be set")
+	}
+	if task.UpdatedAt.AsTime().After(old.Add(time.Second)) {
+		t.Errorf("Expected updated_at to be preserved, got %v", task.UpdatedAt.AsTime())
+	}
+
+	tasks, pagination, err := repo.List(ctx, &ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 2 || pagination.TotalItems != 2 {
+		t.Errorf("Expected archived task to be hidden, got %d tasks (total %d)", len(tasks), pagination.TotalItems)
+	}
+
+	tasks, _, err = repo.List(ctx, &ListTasksRequest{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Errorf("Expected 3 tasks with include_archived, got %d", len(tasks))
+	}
+
+	archived, err = repo.ArchiveCompleted(ctx, time.Now().Add(-30*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to archive tasks: %v", err)
+	}
+	if archived != 0 {
+		t.Errorf("Expected second run to archive nothing, got %d", archived)
+	}
+}
+
+// collectStream drains a StreamAll result, returning the tasks and the final error
+func collectStream(tasks <-chan *todov1.Task, errs <-chan error) ([]*todov1.Task, error) {
+	var collected []*todov1.Task
+	for task := range tasks {
+		collected = append(collected, task)
+	}
+	return collected, <-errs
+}
+
+func TestMySQLTodoRepository_StreamAll(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db).(*mysqlTodoRepository)
+	repo.streamBatchSize = 3
+	ctx := context.Background()
+
+	created := map[string]bool{}
+	for i := 0; i < 7; i++ {
+		task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Task"})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		created[task.Id] = true
+	}
+
+	tasks, err := collectStream(repo.StreamAll(ctx))
+	if err != nil {
+		t.Fatalf("Unexpected stream error: %v", err)
+	}
+	if len(tasks) != len(created) {
+		t.Fatalf("Expected %d tasks, got %d", len(created), len(tasks))
+	}
+	seen := map[string]bool{}
+	for _, task := range tasks {
+		if seen[task.Id] {
+			t.Errorf("Task %s delivered more than once", task.Id)
+		}
+		if !created[task.Id] {
+			t.Errorf("Unexpected task %s", task.Id)
+		}
+		seen[task.Id] = true
+	}
+
+	t.Run("cancellation stops the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, errs := repo.StreamAll(ctx)
+
+		<-stream
+		cancel()
+
+		rest, err := collectStream(stream, errs)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if len(rest) >= len(created)-1 {
+			t.Errorf("Expected stream to stop early, got %d more tasks", len(rest))
+		}
+	})
+}
+
+func TestMockTodoRepository_StreamAll(t *testing.T) {
+	mock := NewMockTodoRepository()
+	for _, id := range []string{"c", "a", "b"} {
+		mock.AddTask(&todov1.Task{Id: id, Title: id})
+	}
+
+	tasks, err := collectStream(mock.StreamAll(context.Background()))
+	if err != nil {
+		t.Fatalf("Unexpected stream error: %v", err)
+	}
+	if len(tasks) != 3 || tasks[0].Id != "a" || tasks[1].Id != "b" || tasks[2].Id != "c" {
+		t.Errorf("Expected tasks a, b, c exactly once, got %v", tasks)
+	}
+}
+
+func TestListStream(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		for i := 0; i < 25; i++ {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: fmt.Sprintf("Task %02d", i)}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+		}
+		filters := &ListTasksRequest{
+			PageSize:  150, // beyond List's cap of 100
+			Query:     "Task 0",
+			SortBy:    todov1.SortField_SORT_FIELD_TITLE,
+			SortOrder: todov1.SortOrder_SORT_ORDER_ASC,
+		}
+
+		var chunks [][]*todov1.Task
+		pagination, err := repo.ListStream(ctx, filters, 4, func(tasks []*todov1.Task) error {
+			chunks = append(chunks, tasks)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream tasks: %v", err)
+		}
+
+		// "Task 00" to "Task 09" in chunks of 4, 4 and 2
+		var titles []string
+		for _, chunk := range chunks {
+			for _, task := range chunk {
+				titles = append(titles, task.Title)
+			}
+		}
+		if len(chunks) != 3 || len(chunks[2]) != 2 {
+			t.Errorf("Expected chunks of 4, 4 and 2, got %d chunks", len(chunks))
+		}
+		if len(titles) != 10 || titles[0] != "Task 00" || titles[9] != "Task 09" || !sort.StringsAreSorted(titles) {
+			t.Errorf("Expected Task 00 to Task 09 in order, got %v", titles)
+		}
+		if pagination.TotalItems != 10 || pagination.TotalUnfiltered != 25 || pagination.PageSize != 150 || pagination.HasNext {
+			t.Errorf("Unexpected pagination %+v", pagination)
+		}
+
+		// The stream matches List for pages List can serve
+		listed, _, err := repo.List(ctx, &ListTasksRequest{PageSize: 10, Page: 2, SortBy: todov1.SortField_SORT_FIELD_TITLE})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		var streamed []*todov1.Task
+		if _, err := repo.ListStream(ctx, &ListTasksRequest{PageSize: 10, Page: 2, SortBy: todov1.SortField_SORT_FIELD_TITLE}, 3, func(tasks []*todov1.Task) error {
+			streamed = append(streamed, tasks...)
+			return nil
+		}); err != nil {
+			t.Fatalf("Failed to stream tasks: %v", err)
+		}
+		if len(streamed) != len(listed) {
+			t.Fatalf("Expected %d streamed tasks, got %d", len(listed), len(streamed))
+		}
+		for i := range listed {
+			if streamed[i].Id != listed[i].Id {
+				t.Errorf("Position %d: expected %s, got %s", i, listed[i].Title, streamed[i].Title)
+			}
+		}
+
+		t.Run("stops when emit fails or ctx is cancelled", func(t *testing.T) {
+			stop := errors.New("client went away")
+			calls := 0
+			_, err := repo.ListStream(ctx, &ListTasksRequest{PageSize: 25}, 5, func([]*todov1.Task) error {
+				calls++
+				return stop
+			})
+			if !errors.Is(err, stop) || calls != 1 {
+				t.Errorf("Expected to stop after the failed chunk, got %d calls and %v", calls, err)
+			}
+
+			cancelled, cancel := context.WithCancel(ctx)
+			calls = 0
+			_, err = repo.ListStream(cancelled, &ListTasksRequest{PageSize: 25}, 5, func([]*todov1.Task) error {
+				calls++
+				cancel()
+				return nil
+			})
+			if !errors.Is(err, context.Canceled) || calls != 1 {
+				t.Errorf("Expected to stop after cancellation, got %d calls and %v", calls, err)
+			}
+		})
+	})
+}
+
+func TestMySQLTodoRepository_InvalidTimestamps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMySQLTodoRepository(db)
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	farFuture := time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)
+	valid := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("task-1", "Zero dates", false, time.Time{}, farFuture, valid, 1024.0, nil))
+
+	task, err := repo.GetByID(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+
+	for name, ts := range map[string]*timestamppb.Timestamp{"created_at": task.CreatedAt, "updated_at": task.UpdatedAt} {
+		if err := ts.CheckValid(); err != nil {
+			t.Errorf("%s: expected a valid timestamp, got %v", name, err)
+		}
+		if !ts.AsTime().Equal(time.Unix(0, 0)) {
+			t.Errorf("%s: expected epoch fallback, got %v", name, ts.AsTime())
+		}
+	}
+	if !task.ArchivedAt.AsTime().Equal(valid) {
+		t.Errorf("Expected valid timestamp to be preserved, got %v", task.ArchivedAt.AsTime())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQLTodoRepository_NonUTCSessionTimestamps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewMySQLTodoRepository(db)
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	tokyo := time.FixedZone("JST", 9*60*60)
+	created := time.Date(2024, 5, 1, 21, 0, 0, 0, tokyo)
+
+	mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("task-1", "Tokyo", false, created, created, nil, 1024.0, nil))
+
+	task, err := repo.GetByID(context.Background(), "task-1")
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+
+	want := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got := task.CreatedAt.AsTime(); got != want {
+		t.Errorf("Expected created_at %v, got %v", want, got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMySQLTodoRepository_ApproximateCount(t *testing.T) {
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+
+	t.Run("large unfiltered table uses estimate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1000000))
+
+		mock.ExpectQuery("SELECT TABLE_ROWS FROM information_schema.TABLES").
+			WillReturnRows(sqlmock.NewRows([]string{"TABLE_ROWS"}).AddRow(5000000))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, pagination, err := repo.List(context.Background(), &ListTasksRequest{PageSize: 10, IncludeArchived: true})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if !pagination.Approximate {
+			t.Error("Expected pagination to be flagged approximate")
+		}
+		if pagination.TotalItems != 5000000 {
+			t.Errorf("Expected estimated total 5000000, got %d", pagination.TotalItems)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("small table falls back to exact count", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1000000))
+
+		mock.ExpectQuery("SELECT TABLE_ROWS FROM information_schema.TABLES").
+			WillReturnRows(sqlmock.NewRows([]string{"TABLE_ROWS"}).AddRow(42))
+		mock.ExpectQuery("SELECT COUNT").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(40))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, pagination, err := repo.List(context.Background(), &ListTasksRequest{IncludeArchived: true})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if pagination.Approximate || pagination.TotalItems != 40 {
+			t.Errorf("Expected exact total 40, got %d (approximate=%v)", pagination.TotalItems, pagination.Approximate)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("filtered query is always exact", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1))
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE archived_at IS NULL AND title LIKE").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE archived_at IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(10))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, pagination, err := repo.List(context.Background(), &ListTasksRequest{Query: "milk"})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if pagination.Approximate || pagination.TotalItems != 3 {
+			t.Errorf("Expected exact filtered total 3, got %d (approximate=%v)", pagination.TotalItems, pagination.Approximate)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("default request subtracts archived tasks from the estimate", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1000000))
+
+		mock.ExpectQuery("SELECT TABLE_ROWS FROM information_schema.TABLES").
+			WillReturnRows(sqlmock.NewRows([]string{"TABLE_ROWS"}).AddRow(5000000))
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE archived_at IS NOT NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(200000))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, pagination, err := repo.List(context.Background(), &ListTasksRequest{})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if !pagination.Approximate || pagination.TotalItems != 4800000 {
+			t.Errorf("Expected approximate total 4800000, got %d (approximate=%v)", pagination.TotalItems, pagination.Approximate)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("tenant-scoped query is always exact", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		repo := NewMySQLTodoRepository(db, WithApproximateCountThreshold(1))
+
+		mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM tasks WHERE archived_at IS NULL AND tenant_id = \\?").
+			WithArgs("acme").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(7))
+		mock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, pagination, err := repo.List(middleware.WithTenant(context.Background(), "acme"), &ListTasksRequest{})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if pagination.Approximate || pagination.TotalItems != 7 {
+			t.Errorf("Expected exact total 7, got %d (approximate=%v)", pagination.TotalItems, pagination.Approximate)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestMySQLTodoRepository_ReadReplica(t *testing.T) {
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	newDBs := func(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *sql.DB, sqlmock.Sqlmock) {
+		primary, primaryMock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create primary sqlmock: %v", err)
+		}
+		replica, replicaMock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create replica sqlmock: %v", err)
+		}
+		t.Cleanup(func() {
+			primary.Close()
+			replica.Close()
+		})
+		return primary, primaryMock, replica, replicaMock
+	}
+
+	t.Run("reads go to the replica", func(t *testing.T) {
+		primary, primaryMock, replica, replicaMock := newDBs(t)
+		repo := NewMySQLTodoRepository(primary, WithReadReplica(replica))
+
+		replicaMock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+			WithArgs("task-1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("task-1", "Replica task", false, now, now, nil, 1024.0, nil))
+		replicaMock.ExpectQuery("SELECT COUNT").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(0))
+		replicaMock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		if _, err := repo.GetByID(context.Background(), "task-1"); err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+		if _, _, err := repo.List(context.Background(), &ListTasksRequest{}); err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+
+		if err := replicaMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		if err := primaryMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("writes and their read-back use the primary", func(t *testing.T) {
+		primary, primaryMock, replica, replicaMock := newDBs(t)
+		repo := NewMySQLTodoRepository(primary, WithReadReplica(replica), WithIDGenerator(NewSequentialGenerator(0)))
+
+		primaryMock.ExpectExec("INSERT INTO tasks").
+			WithArgs("1", "New task", DefaultTenant, nil).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("1", "New task", false, now, now, nil, 1024.0, nil))
+
+		if _, err := repo.Create(context.Background(), &CreateTaskRequest{Title: "New task"}); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+
+		if err := primaryMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		if err := replicaMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("reads stick to the primary shortly after a write", func(t *testing.T) {
+		primary, primaryMock, replica, replicaMock := newDBs(t)
+		repo := NewMySQLTodoRepository(primary, WithReadReplica(replica), WithPrimaryReadWindow(time.Minute))
+
+		primaryMock.ExpectExec("DELETE FROM tasks").
+			WithArgs("task-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		primaryMock.ExpectQuery("SELECT id, title, completed, created_at, updated_at, archived_at, position, due_date FROM tasks WHERE id").
+			WithArgs("task-2").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("task-2", "Fresh read", false, now, now, nil, 1024.0, nil))
+
+		if err := repo.Delete(context.Background(), "task-1"); err != nil {
+			t.Fatalf("Failed to delete task: %v", err)
+		}
+		if _, err := repo.GetByID(context.Background(), "task-2"); err != nil {
+			t.Fatalf("Failed to get task: %v", err)
+		}
+
+		if err := primaryMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		if err := replicaMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("counts for limits use the primary", func(t *testing.T) {
+		primary, primaryMock, replica, replicaMock := newDBs(t)
+		repo := NewMySQLTodoRepository(primary, WithReadReplica(replica))
+
+		primaryMock.ExpectQuery("SELECT COUNT").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(7))
+
+		if count, err := repo.Count(context.Background()); err != nil || count != 7 {
+			t.Fatalf("Expected 7 tasks from the primary, got %d (%v)", count, err)
+		}
+
+		if err := primaryMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		if err := replicaMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("an unreachable replica does not fail the health check", func(t *testing.T) {
+		primary, primaryMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		if err != nil {
+			t.Fatalf("Failed to create primary sqlmock: %v", err)
+		}
+		replica, replicaMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+		if err != nil {
+			t.Fatalf("Failed to create replica sqlmock: %v", err)
+		}
+		t.Cleanup(func() {
+			primary.Close()
+			replica.Close()
+		})
+		repo := NewMySQLTodoRepository(primary, WithReadReplica(replica))
+
+		primaryMock.ExpectPing()
+		replicaMock.ExpectPing().WillReturnError(errors.New("connection refused"))
+
+		if err := repo.HealthCheck(context.Background()); err != nil {
+			t.Errorf("Expected the primary to be healthy, got %v", err)
+		}
+		if err := repo.ReplicaHealthCheck(context.Background()); err == nil || errors.Is(err, ErrNoReplica) {
+			t.Errorf("Expected the replica check to fail, got %v", err)
+		}
+		if err := NewMySQLTodoRepository(primary).ReplicaHealthCheck(context.Background()); !errors.Is(err, ErrNoReplica) {
+			t.Errorf("Expected ErrNoReplica without a replica, got %v", err)
+		}
+
+		if err := primaryMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		if err := replicaMock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestMySQLTodoRepository_Duplicate(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMySQLTodoRepository(db)
+	ctx := context.Background()
+
+	source, err := repo.Create(ctx, &CreateTaskRequest{Title: "Original"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: source.Id, Completed: true}); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+
+	copied, err := repo.Duplicate(ctx, source.Id, " (copy)")
+	if err != nil {
+		t.Fatalf("Failed to duplicate task: %v", err)
+	}
+	if copied.Id == source.Id {
+		t.Error("Expected duplicate to get a fresh ID")
+	}
+	if copied.Title != "Original (copy)" {
+		t.Errorf("Expected copied title, got %q", copied.Title)
+	}
+	if copied.Completed {
+		t.Error("Expected duplicate to be pending")
+	}
+
+	if _, err := repo.Duplicate(ctx, "missing-id", ""); err == nil {
+		t.Error("Expected duplicating a missing task to fail")
+	}
+}
+
+func TestMySQLTodoRepository_DuplicateLocksSource(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	mock.ExpectBegin()
+	// The source stays locked until the copy commits
+	mock.ExpectQuery(`SELECT .* FROM tasks\s+WHERE id = \? FOR UPDATE`).
+		WithArgs("task-1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("task-1", "Original", true, now, now, nil, 1024.0, nil))
+	mock.ExpectExec("INSERT INTO tasks").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT .* FROM tasks").
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("1", "Original (copy)", false, now, now, nil, 2048.0, nil))
+	mock.ExpectCommit()
+
+	repo := NewMySQLTodoRepository(db, WithIDGenerator(NewSequentialGenerator(0)))
+	if _, err := repo.Duplicate(context.Background(), "task-1", " (copy)"); err != nil {
+		t.Fatalf("Failed to duplicate task: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTodoRepository_ListDueDateFilter(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		due := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+		scheduled, err := repo.Create(ctx, &CreateTaskRequest{Title: "Scheduled", DueDate: &due})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		if scheduled.DueDate == nil || !scheduled.DueDate.AsTime().Equal(due) {
+			t.Fatalf("Expected due date %v, got %v", due, scheduled.DueDate)
+		}
+		for _, title := range []string{"Unscheduled 1", "Unscheduled 2"} {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: title}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+		}
+
+		tests := []struct {
+			filter todov1.DueDateFilter
+			want   int
+		}{
+			{filter: todov1.DueDateFilter_DUE_DATE_FILTER_UNSPECIFIED, want: 3},
+			{filter: todov1.DueDateFilter_DUE_DATE_FILTER_ANY, want: 3},
+			{filter: todov1.DueDateFilter_DUE_DATE_FILTER_SCHEDULED, want: 1},
+			{filter: todov1.DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED, want: 2},
+		}
+		for _, tt := range tests {
+			tasks, pagination, err := repo.List(ctx, &ListTasksRequest{DueDate: tt.filter})
+			if err != nil {
+				t.Fatalf("%s: failed to list tasks: %v", tt.filter, err)
+			}
+			if len(tasks) != tt.want || pagination.TotalItems != uint32(tt.want) {
+				t.Errorf("%s: expected %d tasks, got %d (total %d)", tt.filter, tt.want, len(tasks), pagination.TotalItems)
+			}
+			if pagination.TotalUnfiltered != 3 {
+				t.Errorf("%s: expected 3 unfiltered tasks, got %d", tt.filter, pagination.TotalUnfiltered)
+			}
+		}
+
+		// Clearing the due date moves the task to the unscheduled set
+		if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: scheduled.Id, ClearDueDate: true}); err != nil {
+			t.Fatalf("Failed to clear due date: %v", err)
+		}
+		_, pagination, err := repo.List(ctx, &ListTasksRequest{DueDate: todov1.DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED})
+		if err != nil {
+			t.Fatalf("Failed to list tasks: %v", err)
+		}
+		if pagination.TotalItems != 3 {
+			t.Errorf("Expected 3 unscheduled tasks after clearing, got %d", pagination.TotalItems)
+		}
+	})
+}
+
+func TestTodoRepository_UpdateReportsChanged(t *testing.T) {
+	due := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	later := due.Add(24 * time.Hour)
+
+	tests := []struct {
+		name        string
+		req         UpdateTaskRequest
+		wantChanged bool
+	}{
+		{name: "same values", req: UpdateTaskRequest{Title: "Original", Completed: false}, wantChanged: false},
+		{name: "empty title keeps title", req: UpdateTaskRequest{Completed: false}, wantChanged: false},
+		{name: "same due date", req: UpdateTaskRequest{Completed: false, DueDate: &due}, wantChanged: false},
+		{name: "new title", req: UpdateTaskRequest{Title: "Renamed"}, wantChanged: true},
+		{name: "completed", req: UpdateTaskRequest{Completed: true}, wantChanged: true},
+		{name: "new due date", req: UpdateTaskRequest{DueDate: &later}, wantChanged: true},
+		{name: "cleared due date", req: UpdateTaskRequest{ClearDueDate: true}, wantChanged: true},
+		{name: "masked unchanged field", req: UpdateTaskRequest{Title: "Renamed", Completed: false, Mask: &UpdateMask{Completed: true}}, wantChanged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+				ctx := context.Background()
+				task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Original", DueDate: &due})
+				if err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+
+				req := tt.req
+				req.ID = task.Id
+				_, previous, err := repo.Update(ctx, &req)
+				if err != nil {
+					t.Fatalf("Failed to update task: %v", err)
+				}
+				if changed := req.Changes(previous); changed != tt.wantChanged {
+					t.Errorf("Expected changed %v, got %v", tt.wantChanged, changed)
+				}
+
+				// Repeating an update that changed the task is a no-op
+				if _, previous, err := repo.Update(ctx, &req); err != nil || req.Changes(previous) {
+					t.Errorf("Expected a repeated update to report no change, got %v (%v)", previous, err)
+				}
+			})
+		})
+	}
+}
+
+func TestTodoRepository_UpdateSkipNoOp(t *testing.T) {
+	created := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	repos := map[string]func(clock Clock) TodoRepository{
+		"mysql": func(clock Clock) TodoRepository { return NewMySQLTodoRepository(setupTestDB(t), WithClock(clock)) },
+		"mock": func(clock Clock) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetClock(clock)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := &FixedClock{T: created}
+			repo := newRepo(clock)
+
+			task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Original"})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			clock.T = created.Add(time.Hour)
+
+			req := &UpdateTaskRequest{ID: task.Id, Title: "Original", SkipNoOp: true}
+			unchanged, previous, err := repo.Update(ctx, req)
+			if err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if req.Changes(previous) || !unchanged.UpdatedAt.AsTime().Equal(created) {
+				t.Errorf("Expected a skipped no-op to keep updated_at %v, got %v", created, unchanged.UpdatedAt.AsTime())
+			}
+
+			req = &UpdateTaskRequest{ID: task.Id, Title: "Original", Completed: true, SkipNoOp: true}
+			updated, previous, err := repo.Update(ctx, req)
+			if err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if !req.Changes(previous) || !updated.Completed || !updated.UpdatedAt.AsTime().Equal(clock.T) {
+				t.Errorf("Expected a real change to be written at %v, got %v", clock.T, updated)
+			}
+			if previous.Completed || !previous.UpdatedAt.AsTime().Equal(created) {
+				t.Errorf("Expected the task as it was before the write, got %v", previous)
+			}
+		})
+	}
+}
+
+func TestTodoRepository_UpdateMask(t *testing.T) {
+	due := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		req           UpdateTaskRequest
+		wantTitle     string
+		wantCompleted bool
+		wantDue       bool
+	}{
+		{
+			name:      "completed only",
+			req:       UpdateTaskRequest{Mask: &UpdateMask{Completed: true}},
+			wantTitle: "Original", wantCompleted: false, wantDue: true,
+		},
+		{
+			name:      "title only",
+			req:       UpdateTaskRequest{Title: "Renamed", Mask: &UpdateMask{Title: true}},
+			wantTitle: "Renamed", wantCompleted: true, wantDue: true,
+		},
+		{
+			name:      "title and completed",
+			req:       UpdateTaskRequest{Title: "Renamed", Mask: &UpdateMask{Title: true, Completed: true}},
+			wantTitle: "Renamed", wantCompleted: false, wantDue: true,
+		},
+		{
+			name:      "due date only clears when unset",
+			req:       UpdateTaskRequest{Title: "Ignored", Mask: &UpdateMask{DueDate: true}},
+			wantTitle: "Original", wantCompleted: true, wantDue: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+				ctx := context.Background()
+				task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Original", DueDate: &due})
+				if err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+				if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: task.Id, Completed: true}); err != nil {
+					t.Fatalf("Failed to complete task: %v", err)
+				}
+
+				req := tt.req
+				req.ID = task.Id
+				updated, _, err := repo.Update(ctx, &req)
+				if err != nil {
+					t.Fatalf("Failed to update task: %v", err)
+				}
+				if updated.Title != tt.wantTitle || updated.Completed != tt.wantCompleted || (updated.DueDate != nil) != tt.wantDue {
+					t.Errorf("Expected title %q, completed %v, due date %v; got %q, %v, %v",
+						tt.wantTitle, tt.wantCompleted, tt.wantDue, updated.Title, updated.Completed, updated.DueDate != nil)
+				}
+			})
+		})
+	}
+}
+
+func TestMySQLTodoRepository_UpdateNeverBlanksTitle(t *testing.T) {
+	const id = "550e8400-e29b-41d4-a716-446655440000"
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	t.Run("completed only leaves title out of the SQL", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		// Without a mask an empty title means "keep it"; the mask says so explicitly
+		for _, req := range []*UpdateTaskRequest{
+			{ID: id, Completed: true},
+			{ID: id, Completed: true, Mask: &UpdateMask{Completed: true}},
+		} {
+			mock.ExpectBegin()
+			// The read locks the row it is about to write
+			mock.ExpectQuery(`SELECT .* FROM tasks\s+WHERE id = \? FOR UPDATE`).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "Keep me", false, now, now, nil, 1024.0, nil))
+			mock.ExpectExec(`UPDATE tasks\s+SET completed = \?\s+WHERE id = \?`).
+				WithArgs(true, id).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("SELECT .* FROM tasks").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "Keep me", true, now, now, nil, 1024.0, nil))
+			mock.ExpectCommit()
+
+			task, _, err := NewMySQLTodoRepository(db).Update(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if task.Title != "Keep me" {
+				t.Errorf("Expected title to be untouched, got %q", task.Title)
+			}
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("masked empty title is rejected before any SQL", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		_, _, err = NewMySQLTodoRepository(db).Update(context.Background(), &UpdateTaskRequest{
+			ID: id, Completed: true, Mask: &UpdateMask{Title: true, Completed: true},
+		})
+		if !errors.Is(err, errEmptyTitleUpdate) {
+			t.Errorf("Expected errEmptyTitleUpdate, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestTodoRepository_ClaimDueReminders(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+		now := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+		past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+		due, _ := repo.Create(ctx, &CreateTaskRequest{Title: "Due", DueDate: &past})
+		repo.Create(ctx, &CreateTaskRequest{Title: "Later", DueDate: &future})
+		repo.Create(ctx, &CreateTaskRequest{Title: "Unscheduled"})
+		done, _ := repo.Create(ctx, &CreateTaskRequest{Title: "Done", DueDate: &past})
+		repo.Update(ctx, &UpdateTaskRequest{ID: done.Id, Completed: true})
+
+		claimed, err := repo.ClaimDueReminders(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("Failed to claim reminders: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].Id != due.Id {
+			t.Fatalf("Expected only the due task, got %v", claimed)
+		}
+
+		claimed, err = repo.ClaimDueReminders(ctx, now, 10)
+		if err != nil {
+			t.Fatalf("Failed to claim reminders: %v", err)
+		}
+		if len(claimed) != 0 {
+			t.Errorf("Expected the reminder to be claimed once, got %v", claimed)
+		}
+	})
+}
+
+func TestDuplicateTitle_FitsColumn(t *testing.T) {
+	long := strings.Repeat("é", 127) + "x" // 255 bytes
+	title := duplicateTitle(long, " (copy)")
+
+	if len(title) > validator.MaxTitleLength {
+		t.Errorf("Expected title to fit in %d bytes, got %d", validator.MaxTitleLength, len(title))
+	}
+	if !utf8.ValidString(title) {
+		t.Error("Expected truncation to keep valid UTF-8")
+	}
+	if !strings.HasSuffix(title, " (copy)") {
+		t.Errorf("Expected suffix to be preserved, got %q", title)
+	}
+}
+
+// TestRepositoryInterface ensures our repository implements the interface correctly
+func TestRepositoryInterface(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// This should compile if the interface is implemented correctly
+	var repo TodoRepository = NewMySQLTodoRepository(db)
+	
+	if repo == nil {
+		t.Fatal("Expected repository to be created")
+	}
+
+	// Test with custom logger
+	logger := middleware.NewStructuredLogger(middleware.LevelDebug)
+	var repoWithLogger TodoRepository = NewMySQLTodoRepositoryWithLogger(db, logger)
+	
+	if repoWithLogger == nil {
+		t.Fatal("Expected repository with logger to be created")
+	}
+}
+
+// forEachRepository runs a test against both the SQLite-backed and mock repositories
+func forEachRepository(t *testing.T, run func(t *testing.T, repo TodoRepository)) {
+	t.Run("mysql", func(t *testing.T) {
+		run(t, NewMySQLTodoRepository(setupTestDB(t)))
+	})
+	t.Run("mock", func(t *testing.T) {
+		run(t, NewMockTodoRepository())
+	})
+}
+
+// setupTestDB opens an in-memory SQLite database with the tasks,
+// list_versions and deleted_tasks tables created
+func setupTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	// Each pooled connection to :memory: gets its own database, so pin to one
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE tasks (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			completed BOOLEAN DEFAULT FALSE,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			archived_at DATETIME,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			position REAL NOT NULL DEFAULT 0,
+			due_date DATETIME,
+			reminded_at DATETIME,
+			title_lower TEXT GENERATED ALWAYS AS (lower(title)) STORED
+		);
+		CREATE TABLE list_versions (
+			tenant_id TEXT PRIMARY KEY,
+			version INTEGER NOT NULL DEFAULT 0
+		);
+		CREATE TABLE deleted_tasks (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			completed BOOLEAN DEFAULT FALSE,
+			created_at DATETIME,
+			updated_at DATETIME,
+			archived_at DATETIME,
+			tenant_id TEXT NOT NULL DEFAULT 'default',
+			position REAL NOT NULL DEFAULT 0,
+			due_date DATETIME,
+			reminded_at DATETIME,
+			deleted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	return db
+}
+
+// Helper function to extract source from context for testing
+func getSourceFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if source, ok := ctx.Value("source").(string); ok {
+		return source
+	}
+	return ""
+}
+func TestMockTodoRepository_ListOrdering(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) *timestamppb.Timestamp {
+		return timestamppb.New(base.Add(time.Duration(minutes) * time.Minute))
+	}
+
+	mock := NewMockTodoRepository()
+	mock.AddTask(&todov1.Task{Id: "a", Title: "Charlie", CreatedAt: at(1), UpdatedAt: at(30)})
+	mock.AddTask(&todov1.Task{Id: "b", Title: "alpha", CreatedAt: at(2), UpdatedAt: at(10)})
+	mock.AddTask(&todov1.Task{Id: "c", Title: "Bravo", CreatedAt: at(3), UpdatedAt: at(20)})
+	// Same created_at as "a" to exercise the id tie-breaker
+	mock.AddTask(&todov1.Task{Id: "d", Title: "Delta", CreatedAt: at(1), UpdatedAt: at(40)})
+
+	tests := []struct {
+		name      string
+		sortBy    todov1.SortField
+		sortOrder todov1.SortOrder
+		expected  []string
+	}{
+		{"default is created_at descending", todov1.SortField_SORT_FIELD_UNSPECIFIED, todov1.SortOrder_SORT_ORDER_UNSPECIFIED, []string{"c", "b", "d", "a"}},
+		{"created_at ascending", todov1.SortField_SORT_FIELD_CREATED_AT, todov1.SortOrder_SORT_ORDER_ASC, []string{"a", "d", "b", "c"}},
+		{"updated_at descending", todov1.SortField_SORT_FIELD_UPDATED_AT, todov1.SortOrder_SORT_ORDER_DESC, []string{"d", "a", "c", "b"}},
+		{"updated_at ascending", todov1.SortField_SORT_FIELD_UPDATED_AT, todov1.SortOrder_SORT_ORDER_ASC, []string{"b", "c", "a", "d"}},
+		{"title ascending", todov1.SortField_SORT_FIELD_TITLE, todov1.SortOrder_SORT_ORDER_ASC, []string{"b", "c", "a", "d"}},
+		{"title descending", todov1.SortField_SORT_FIELD_TITLE, todov1.SortOrder_SORT_ORDER_DESC, []string{"d", "a", "c", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch map iteration order leaking through
+			for i := 0; i < 5; i++ {
+				tasks, _, err := mock.List(context.Background(), &ListTasksRequest{SortBy: tt.sortBy, SortOrder: tt.sortOrder})
+				if err != nil {
+					t.Fatalf("Failed to list tasks: %v", err)
+				}
+				ids := make([]string, len(tasks))
+				for j, task := range tasks {
+					ids[j] = task.Id
+				}
+				if strings.Join(ids, ",") != strings.Join(tt.expected, ",") {
+					t.Fatalf("Expected order %v, got %v", tt.expected, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestMySQLTodoRepository_WithClock(t *testing.T) {
+	db := setupTestDB(t)
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := &FixedClock{T: created}
+	repo := NewMySQLTodoRepository(db, WithClock(clock))
+	ctx := context.Background()
+
+	task, err := repo.Create(ctx, &CreateTaskRequest{Title: "Stamped"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if !task.CreatedAt.AsTime().Equal(created) || !task.UpdatedAt.AsTime().Equal(created) {
+		t.Errorf("Expected both timestamps to be %v, got %v and %v", created, task.CreatedAt.AsTime(), task.UpdatedAt.AsTime())
+	}
+
+	updated := created.Add(time.Hour)
+	clock.T = updated
+	task, _, err = repo.Update(ctx, &UpdateTaskRequest{ID: task.Id, Completed: true})
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if !task.CreatedAt.AsTime().Equal(created) {
+		t.Errorf("Expected created_at to stay %v, got %v", created, task.CreatedAt.AsTime())
+	}
+	if !task.UpdatedAt.AsTime().Equal(updated) {
+		t.Errorf("Expected updated_at %v, got %v", updated, task.UpdatedAt.AsTime())
+	}
+
+	archived := updated.Add(time.Hour)
+	clock.T = archived
+	if _, err := repo.ArchiveCompleted(ctx, archived); err != nil {
+		t.Fatalf("Failed to archive: %v", err)
+	}
+	task, err = repo.GetByID(ctx, task.Id)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if task.ArchivedAt == nil || !task.ArchivedAt.AsTime().Equal(archived) {
+		t.Errorf("Expected archived_at %v, got %v", archived, task.ArchivedAt)
+	}
+}
+
+func TestSQLiteTodoRepository_UpdatedAtAdvances(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	ctx := context.Background()
+
+	first, err := repo.Create(ctx, &CreateTaskRequest{Title: "First"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Second"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	last := first.UpdatedAt.AsTime()
+	expectAdvanced := func(step string, task *todov1.Task) {
+		t.Helper()
+		if !task.UpdatedAt.AsTime().After(last) {
+			t.Errorf("Expected %s to advance updated_at past %v, got %v", step, last, task.UpdatedAt.AsTime())
+		}
+		if !task.CreatedAt.AsTime().Equal(first.CreatedAt.AsTime()) {
+			t.Errorf("Expected %s to keep created_at %v, got %v", step, first.CreatedAt.AsTime(), task.CreatedAt.AsTime())
+		}
+		last = task.UpdatedAt.AsTime()
+	}
+
+	updated, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: first.Id, Completed: true})
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	expectAdvanced("an update", updated)
+
+	renamed, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: first.Id, Title: "Renamed", Mask: &UpdateMask{Title: true}})
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	expectAdvanced("a masked update", renamed)
+
+	moved, err := repo.Reorder(ctx, first.Id, "")
+	if err != nil {
+		t.Fatalf("Failed to reorder task: %v", err)
+	}
+	expectAdvanced("a reorder", moved)
+}
+
+func TestMySQLTodoRepository_AppTimestamps(t *testing.T) {
+	const id = "550e8400-e29b-41d4-a716-446655440000"
+	columns := []string{"id", "title", "completed", "created_at", "updated_at", "archived_at", "position", "due_date"}
+	now := time.Now()
+
+	for name, tt := range map[string]struct {
+		opts []Option
+		set  string
+	}{
+		"database managed": {set: `SET completed = \?\s+WHERE`},
+		"app managed":      {opts: []Option{WithAppTimestamps()}, set: `SET completed = \?, updated_at = \?\s+WHERE`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock: %v", err)
+			}
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT .* FROM tasks").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "Task", false, now, now, nil, 1024.0, nil))
+			mock.ExpectExec(`UPDATE tasks\s+` + tt.set).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery("SELECT .* FROM tasks").
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(id, "Task", true, now, now, nil, 1024.0, nil))
+			mock.ExpectCommit()
+
+			if _, _, err := NewMySQLTodoRepository(db, tt.opts...).Update(context.Background(), &UpdateTaskRequest{ID: id, Completed: true}); err != nil {
+				t.Fatalf("Failed to update task: %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
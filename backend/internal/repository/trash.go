@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+// WithSoftDelete has Delete and DeleteMany move tasks into the deleted_tasks
+// table, stamped with deleted_at, instead of discarding them. The move happens
+// in the delete's transaction, so every query on tasks keeps seeing only live
+// tasks. The table must exist; see db.InitDB.
+func WithSoftDelete() Option {
+	return func(r *mysqlTodoRepository) {
+		r.softDelete = true
+	}
+}
+
+// trashColumns are the tasks columns copied into deleted_tasks
+const trashColumns = "id, title, completed, created_at, updated_at, archived_at, tenant_id, position, due_date, reminded_at"
+
+// deleteTasks deletes the tasks matching where, moving them to deleted_tasks
+// first when soft delete is on, and advances the list version
+func (r *mysqlTodoRepository) deleteTasks(ctx context.Context, where string, args ...interface{}) (sql.Result, error) {
+	if !r.softDelete {
+		return r.execMutation(ctx, "DELETE FROM tasks WHERE "+where, args...)
+	}
+
+	var result sql.Result
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if result, err = r.trashAndDelete(ctx, tx, where, args...); err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		return r.bumpListVersion(ctx, tx, rowsAffected)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// trashAndDelete deletes the tasks matching where within tx, first copying
+// them to deleted_tasks when soft delete is on. A task whose ID is already in
+// the trash, because a caller reused the ID of a deleted task, replaces the
+// older copy.
+func (r *mysqlTodoRepository) trashAndDelete(ctx context.Context, tx *sql.Tx, where string, args ...interface{}) (sql.Result, error) {
+	if r.softDelete {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM deleted_tasks WHERE id IN (SELECT id FROM tasks WHERE "+where+")", args...); err != nil {
+			return nil, fmt.Errorf("failed to clear trashed copies: %w", err)
+		}
+
+		deletedAt, deletedAtArgs := "CURRENT_TIMESTAMP", []interface{}{}
+		if r.clock != nil {
+			deletedAt, deletedAtArgs = "?", []interface{}{r.clock.Now()}
+		}
+		insert := "INSERT INTO deleted_tasks (" + trashColumns + ", deleted_at) SELECT " + trashColumns + ", " + deletedAt + " FROM tasks WHERE " + where
+		if _, err := tx.ExecContext(ctx, insert, append(deletedAtArgs, args...)...); err != nil {
+			return nil, fmt.Errorf("failed to move tasks to the trash: %w", err)
+		}
+	}
+	return tx.ExecContext(ctx, "DELETE FROM tasks WHERE "+where, args...)
+}
+
+// ErrSoftDeleteDisabled is returned by PurgeDeleted when the repository does
+// not keep deleted tasks
+var ErrSoftDeleteDisabled = errors.New("soft delete is not enabled")
+
+// PurgeDeleted permanently removes trashed tasks deleted before the cutoff and
+// returns how many were removed. It works through them oldest first in
+// batches of batchSize, each its own statement, so a large backlog never
+// holds locks on deleted_tasks for long. Without a tenant in ctx every
+// tenant's trash is purged.
+func (r *mysqlTodoRepository) PurgeDeleted(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	if !r.softDelete {
+		return 0, ErrSoftDeleteDisabled
+	}
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid purge batch size: %d", batchSize)
+	}
+	start := time.Now()
+	ctx = middleware.WithSource(ctx, "repository.PurgeDeleted")
+
+	where, args := scopedWhere(ctx, "deleted_at < ?", before)
+	selectQuery := "SELECT id FROM deleted_tasks WHERE " + where + " ORDER BY deleted_at LIMIT ?"
+	selectArgs := append(append([]interface{}{}, args...), batchSize)
+
+	var purged int64
+	for {
+		ids, err := r.trashedIDs(ctx, selectQuery, selectArgs...)
+		if err != nil {
+			return purged, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		// Re-check the cutoff so a task trashed again since the SELECT survives
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+		deleteArgs := make([]interface{}, 0, len(ids)+1)
+		for _, id := range ids {
+			deleteArgs = append(deleteArgs, id)
+		}
+		deleteArgs = append(deleteArgs, before)
+		result, err := r.primary(ctx).ExecContext(ctx, "DELETE FROM deleted_tasks WHERE id IN ("+placeholders+") AND deleted_at < ?", deleteArgs...)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge deleted tasks: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return purged, fmt.Errorf("failed to check rows affected: %w", err)
+		}
+		purged += rowsAffected
+
+		if len(ids) < batchSize {
+			break
+		}
+	}
+	r.logger.LogDatabaseOperation(ctx, "DELETE deleted_tasks purge", time.Since(start), true, purged)
+
+	return purged, nil
+}
+
+// trashedIDs returns the IDs selected by query from deleted_tasks
+func (r *mysqlTodoRepository) trashedIDs(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.primary(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deleted tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted task ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read deleted tasks: %w", err)
+	}
+	return ids, nil
+}
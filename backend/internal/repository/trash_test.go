@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestPurgeDeleted(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	// Each constructor returns a repository and a function that deletes a
+	// task as if at the given time
+	repos := map[string]func() (TodoRepository, func(at time.Time, id string) error){
+		"mysql": func() (TodoRepository, func(time.Time, string) error) {
+			db := setupTestDB(t)
+			deleteAt := func(at time.Time, id string) error {
+				return NewSQLiteTodoRepository(db, WithSoftDelete(), WithClock(FixedClock{T: at})).Delete(context.Background(), id)
+			}
+			return NewSQLiteTodoRepository(db, WithSoftDelete()), deleteAt
+		},
+		"mock": func() (TodoRepository, func(time.Time, string) error) {
+			mock := NewMockTodoRepository()
+			mock.SetSoftDelete(true)
+			deleteAt := func(at time.Time, id string) error {
+				mock.SetClock(FixedClock{T: at})
+				return mock.Delete(context.Background(), id)
+			}
+			return mock, deleteAt
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, deleteAt := newRepo()
+
+			deletions := map[string]time.Time{
+				"old-1":  now.Add(-40 * 24 * time.Hour),
+				"old-2":  now.Add(-35 * 24 * time.Hour),
+				"old-3":  now.Add(-31 * 24 * time.Hour),
+				"recent": now.Add(-29 * 24 * time.Hour),
+			}
+			for id, at := range deletions {
+				if _, err := repo.Create(ctx, &CreateTaskRequest{ID: id, Title: id}); err != nil {
+					t.Fatalf("Failed to create task: %v", err)
+				}
+				if err := deleteAt(at, id); err != nil {
+					t.Fatalf("Failed to delete task: %v", err)
+				}
+			}
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "live"}); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+
+			// A batch size of 2 takes two rounds to clear the three old rows
+			purged, err := repo.PurgeDeleted(ctx, now.Add(-30*24*time.Hour), 2)
+			if err != nil {
+				t.Fatalf("Failed to purge: %v", err)
+			}
+			if purged != 3 {
+				t.Errorf("Expected 3 purged, got %d", purged)
+			}
+
+			stats, err := repo.CountByState(ctx)
+			if err != nil {
+				t.Fatalf("Failed to count tasks: %v", err)
+			}
+			if stats.Deleted != 1 || stats.Active != 1 {
+				t.Errorf("Expected the recent deletion and the live task to remain, got %+v", *stats)
+			}
+
+			purged, err = repo.PurgeDeleted(ctx, now.Add(-30*24*time.Hour), 2)
+			if err != nil || purged != 0 {
+				t.Errorf("Expected nothing left to purge, got %d (%v)", purged, err)
+			}
+		})
+	}
+}
+
+func TestPurgeDeleted_TenantScoped(t *testing.T) {
+	db := setupTestDB(t)
+	deletedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewSQLiteTodoRepository(db, WithSoftDelete(), WithClock(FixedClock{T: deletedAt}))
+
+	for _, tenant := range []string{"acme", "globex"} {
+		ctx := middleware.WithTenant(context.Background(), tenant)
+		task, err := repo.Create(ctx, &CreateTaskRequest{Title: tenant})
+		if err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		if err := repo.Delete(ctx, task.Id); err != nil {
+			t.Fatalf("Failed to delete task: %v", err)
+		}
+	}
+
+	purged, err := repo.PurgeDeleted(middleware.WithTenant(context.Background(), "acme"), deletedAt.Add(time.Hour), 10)
+	if err != nil || purged != 1 {
+		t.Fatalf("Expected to purge only acme's task, got %d (%v)", purged, err)
+	}
+	var remaining string
+	if err := db.QueryRow("SELECT tenant_id FROM deleted_tasks").Scan(&remaining); err != nil || remaining != "globex" {
+		t.Errorf("Expected globex's task to remain, got %q (%v)", remaining, err)
+	}
+}
+
+func TestPurgeDeleted_SoftDeleteDisabled(t *testing.T) {
+	repo := NewSQLiteTodoRepository(setupTestDB(t))
+	if _, err := repo.PurgeDeleted(context.Background(), time.Now(), 10); !errors.Is(err, ErrSoftDeleteDisabled) {
+		t.Errorf("Expected ErrSoftDeleteDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// txKey marks a context that carries a transaction opened by WithTx on repo
+type txKey struct {
+	repo *mysqlTodoRepository
+}
+
+// WithTx runs fn in a transaction on the primary. Repository calls made with
+// the context passed to fn join that transaction, reads included, so they see
+// each other's writes and commit or roll back together: the transaction
+// commits when fn returns nil and is rolled back when it returns an error or
+// panics. Calls must not be made concurrently within one transaction.
+func (r *mysqlTodoRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, txKey{r}, tx))
+	})
+}
+
+// txFrom returns the transaction ctx carries from WithTx, or nil
+func (r *mysqlTodoRepository) txFrom(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{r}).(*sql.Tx)
+	return tx
+}
+
+// conn is the subset of *sql.DB and *sql.Tx that statements run on
+type conn interface {
+	queryer
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// primary returns the transaction ctx carries, or the primary pool
+func (r *mysqlTodoRepository) primary(ctx context.Context) conn {
+	if tx := r.txFrom(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// withTx runs fn in a transaction on the primary, committing when fn returns
+// nil. When fn returns an error the transaction is rolled back and the error
+// returned, joined with the rollback's error if that fails too; when fn panics
+// the transaction is rolled back and the panic continues. Within WithTx, fn
+// runs in the caller's transaction, which is left for WithTx to finish.
+func (r *mysqlTodoRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if tx := r.txFrom(ctx); tx != nil {
+		return fn(tx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// WithTx runs fn and, when it returns an error or panics, restores the tasks
+// as they were before it started, like a rolled back transaction. Calls made
+// concurrently by others are not isolated from fn and are undone with it.
+func (m *MockTodoRepository) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.mu.RLock()
+	tasks := make(map[string]*todov1.Task, len(m.tasks))
+	for id, task := range m.tasks {
+		tasks[id] = proto.Clone(task).(*todov1.Task)
+	}
+	remindedAt := maps.Clone(m.remindedAt)
+	deletedAt := maps.Clone(m.deletedAt)
+	listVersion := m.listVersion
+	m.mu.RUnlock()
+
+	rollback := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tasks, m.remindedAt, m.deletedAt, m.listVersion = tasks, remindedAt, deletedAt, listVersion
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestWithTx(t *testing.T) {
+	insert := func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO tasks (id, title) VALUES ('tx-task', 'In a transaction')")
+		return err
+	}
+	countTasks := func(t *testing.T, db *sql.DB) int {
+		t.Helper()
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count); err != nil {
+			t.Fatalf("Failed to count tasks: %v", err)
+		}
+		return count
+	}
+
+	t.Run("commits on success", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewSQLiteTodoRepository(db).(*mysqlTodoRepository)
+
+		if err := repo.withTx(context.Background(), insert); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := countTasks(t, db); got != 1 {
+			t.Errorf("Expected the insert to be committed, got %d tasks", got)
+		}
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewSQLiteTodoRepository(db).(*mysqlTodoRepository)
+		errFailed := errors.New("later step failed")
+
+		err := repo.withTx(context.Background(), func(tx *sql.Tx) error {
+			if err := insert(tx); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("Expected the callback's error, got %v", err)
+		}
+		if got := countTasks(t, db); got != 0 {
+			t.Errorf("Expected the insert to be rolled back, got %d tasks", got)
+		}
+	})
+
+	t.Run("rolls back on panic", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := NewSQLiteTodoRepository(db).(*mysqlTodoRepository)
+
+		func() {
+			defer func() {
+				if p := recover(); p != "boom" {
+					t.Errorf("Expected the panic to propagate, got %v", p)
+				}
+			}()
+			repo.withTx(context.Background(), func(tx *sql.Tx) error {
+				if err := insert(tx); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+
+		// With a single connection, a transaction left open would block this
+		if got := countTasks(t, db); got != 0 {
+			t.Errorf("Expected the insert to be rolled back, got %d tasks", got)
+		}
+	})
+
+	t.Run("joins a failed rollback", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("Failed to create sqlmock: %v", err)
+		}
+		defer db.Close()
+
+		errFailed := errors.New("callback failed")
+		errRollback := errors.New("connection lost")
+		mock.ExpectBegin()
+		mock.ExpectRollback().WillReturnError(errRollback)
+
+		repo := NewMySQLTodoRepository(db).(*mysqlTodoRepository)
+		err = repo.withTx(context.Background(), func(tx *sql.Tx) error { return errFailed })
+		if !errors.Is(err, errFailed) || !errors.Is(err, errRollback) {
+			t.Errorf("Expected both the callback and rollback errors, got %v", err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestTodoRepository_WithTx(t *testing.T) {
+	forEachRepository(t, func(t *testing.T, repo TodoRepository) {
+		ctx := context.Background()
+
+		var created *todov1.Task
+		err := repo.WithTx(ctx, func(ctx context.Context) error {
+			var err error
+			if created, err = repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"}); err != nil {
+				return err
+			}
+			// Reads in the transaction see its writes
+			if count, err := repo.Count(ctx); err != nil || count != 1 {
+				t.Errorf("Expected the transaction to see 1 task, got %d (%v)", count, err)
+			}
+			_, _, err = repo.Update(ctx, &UpdateTaskRequest{ID: created.Id, Completed: true})
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		task, err := repo.GetByID(ctx, created.Id)
+		if err != nil || !task.Completed {
+			t.Fatalf("Expected the committed task to be completed, got %v (%v)", task, err)
+		}
+
+		errFailed := errors.New("later step failed")
+		err = repo.WithTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy eggs"}); err != nil {
+				return err
+			}
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: created.Id, Title: "Buy oat milk"}); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("Expected the callback's error, got %v", err)
+		}
+		if count, err := repo.Count(ctx); err != nil || count != 1 {
+			t.Errorf("Expected the create to be rolled back, got %d tasks (%v)", count, err)
+		}
+		if task, err := repo.GetByID(ctx, created.Id); err != nil || task.Title != "Buy milk" {
+			t.Errorf("Expected the rename to be rolled back, got %v (%v)", task, err)
+		}
+	})
+}
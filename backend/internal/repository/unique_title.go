@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateTitle is returned by Create, Update and Duplicate when unique
+// titles are enforced and another task in the tenant already has the title
+var ErrDuplicateTitle = errors.New("a task with this title already exists")
+
+// WithUniqueTitles has Create, Update and Duplicate reject a title that another
+// task in the same tenant already uses with ErrDuplicateTitle. Archived tasks
+// keep their titles reserved, as they do under the uniq_tenant_title index.
+// The check runs in the same statement or transaction as the write and, on
+// MySQL, locks the title's index range, so of two concurrent writes of one
+// title the second waits or is rolled back as a deadlock (mapped to Aborted)
+// rather than both succeeding. Only the unique index also covers writes made
+// by other clients of the database.
+func WithUniqueTitles() Option {
+	return func(r *mysqlTodoRepository) {
+		r.uniqueTitles = true
+	}
+}
+
+// duplicateTitleError wraps ErrDuplicateTitle with the rejected title
+func duplicateTitleError(title string) error {
+	return fmt.Errorf("%w: %q", ErrDuplicateTitle, title)
+}
+
+// titleTaken reports whether a task other than excludeID in ctx's tenant has
+// the given title. Matching follows the column collation, which is
+// case-insensitive in MySQL. Within a MySQL transaction the matching index
+// range stays locked until the transaction ends.
+func (r *mysqlTodoRepository) titleTaken(ctx context.Context, q queryer, title, excludeID string) (bool, error) {
+	query := "SELECT 1 FROM tasks WHERE tenant_id = ? AND title = ?"
+	args := []interface{}{tenantOf(ctx), title}
+	if excludeID != "" {
+		query += " AND id <> ?"
+		args = append(args, excludeID)
+	}
+	query += " LIMIT 1"
+	if _, inTx := q.(*sql.Tx); inTx {
+		query += r.forUpdate()
+	}
+
+	var exists int
+	err := q.QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up task title: %w", err)
+	}
+	return true, nil
+}
+
+// checkTitleFree returns a duplicate title error when unique titles are
+// enforced and title is taken by a task other than excludeID
+func (r *mysqlTodoRepository) checkTitleFree(ctx context.Context, tx *sql.Tx, title, excludeID string) error {
+	if !r.uniqueTitles {
+		return nil
+	}
+	taken, err := r.titleTaken(ctx, tx, title, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return duplicateTitleError(title)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+)
+
+func TestMySQLTodoRepository_UniqueTitles(t *testing.T) {
+	repos := map[string]func(t *testing.T) TodoRepository{
+		"reread": func(t *testing.T) TodoRepository {
+			return NewMySQLTodoRepository(setupTestDB(t), WithUniqueTitles())
+		},
+		"without reread": func(t *testing.T) TodoRepository {
+			return NewSQLiteTodoRepository(setupTestDB(t), WithUniqueTitles(), WithoutCreateReread())
+		},
+		"mock": func(t *testing.T) TodoRepository {
+			repo := NewMockTodoRepository()
+			repo.SetUniqueTitles(true)
+			return repo
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newRepo(t)
+
+			milk, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			eggs, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy eggs"})
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+
+			if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"}); !errors.Is(err, ErrDuplicateTitle) {
+				t.Errorf("Expected ErrDuplicateTitle creating a taken title, got %v", err)
+			}
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: eggs.Id, Title: "Buy milk"}); !errors.Is(err, ErrDuplicateTitle) {
+				t.Errorf("Expected ErrDuplicateTitle renaming to a taken title, got %v", err)
+			}
+			if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: milk.Id, Title: "Buy milk", Completed: true}); err != nil {
+				t.Errorf("Expected a task to keep its own title, got %v", err)
+			}
+			if _, err := repo.Duplicate(ctx, milk.Id, ""); !errors.Is(err, ErrDuplicateTitle) {
+				t.Errorf("Expected ErrDuplicateTitle duplicating without a suffix, got %v", err)
+			}
+			if _, err := repo.Duplicate(ctx, milk.Id, " (copy)"); err != nil {
+				t.Errorf("Expected a suffixed copy to be allowed, got %v", err)
+			}
+
+			// A rejected write leaves nothing behind
+			if count, err := repo.Count(ctx); err != nil || count != 3 {
+				t.Errorf("Expected 3 tasks, got %d (%v)", count, err)
+			}
+		})
+	}
+}
+
+func TestMySQLTodoRepository_UniqueTitlesArchivedAndTenants(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMySQLTodoRepository(setupTestDB(t), WithUniqueTitles())
+
+	done, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if _, _, err := repo.Update(ctx, &UpdateTaskRequest{ID: done.Id, Completed: true}); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	if archived, err := repo.ArchiveCompleted(ctx, time.Now().Add(time.Hour)); err != nil || archived != 1 {
+		t.Fatalf("Expected 1 archived task, got %d (%v)", archived, err)
+	}
+
+	// Archived tasks keep their titles reserved
+	if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"}); !errors.Is(err, ErrDuplicateTitle) {
+		t.Errorf("Expected an archived task's title to stay taken, got %v", err)
+	}
+
+	// Titles are unique per tenant
+	acme := middleware.WithTenant(ctx, "acme")
+	if _, err := repo.Create(acme, &CreateTaskRequest{Title: "Buy milk"}); err != nil {
+		t.Errorf("Expected another tenant to reuse the title, got %v", err)
+	}
+}
+
+func TestMySQLTodoRepository_UniqueTitleIndexViolation(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	if _, err := db.Exec("CREATE UNIQUE INDEX uniq_tenant_title ON tasks (tenant_id, title)"); err != nil {
+		t.Fatalf("Failed to add index: %v", err)
+	}
+	// Without the repository check, the index alone rejects the rename
+	repo := NewMySQLTodoRepository(db)
+
+	if _, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy milk"}); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	eggs, err := repo.Create(ctx, &CreateTaskRequest{Title: "Buy eggs"})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	_, _, err = repo.Update(ctx, &UpdateTaskRequest{ID: eggs.Id, Title: "Buy milk"})
+	if err == nil {
+		t.Fatal("Expected the unique index to reject the rename")
+	}
+	mapped := middleware.NewErrorHandler(middleware.NewStructuredLogger(middleware.LevelError)).HandleRepositoryError(err)
+	if connect.CodeOf(mapped) != connect.CodeAlreadyExists {
+		t.Errorf("Expected AlreadyExists, got %v", mapped)
+	}
+}
@@ -0,0 +1,243 @@
+// Package server assembles the complete TodoService HTTP stack so it can be
+// served on its own or mounted inside a larger mux
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpchealth"
+	"connectrpc.com/grpcreflect"
+	"github.com/wcygan/simple-connect-web-stack/internal/db"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1/todov1connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/health"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/openapi"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+	"github.com/wcygan/simple-connect-web-stack/internal/service"
+)
+
+// Config selects how NewServer assembles the stack. The zero value serves a
+// MySQL-backed TodoService with the default middleware, CORS, health checks
+// and OpenAPI document, and no message size limit.
+type Config struct {
+	// Dialect is the SQL dialect of the database passed to NewServer
+	Dialect db.Dialect
+
+	// Repository, when set, serves the API instead of a repository built on
+	// the database, e.g. one wrapped in a cache that background jobs share
+	Repository repository.TodoRepository
+
+	// RepositoryOptions configure the repository built on the database
+	RepositoryOptions []repository.Option
+
+	// Logger is shared by the repository, service and middleware; nil logs at info level
+	Logger *middleware.StructuredLogger
+
+	// Service configures the TodoService; a nil Service.Logger uses Logger
+	Service service.Config
+
+	// Middleware, when set, configures the middleware stack before it wraps
+	// the handlers, e.g. to set security headers or enable tenancy
+	Middleware func(stack *middleware.MiddlewareStack)
+
+	// RecentErrors, when set, records request errors and serves them at
+	// RecentErrorsPath (default /debug/errors)
+	RecentErrors     *middleware.RecentErrors
+	RecentErrorsPath string
+
+	// PreflightPaths are the path prefixes whose OPTIONS requests are answered
+	// as CORS preflight; OPTIONS to any other path reaches the mux, which
+	// returns 404 for unknown paths. nil covers the RPC services.
+	PreflightPaths []string
+
+	MaxMessageBytes   int    // Largest accepted request message; 0 is unlimited
+	StrictJSON        bool   // Reject unknown fields in JSON requests
+	DisableGRPCHealth bool   // Skip the gRPC health service and /readyz
+	Reflection        bool   // Serve gRPC reflection
+	Version           string // Version in the OpenAPI document; "" is "dev"
+}
+
+// NewServer assembles the repository, service, middleware stack, health
+// endpoints, OpenAPI document and Connect handler into a handler with CORS
+// applied. The database must already have its schema; the caller keeps
+// ownership of it and closes it after the handler stops serving.
+func NewServer(database *sql.DB, cfg Config) (http.Handler, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = middleware.NewStructuredLogger(middleware.LevelInfo)
+	}
+
+	repo := cfg.Repository
+	if repo == nil {
+		if database == nil {
+			return nil, errors.New("server: a database or repository is required")
+		}
+		if cfg.Dialect == db.SQLite {
+			repo = repository.NewSQLiteTodoRepositoryWithLogger(database, logger, cfg.RepositoryOptions...)
+		} else {
+			repo = repository.NewMySQLTodoRepositoryWithLogger(database, logger, cfg.RepositoryOptions...)
+		}
+	}
+
+	serviceConfig := cfg.Service
+	if serviceConfig.Logger == nil {
+		serviceConfig.Logger = logger
+	}
+	todoService := service.NewTodoServiceWithConfig(repo, serviceConfig)
+
+	var stackLogger middleware.Logger = logger
+	if cfg.RecentErrors != nil {
+		stackLogger = middleware.NewRecordingLogger(logger, cfg.RecentErrors)
+	}
+	middlewareStack := middleware.NewMiddlewareStack(stackLogger)
+	if cfg.Middleware != nil {
+		cfg.Middleware(middlewareStack)
+	}
+
+	mux := http.NewServeMux()
+
+	// Mount the TodoService with Connect interceptors, rejecting oversized
+	// messages before they are decoded
+	messageLimit := middleware.NewMessageSizeLimit(cfg.MaxMessageBytes, logger)
+	interceptors := append(middlewareStack.GetConnectInterceptors(), service.TimeZoneInterceptor())
+	handlerOptions := []connect.HandlerOption{connect.WithInterceptors(interceptors...)}
+	if cfg.StrictJSON {
+		handlerOptions = append(handlerOptions, middleware.StrictJSON())
+	}
+	mountTodoService(mux, todoService, messageLimit, handlerOptions...)
+
+	// Standard gRPC health protocol for load balancers and meshes, sharing
+	// its readiness check with /readyz
+	if !cfg.DisableGRPCHealth {
+		checker := health.NewChecker(repo.HealthCheck, todov1connect.TodoServiceName)
+		mux.Handle(health.NewHandler(checker))
+		mux.Handle("/readyz", checker.ReadyzHandler())
+	}
+
+	// Let tools like grpcurl discover the API without the proto files
+	if cfg.Reflection {
+		mountReflection(mux)
+	}
+
+	// Describe the Connect JSON API for REST tooling, generated from the
+	// compiled proto so it tracks the service definition
+	if err := mountOpenAPI(mux, cfg.Version); err != nil {
+		return nil, err
+	}
+
+	if cfg.RecentErrors != nil {
+		debugPath := cfg.RecentErrorsPath
+		if debugPath == "" {
+			debugPath = "/debug/errors"
+		}
+		mux.Handle(debugPath, cfg.RecentErrors.Handler())
+	}
+
+	// Apply the middleware stack (logging, recovery, request ID, etc.), then CORS on top
+	preflightPaths := cfg.PreflightPaths
+	if preflightPaths == nil {
+		preflightPaths = defaultPreflightPaths()
+	}
+	return withCORS(middlewareStack.WrapHandler(mux), preflightPaths), nil
+}
+
+// mountOpenAPI serves the TodoService OpenAPI document at /openapi.json
+func mountOpenAPI(mux *http.ServeMux, version string) error {
+	if version == "" {
+		version = "dev"
+	}
+	service := todov1.File_todo_v1_todo_proto.Services().ByName("TodoService")
+	handler, err := openapi.Handler(openapi.Build(service, version))
+	if err != nil {
+		return err
+	}
+	mux.Handle("GET /openapi.json", handler)
+	return nil
+}
+
+// mountTodoService registers the TodoService handler with the message size
+// limit applied
+func mountTodoService(mux *http.ServeMux, svc todov1connect.TodoServiceHandler, limit *middleware.MessageSizeLimit, options ...connect.HandlerOption) {
+	path, handler := todov1connect.NewTodoServiceHandler(svc,
+		append(options, limit.HandlerOption())...,
+	)
+	mux.Handle(path, limit.Middleware(handler))
+}
+
+// mountReflection registers the v1 and v1alpha gRPC reflection services for
+// TodoService and the standard health service
+func mountReflection(mux *http.ServeMux) {
+	reflector := grpcreflect.NewStaticReflector(todov1connect.TodoServiceName, grpchealth.HealthV1ServiceName)
+	mux.Handle(grpcreflect.NewHandlerV1(reflector))
+	mux.Handle(grpcreflect.NewHandlerV1Alpha(reflector))
+}
+
+// corsMaxAge is how long browsers may cache a preflight response
+const corsMaxAge = "7200"
+
+// corsAllowedMethods are the methods preflight requests may ask for
+const corsAllowedMethods = "GET, POST, PUT, DELETE, OPTIONS"
+
+// corsExposedHeaders are the response headers browser clients may read
+const corsExposedHeaders = "ETag, Location, X-Not-Modified, X-Correlation-ID"
+
+// defaultPreflightPaths are the RPC service paths browsers call cross-origin
+func defaultPreflightPaths() []string {
+	return []string{
+		"/" + todov1connect.TodoServiceName + "/",
+		"/" + grpchealth.HealthV1ServiceName + "/",
+	}
+}
+
+// isPreflightPath reports whether path falls under one of the prefixes
+func isPreflightPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withCORS adds CORS headers to support browser requests and answers OPTIONS
+// requests under preflightPaths without calling h
+func withCORS(h http.Handler, preflightPaths []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Allow requests from the frontend
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		if r.Method == "OPTIONS" && isPreflightPath(r.URL.Path, preflightPaths) {
+			// Preflight: reflect what the browser asked for so custom Connect
+			// headers (e.g. Connect-Timeout-Ms) are accepted
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+			allowMethods := corsAllowedMethods
+			method := strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))
+			for _, allowed := range strings.Split(corsAllowedMethods, ", ") {
+				if method == allowed {
+					allowMethods = method
+				}
+			}
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+
+			allowHeaders := "Content-Type, Connect-Protocol-Version, If-None-Match, X-Tenant-ID, X-API-Key, X-Timezone, X-Correlation-ID, X-Causation-ID"
+			if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				allowHeaders = requested
+			}
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
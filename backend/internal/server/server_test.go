@@ -0,0 +1,363 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"connectrpc.com/grpcreflect"
+	"github.com/wcygan/simple-connect-web-stack/internal/db"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1/todov1connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+	"github.com/wcygan/simple-connect-web-stack/internal/service"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestWithCORS_Preflight(t *testing.T) {
+	called := false
+	handler := withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), defaultPreflightPaths())
+
+	req := httptest.NewRequest(http.MethodOptions, "/todo.v1.TodoService/CreateTask", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "content-type,connect-protocol-version,connect-timeout-ms")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", w.Code)
+	}
+	if called {
+		t.Error("Expected preflight not to reach the wrapped handler")
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "POST" {
+		t.Errorf("Expected reflected method POST, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "content-type,connect-protocol-version,connect-timeout-ms" {
+		t.Errorf("Expected reflected headers, got %q", got)
+	}
+	if w.Header().Get("Access-Control-Max-Age") == "" {
+		t.Error("Expected Access-Control-Max-Age to be set")
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("Expected Access-Control-Allow-Origin to be set")
+	}
+}
+
+func TestWithCORS_DisallowedMethodNotReflected(t *testing.T) {
+	handler := withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), defaultPreflightPaths())
+
+	req := httptest.NewRequest(http.MethodOptions, "/todo.v1.TodoService/CreateTask", nil)
+	req.Header.Set("Access-Control-Request-Method", "PATCH")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != corsAllowedMethods {
+		t.Errorf("Expected default allowed methods, got %q", got)
+	}
+}
+
+func TestWithCORS_PassesThroughRequests(t *testing.T) {
+	handler := withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), defaultPreflightPaths())
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/todo.v1.TodoService/ListTasks", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("Expected Access-Control-Allow-Origin on simple requests")
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "ETag, Location, X-Not-Modified, X-Correlation-ID" {
+		t.Errorf("Expected ETag, Location, X-Not-Modified and X-Correlation-ID to be exposed, got %q", got)
+	}
+}
+
+func TestWithCORS_OptionsOutsidePreflightPaths(t *testing.T) {
+	called := false
+	handler := withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNotFound)
+	}), []string{"/api/"})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/todo.v1.TodoService/CreateTask", nil))
+
+	if !called || w.Code != http.StatusNotFound {
+		t.Errorf("Expected OPTIONS outside the configured paths to reach the handler, got %d", w.Code)
+	}
+	if w.Header().Get("Access-Control-Allow-Methods") != "" {
+		t.Error("Expected no preflight headers outside the configured paths")
+	}
+}
+
+func TestNewServer_Options(t *testing.T) {
+	handler, err := NewServer(nil, Config{
+		Repository: repository.NewMockTodoRepository(),
+		Logger:     middleware.NewStructuredLogger(middleware.LevelError),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build server: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+	}{
+		{"preflight on the service path", "/" + todov1connect.TodoServiceName + "/CreateTask", http.StatusNoContent},
+		{"unknown path", "/wp-admin/setup.php", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			req.Header.Set("Origin", "http://localhost:5173")
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestMountReflection_ListsTodoService(t *testing.T) {
+	mux := http.NewServeMux()
+	mountReflection(mux)
+
+	// Reflection is a bidi stream, so it needs HTTP/2
+	server := httptest.NewUnstartedServer(withCORS(mux, defaultPreflightPaths()))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := grpcreflect.NewClient(server.Client(), server.URL)
+	stream := client.NewStream(context.Background())
+	defer stream.Close()
+
+	services, err := stream.ListServices()
+	if err != nil {
+		t.Fatalf("Failed to list services: %v", err)
+	}
+
+	found := false
+	for _, name := range services {
+		if string(name) == todov1connect.TodoServiceName {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected %s in reflected services, got %v", todov1connect.TodoServiceName, services)
+	}
+}
+
+func TestMountOpenAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	if err := mountOpenAPI(mux, ""); err != nil {
+		t.Fatalf("Failed to mount OpenAPI: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		Paths map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Served document is not valid JSON: %v", err)
+	}
+	for _, method := range []string{"CreateTask", "GetTask", "ListTasks", "UpdateTask", "DeleteTask"} {
+		if _, ok := doc.Paths["/"+todov1connect.TodoServiceName+"/"+method]; !ok {
+			t.Errorf("Missing operation %s", method)
+		}
+	}
+}
+
+func TestMountTodoService_RejectsOversizedMessages(t *testing.T) {
+	mux := http.NewServeMux()
+	svc := service.NewTodoServiceWithRepository(repository.NewMockTodoRepository())
+	logger := middleware.NewStructuredLogger(middleware.LevelError)
+	mountTodoService(mux, svc, middleware.NewMessageSizeLimit(1024, logger))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := todov1connect.NewTodoServiceClient(server.Client(), server.URL)
+
+	if _, err := client.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"})); err != nil {
+		t.Fatalf("Expected a small CreateTask to succeed, got %v", err)
+	}
+
+	_, err := client.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: strings.Repeat("x", 1<<20)}))
+	if connect.CodeOf(err) != connect.CodeResourceExhausted {
+		t.Errorf("Expected resource_exhausted for an oversized CreateTask, got %v", err)
+	}
+}
+
+func TestMountTodoService_StrictJSON(t *testing.T) {
+	body := `{"title":"Buy milk","priorty":"high"}`
+	limit := middleware.NewMessageSizeLimit(0, middleware.NewStructuredLogger(middleware.LevelError))
+
+	tests := []struct {
+		name     string
+		options  []connect.HandlerOption
+		wantCode int
+	}{
+		{name: "default ignores unknown fields", wantCode: http.StatusOK},
+		{name: "strict rejects unknown fields", options: []connect.HandlerOption{middleware.StrictJSON()}, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			svc := service.NewTodoServiceWithRepository(repository.NewMockTodoRepository())
+			mountTodoService(mux, svc, limit, tt.options...)
+
+			req := httptest.NewRequest("POST", "/"+todov1connect.TodoServiceName+"/CreateTask", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode == http.StatusBadRequest {
+				var connectErr struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &connectErr); err != nil {
+					t.Fatalf("Failed to parse error body: %v", err)
+				}
+				if connectErr.Code != connect.CodeInvalidArgument.String() || !strings.Contains(connectErr.Message, "priorty") {
+					t.Errorf("Expected invalid_argument naming the field, got %+v", connectErr)
+				}
+			}
+		})
+	}
+}
+
+func TestNewServer_SQLiteEndToEnd(t *testing.T) {
+	database, dialect, err := db.Open("sqlite://:memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+	if err := db.InitSchema(database, dialect); err != nil {
+		t.Fatalf("Failed to initialize schema: %v", err)
+	}
+
+	handler, err := NewServer(database, Config{
+		Dialect: dialect,
+		Logger:  middleware.NewStructuredLogger(middleware.LevelError),
+	})
+	if err != nil {
+		t.Fatalf("Failed to build server: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := todov1connect.NewTodoServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	health, err := client.HealthCheck(ctx, connect.NewRequest(&emptypb.Empty{}))
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if health.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("Expected CORS headers on RPC responses")
+	}
+	if health.Header().Get("X-Request-ID") == "" {
+		t.Error("Expected the middleware stack to assign a request ID")
+	}
+
+	for _, path := range []string{"/readyz", "/openapi.json"} {
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Failed to GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected %s to return 200, got %d", path, resp.StatusCode)
+		}
+	}
+
+	created, err := client.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"}))
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	task := created.Msg.Task
+	if _, err := client.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Walk dog"})); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	updated, err := client.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: task.Id, Title: "Buy oat milk", Completed: true}))
+	if err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	if !updated.Msg.Task.Completed || updated.Msg.Task.Title != "Buy oat milk" {
+		t.Errorf("Expected the update to apply, got %v", updated.Msg.Task)
+	}
+	if !updated.Msg.Task.UpdatedAt.AsTime().After(task.UpdatedAt.AsTime()) {
+		t.Errorf("Expected updated_at to advance past %v, got %v", task.UpdatedAt.AsTime(), updated.Msg.Task.UpdatedAt.AsTime())
+	}
+	if !updated.Msg.Task.CreatedAt.AsTime().Equal(task.CreatedAt.AsTime()) {
+		t.Errorf("Expected created_at to be unchanged, got %v", updated.Msg.Task.CreatedAt.AsTime())
+	}
+
+	listed, err := client.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{Query: "oat"}))
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(listed.Msg.Tasks) != 1 || listed.Msg.Pagination.TotalUnfiltered != 2 {
+		t.Errorf("Expected 1 of 2 tasks to match, got %d of %d", len(listed.Msg.Tasks), listed.Msg.Pagination.TotalUnfiltered)
+	}
+
+	if _, err := client.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: task.Id})); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+	_, err = client.GetTask(ctx, connect.NewRequest(&todov1.GetTaskRequest{Id: task.Id}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("Expected not_found after delete, got %v", err)
+	}
+}
+
+func TestNewServer_RequiresStorage(t *testing.T) {
+	if _, err := NewServer(nil, Config{}); err == nil {
+		t.Error("Expected an error without a database or repository")
+	}
+
+	handler, err := NewServer(nil, Config{
+		Repository:        repository.NewMockTodoRepository(),
+		Logger:            middleware.NewStructuredLogger(middleware.LevelError),
+		DisableGRPCHealth: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to build server on a repository: %v", err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected /readyz to be absent with gRPC health disabled, got %d", w.Code)
+	}
+}
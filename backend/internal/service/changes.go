@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// changesPageSize caps the tasks one ListChangedSince response carries
+const changesPageSize = 100
+
+// defaultChangePollInterval is used when Config.ChangePollInterval is zero
+const defaultChangePollInterval = time.Second
+
+// waiter is implemented by clocks that can also wait, so tests can drive
+// ListChangedSince's polling without sleeping
+type waiter interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+// after returns a channel that receives once d has passed on the configured clock
+func (s *TodoService) after(d time.Duration) <-chan time.Time {
+	if w, ok := s.config.Clock.(waiter); ok {
+		return w.After(d)
+	}
+	return time.After(d)
+}
+
+// ListChangedSince returns tasks changed after the since and since_id cursor at
+// once when there are any. Otherwise it checks again every ChangePollInterval until a change
+// appears or wait_timeout_ms passes, then returns what it found, possibly
+// nothing. A client that goes away ends the wait.
+func (s *TodoService) ListChangedSince(
+	ctx context.Context,
+	req *connect.Request[todov1.ListChangedSinceRequest],
+) (*connect.Response[todov1.ListChangedSinceResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateListChangedSince(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	var since time.Time
+	if req.Msg.Since != nil {
+		since = req.Msg.Since.AsTime()
+	}
+	interval := s.config.ChangePollInterval
+	if interval <= 0 {
+		interval = defaultChangePollInterval
+	}
+	deadline := s.now().Add(time.Duration(req.Msg.WaitTimeoutMs) * time.Millisecond)
+
+	for {
+		// One extra task tells whether more changes are waiting
+		tasks, err := s.repo.ListChangedSince(ctx, since, req.Msg.SinceId, changesPageSize+1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, connect.NewError(connect.CodeCanceled, ctx.Err())
+			}
+			return nil, s.errorHandler.HandleRepositoryError(err)
+		}
+
+		remaining := deadline.Sub(s.now())
+		if len(tasks) > 0 || remaining <= 0 {
+			return connect.NewResponse(changesResponse(tasks, req.Msg.Since, req.Msg.SinceId)), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, connect.NewError(connect.CodeCanceled, ctx.Err())
+		case <-s.after(min(interval, remaining)):
+		}
+	}
+}
+
+// CountCreatedSince returns the number of tasks created at or after since
+func (s *TodoService) CountCreatedSince(
+	ctx context.Context,
+	req *connect.Request[todov1.CountCreatedSinceRequest],
+) (*connect.Response[todov1.CountCreatedSinceResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateCountCreatedSince(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	count, err := s.repo.CountCreatedSince(ctx, req.Msg.Since.AsTime())
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	return connect.NewResponse(&todov1.CountCreatedSinceResponse{
+		Count: count,
+	}), nil
+}
+
+// GetListVersion returns the list version, which clients compare with the one
+// from their last ListTasks to decide whether to fetch again
+func (s *TodoService) GetListVersion(
+	ctx context.Context,
+	req *connect.Request[emptypb.Empty],
+) (*connect.Response[todov1.GetListVersionResponse], error) {
+	version, err := s.repo.ListVersion(ctx)
+	if errors.Is(err, repository.ErrListVersionDisabled) {
+		return nil, connect.NewError(connect.CodeUnimplemented, err)
+	}
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	return connect.NewResponse(&todov1.GetListVersionResponse{
+		ListVersion: version,
+	}), nil
+}
+
+// listVersion returns the list version for a ListTasks response, or 0 when
+// versions are not tracked
+func (s *TodoService) listVersion(ctx context.Context) (uint64, error) {
+	version, err := s.repo.ListVersion(ctx)
+	if errors.Is(err, repository.ErrListVersionDisabled) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// changesResponse builds a response from up to changesPageSize+1 changed
+// tasks, ordered oldest change first. The cursor names the last task returned,
+// so the next poll resumes after it even when later tasks share its timestamp.
+func changesResponse(tasks []*todov1.Task, since *timestamppb.Timestamp, sinceID string) *todov1.ListChangedSinceResponse {
+	hasMore := len(tasks) > changesPageSize
+	if hasMore {
+		tasks = tasks[:changesPageSize]
+	}
+
+	nextSince, nextSinceID := since, sinceID
+	if len(tasks) > 0 {
+		last := tasks[len(tasks)-1]
+		nextSince, nextSinceID = last.UpdatedAt, last.Id
+	}
+	return &todov1.ListChangedSinceResponse{
+		Tasks:       tasks,
+		NextSince:   nextSince,
+		HasMore:     hasMore,
+		NextSinceId: nextSinceID,
+	}
+}
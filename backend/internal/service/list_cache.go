@@ -0,0 +1,40 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"strconv"
+
+	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+// isConnectJSON reports whether req uses the Connect protocol with JSON, the
+// encoding the web frontend uses
+func isConnectJSON(req connect.AnyRequest) bool {
+	if req.Peer().Protocol != connect.ProtocolConnect {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(req.Header().Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// listETag returns a weak ETag for a page of tasks, combining the latest
+// updated_at on the page with a hash of every task's ETag and the pagination.
+// Any create, update, archive or delete that affects the page changes it.
+func listETag(resp *todov1.ListTasksResponse) string {
+	hash := sha256.New()
+	var latest int64
+	for _, task := range resp.Tasks {
+		if task.UpdatedAt != nil {
+			latest = max(latest, task.UpdatedAt.AsTime().UnixNano())
+		}
+		fmt.Fprintf(hash, "%s;", taskETag(task))
+	}
+	if p := resp.Pagination; p != nil {
+		fmt.Fprintf(hash, "%d|%d|%d|%d|%d", p.Page, p.PageSize, p.TotalPages, p.TotalItems, p.TotalUnfiltered)
+	}
+	return `W/"` + strconv.FormatInt(latest, 36) + "-" + hex.EncodeToString(hash.Sum(nil)[:12]) + `"`
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetStats counts the caller's tasks by state, so operators can watch how
+// many completed, archived and deleted tasks accumulate
+func (s *TodoService) GetStats(
+	ctx context.Context,
+	req *connect.Request[emptypb.Empty],
+) (*connect.Response[todov1.GetStatsResponse], error) {
+	stats, err := s.repo.CountByState(ctx)
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	return connect.NewResponse(&todov1.GetStatsResponse{
+		Active:    stats.Active,
+		Completed: stats.Completed,
+		Archived:  stats.Archived,
+		Deleted:   stats.Deleted,
+	}), nil
+}
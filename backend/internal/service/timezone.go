@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TimeZoneHeader names an IANA time zone, e.g. "Europe/Amsterdam", in which
+// returned tasks should also carry display timestamps
+const TimeZoneHeader = "X-Timezone"
+
+// TimeZoneInterceptor fills Task.local_times in responses, including each
+// message of a stream, when the request sends an X-Timezone header. Stored and
+// returned timestamps stay UTC instants; this only adds a rendering for
+// display. Unknown zones are rejected with InvalidArgument before the RPC runs.
+func TimeZoneInterceptor() connect.Interceptor {
+	return timeZoneInterceptor{}
+}
+
+// timeZoneInterceptor implements TimeZoneInterceptor
+type timeZoneInterceptor struct{}
+
+// WrapUnary localizes the response of a unary RPC
+func (timeZoneInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		loc, err := requestTimeZone(req.Header())
+		if err != nil {
+			return nil, err
+		}
+		if loc == nil {
+			return next(ctx, req)
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		localizeResponse(resp.Any(), loc)
+		return resp, nil
+	}
+}
+
+// WrapStreamingClient leaves outgoing streams untouched
+func (timeZoneInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler localizes every message a streaming RPC sends
+func (timeZoneInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		loc, err := requestTimeZone(conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+		if loc != nil {
+			conn = &localizingConn{StreamingHandlerConn: conn, loc: loc}
+		}
+		return next(ctx, conn)
+	}
+}
+
+// localizingConn localizes each message before sending it
+type localizingConn struct {
+	connect.StreamingHandlerConn
+	loc *time.Location
+}
+
+// Send localizes and sends one message
+func (c *localizingConn) Send(msg any) error {
+	localizeResponse(msg, c.loc)
+	return c.StreamingHandlerConn.Send(msg)
+}
+
+// requestTimeZone returns the zone named by the X-Timezone header, nil when
+// the header is absent, or an InvalidArgument error for unknown zones
+func requestTimeZone(header http.Header) (*time.Location, error) {
+	name := strings.TrimSpace(header.Get(TimeZoneHeader))
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := loadTimeZone(name)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return loc, nil
+}
+
+// loadTimeZone resolves an IANA zone name. "Local" is refused because it would
+// expose the server's zone rather than name the client's.
+func loadTimeZone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("invalid %s %q: use an IANA time zone name", TimeZoneHeader, name)
+	}
+	return loc, nil
+}
+
+// localizeResponse replaces every task in a response with a copy carrying
+// local times, leaving the tasks the repository returned untouched
+func localizeResponse(msg any, loc *time.Location) {
+	localizeAll := func(tasks []*todov1.Task) {
+		for i, task := range tasks {
+			tasks[i] = localized(task, loc)
+		}
+	}
+
+	switch msg := msg.(type) {
+	case *todov1.CreateTaskResponse:
+		msg.Task = localized(msg.Task, loc)
+	case *todov1.GetTaskResponse:
+		msg.Task = localized(msg.Task, loc)
+	case *todov1.UpdateTaskResponse:
+		msg.Task = localized(msg.Task, loc)
+	case *todov1.DuplicateTaskResponse:
+		msg.Task = localized(msg.Task, loc)
+	case *todov1.ReorderTaskResponse:
+		msg.Task = localized(msg.Task, loc)
+	case *todov1.ListChangedSinceResponse:
+		localizeAll(msg.Tasks)
+	case *todov1.ListTasksStreamResponse:
+		if chunk := msg.GetChunk(); chunk != nil {
+			localizeAll(chunk.Tasks)
+		}
+	case *todov1.ListTasksResponse:
+		localizeAll(msg.Tasks)
+		for _, group := range []*todov1.TaskGroup{msg.Pending, msg.Completed} {
+			if group != nil {
+				localizeAll(group.Tasks)
+			}
+		}
+	}
+}
+
+// localized returns a copy of task with its timestamps rendered in loc
+func localized(task *todov1.Task, loc *time.Location) *todov1.Task {
+	if task == nil {
+		return nil
+	}
+	render := func(ts *timestamppb.Timestamp) string {
+		if ts == nil {
+			return ""
+		}
+		return ts.AsTime().In(loc).Format(time.RFC3339)
+	}
+
+	task = proto.Clone(task).(*todov1.Task)
+	task.LocalTimes = &todov1.LocalTimes{
+		TimeZone:   loc.String(),
+		CreatedAt:  render(task.CreatedAt),
+		UpdatedAt:  render(task.UpdatedAt),
+		ArchivedAt: render(task.ArchivedAt),
+		DueDate:    render(task.DueDate),
+	}
+	return task
+}
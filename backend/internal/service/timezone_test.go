@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1/todov1connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+)
+
+func TestTimeZoneInterceptor(t *testing.T) {
+	created := time.Date(2024, 1, 15, 14, 30, 0, 0, time.UTC)
+	repo := repository.NewMockTodoRepository()
+	repo.SetClock(repository.FixedClock{T: created})
+	service := NewTodoServiceWithRepository(repo)
+
+	_, handler := todov1connect.NewTodoServiceHandler(service, connect.WithInterceptors(TimeZoneInterceptor()))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := todov1connect.NewTodoServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"}))
+	assert.NoError(t, err)
+	assert.Nil(t, task.Msg.Task.LocalTimes, "expected no local times without the header")
+
+	t.Run("converts for display", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.ListTasksRequest{})
+		req.Header().Set(TimeZoneHeader, "America/New_York")
+		resp, err := client.ListTasks(ctx, req)
+		assert.NoError(t, err)
+		if !assert.Len(t, resp.Msg.Tasks, 1) {
+			return
+		}
+
+		got := resp.Msg.Tasks[0]
+		assert.Equal(t, created, got.CreatedAt.AsTime(), "stored timestamps stay UTC")
+		if assert.NotNil(t, got.LocalTimes) {
+			assert.Equal(t, "America/New_York", got.LocalTimes.TimeZone)
+			assert.Equal(t, "2024-01-15T09:30:00-05:00", got.LocalTimes.CreatedAt)
+			assert.Empty(t, got.LocalTimes.DueDate)
+		}
+	})
+
+	t.Run("converts streamed tasks", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.ListTasksRequest{})
+		req.Header().Set(TimeZoneHeader, "Asia/Tokyo")
+		stream, err := client.ListTasksStream(ctx, req)
+		assert.NoError(t, err)
+		defer stream.Close()
+
+		var localTimes []*todov1.LocalTimes
+		for stream.Receive() {
+			for _, task := range stream.Msg().GetChunk().GetTasks() {
+				localTimes = append(localTimes, task.LocalTimes)
+			}
+		}
+		assert.NoError(t, stream.Err())
+		if assert.Len(t, localTimes, 1) && assert.NotNil(t, localTimes[0]) {
+			assert.Equal(t, "2024-01-15T23:30:00+09:00", localTimes[0].CreatedAt)
+		}
+	})
+
+	t.Run("rejects unknown zones on streams", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.ListTasksRequest{})
+		req.Header().Set(TimeZoneHeader, "Mars/Olympus_Mons")
+		stream, err := client.ListTasksStream(ctx, req)
+		assert.NoError(t, err)
+		defer stream.Close()
+		for stream.Receive() {
+		}
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+	})
+
+	t.Run("does not alter stored tasks", func(t *testing.T) {
+		resp, err := client.GetTask(ctx, connect.NewRequest(&todov1.GetTaskRequest{Id: task.Msg.Task.Id}))
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Msg.Task.LocalTimes)
+	})
+
+	t.Run("rejects unknown zones", func(t *testing.T) {
+		for _, zone := range []string{"Mars/Olympus_Mons", "Local"} {
+			req := connect.NewRequest(&todov1.GetTaskRequest{Id: task.Msg.Task.Id})
+			req.Header().Set(TimeZoneHeader, zone)
+			_, err := client.GetTask(ctx, req)
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), zone)
+		}
+	})
+}
@@ -2,15 +2,26 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1/todov1connect"
 	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
 	"github.com/wcygan/simple-connect-web-stack/internal/repository"
 	"github.com/wcygan/simple-connect-web-stack/internal/validator"
-	todov1 "buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go/todo/v1"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // TodoService implements the TodoService RPC service
@@ -18,6 +29,51 @@ type TodoService struct {
 	repo         repository.TodoRepository
 	validator    *validator.TodoValidator
 	errorHandler *middleware.ErrorHandler
+	logger       *middleware.StructuredLogger
+	config       Config
+}
+
+// Config holds optional service behavior
+type Config struct {
+	// PublicBaseURL prefixes the Location header returned by CreateTask,
+	// e.g. "https://api.example.com". Empty yields a path-only Location.
+	PublicBaseURL string
+
+	// MaxTasks caps the total number of stored tasks; zero means unlimited
+	MaxTasks uint32
+
+	// ArchiveAfter is the retention used by ArchiveOldCompleted when the request
+	// does not specify one; zero requires callers to pass older_than_days
+	ArchiveAfter time.Duration
+
+	// TrashRetention is the retention used by PurgeDeleted when the request
+	// does not specify one; zero requires callers to pass older_than_days
+	TrashRetention time.Duration
+
+	// Validation enables optional request checks such as the title blocklist
+	Validation validator.Config
+
+	// WriteNoOpUpdates makes UpdateTask write (and bump updated_at) even when
+	// the request matches the stored task; by default such updates are skipped
+	WriteNoOpUpdates bool
+
+	// Logger receives service-level debug logs; nil keeps the default logger
+	Logger *middleware.StructuredLogger
+
+	// GitCommit is reported by GetVersion; empty reports "unknown"
+	GitCommit string
+
+	// Clock is the time source for cutoffs the service computes; nil uses the
+	// system clock
+	Clock repository.Clock
+
+	// StreamChunkSize is how many tasks each ListTasksStream message carries;
+	// zero uses 100
+	StreamChunkSize int
+
+	// ChangePollInterval is how often a waiting ListChangedSince checks for
+	// changes; zero uses one second
+	ChangePollInterval time.Duration
 }
 
 // NewTodoService creates a new TodoService
@@ -27,6 +83,7 @@ func NewTodoService(db *sql.DB) *TodoService {
 		repo:         repository.NewMySQLTodoRepository(db),
 		validator:    validator.NewTodoValidator(),
 		errorHandler: middleware.NewErrorHandler(logger),
+		logger:       logger,
 	}
 }
 
@@ -37,16 +94,37 @@ func NewTodoServiceWithRepository(repo repository.TodoRepository) *TodoService {
 		repo:         repo,
 		validator:    validator.NewTodoValidator(),
 		errorHandler: middleware.NewErrorHandler(logger),
+		logger:       logger,
 	}
 }
 
+// NewTodoServiceWithConfig creates a TodoService with a custom repository and configuration
+func NewTodoServiceWithConfig(repo repository.TodoRepository, config Config) *TodoService {
+	service := NewTodoServiceWithRepository(repo)
+	service.config = config
+	service.validator = validator.NewTodoValidatorWithConfig(config.Validation)
+	if config.Logger != nil {
+		service.logger = config.Logger
+	}
+	return service
+}
+
 // NewTodoServiceWithDependencies creates a TodoService with all dependencies
 func NewTodoServiceWithDependencies(repo repository.TodoRepository, validator *validator.TodoValidator, errorHandler *middleware.ErrorHandler) *TodoService {
 	return &TodoService{
 		repo:         repo,
 		validator:    validator,
 		errorHandler: errorHandler,
+		logger:       middleware.NewStructuredLogger(middleware.LevelInfo),
+	}
+}
+
+// now returns the current time from the configured clock
+func (s *TodoService) now() time.Time {
+	if s.config.Clock != nil {
+		return s.config.Clock.Now()
 	}
+	return time.Now()
 }
 
 // CreateTask creates a new task
@@ -59,19 +137,92 @@ func (s *TodoService) CreateTask(
 		return nil, s.errorHandler.HandleValidationError(err)
 	}
 
+	if err := s.checkTaskLimit(ctx); err != nil {
+		return nil, err
+	}
+
 	// Create task
 	createReq := &repository.CreateTaskRequest{
-		Title: strings.TrimSpace(req.Msg.Title),
+		ID:      req.Msg.Id,
+		Title:   strings.TrimSpace(req.Msg.Title),
+		DueDate: fromTimestamp(req.Msg.DueDate),
 	}
 
 	task, err := s.repo.Create(ctx, createReq)
 	if err != nil {
-		return nil, s.errorHandler.HandleRepositoryError(err)
+		return nil, s.handleWriteError(err)
 	}
+	s.logger.LogEvent(ctx, "task_created", map[string]interface{}{
+		"task_id": task.Id,
+	})
 
-	return connect.NewResponse(&todov1.CreateTaskResponse{
+	resp := connect.NewResponse(&todov1.CreateTaskResponse{
 		Task: task,
-	}), nil
+	})
+	resp.Header().Set("Location", s.taskLocation(task.Id))
+
+	return resp, nil
+}
+
+// handleWriteError converts a repository error from a write that may set a
+// title, reporting a title already in use as AlreadyExists
+func (s *TodoService) handleWriteError(err error) error {
+	if errors.Is(err, repository.ErrDuplicateTitle) {
+		return connect.NewError(connect.CodeAlreadyExists, err)
+	}
+	return s.errorHandler.HandleRepositoryError(err)
+}
+
+// checkTaskLimit rejects new tasks once the configured maximum has been reached
+func (s *TodoService) checkTaskLimit(ctx context.Context) error {
+	if s.config.MaxTasks == 0 {
+		return nil
+	}
+
+	count, err := s.repo.Count(ctx)
+	if err != nil {
+		return s.errorHandler.HandleRepositoryError(err)
+	}
+
+	if count >= s.config.MaxTasks {
+		return connect.NewError(connect.CodeResourceExhausted,
+			fmt.Errorf("task limit of %d reached", s.config.MaxTasks))
+	}
+
+	return nil
+}
+
+// taskETag returns a strong ETag derived from the fields a client can observe change
+func taskETag(task *todov1.Task) string {
+	var updatedAt, archivedAt int64
+	if task.UpdatedAt != nil {
+		updatedAt = task.UpdatedAt.AsTime().UnixNano()
+	}
+	// Archiving leaves updated_at alone, so it is hashed separately
+	if task.ArchivedAt != nil {
+		archivedAt = task.ArchivedAt.AsTime().UnixNano()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%t|%s", task.Id, updatedAt, archivedAt, task.Completed, task.Title)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// accepting "*", comma-separated lists, and weak validators
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// taskLocation returns the URL at which a task can be fetched via GetTask
+func (s *TodoService) taskLocation(id string) string {
+	base := strings.TrimSuffix(s.config.PublicBaseURL, "/")
+	return base + todov1connect.TodoServiceGetTaskProcedure + "?id=" + url.QueryEscape(id)
 }
 
 // GetTask retrieves a task by ID
@@ -89,9 +240,33 @@ func (s *TodoService) GetTask(
 		return nil, s.errorHandler.HandleRepositoryError(err)
 	}
 
-	return connect.NewResponse(&todov1.GetTaskResponse{
+	// Connect has no 304, so an unchanged task is signalled by an empty
+	// response with not_modified set, carrying the ETag and X-Not-Modified headers
+	etag := taskETag(task)
+	if etagMatches(req.Header().Get("If-None-Match"), etag) || notModifiedSince(task, req.Msg.IfModifiedSince) {
+		resp := connect.NewResponse(&todov1.GetTaskResponse{NotModified: true})
+		resp.Header().Set("ETag", etag)
+		resp.Header().Set("X-Not-Modified", "true")
+		return resp, nil
+	}
+
+	resp := connect.NewResponse(&todov1.GetTaskResponse{
 		Task: task,
-	}), nil
+	})
+	resp.Header().Set("ETag", etag)
+
+	return resp, nil
+}
+
+// notModifiedSince reports whether task was last updated strictly before since.
+// updated_at keeps whole seconds on MySQL, so a task stamped with the same
+// time as since may have changed again within that second and counts as
+// modified. A task without updated_at always counts as modified.
+func notModifiedSince(task *todov1.Task, since *timestamppb.Timestamp) bool {
+	if since == nil || task.UpdatedAt == nil {
+		return false
+	}
+	return task.UpdatedAt.AsTime().Before(since.AsTime())
 }
 
 // ListTasks retrieves tasks with pagination and filtering
@@ -110,8 +285,44 @@ func (s *TodoService) ListTasks(
 		PageSize:  req.Msg.PageSize,
 		Query:     req.Msg.Query,
 		Status:    req.Msg.Status,
+		DueDate:   req.Msg.DueDate,
 		SortBy:    req.Msg.SortBy,
 		SortOrder: req.Msg.SortOrder,
+
+		IncludeArchived: req.Msg.IncludeArchived,
+	}
+
+	// Read before the page so a change racing the list shows up as a newer
+	// version on the next poll rather than being missed
+	version, err := s.listVersion(ctx)
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	if req.Msg.IdsOnly {
+		ids, pagination, err := s.repo.ListIDs(ctx, filters)
+		if err != nil {
+			return nil, s.errorHandler.HandleRepositoryError(err)
+		}
+
+		return connect.NewResponse(&todov1.ListTasksResponse{
+			TaskIds:     ids,
+			Pagination:  toPaginationMetadata(pagination),
+			ListVersion: version,
+		}), nil
+	}
+
+	if req.Msg.GroupByStatus {
+		grouped, err := s.repo.ListGrouped(ctx, filters)
+		if err != nil {
+			return nil, s.errorHandler.HandleRepositoryError(err)
+		}
+
+		return connect.NewResponse(&todov1.ListTasksResponse{
+			Pending:     toTaskGroup(grouped.Pending),
+			Completed:   toTaskGroup(grouped.Completed),
+			ListVersion: version,
+		}), nil
 	}
 
 	tasks, pagination, err := s.repo.List(ctx, filters)
@@ -119,17 +330,93 @@ func (s *TodoService) ListTasks(
 		return nil, s.errorHandler.HandleRepositoryError(err)
 	}
 
-	return connect.NewResponse(&todov1.ListTasksResponse{
-		Tasks: tasks,
-		Pagination: &todov1.PaginationMetadata{
-			Page:        pagination.Page,
-			PageSize:    pagination.PageSize,
-			TotalPages:  pagination.TotalPages,
-			TotalItems:  pagination.TotalItems,
-			HasPrevious: pagination.HasPrevious,
-			HasNext:     pagination.HasNext,
-		},
-	}), nil
+	resp := &todov1.ListTasksResponse{
+		Tasks:       tasks,
+		Pagination:  toPaginationMetadata(pagination),
+		ListVersion: version,
+	}
+	// Estimated totals drift without any task changing, so only exact pages
+	// get a validator
+	if !isConnectJSON(req) || pagination.Approximate {
+		return connect.NewResponse(resp), nil
+	}
+
+	etag := listETag(resp)
+	if etagMatches(req.Header().Get("If-None-Match"), etag) {
+		notModified := connect.NewResponse(&todov1.ListTasksResponse{NotModified: true, ListVersion: version})
+		notModified.Header().Set("ETag", etag)
+		notModified.Header().Set("X-Not-Modified", "true")
+		return notModified, nil
+	}
+
+	cached := connect.NewResponse(resp)
+	cached.Header().Set("ETag", etag)
+	return cached, nil
+}
+
+// ListTasksStream sends one page of tasks in chunks as they are read from the
+// database, then the page's pagination. It takes ListTasks filters, but pages
+// may hold up to 10000 tasks and grouping is not supported.
+func (s *TodoService) ListTasksStream(
+	ctx context.Context,
+	req *connect.Request[todov1.ListTasksRequest],
+	stream *connect.ServerStream[todov1.ListTasksStreamResponse],
+) error {
+	// Validate request
+	if err := s.validator.ValidateListTasksStream(req.Msg); err != nil {
+		return s.errorHandler.HandleValidationError(err)
+	}
+
+	filters := &repository.ListTasksRequest{
+		Page:      req.Msg.Page,
+		PageSize:  req.Msg.PageSize,
+		Query:     req.Msg.Query,
+		Status:    req.Msg.Status,
+		DueDate:   req.Msg.DueDate,
+		SortBy:    req.Msg.SortBy,
+		SortOrder: req.Msg.SortOrder,
+
+		IncludeArchived: req.Msg.IncludeArchived,
+	}
+
+	pagination, err := s.repo.ListStream(ctx, filters, s.config.StreamChunkSize, func(tasks []*todov1.Task) error {
+		return stream.Send(&todov1.ListTasksStreamResponse{
+			Payload: &todov1.ListTasksStreamResponse_Chunk{Chunk: &todov1.TaskChunk{Tasks: tasks}},
+		})
+	})
+	if err != nil {
+		// The client going away is not a server failure
+		if ctx.Err() != nil {
+			return connect.NewError(connect.CodeCanceled, ctx.Err())
+		}
+		return s.errorHandler.HandleRepositoryError(err)
+	}
+
+	return stream.Send(&todov1.ListTasksStreamResponse{
+		Payload: &todov1.ListTasksStreamResponse_Pagination{Pagination: toPaginationMetadata(pagination)},
+	})
+}
+
+// toPaginationMetadata converts repository pagination to its API representation
+func toPaginationMetadata(pagination *repository.PaginationResult) *todov1.PaginationMetadata {
+	return &todov1.PaginationMetadata{
+		Page:            pagination.Page,
+		PageSize:        pagination.PageSize,
+		TotalPages:      pagination.TotalPages,
+		TotalItems:      pagination.TotalItems,
+		HasPrevious:     pagination.HasPrevious,
+		HasNext:         pagination.HasNext,
+		TotalUnfiltered: pagination.TotalUnfiltered,
+		Approximate:     pagination.Approximate,
+	}
+}
+
+// toTaskGroup converts a repository page to a TaskGroup
+func toTaskGroup(page repository.TaskPage) *todov1.TaskGroup {
+	return &todov1.TaskGroup{
+		Tasks:      page.Tasks,
+		Pagination: toPaginationMetadata(page.Pagination),
+	}
 }
 
 // UpdateTask updates an existing task
@@ -147,11 +434,42 @@ func (s *TodoService) UpdateTask(
 		ID:        req.Msg.Id,
 		Title:     strings.TrimSpace(req.Msg.Title),
 		Completed: req.Msg.Completed,
+
+		DueDate:      fromTimestamp(req.Msg.DueDate),
+		ClearDueDate: req.Msg.ClearDueDate,
+
+		SkipNoOp: !s.config.WriteNoOpUpdates,
+	}
+	if req.Msg.UpdateMask != nil {
+		updateReq.Mask = &repository.UpdateMask{}
+		for _, path := range req.Msg.UpdateMask.Paths {
+			switch path {
+			case "title":
+				updateReq.Mask.Title = true
+			case "completed":
+				updateReq.Mask.Completed = true
+			case "due_date":
+				updateReq.Mask.DueDate = true
+			}
+		}
 	}
 
-	task, err := s.repo.Update(ctx, updateReq)
+	// The repository compares against the primary inside the update's
+	// transaction, so a stale replica or cache cannot turn a change into a no-op
+	task, previous, err := s.repo.Update(ctx, updateReq)
 	if err != nil {
-		return nil, s.errorHandler.HandleRepositoryError(err)
+		return nil, s.handleWriteError(err)
+	}
+	changed := updateReq.Changes(previous)
+	if !changed && updateReq.SkipNoOp {
+		s.logger.Debug(ctx, "Skipped no-op task update", map[string]interface{}{
+			"task_id": task.Id,
+		})
+	}
+	if changed {
+		s.logger.LogEvent(ctx, updateEvent(previous, task), map[string]interface{}{
+			"task_id": task.Id,
+		})
 	}
 
 	return connect.NewResponse(&todov1.UpdateTaskResponse{
@@ -159,6 +477,29 @@ func (s *TodoService) UpdateTask(
 	}), nil
 }
 
+// updateEvent names the business event for an update from the task before
+// and after it: task_completed or task_reopened when the completion status
+// flipped, else task_updated
+func updateEvent(previous, task *todov1.Task) string {
+	switch {
+	case previous.Completed == task.Completed:
+		return "task_updated"
+	case task.Completed:
+		return "task_completed"
+	default:
+		return "task_reopened"
+	}
+}
+
+// fromTimestamp converts an optional protobuf timestamp to a time pointer
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
 // DeleteTask deletes a task
 func (s *TodoService) DeleteTask(
 	ctx context.Context,
@@ -173,10 +514,140 @@ func (s *TodoService) DeleteTask(
 	if err != nil {
 		return nil, s.errorHandler.HandleRepositoryError(err)
 	}
+	s.logger.LogEvent(ctx, "task_deleted", map[string]interface{}{
+		"task_id": req.Msg.Id,
+	})
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
 }
 
+// DuplicateTask creates a new pending task copied from an existing one
+func (s *TodoService) DuplicateTask(
+	ctx context.Context,
+	req *connect.Request[todov1.DuplicateTaskRequest],
+) (*connect.Response[todov1.DuplicateTaskResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateDuplicateTask(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	if err := s.checkTaskLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	suffix := ""
+	if req.Msg.AddCopySuffix {
+		suffix = " (copy)"
+	}
+
+	task, err := s.repo.Duplicate(ctx, req.Msg.Id, suffix)
+	if err != nil {
+		return nil, s.handleWriteError(err)
+	}
+	s.logger.LogEvent(ctx, "task_created", map[string]interface{}{
+		"task_id":        task.Id,
+		"source_task_id": req.Msg.Id,
+	})
+
+	resp := connect.NewResponse(&todov1.DuplicateTaskResponse{
+		Task: task,
+	})
+	resp.Header().Set("Location", s.taskLocation(task.Id))
+
+	return resp, nil
+}
+
+// ReorderTask moves a task directly after another in the manual ordering
+func (s *TodoService) ReorderTask(
+	ctx context.Context,
+	req *connect.Request[todov1.ReorderTaskRequest],
+) (*connect.Response[todov1.ReorderTaskResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateReorderTask(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	task, err := s.repo.Reorder(ctx, req.Msg.Id, req.Msg.AfterId)
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+	s.logger.LogEvent(ctx, "task_reordered", map[string]interface{}{
+		"task_id": task.Id,
+	})
+
+	return connect.NewResponse(&todov1.ReorderTaskResponse{
+		Task: task,
+	}), nil
+}
+
+// ArchiveOldCompleted archives completed tasks that have not changed within the retention period
+func (s *TodoService) ArchiveOldCompleted(
+	ctx context.Context,
+	req *connect.Request[todov1.ArchiveOldCompletedRequest],
+) (*connect.Response[todov1.ArchiveOldCompletedResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateArchiveOldCompleted(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	retention := time.Duration(req.Msg.OlderThanDays) * 24 * time.Hour
+	if retention == 0 {
+		retention = s.config.ArchiveAfter
+	}
+	if retention == 0 {
+		return nil, s.errorHandler.HandleValidationError(validator.ValidationError{
+			Field:   "older_than_days",
+			Message: "older_than_days is required when no default retention is configured",
+		})
+	}
+
+	archived, err := s.repo.ArchiveCompleted(ctx, s.now().Add(-retention))
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+	s.logger.LogEvent(ctx, "tasks_archived", map[string]interface{}{
+		"archived_count": archived,
+	})
+
+	return connect.NewResponse(&todov1.ArchiveOldCompletedResponse{
+		ArchivedCount: uint32(archived),
+	}), nil
+}
+
+// DeleteTasks deletes several tasks, reporting a result per id rather than
+// failing the whole batch on the first missing task
+func (s *TodoService) DeleteTasks(
+	ctx context.Context,
+	req *connect.Request[todov1.DeleteTasksRequest],
+) (*connect.Response[todov1.DeleteTasksResponse], error) {
+	// Validate request
+	if err := s.validator.ValidateDeleteTasks(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	results, err := s.repo.DeleteMany(ctx, req.Msg.Ids)
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	resp := &todov1.DeleteTasksResponse{Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case todov1.DeleteResultStatus_DELETE_RESULT_STATUS_DELETED:
+			resp.DeletedCount++
+			s.logger.LogEvent(ctx, "task_deleted", map[string]interface{}{
+				"task_id": result.Id,
+			})
+		case todov1.DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND:
+			resp.NotFoundCount++
+		default:
+			resp.ErrorCount++
+		}
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
 // HealthCheck returns the service health status
 func (s *TodoService) HealthCheck(
 	ctx context.Context,
@@ -187,8 +658,115 @@ func (s *TodoService) HealthCheck(
 		return nil, s.errorHandler.HandleRepositoryError(err)
 	}
 
-	return connect.NewResponse(&todov1.HealthCheckResponse{
-		Status: "ok",
+	// The replica is reported but does not fail the check
+	resp := &todov1.HealthCheckResponse{Status: "ok"}
+	switch err := s.repo.ReplicaHealthCheck(ctx); {
+	case errors.Is(err, repository.ErrNoReplica):
+	case err != nil:
+		s.logger.Warn(ctx, "Read replica health check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		resp.ReplicaStatus = "unavailable"
+	default:
+		resp.ReplicaStatus = "ok"
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// GetVersion reports which build is running, using the logger's service metadata
+func (s *TodoService) GetVersion(
+	ctx context.Context,
+	req *connect.Request[emptypb.Empty],
+) (*connect.Response[todov1.GetVersionResponse], error) {
+	name, version, environment := s.logger.Metadata()
+
+	commit := s.config.GitCommit
+	if commit == "" {
+		commit = "unknown"
+	}
+
+	return connect.NewResponse(&todov1.GetVersionResponse{
+		Service:     name,
+		Version:     version,
+		Environment: environment,
+		GoVersion:   runtime.Version(),
+		GitCommit:   commit,
 	}), nil
 }
 
+// redactedHeaders are echoed by DebugEcho without their values
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"X-Api-Key":     true,
+}
+
+// DebugEcho reports the headers, protocol, client address and request ID the
+// server received. It is disabled in production, where it would expose
+// infrastructure details.
+func (s *TodoService) DebugEcho(
+	ctx context.Context,
+	req *connect.Request[emptypb.Empty],
+) (*connect.Response[todov1.DebugEchoResponse], error) {
+	if _, _, environment := s.logger.Metadata(); environment == "production" {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("DebugEcho is disabled in production"))
+	}
+
+	names := make([]string, 0, len(req.Header()))
+	for name := range req.Header() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]*todov1.DebugHeader, 0, len(names))
+	for _, name := range names {
+		values := req.Header().Values(name)
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			values = []string{"[REDACTED]"}
+		}
+		headers = append(headers, &todov1.DebugHeader{Name: name, Values: values})
+	}
+
+	return connect.NewResponse(&todov1.DebugEchoResponse{
+		Headers:    headers,
+		Protocol:   req.Peer().Protocol,
+		ClientIp:   middleware.GetClientIP(ctx),
+		PeerAddr:   req.Peer().Addr,
+		RequestId:  middleware.GetRequestID(ctx),
+		Procedure:  req.Spec().Procedure,
+		HttpMethod: req.HTTPMethod(),
+	}), nil
+}
+
+// DiagnoseStorage runs the repository's read-only integrity checks and reports
+// what they found. Like DebugEcho it is disabled in production, where the full
+// table scan and the sampled IDs of every tenant are not appropriate.
+func (s *TodoService) DiagnoseStorage(
+	ctx context.Context,
+	req *connect.Request[emptypb.Empty],
+) (*connect.Response[todov1.DiagnoseStorageResponse], error) {
+	if _, _, environment := s.logger.Metadata(); environment == "production" {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("DiagnoseStorage is disabled in production"))
+	}
+
+	report, err := s.repo.Diagnose(ctx)
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+
+	checks := make([]*todov1.StorageCheck, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, &todov1.StorageCheck{
+			Name:      check.Name,
+			Count:     check.Count,
+			SampleIds: check.SampleIDs,
+		})
+	}
+
+	return connect.NewResponse(&todov1.DiagnoseStorageResponse{
+		TotalRows: report.TotalRows,
+		Checks:    checks,
+		Healthy:   report.Healthy(),
+	}), nil
+}
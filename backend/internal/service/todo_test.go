@@ -1,15 +1,28 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/stretchr/testify/assert"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1/todov1connect"
+	"github.com/wcygan/simple-connect-web-stack/internal/middleware"
 	"github.com/wcygan/simple-connect-web-stack/internal/repository"
-	todov1 "buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestTodoService_HealthCheck_Refactored(t *testing.T) {
@@ -25,6 +38,24 @@ func TestTodoService_HealthCheck_Refactored(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
 		assert.Equal(t, "ok", resp.Msg.Status)
+		assert.Empty(t, resp.Msg.ReplicaStatus)
+	})
+
+	t.Run("replica state is reported without failing the check", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+		req := connect.NewRequest(&emptypb.Empty{})
+
+		mockRepo.SetReplicaHealthError(nil)
+		resp, err := service.HealthCheck(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp.Msg.ReplicaStatus)
+
+		mockRepo.SetReplicaHealthError(errors.New("replica connection failed"))
+		resp, err = service.HealthCheck(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp.Msg.Status)
+		assert.Equal(t, "unavailable", resp.Msg.ReplicaStatus)
 	})
 
 	t.Run("repository health check fails", func(t *testing.T) {
@@ -43,6 +74,169 @@ func TestTodoService_HealthCheck_Refactored(t *testing.T) {
 	})
 }
 
+func TestTodoService_GetVersion(t *testing.T) {
+	logger := middleware.NewStructuredLoggerWithMetadata(middleware.LevelInfo, "todo-service", "1.4.2", "staging")
+	service := NewTodoServiceWithConfig(repository.NewMockTodoRepository(), Config{
+		Logger:    logger,
+		GitCommit: "abc123",
+	})
+
+	resp, err := service.GetVersion(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "todo-service", resp.Msg.Service)
+	assert.Equal(t, "1.4.2", resp.Msg.Version)
+	assert.Equal(t, "staging", resp.Msg.Environment)
+	assert.Equal(t, "abc123", resp.Msg.GitCommit)
+	assert.Equal(t, runtime.Version(), resp.Msg.GoVersion)
+}
+
+func TestTodoService_DebugEcho(t *testing.T) {
+	serve := func(environment string) (todov1connect.TodoServiceClient, func()) {
+		logger := middleware.NewStructuredLoggerWithMetadata(middleware.LevelError, "todo-service", "dev", environment)
+		service := NewTodoServiceWithConfig(repository.NewMockTodoRepository(), Config{Logger: logger})
+
+		errorHandler := middleware.NewErrorHandler(logger)
+		errorHandler.SetTrustedProxies(1)
+		_, handler := todov1connect.NewTodoServiceHandler(service)
+		server := httptest.NewServer(middleware.RequestIDMiddleware(errorHandler.LoggingMiddleware(handler)))
+		return todov1connect.NewTodoServiceClient(server.Client(), server.URL), server.Close
+	}
+
+	t.Run("enabled outside production", func(t *testing.T) {
+		client, stop := serve("staging")
+		defer stop()
+
+		req := connect.NewRequest(&emptypb.Empty{})
+		req.Header().Set("X-Forwarded-For", "203.0.113.7")
+		req.Header().Set("Authorization", "Bearer secret")
+		resp, err := client.DebugEcho(context.Background(), req)
+		assert.NoError(t, err)
+
+		headers := map[string][]string{}
+		for _, header := range resp.Msg.Headers {
+			headers[header.Name] = header.Values
+		}
+		assert.Equal(t, []string{"203.0.113.7"}, headers["X-Forwarded-For"])
+		assert.Equal(t, []string{"[REDACTED]"}, headers["Authorization"])
+		assert.Equal(t, connect.ProtocolConnect, resp.Msg.Protocol)
+		assert.Equal(t, "203.0.113.7", resp.Msg.ClientIp)
+		assert.NotEmpty(t, resp.Msg.PeerAddr)
+		assert.Equal(t, resp.Header().Get("X-Request-ID"), resp.Msg.RequestId)
+		assert.NotEmpty(t, resp.Msg.RequestId)
+		assert.Equal(t, todov1connect.TodoServiceDebugEchoProcedure, resp.Msg.Procedure)
+	})
+
+	t.Run("disabled in production", func(t *testing.T) {
+		client, stop := serve("production")
+		defer stop()
+
+		_, err := client.DebugEcho(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+		assert.Equal(t, connect.CodeUnimplemented, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_DiagnoseStorage(t *testing.T) {
+	newService := func(environment string) (*TodoService, *repository.MockTodoRepository) {
+		logger := middleware.NewStructuredLoggerWithMetadata(middleware.LevelError, "todo-service", "dev", environment)
+		repo := repository.NewMockTodoRepository()
+		return NewTodoServiceWithConfig(repo, Config{Logger: logger}), repo
+	}
+	ctx := context.Background()
+
+	t.Run("reports drift", func(t *testing.T) {
+		service, repo := newService("staging")
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"}))
+		assert.NoError(t, err)
+		repo.AddTask(&todov1.Task{Id: "edited by hand", Title: "Walk dog"})
+
+		resp, err := service.DiagnoseStorage(ctx, connect.NewRequest(&emptypb.Empty{}))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Msg.TotalRows)
+		assert.False(t, resp.Msg.Healthy)
+
+		flagged := map[string][]string{}
+		for _, check := range resp.Msg.Checks {
+			flagged[check.Name] = check.SampleIds
+		}
+		assert.Equal(t, []string{"edited by hand"}, flagged[repository.CheckInvalidIDs])
+		assert.Equal(t, []string{"edited by hand"}, flagged[repository.CheckNullTimestamps])
+		assert.Empty(t, flagged[repository.CheckDuplicateIDs])
+	})
+
+	t.Run("disabled in production", func(t *testing.T) {
+		service, _ := newService("production")
+		_, err := service.DiagnoseStorage(ctx, connect.NewRequest(&emptypb.Empty{}))
+		assert.Equal(t, connect.CodeUnimplemented, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_ListTasksStream(t *testing.T) {
+	repo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(repo, Config{StreamChunkSize: 10})
+	_, handler := todov1connect.NewTodoServiceHandler(service)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	client := todov1connect.NewTodoServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	for i := 0; i < 250; i++ {
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: fmt.Sprintf("Task %03d", i)}))
+		assert.NoError(t, err)
+	}
+
+	t.Run("chunks then pagination", func(t *testing.T) {
+		stream, err := client.ListTasksStream(ctx, connect.NewRequest(&todov1.ListTasksRequest{
+			PageSize:  1000,
+			SortBy:    todov1.SortField_SORT_FIELD_TITLE,
+			SortOrder: todov1.SortOrder_SORT_ORDER_ASC,
+		}))
+		assert.NoError(t, err)
+		defer stream.Close()
+
+		var titles []string
+		var chunks int
+		var pagination *todov1.PaginationMetadata
+		for stream.Receive() {
+			assert.Nil(t, pagination, "expected pagination to be the last message")
+			switch payload := stream.Msg().Payload.(type) {
+			case *todov1.ListTasksStreamResponse_Chunk:
+				chunks++
+				for _, task := range payload.Chunk.Tasks {
+					titles = append(titles, task.Title)
+				}
+			case *todov1.ListTasksStreamResponse_Pagination:
+				pagination = payload.Pagination
+			}
+		}
+		assert.NoError(t, stream.Err())
+
+		assert.Equal(t, 25, chunks)
+		if assert.Len(t, titles, 250) {
+			assert.Equal(t, "Task 000", titles[0])
+			assert.Equal(t, "Task 249", titles[249])
+			for i := 1; i < len(titles); i++ {
+				assert.Less(t, titles[i-1], titles[i])
+			}
+		}
+		if assert.NotNil(t, pagination) {
+			assert.Equal(t, uint32(250), pagination.TotalItems)
+			assert.Equal(t, uint32(1000), pagination.PageSize)
+		}
+	})
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		for _, req := range []*todov1.ListTasksRequest{{PageSize: 10001}, {GroupByStatus: true}} {
+			stream, err := client.ListTasksStream(ctx, connect.NewRequest(req))
+			assert.NoError(t, err)
+			for stream.Receive() {
+			}
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(stream.Err()))
+			stream.Close()
+		}
+	})
+}
+
 func TestTodoService_CreateTask_Refactored(t *testing.T) {
 	t.Run("valid task creation", func(t *testing.T) {
 		mockRepo := repository.NewMockTodoRepository()
@@ -94,4 +288,1131 @@ func TestTodoService_CreateTask_Refactored(t *testing.T) {
 		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
 		assert.Nil(t, resp)
 	})
-}
\ No newline at end of file
+}
+
+func TestTodoService_DeleteTasks(t *testing.T) {
+	t.Run("mix of existing and missing ids", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "First"})
+		mockRepo.AddTask(&todov1.Task{Id: "task-2", Title: "Second"})
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		ctx := context.Background()
+		req := connect.NewRequest(&todov1.DeleteTasksRequest{
+			Ids: []string{"task-1", "missing", "task-2"},
+		})
+
+		resp, err := service.DeleteTasks(ctx, req)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.Equal(t, uint32(2), resp.Msg.DeletedCount)
+		assert.Equal(t, uint32(1), resp.Msg.NotFoundCount)
+		assert.Equal(t, uint32(0), resp.Msg.ErrorCount)
+		assert.Len(t, resp.Msg.Results, 3)
+		assert.Equal(t, todov1.DeleteResultStatus_DELETE_RESULT_STATUS_NOT_FOUND, resp.Msg.Results[1].Status)
+		assert.Empty(t, mockRepo.GetAllTasks())
+	})
+
+	t.Run("per-id errors are reported", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.SetDeleteError(errors.New("lock wait timeout"))
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		ctx := context.Background()
+		req := connect.NewRequest(&todov1.DeleteTasksRequest{
+			Ids: []string{"task-1"},
+		})
+
+		resp, err := service.DeleteTasks(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), resp.Msg.ErrorCount)
+		assert.Equal(t, "lock wait timeout", resp.Msg.Results[0].Error)
+	})
+
+	t.Run("empty ids", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		ctx := context.Background()
+		req := connect.NewRequest(&todov1.DeleteTasksRequest{})
+
+		resp, err := service.DeleteTasks(ctx, req)
+
+		assert.Error(t, err)
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+		assert.Nil(t, resp)
+	})
+}
+
+func TestTodoService_ListTasks_TotalUnfiltered(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "1", Title: "Buy milk"})
+	mockRepo.AddTask(&todov1.Task{Id: "2", Title: "Walk dog", Completed: true})
+	mockRepo.AddTask(&todov1.Task{Id: "3", Title: "Buy eggs"})
+	service := NewTodoServiceWithRepository(mockRepo)
+
+	ctx := context.Background()
+	req := connect.NewRequest(&todov1.ListTasksRequest{Query: "buy"})
+
+	resp, err := service.ListTasks(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), resp.Msg.Pagination.TotalItems)
+	assert.Equal(t, uint32(3), resp.Msg.Pagination.TotalUnfiltered)
+}
+
+func TestTodoService_ListTasks_RejectsBadQuery(t *testing.T) {
+	service := NewTodoServiceWithRepository(repository.NewMockTodoRepository())
+	ctx := context.Background()
+
+	for _, query := range []string{strings.Repeat("x", 4096), "buy\nmilk", "milk\x00"} {
+		_, err := service.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{Query: query}))
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	}
+}
+
+func TestTodoService_ListTasks_GroupByStatus(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "1", Title: "Buy milk"})
+	mockRepo.AddTask(&todov1.Task{Id: "2", Title: "Walk dog", Completed: true})
+	mockRepo.AddTask(&todov1.Task{Id: "3", Title: "Buy eggs"})
+	mockRepo.AddTask(&todov1.Task{Id: "4", Title: "Buy bread", Completed: true})
+	service := NewTodoServiceWithRepository(mockRepo)
+
+	t.Run("groups by completion", func(t *testing.T) {
+		resp, err := service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{
+			Query:         "buy",
+			GroupByStatus: true,
+		}))
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Msg.Tasks)
+		assert.Nil(t, resp.Msg.Pagination)
+		assert.Equal(t, uint32(2), resp.Msg.Pending.Pagination.TotalItems)
+		assert.Len(t, resp.Msg.Pending.Tasks, 2)
+		assert.Equal(t, uint32(1), resp.Msg.Completed.Pagination.TotalItems)
+		assert.Equal(t, "Buy bread", resp.Msg.Completed.Tasks[0].Title)
+	})
+
+	t.Run("rejects status filter", func(t *testing.T) {
+		_, err := service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{
+			Status:        todov1.StatusFilter_STATUS_FILTER_PENDING,
+			GroupByStatus: true,
+		}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_ListTasks_IdsOnly(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "1", Title: "Buy milk"})
+	mockRepo.AddTask(&todov1.Task{Id: "2", Title: "Walk dog"})
+	mockRepo.AddTask(&todov1.Task{Id: "3", Title: "Buy eggs"})
+	service := NewTodoServiceWithRepository(mockRepo)
+
+	t.Run("returns only IDs", func(t *testing.T) {
+		resp, err := service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{
+			Query:     "buy",
+			SortBy:    todov1.SortField_SORT_FIELD_TITLE,
+			SortOrder: todov1.SortOrder_SORT_ORDER_ASC,
+			IdsOnly:   true,
+		}))
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Msg.Tasks)
+		assert.Equal(t, []string{"3", "1"}, resp.Msg.TaskIds)
+		assert.Equal(t, uint32(2), resp.Msg.Pagination.TotalItems)
+	})
+
+	t.Run("rejects grouping", func(t *testing.T) {
+		_, err := service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{
+			IdsOnly:       true,
+			GroupByStatus: true,
+		}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_GetStats(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "1", Title: "Pending"})
+	mockRepo.AddTask(&todov1.Task{Id: "2", Title: "Done", Completed: true})
+	mockRepo.AddTask(&todov1.Task{Id: "3", Title: "Old", Completed: true, ArchivedAt: timestamppb.Now()})
+	mockRepo.AddTask(&todov1.Task{Id: "4", Title: "Unwanted"})
+	mockRepo.SetSoftDelete(true)
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+
+	_, err := service.DeleteTask(ctx, connect.NewRequest(&todov1.DeleteTaskRequest{Id: "4"}))
+	assert.NoError(t, err)
+
+	resp, err := service.GetStats(ctx, connect.NewRequest(&emptypb.Empty{}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Msg.Active)
+	assert.Equal(t, uint32(1), resp.Msg.Completed)
+	assert.Equal(t, uint32(1), resp.Msg.Archived)
+	assert.Equal(t, uint32(1), resp.Msg.Deleted)
+}
+
+func TestTodoService_DueDates(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+	due := timestamppb.New(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC))
+
+	created, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Scheduled", DueDate: due}))
+	assert.NoError(t, err)
+	assert.True(t, created.Msg.Task.DueDate.AsTime().Equal(due.AsTime()))
+	_, err = service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Unscheduled"}))
+	assert.NoError(t, err)
+
+	list, err := service.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{DueDate: todov1.DueDateFilter_DUE_DATE_FILTER_UNSCHEDULED}))
+	assert.NoError(t, err)
+	assert.Len(t, list.Msg.Tasks, 1)
+	assert.Equal(t, "Unscheduled", list.Msg.Tasks[0].Title)
+
+	_, err = service.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{DueDate: todov1.DueDateFilter(99)}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	// Clearing a due date is a real change, not a skipped no-op
+	updated, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: created.Msg.Task.Id, ClearDueDate: true}))
+	assert.NoError(t, err)
+	assert.Nil(t, updated.Msg.Task.DueDate)
+
+	_, err = service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: created.Msg.Task.Id, DueDate: due, ClearDueDate: true}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestTodoService_UpdateTask_NoOp(t *testing.T) {
+	updatedAt := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	newService := func(config Config) *TodoService {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Write docs", UpdatedAt: updatedAt})
+		return NewTodoServiceWithConfig(mockRepo, config)
+	}
+
+	t.Run("unchanged task is not written", func(t *testing.T) {
+		service := newService(Config{})
+
+		for _, title := range []string{"Write docs", "  Write docs  ", "", "   "} {
+			resp, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{
+				Id:    "task-1",
+				Title: title,
+			}))
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Write docs", resp.Msg.Task.Title)
+			assert.True(t, updatedAt.AsTime().Equal(resp.Msg.Task.UpdatedAt.AsTime()), "title %q bumped updated_at", title)
+		}
+	})
+
+	t.Run("real change is written", func(t *testing.T) {
+		service := newService(Config{})
+
+		resp, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:        "task-1",
+			Completed: true,
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, resp.Msg.Task.Completed)
+		assert.True(t, resp.Msg.Task.UpdatedAt.AsTime().After(updatedAt.AsTime()))
+	})
+
+	t.Run("stale cached read does not hide a change", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Write docs", UpdatedAt: updatedAt})
+		cached := repository.NewCachingTodoRepository(mockRepo, 10, time.Hour)
+		service := NewTodoServiceWithConfig(cached, Config{})
+
+		// Cache the open task, then complete it behind the cache's back
+		_, err := cached.GetByID(context.Background(), "task-1")
+		assert.NoError(t, err)
+		mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Write docs", Completed: true, UpdatedAt: updatedAt})
+
+		resp, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:        "task-1",
+			Completed: false,
+		}))
+
+		assert.NoError(t, err)
+		assert.False(t, resp.Msg.Task.Completed, "expected the reopen to be written")
+		assert.True(t, resp.Msg.Task.UpdatedAt.AsTime().After(updatedAt.AsTime()))
+	})
+
+	t.Run("configured to write no-op updates", func(t *testing.T) {
+		service := newService(Config{WriteNoOpUpdates: true})
+
+		resp, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:    "task-1",
+			Title: "Write docs",
+		}))
+
+		assert.NoError(t, err)
+		assert.True(t, resp.Msg.Task.UpdatedAt.AsTime().After(updatedAt.AsTime()))
+	})
+
+	t.Run("missing task", func(t *testing.T) {
+		service := newService(Config{})
+
+		_, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{Id: "missing"}))
+
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_CreateTask_LogsEvent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := middleware.NewStructuredLogger(middleware.LevelInfo)
+	logger.SetOutput(&buf)
+	service := NewTodoServiceWithConfig(repository.NewMockTodoRepository(), Config{Logger: logger})
+
+	resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Ship it"}))
+	assert.NoError(t, err)
+
+	var event *middleware.LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry middleware.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Fields["category"] == "event" {
+			event = &entry
+		}
+	}
+	if assert.NotNil(t, event, "expected an event log entry in %s", buf.String()) {
+		assert.Equal(t, "INFO", event.Level)
+		assert.Equal(t, "task_created", event.Fields["event"])
+		assert.Equal(t, resp.Msg.Task.Id, event.Fields["task_id"])
+	}
+}
+
+func TestTodoService_UpdateTask_LogsTransition(t *testing.T) {
+	var buf bytes.Buffer
+	logger := middleware.NewStructuredLogger(middleware.LevelInfo)
+	logger.SetOutput(&buf)
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, Config{Logger: logger})
+	ctx := context.Background()
+	mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Write docs", UpdatedAt: timestamppb.Now()})
+
+	lastEvent := func() string {
+		event := ""
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var entry middleware.LogEntry
+			if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Fields["category"] == "event" {
+				event, _ = entry.Fields["event"].(string)
+			}
+		}
+		buf.Reset()
+		return event
+	}
+
+	for _, step := range []struct {
+		name string
+		req  *todov1.UpdateTaskRequest
+		want string
+	}{
+		{"completing", &todov1.UpdateTaskRequest{Id: "task-1", Completed: true}, "task_completed"},
+		{"renaming a completed task", &todov1.UpdateTaskRequest{Id: "task-1", Title: "Write more docs", Completed: true}, "task_updated"},
+		{"reopening", &todov1.UpdateTaskRequest{Id: "task-1"}, "task_reopened"},
+		{"renaming an open task", &todov1.UpdateTaskRequest{Id: "task-1", Title: "Write docs"}, "task_updated"},
+	} {
+		_, err := service.UpdateTask(ctx, connect.NewRequest(step.req))
+		assert.NoError(t, err, step.name)
+		assert.Equal(t, step.want, lastEvent(), step.name)
+	}
+}
+
+func TestTodoService_UpdateTask_Mask(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+	mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Write docs", Completed: true, UpdatedAt: timestamppb.Now()})
+
+	t.Run("completed alone keeps the title", func(t *testing.T) {
+		resp, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:         "task-1",
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"completed"}},
+		}))
+		assert.NoError(t, err)
+		assert.False(t, resp.Msg.Task.Completed)
+		assert.Equal(t, "Write docs", resp.Msg.Task.Title)
+	})
+
+	t.Run("title alone keeps completed", func(t *testing.T) {
+		mockRepo.AddTask(&todov1.Task{Id: "task-2", Title: "Draft", Completed: true, UpdatedAt: timestamppb.Now()})
+		resp, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{
+			Id:         "task-2",
+			Title:      "Final",
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		}))
+		assert.NoError(t, err)
+		assert.True(t, resp.Msg.Task.Completed)
+		assert.Equal(t, "Final", resp.Msg.Task.Title)
+	})
+
+	t.Run("invalid masks", func(t *testing.T) {
+		for _, req := range []*todov1.UpdateTaskRequest{
+			{Id: "task-1", UpdateMask: &fieldmaskpb.FieldMask{}},
+			{Id: "task-1", UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"position"}}},
+			{Id: "task-1", UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}}},
+			{Id: "task-1", ClearDueDate: true, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"due_date"}}},
+		} {
+			_, err := service.UpdateTask(ctx, connect.NewRequest(req))
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), "mask %v", req.UpdateMask.Paths)
+		}
+	})
+}
+
+func TestTodoService_ReorderTask(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+
+	first, _ := mockRepo.Create(ctx, &repository.CreateTaskRequest{Title: "First"})
+	second, _ := mockRepo.Create(ctx, &repository.CreateTaskRequest{Title: "Second"})
+
+	t.Run("moves to the top", func(t *testing.T) {
+		resp, err := service.ReorderTask(ctx, connect.NewRequest(&todov1.ReorderTaskRequest{Id: second.Id}))
+
+		assert.NoError(t, err)
+		assert.Less(t, resp.Msg.Task.Position, first.Position)
+	})
+
+	t.Run("rejects moving after itself", func(t *testing.T) {
+		_, err := service.ReorderTask(ctx, connect.NewRequest(&todov1.ReorderTaskRequest{Id: first.Id, AfterId: first.Id}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+
+	t.Run("missing anchor", func(t *testing.T) {
+		_, err := service.ReorderTask(ctx, connect.NewRequest(&todov1.ReorderTaskRequest{Id: first.Id, AfterId: "missing"}))
+
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_CreateTask_Location(t *testing.T) {
+	t.Run("path-only location by default", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{
+			Title: "Located task",
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/todo.v1.TodoService/GetTask?id="+resp.Msg.Task.Id, resp.Header().Get("Location"))
+	})
+
+	t.Run("configured public base url", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithConfig(mockRepo, Config{PublicBaseURL: "https://api.example.com/"})
+
+		resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{
+			Title: "Located task",
+		}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://api.example.com/todo.v1.TodoService/GetTask?id="+resp.Msg.Task.Id, resp.Header().Get("Location"))
+	})
+}
+
+func TestTodoService_DuplicateTask(t *testing.T) {
+	t.Run("copies title with suffix as a new pending task", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "source", Title: "Write report", Completed: true})
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		resp, err := service.DuplicateTask(context.Background(), connect.NewRequest(&todov1.DuplicateTaskRequest{
+			Id:            "source",
+			AddCopySuffix: true,
+		}))
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "source", resp.Msg.Task.Id)
+		assert.Equal(t, "Write report (copy)", resp.Msg.Task.Title)
+		assert.False(t, resp.Msg.Task.Completed)
+		assert.Len(t, mockRepo.GetAllTasks(), 2)
+	})
+
+	t.Run("source task missing", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		resp, err := service.DuplicateTask(context.Background(), connect.NewRequest(&todov1.DuplicateTaskRequest{
+			Id: "gone",
+		}))
+
+		assert.Error(t, err)
+		assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+		assert.Nil(t, resp)
+	})
+}
+
+func TestTodoService_CreateTask_CustomRule(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithConfig(mockRepo, Config{
+		Validation: validator.Config{
+			CreateRules: []validator.CreateTaskRule{func(req *todov1.CreateTaskRequest) error {
+				if strings.HasPrefix(req.Title, "TODO:") {
+					return validator.ValidationError{Field: "title", Message: `title cannot start with "TODO:"`}
+				}
+				return nil
+			}},
+		},
+	})
+
+	_, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "TODO: write tests"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.Empty(t, mockRepo.GetAllTasks())
+
+	_, err = service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Write tests"}))
+	assert.NoError(t, err)
+}
+
+func TestTodoService_CreateTask_MaxTasks(t *testing.T) {
+	newService := func(existing int) (*TodoService, *repository.MockTodoRepository) {
+		mockRepo := repository.NewMockTodoRepository()
+		for i := 0; i < existing; i++ {
+			mockRepo.AddTask(&todov1.Task{Id: fmt.Sprintf("task-%d", i), Title: "Existing"})
+		}
+		return NewTodoServiceWithConfig(mockRepo, Config{MaxTasks: 3}), mockRepo
+	}
+
+	t.Run("one below the limit", func(t *testing.T) {
+		service, mockRepo := newService(2)
+
+		_, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Third"}))
+
+		assert.NoError(t, err)
+		assert.Len(t, mockRepo.GetAllTasks(), 3)
+	})
+
+	t.Run("exactly at the limit", func(t *testing.T) {
+		service, mockRepo := newService(3)
+
+		resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Fourth"}))
+
+		assert.Error(t, err)
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+		assert.Nil(t, resp)
+		assert.Len(t, mockRepo.GetAllTasks(), 3)
+	})
+
+	t.Run("duplicate counts against the limit", func(t *testing.T) {
+		service, _ := newService(3)
+
+		_, err := service.DuplicateTask(context.Background(), connect.NewRequest(&todov1.DuplicateTaskRequest{Id: "task-0"}))
+
+		assert.Equal(t, connect.CodeResourceExhausted, connect.CodeOf(err))
+	})
+
+	t.Run("zero means unlimited", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		for i := 0; i < 5; i++ {
+			mockRepo.AddTask(&todov1.Task{Id: fmt.Sprintf("task-%d", i), Title: "Existing"})
+		}
+		service := NewTodoServiceWithConfig(mockRepo, Config{})
+
+		_, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Sixth"}))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestTodoService_ArchiveOldCompleted(t *testing.T) {
+	newRepo := func() *repository.MockTodoRepository {
+		mockRepo := repository.NewMockTodoRepository()
+		old := timestamppb.New(time.Now().Add(-10 * 24 * time.Hour))
+		mockRepo.AddTask(&todov1.Task{Id: "old-done", Title: "Old", Completed: true, UpdatedAt: old})
+		mockRepo.AddTask(&todov1.Task{Id: "old-pending", Title: "Pending", UpdatedAt: old})
+		mockRepo.AddTask(&todov1.Task{Id: "new-done", Title: "New", Completed: true, UpdatedAt: timestamppb.Now()})
+		return mockRepo
+	}
+
+	t.Run("archives and hides old completed tasks", func(t *testing.T) {
+		mockRepo := newRepo()
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		resp, err := service.ArchiveOldCompleted(context.Background(), connect.NewRequest(&todov1.ArchiveOldCompletedRequest{OlderThanDays: 7}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), resp.Msg.ArchivedCount)
+
+		list, err := service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{}))
+		assert.NoError(t, err)
+		assert.Len(t, list.Msg.Tasks, 2)
+
+		list, err = service.ListTasks(context.Background(), connect.NewRequest(&todov1.ListTasksRequest{IncludeArchived: true}))
+		assert.NoError(t, err)
+		assert.Len(t, list.Msg.Tasks, 3)
+	})
+
+	t.Run("falls back to configured retention", func(t *testing.T) {
+		service := NewTodoServiceWithConfig(newRepo(), Config{ArchiveAfter: 7 * 24 * time.Hour})
+
+		resp, err := service.ArchiveOldCompleted(context.Background(), connect.NewRequest(&todov1.ArchiveOldCompletedRequest{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), resp.Msg.ArchivedCount)
+	})
+
+	t.Run("requires retention when none is configured", func(t *testing.T) {
+		service := NewTodoServiceWithRepository(newRepo())
+
+		_, err := service.ArchiveOldCompleted(context.Background(), connect.NewRequest(&todov1.ArchiveOldCompletedRequest{}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_PurgeDeleted(t *testing.T) {
+	now := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	newService := func(config Config) *TodoService {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "old", Title: "Old"})
+		mockRepo.AddTask(&todov1.Task{Id: "recent", Title: "Recent"})
+		mockRepo.SetSoftDelete(true)
+		mockRepo.SetClock(repository.FixedClock{T: now.Add(-10 * 24 * time.Hour)})
+		mockRepo.Delete(context.Background(), "old")
+		mockRepo.SetClock(repository.FixedClock{T: now.Add(-24 * time.Hour)})
+		mockRepo.Delete(context.Background(), "recent")
+
+		config.Clock = repository.FixedClock{T: now}
+		return NewTodoServiceWithConfig(mockRepo, config)
+	}
+
+	t.Run("purges only tasks deleted before the retention period", func(t *testing.T) {
+		service := newService(Config{})
+
+		resp, err := service.PurgeDeleted(context.Background(), connect.NewRequest(&todov1.PurgeDeletedRequest{OlderThanDays: 7}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), resp.Msg.PurgedCount)
+
+		stats, err := service.GetStats(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), stats.Msg.Deleted)
+	})
+
+	t.Run("falls back to configured retention", func(t *testing.T) {
+		service := newService(Config{TrashRetention: 12 * time.Hour})
+
+		resp, err := service.PurgeDeleted(context.Background(), connect.NewRequest(&todov1.PurgeDeletedRequest{}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(2), resp.Msg.PurgedCount)
+	})
+
+	t.Run("requires retention when none is configured", func(t *testing.T) {
+		service := newService(Config{})
+
+		_, err := service.PurgeDeleted(context.Background(), connect.NewRequest(&todov1.PurgeDeletedRequest{}))
+
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
+
+func TestTodoService_FrozenClock(t *testing.T) {
+	frozen := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := repository.FixedClock{T: frozen}
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.SetClock(clock)
+	service := NewTodoServiceWithConfig(mockRepo, Config{Clock: clock})
+
+	created, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Stamped"}))
+	assert.NoError(t, err)
+	assert.True(t, created.Msg.Task.CreatedAt.AsTime().Equal(frozen))
+	assert.True(t, created.Msg.Task.UpdatedAt.AsTime().Equal(frozen))
+
+	// The archive cutoff comes from the service clock, so a task completed
+	// eight days before the frozen instant is archived regardless of wall time
+	mockRepo.AddTask(&todov1.Task{Id: "done", Title: "Done", Completed: true, UpdatedAt: timestamppb.New(frozen.Add(-8 * 24 * time.Hour))})
+	resp, err := service.ArchiveOldCompleted(context.Background(), connect.NewRequest(&todov1.ArchiveOldCompletedRequest{OlderThanDays: 7}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), resp.Msg.ArchivedCount)
+
+	archived, err := mockRepo.GetByID(context.Background(), "done")
+	assert.NoError(t, err)
+	assert.True(t, archived.ArchivedAt.AsTime().Equal(frozen))
+}
+
+func TestTodoService_GetTask_IfModifiedSince(t *testing.T) {
+	updatedAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Poll me", UpdatedAt: timestamppb.New(updatedAt)})
+	service := NewTodoServiceWithRepository(mockRepo)
+
+	tests := []struct {
+		name         string
+		since        *timestamppb.Timestamp
+		wantModified bool
+	}{
+		{name: "no condition", since: nil, wantModified: true},
+		{name: "older than the update", since: timestamppb.New(updatedAt.Add(-time.Second)), wantModified: true},
+		// A later write within the same second keeps the same updated_at
+		{name: "equal to the update", since: timestamppb.New(updatedAt), wantModified: true},
+		{name: "later in the same second", since: timestamppb.New(updatedAt.Add(500 * time.Millisecond)), wantModified: false},
+		{name: "newer than the update", since: timestamppb.New(updatedAt.Add(time.Hour)), wantModified: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.GetTask(context.Background(), connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1", IfModifiedSince: tt.since}))
+
+			assert.NoError(t, err)
+			assert.Equal(t, !tt.wantModified, resp.Msg.NotModified)
+			if tt.wantModified {
+				assert.NotNil(t, resp.Msg.Task)
+			} else {
+				assert.Nil(t, resp.Msg.Task)
+				assert.Equal(t, "true", resp.Header().Get("X-Not-Modified"))
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTask_ETag(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Cache me", UpdatedAt: timestamppb.Now()})
+	service := NewTodoServiceWithRepository(mockRepo)
+
+	first, err := service.GetTask(context.Background(), connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1"}))
+	assert.NoError(t, err)
+	etag := first.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.NotNil(t, first.Msg.Task)
+
+	t.Run("matching If-None-Match returns not modified", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1"})
+		req.Header().Set("If-None-Match", etag)
+
+		resp, err := service.GetTask(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Msg.Task)
+		assert.Equal(t, "true", resp.Header().Get("X-Not-Modified"))
+		assert.Equal(t, etag, resp.Header().Get("ETag"))
+	})
+
+	t.Run("weak and listed validators match", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1"})
+		req.Header().Set("If-None-Match", `"stale", W/`+etag)
+
+		resp, err := service.GetTask(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "true", resp.Header().Get("X-Not-Modified"))
+	})
+
+	t.Run("mismatched If-None-Match returns the task", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1"})
+		req.Header().Set("If-None-Match", `"stale"`)
+
+		resp, err := service.GetTask(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp.Msg.Task)
+		assert.Empty(t, resp.Header().Get("X-Not-Modified"))
+	})
+
+	t.Run("ETag changes when the task changes", func(t *testing.T) {
+		_, err := service.UpdateTask(context.Background(), connect.NewRequest(&todov1.UpdateTaskRequest{Id: "task-1", Completed: true}))
+		assert.NoError(t, err)
+
+		req := connect.NewRequest(&todov1.GetTaskRequest{Id: "task-1"})
+		req.Header().Set("If-None-Match", etag)
+
+		resp, err := service.GetTask(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, resp.Msg.Task)
+		assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	})
+}
+
+func TestTodoService_ListTasks_ETag(t *testing.T) {
+	repo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(repo)
+	_, handler := todov1connect.NewTodoServiceHandler(service)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	jsonClient := todov1connect.NewTodoServiceClient(server.Client(), server.URL, connect.WithProtoJSON())
+	ctx := context.Background()
+
+	created, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Cache me"}))
+	assert.NoError(t, err)
+
+	first, err := jsonClient.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{}))
+	assert.NoError(t, err)
+	etag := first.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), "expected a weak ETag, got %q", etag)
+	// ListTasks is a POST, which browsers never cache, so no freshness is claimed
+	assert.Empty(t, first.Header().Get("Cache-Control"))
+	assert.Len(t, first.Msg.Tasks, 1)
+
+	t.Run("matching If-None-Match returns not modified", func(t *testing.T) {
+		req := connect.NewRequest(&todov1.ListTasksRequest{})
+		req.Header().Set("If-None-Match", etag)
+
+		resp, err := jsonClient.ListTasks(ctx, req)
+
+		assert.NoError(t, err)
+		assert.True(t, resp.Msg.NotModified)
+		assert.Empty(t, resp.Msg.Tasks)
+		assert.Equal(t, etag, resp.Header().Get("ETag"))
+		assert.Equal(t, "true", resp.Header().Get("X-Not-Modified"))
+	})
+
+	t.Run("different filters get a different ETag", func(t *testing.T) {
+		resp, err := jsonClient.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{Status: todov1.StatusFilter_STATUS_FILTER_COMPLETED}))
+		assert.NoError(t, err)
+		assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	})
+
+	t.Run("binary protocol gets no ETag", func(t *testing.T) {
+		protoClient := todov1connect.NewTodoServiceClient(server.Client(), server.URL)
+		resp, err := protoClient.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{}))
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Header().Get("ETag"))
+	})
+
+	t.Run("ETag changes when a task changes", func(t *testing.T) {
+		_, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: created.Msg.Task.Id, Completed: true}))
+		assert.NoError(t, err)
+
+		req := connect.NewRequest(&todov1.ListTasksRequest{})
+		req.Header().Set("If-None-Match", etag)
+		resp, err := jsonClient.ListTasks(ctx, req)
+
+		assert.NoError(t, err)
+		assert.False(t, resp.Msg.NotModified)
+		assert.Len(t, resp.Msg.Tasks, 1)
+		assert.NotEqual(t, etag, resp.Header().Get("ETag"))
+	})
+}
+
+func TestListETag(t *testing.T) {
+	updated := timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	page := func(tasks ...*todov1.Task) *todov1.ListTasksResponse {
+		return &todov1.ListTasksResponse{Tasks: tasks, Pagination: &todov1.PaginationMetadata{Page: 1, PageSize: 20, TotalItems: uint32(len(tasks))}}
+	}
+	a := &todov1.Task{Id: "a", Title: "A", UpdatedAt: updated}
+	b := &todov1.Task{Id: "b", Title: "B", UpdatedAt: updated}
+
+	assert.Equal(t, listETag(page(a, b)), listETag(page(a, b)), "same page should give the same ETag")
+	assert.NotEqual(t, listETag(page(a, b)), listETag(page(a)), "removing a task should change the ETag")
+	assert.NotEqual(t, listETag(page(a, b)), listETag(page(b, a)), "reordering should change the ETag")
+	renamed := &todov1.Task{Id: "b", Title: "B2", UpdatedAt: updated}
+	assert.NotEqual(t, listETag(page(a, b)), listETag(page(a, renamed)), "an edit should change the ETag")
+	assert.NotEqual(t, listETag(page()), "", "an empty page still has an ETag")
+}
+
+func TestTodoService_UniqueTitles(t *testing.T) {
+	ctx := context.Background()
+	newService := func(tasks ...*todov1.Task) (*TodoService, *repository.MockTodoRepository) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.SetUniqueTitles(true)
+		for _, task := range tasks {
+			mockRepo.AddTask(task)
+		}
+		return NewTodoServiceWithRepository(mockRepo), mockRepo
+	}
+
+	t.Run("conflicting title is rejected", func(t *testing.T) {
+		service, mockRepo := newService(&todov1.Task{Id: "task-1", Title: "Buy milk"})
+
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "  buy MILK "}))
+
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+		assert.Len(t, mockRepo.GetAllTasks(), 1)
+	})
+
+	t.Run("new title is allowed", func(t *testing.T) {
+		service, mockRepo := newService(&todov1.Task{Id: "task-1", Title: "Buy milk"})
+
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy eggs"}))
+
+		assert.NoError(t, err)
+		assert.Len(t, mockRepo.GetAllTasks(), 2)
+	})
+
+	t.Run("archived task keeps its title", func(t *testing.T) {
+		service, _ := newService(&todov1.Task{Id: "task-1", Title: "Buy milk", Completed: true, ArchivedAt: timestamppb.Now()})
+
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"}))
+
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+	})
+
+	t.Run("rename to a taken title is rejected", func(t *testing.T) {
+		service, _ := newService(
+			&todov1.Task{Id: "task-1", Title: "Buy milk"},
+			&todov1.Task{Id: "task-2", Title: "Buy eggs"},
+		)
+
+		_, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: "task-2", Title: "Buy milk"}))
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+
+		// A task can keep its own title, or change only its case
+		resp, err := service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: "task-1", Title: "Buy Milk", Completed: true}))
+		assert.NoError(t, err)
+		assert.Equal(t, "Buy Milk", resp.Msg.Task.Title)
+	})
+
+	t.Run("duplicate with a taken title is rejected", func(t *testing.T) {
+		service, mockRepo := newService(
+			&todov1.Task{Id: "task-1", Title: "Buy milk"},
+			&todov1.Task{Id: "task-2", Title: "Buy milk (copy)"},
+		)
+
+		_, err := service.DuplicateTask(ctx, connect.NewRequest(&todov1.DuplicateTaskRequest{Id: "task-1", AddCopySuffix: true}))
+
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+		assert.Len(t, mockRepo.GetAllTasks(), 2)
+	})
+
+	t.Run("duplicates allowed by default", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		mockRepo.AddTask(&todov1.Task{Id: "task-1", Title: "Buy milk"})
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		_, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Buy milk"}))
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestTodoService_CreateTask_ClientID(t *testing.T) {
+	const clientID = "0f8fad5b-d9cb-469f-a165-70867728950e"
+
+	t.Run("provided id is used", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+
+		resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Id: clientID, Title: "Offline task"}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, clientID, resp.Msg.Task.Id)
+	})
+
+	t.Run("omitted id is generated", func(t *testing.T) {
+		service := NewTodoServiceWithRepository(repository.NewMockTodoRepository())
+
+		resp, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Title: "Server task"}))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Msg.Task.Id)
+		assert.NotEqual(t, clientID, resp.Msg.Task.Id)
+	})
+
+	t.Run("duplicate id returns already exists", func(t *testing.T) {
+		mockRepo := repository.NewMockTodoRepository()
+		service := NewTodoServiceWithRepository(mockRepo)
+		req := &todov1.CreateTaskRequest{Id: clientID, Title: "Offline task"}
+
+		_, err := service.CreateTask(context.Background(), connect.NewRequest(req))
+		assert.NoError(t, err)
+
+		_, err = service.CreateTask(context.Background(), connect.NewRequest(req))
+		assert.Equal(t, connect.CodeAlreadyExists, connect.CodeOf(err))
+		assert.Len(t, mockRepo.GetAllTasks(), 1)
+	})
+
+	t.Run("invalid id is rejected", func(t *testing.T) {
+		service := NewTodoServiceWithRepository(repository.NewMockTodoRepository())
+
+		for _, id := range []string{"not-a-uuid", strings.ToUpper(clientID), "{" + clientID + "}"} {
+			_, err := service.CreateTask(context.Background(), connect.NewRequest(&todov1.CreateTaskRequest{Id: id, Title: "Task"}))
+			assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), id)
+		}
+	})
+}
+
+// steppingClock is a Clock whose After advances time at once instead of
+// sleeping, running onWait on every wait
+type steppingClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waits  int
+	onWait func(wait int)
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *steppingClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.waits++
+	wait, now := c.waits, c.now
+	c.mu.Unlock()
+
+	if c.onWait != nil {
+		c.onWait(wait)
+	}
+	ch := make(chan time.Time, 1)
+	ch <- now
+	return ch
+}
+
+func TestTodoService_CountCreatedSince(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+
+	since := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.AddTask(&todov1.Task{Id: "before", Title: "Before", CreatedAt: timestamppb.New(since.Add(-time.Second))})
+	mockRepo.AddTask(&todov1.Task{Id: "at", Title: "At", CreatedAt: timestamppb.New(since)})
+	mockRepo.AddTask(&todov1.Task{Id: "after", Title: "After", CreatedAt: timestamppb.New(since.Add(time.Hour))})
+
+	resp, err := service.CountCreatedSince(ctx, connect.NewRequest(&todov1.CountCreatedSinceRequest{Since: timestamppb.New(since)}))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), resp.Msg.Count)
+
+	_, err = service.CountCreatedSince(ctx, connect.NewRequest(&todov1.CountCreatedSinceRequest{}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestTodoService_GetListVersion(t *testing.T) {
+	mockRepo := repository.NewMockTodoRepository()
+	service := NewTodoServiceWithRepository(mockRepo)
+	ctx := context.Background()
+
+	created, err := service.CreateTask(ctx, connect.NewRequest(&todov1.CreateTaskRequest{Title: "Versioned"}))
+	assert.NoError(t, err)
+
+	list, err := service.ListTasks(ctx, connect.NewRequest(&todov1.ListTasksRequest{}))
+	assert.NoError(t, err)
+	version, err := service.GetListVersion(ctx, connect.NewRequest(&emptypb.Empty{}))
+	assert.NoError(t, err)
+	assert.NotZero(t, list.Msg.ListVersion)
+	assert.Equal(t, list.Msg.ListVersion, version.Msg.ListVersion)
+
+	_, err = service.UpdateTask(ctx, connect.NewRequest(&todov1.UpdateTaskRequest{Id: created.Msg.Task.Id, Title: "Versioned", Completed: true}))
+	assert.NoError(t, err)
+	updated, err := service.GetListVersion(ctx, connect.NewRequest(&emptypb.Empty{}))
+	assert.NoError(t, err)
+	assert.Greater(t, updated.Msg.ListVersion, version.Msg.ListVersion)
+}
+
+func TestTodoService_ListChangedSince(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	since := timestamppb.New(created.Add(-time.Minute))
+	ctx := context.Background()
+
+	newService := func(clock *steppingClock) (*TodoService, *repository.MockTodoRepository) {
+		repo := repository.NewMockTodoRepository()
+		repo.SetClock(repository.FixedClock{T: created})
+		return NewTodoServiceWithConfig(repo, Config{Clock: clock, ChangePollInterval: time.Second}), repo
+	}
+
+	t.Run("returns existing changes immediately", func(t *testing.T) {
+		clock := &steppingClock{now: created}
+		service, repo := newService(clock)
+		repo.AddTask(&todov1.Task{Id: "1", Title: "Changed", CreatedAt: timestamppb.New(created), UpdatedAt: timestamppb.New(created)})
+
+		resp, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: since, WaitTimeoutMs: 30000}))
+		assert.NoError(t, err)
+		assert.Len(t, resp.Msg.Tasks, 1)
+		assert.Equal(t, created, resp.Msg.NextSince.AsTime())
+		assert.False(t, resp.Msg.HasMore)
+		assert.Equal(t, 0, clock.waits, "expected no wait when changes exist")
+	})
+
+	t.Run("times out empty", func(t *testing.T) {
+		clock := &steppingClock{now: created}
+		service, _ := newService(clock)
+
+		resp, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: since, WaitTimeoutMs: 2500}))
+		assert.NoError(t, err)
+		assert.Empty(t, resp.Msg.Tasks)
+		assert.Equal(t, since.AsTime(), resp.Msg.NextSince.AsTime())
+		assert.Equal(t, 3, clock.waits, "expected waits of 1s, 1s and the remaining 0.5s")
+		assert.Equal(t, created.Add(2500*time.Millisecond), clock.Now())
+	})
+
+	t.Run("returns a change that arrives while waiting", func(t *testing.T) {
+		clock := &steppingClock{now: created}
+		service, repo := newService(clock)
+		clock.onWait = func(wait int) {
+			if wait == 2 {
+				repo.AddTask(&todov1.Task{Id: "1", Title: "Late", CreatedAt: timestamppb.New(created), UpdatedAt: timestamppb.New(created)})
+			}
+		}
+
+		resp, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: since, WaitTimeoutMs: 30000}))
+		assert.NoError(t, err)
+		assert.Len(t, resp.Msg.Tasks, 1)
+		assert.Equal(t, 2, clock.waits)
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		service, _ := newService(nil)
+		service.config.Clock = nil // Wait on the real clock
+		cancelled, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err := service.ListChangedSince(cancelled, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: since, WaitTimeoutMs: 30000}))
+		assert.Equal(t, connect.CodeCanceled, connect.CodeOf(err))
+	})
+
+	t.Run("pages through tasks sharing a timestamp", func(t *testing.T) {
+		service, repo := newService(&steppingClock{now: created})
+		stamp := timestamppb.New(created)
+		for i := 0; i < changesPageSize*2+10; i++ {
+			repo.AddTask(&todov1.Task{Id: fmt.Sprintf("%03d", i), Title: "Task", CreatedAt: stamp, UpdatedAt: stamp})
+		}
+
+		seen := map[string]bool{}
+		req := &todov1.ListChangedSinceRequest{Since: since}
+		for polls := 0; ; polls++ {
+			if polls > 3 {
+				t.Fatal("expected paging to finish in 3 polls")
+			}
+			resp, err := service.ListChangedSince(ctx, connect.NewRequest(req))
+			assert.NoError(t, err)
+			for _, task := range resp.Msg.Tasks {
+				assert.False(t, seen[task.Id], "task %s returned twice", task.Id)
+				seen[task.Id] = true
+			}
+			if !resp.Msg.HasMore {
+				break
+			}
+			assert.Len(t, resp.Msg.Tasks, changesPageSize)
+			assert.Equal(t, created, resp.Msg.NextSince.AsTime())
+			req = &todov1.ListChangedSinceRequest{Since: resp.Msg.NextSince, SinceId: resp.Msg.NextSinceId}
+		}
+		assert.Len(t, seen, changesPageSize*2+10, "expected every task sharing the timestamp")
+	})
+
+	t.Run("resumes after a task updated in the same second", func(t *testing.T) {
+		service, repo := newService(&steppingClock{now: created})
+		stamp := timestamppb.New(created)
+		repo.AddTask(&todov1.Task{Id: "1", Title: "First", CreatedAt: stamp, UpdatedAt: stamp})
+
+		resp, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: since}))
+		assert.NoError(t, err)
+		assert.Equal(t, "1", resp.Msg.NextSinceId)
+
+		// A later write within the same second gets the same updated_at
+		repo.AddTask(&todov1.Task{Id: "2", Title: "Second", CreatedAt: stamp, UpdatedAt: stamp})
+		next, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{Since: resp.Msg.NextSince, SinceId: resp.Msg.NextSinceId}))
+		assert.NoError(t, err)
+		if assert.Len(t, next.Msg.Tasks, 1) {
+			assert.Equal(t, "2", next.Msg.Tasks[0].Id)
+		}
+	})
+
+	t.Run("rejects long waits", func(t *testing.T) {
+		service, _ := newService(&steppingClock{now: created})
+		_, err := service.ListChangedSince(ctx, connect.NewRequest(&todov1.ListChangedSinceRequest{WaitTimeoutMs: 30001}))
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	})
+}
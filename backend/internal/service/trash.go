@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"github.com/wcygan/simple-connect-web-stack/internal/repository"
+	"github.com/wcygan/simple-connect-web-stack/internal/validator"
+)
+
+// PurgeBatchSize is how many trashed tasks PurgeDeleted removes per statement
+const PurgeBatchSize = 500
+
+// PurgeDeleted permanently removes tasks that have been in the trash longer
+// than the retention period
+func (s *TodoService) PurgeDeleted(
+	ctx context.Context,
+	req *connect.Request[todov1.PurgeDeletedRequest],
+) (*connect.Response[todov1.PurgeDeletedResponse], error) {
+	// Validate request
+	if err := s.validator.ValidatePurgeDeleted(req.Msg); err != nil {
+		return nil, s.errorHandler.HandleValidationError(err)
+	}
+
+	retention := time.Duration(req.Msg.OlderThanDays) * 24 * time.Hour
+	if retention == 0 {
+		retention = s.config.TrashRetention
+	}
+	if retention == 0 {
+		return nil, s.errorHandler.HandleValidationError(validator.ValidationError{
+			Field:   "older_than_days",
+			Message: "older_than_days is required when no default retention is configured",
+		})
+	}
+
+	purged, err := s.repo.PurgeDeleted(ctx, s.now().Add(-retention), PurgeBatchSize)
+	if errors.Is(err, repository.ErrSoftDeleteDisabled) {
+		return nil, connect.NewError(connect.CodeUnimplemented, err)
+	}
+	if err != nil {
+		return nil, s.errorHandler.HandleRepositoryError(err)
+	}
+	s.logger.LogEvent(ctx, "tasks_purged", map[string]interface{}{
+		"purged_count": purged,
+	})
+
+	return connect.NewResponse(&todov1.PurgeDeletedResponse{
+		PurgedCount: uint32(purged),
+	}), nil
+}
@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+const (
+	// maxBatchSize is the most tasks one batch create may carry
+	maxBatchSize = 100
+
+	// maxBatchTitleChars caps the combined characters of a batch's trimmed
+	// titles, so a request cannot carry megabytes of titles even within
+	// maxBatchSize
+	maxBatchTitleChars = 16 * 1024
+)
+
+// ValidateCreateTasksBatch validates the tasks of a batch create or import.
+// Each element gets the same checks as ValidateCreateTask; the first failure
+// is reported with its index, e.g. field "tasks[3].title".
+func (v *TodoValidator) ValidateCreateTasksBatch(reqs []*todov1.CreateTaskRequest) error {
+	if len(reqs) == 0 {
+		return ValidationError{Field: "tasks", Message: "tasks cannot be empty"}
+	}
+
+	if len(reqs) > maxBatchSize {
+		return ValidationError{Field: "tasks", Message: fmt.Sprintf("cannot create more than %d tasks at once", maxBatchSize)}
+	}
+
+	total := 0
+	for i, req := range reqs {
+		if req == nil {
+			return ValidationError{Field: fmt.Sprintf("tasks[%d]", i), Message: fmt.Sprintf("task at index %d cannot be nil", i)}
+		}
+
+		total += utf8.RuneCountInString(strings.TrimSpace(req.Title))
+		if total > maxBatchTitleChars {
+			return ValidationError{
+				Field:   fmt.Sprintf("tasks[%d].title", i),
+				Message: fmt.Sprintf("titles cannot exceed %d characters in total; the budget runs out at index %d", maxBatchTitleChars, i),
+			}
+		}
+
+		if err := v.ValidateCreateTask(req); err != nil {
+			var validationErr ValidationError
+			if !errors.As(err, &validationErr) {
+				return err
+			}
+			validationErr.Field = fmt.Sprintf("tasks[%d].%s", i, validationErr.Field)
+			validationErr.Message = fmt.Sprintf("task at index %d: %s", i, validationErr.Message)
+			return validationErr
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestValidateCreateTasksBatch(t *testing.T) {
+	v := NewTodoValidator()
+	batch := func(n int, title string) []*todov1.CreateTaskRequest {
+		reqs := make([]*todov1.CreateTaskRequest, n)
+		for i := range reqs {
+			reqs[i] = &todov1.CreateTaskRequest{Title: title}
+		}
+		return reqs
+	}
+
+	t.Run("valid batch", func(t *testing.T) {
+		if err := v.ValidateCreateTasksBatch(batch(maxBatchSize, "Buy milk")); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		if err := v.ValidateCreateTasksBatch(nil); GetValidationField(err) != "tasks" {
+			t.Errorf("Expected an error on tasks, got %v", err)
+		}
+	})
+
+	t.Run("over count", func(t *testing.T) {
+		err := v.ValidateCreateTasksBatch(batch(maxBatchSize+1, "Buy milk"))
+		if GetValidationField(err) != "tasks" || !strings.Contains(err.Error(), "more than 100") {
+			t.Errorf("Expected a batch size error, got %v", err)
+		}
+	})
+
+	t.Run("over title budget", func(t *testing.T) {
+		// 70 titles of 255 characters pass one by one but exceed 16K together
+		err := v.ValidateCreateTasksBatch(batch(70, strings.Repeat("a", 255)))
+		if GetValidationField(err) != "tasks[64].title" {
+			t.Errorf("Expected the budget to run out at index 64, got %v (field %q)", err, GetValidationField(err))
+		}
+	})
+
+	t.Run("title budget counts characters", func(t *testing.T) {
+		// 100 titles of 100 two-byte characters fit in 16K characters but
+		// not in 16KiB
+		if err := v.ValidateCreateTasksBatch(batch(maxBatchSize, strings.Repeat("é", 100))); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("bad title in the middle", func(t *testing.T) {
+		reqs := batch(5, "Buy milk")
+		reqs[2].Title = "   "
+
+		err := v.ValidateCreateTasksBatch(reqs)
+
+		var validationErr ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+		if validationErr.Field != "tasks[2].title" {
+			t.Errorf("Expected field tasks[2].title, got %q", validationErr.Field)
+		}
+		if validationErr.Message != "task at index 2: title cannot be empty" {
+			t.Errorf("Expected the single-task reason with its index, got %q", validationErr.Message)
+		}
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		reqs := batch(3, "Buy milk")
+		reqs[1] = nil
+		if err := v.ValidateCreateTasksBatch(reqs); GetValidationField(err) != "tasks[1]" {
+			t.Errorf("Expected an error on tasks[1], got %v", err)
+		}
+	})
+}
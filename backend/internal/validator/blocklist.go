@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Config holds optional validation behavior
+type Config struct {
+	// Blocklist rejects titles containing any of these terms as whole words,
+	// ignoring case. Empty disables the check.
+	Blocklist []string
+
+	// SuggestTruncation adds a shortened title that fits the limit to the
+	// error returned for over-long titles
+	SuggestTruncation bool
+
+	// RequireLetterOrDigit rejects titles with no letter or digit in any
+	// script, such as "!!!" or a lone emoji
+	RequireLetterOrDigit bool
+
+	// MaxQueryLength caps ListTasks search queries in characters; zero uses
+	// DefaultMaxQueryLength
+	MaxQueryLength int
+
+	// CreateRules and UpdateRules are custom checks run after the built-in
+	// ones; see AddCreateRule
+	CreateRules []CreateTaskRule
+	UpdateRules []UpdateTaskRule
+}
+
+// LoadBlocklist reads blocked terms from a file, one per line. Blank lines and
+// lines starting with # are ignored.
+func LoadBlocklist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open blocklist: %w", err)
+	}
+	defer file.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+
+	return terms, nil
+}
+
+// compileBlocklist splits each term into lowercase words so multi-word terms
+// match as a phrase
+func compileBlocklist(terms []string) [][]string {
+	compiled := make([][]string, 0, len(terms))
+	for _, term := range terms {
+		if words := splitWords(term); len(words) > 0 {
+			compiled = append(compiled, words)
+		}
+	}
+	return compiled
+}
+
+// containsBlockedTerm reports whether title contains any blocked term on word
+// boundaries, so "assignment" does not match "ass"
+func (v *TodoValidator) containsBlockedTerm(title string) bool {
+	if len(v.blocklist) == 0 {
+		return false
+	}
+
+	words := splitWords(title)
+	for _, term := range v.blocklist {
+		for i := 0; i+len(term) <= len(words); i++ {
+			if equalWords(words[i:i+len(term)], term) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// splitWords lowercases s and splits it on anything that is not a letter or digit
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// equalWords reports whether two word slices are identical
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestTodoValidator_Blocklist(t *testing.T) {
+	v := NewTodoValidatorWithConfig(Config{Blocklist: []string{"ass", "Bad Word"}})
+
+	tests := []struct {
+		name    string
+		title   string
+		blocked bool
+	}{
+		{name: "exact match", title: "ass", blocked: true},
+		{name: "case insensitive", title: "Kick ASS today", blocked: true},
+		{name: "punctuation boundary", title: "what an ass!", blocked: true},
+		{name: "phrase match", title: "no bad  word here", blocked: true},
+		{name: "substring does not match", title: "Finish assignment", blocked: false},
+		{name: "prefix does not match", title: "Pass the salt", blocked: false},
+		{name: "partial phrase does not match", title: "bad words only", blocked: false},
+		{name: "clean title", title: "Buy milk", blocked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: tt.title})
+			if tt.blocked {
+				verr, ok := err.(ValidationError)
+				if !ok {
+					t.Fatalf("Expected ValidationError, got %v", err)
+				}
+				if verr.Field != "title" {
+					t.Errorf("Expected field title, got %q", verr.Field)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+
+			err = v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "task-1", Title: tt.title})
+			if (err != nil) != tt.blocked {
+				t.Errorf("ValidateUpdateTask blocked=%v, want %v", err != nil, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestTodoValidator_BlocklistDisabledByDefault(t *testing.T) {
+	if err := NewTodoValidator().ValidateCreateTask(&todov1.CreateTaskRequest{Title: "ass"}); err != nil {
+		t.Errorf("Expected no error without a blocklist, got %v", err)
+	}
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(path, []byte("# banned terms\nfoo\n\n  Bar Baz  \n"), 0o600); err != nil {
+		t.Fatalf("Failed to write blocklist: %v", err)
+	}
+
+	terms, err := LoadBlocklist(path)
+	if err != nil {
+		t.Fatalf("Failed to load blocklist: %v", err)
+	}
+	if len(terms) != 2 || terms[0] != "foo" || terms[1] != "Bar Baz" {
+		t.Errorf("Unexpected terms: %q", terms)
+	}
+
+	if _, err := LoadBlocklist(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
@@ -0,0 +1,29 @@
+package validator
+
+import (
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// The range a MySQL TIMESTAMP column, such as due_date, can hold
+var (
+	minDueDate = time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+	maxDueDate = time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC)
+)
+
+// validateDueDate rejects a due date that is not a valid timestamp or that
+// the due_date column cannot store. MySQL rounds fractional seconds, so the
+// bounds apply to the rounded value.
+func validateDueDate(ts *timestamppb.Timestamp) error {
+	if ts.CheckValid() != nil {
+		return ValidationError{Field: "due_date", Message: "due_date is not a valid timestamp"}
+	}
+	due := ts.AsTime().Round(time.Second)
+	if due.Before(minDueDate) || due.After(maxDueDate) {
+		return ValidationError{Field: "due_date", Message: fmt.Sprintf("due_date must be between %s and %s",
+			minDueDate.Format(time.RFC3339), maxDueDate.Format(time.RFC3339))}
+	}
+	return nil
+}
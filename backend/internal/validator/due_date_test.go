@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestValidateDueDate_Range(t *testing.T) {
+	tests := []struct {
+		name  string
+		due   time.Time
+		valid bool
+	}{
+		{name: "first storable second", due: time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC), valid: true},
+		{name: "epoch", due: time.Unix(0, 0), valid: false},
+		{name: "before 1970", due: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), valid: false},
+		{name: "rounds up into range", due: time.Date(1970, 1, 1, 0, 0, 0, 600_000_000, time.UTC), valid: true},
+		{name: "last storable second", due: time.Date(2038, 1, 19, 3, 14, 7, 0, time.UTC), valid: true},
+		{name: "rounds up out of range", due: time.Date(2038, 1, 19, 3, 14, 7, 500_000_000, time.UTC), valid: false},
+		{name: "after 2038", due: time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC), valid: false},
+		{name: "offset zone in range", due: time.Date(2038, 1, 19, 4, 14, 7, 0, time.FixedZone("CET", 3600)), valid: true},
+	}
+
+	v := NewTodoValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due := timestamppb.New(tt.due)
+			createErr := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: "Renew passport", DueDate: due})
+			updateErr := v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "550e8400-e29b-41d4-a716-446655440000", DueDate: due})
+			for _, err := range []error{createErr, updateErr} {
+				if tt.valid && err != nil {
+					t.Errorf("Expected %v to be accepted, got %v", tt.due, err)
+				}
+				if !tt.valid {
+					if verr, ok := err.(ValidationError); !ok || verr.Field != "due_date" {
+						t.Errorf("Expected a due_date validation error for %v, got %v", tt.due, err)
+					}
+				}
+			}
+		})
+	}
+}
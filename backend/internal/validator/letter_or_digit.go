@@ -0,0 +1,18 @@
+package validator
+
+import "unicode"
+
+// checkLetterOrDigit rejects a title without a single letter or digit when the
+// rule is enabled. Letters in every script count, so CJK, Cyrillic or Arabic
+// titles pass while "!!!" and emoji-only titles do not.
+func (v *TodoValidator) checkLetterOrDigit(title string) error {
+	if !v.requireLetterOrDigit {
+		return nil
+	}
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return nil
+		}
+	}
+	return ValidationError{Field: "title", Message: "title must contain at least one letter or digit"}
+}
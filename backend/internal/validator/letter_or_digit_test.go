@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestTodoValidator_RequireLetterOrDigit(t *testing.T) {
+	v := NewTodoValidatorWithConfig(Config{RequireLetterOrDigit: true})
+
+	tests := []struct {
+		name     string
+		title    string
+		rejected bool
+	}{
+		{name: "latin", title: "Buy milk!"},
+		{name: "cjk", title: "买牛奶"},
+		{name: "japanese", title: "牛乳を買う。"},
+		{name: "cyrillic", title: "Купить молоко"},
+		{name: "arabic", title: "شراء الحليب"},
+		{name: "digits only", title: "42"},
+		{name: "non-latin digits", title: "٤٢"},
+		{name: "emoji with a word", title: "🥛 milk"},
+		{name: "pure punctuation", title: "!!!", rejected: true},
+		{name: "cjk punctuation", title: "。、！", rejected: true},
+		{name: "single emoji", title: "🥛", rejected: true},
+		{name: "emoji sequence", title: "👨‍👩‍👧 🎉", rejected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: tt.title})
+			if tt.rejected {
+				if GetValidationField(err) != "title" {
+					t.Errorf("Expected a title validation error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+
+			err = v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "task-1", Title: tt.title})
+			if (err != nil) != tt.rejected {
+				t.Errorf("ValidateUpdateTask rejected=%v, want %v", err != nil, tt.rejected)
+			}
+		})
+	}
+
+	if err := NewTodoValidator().ValidateCreateTask(&todov1.CreateTaskRequest{Title: "!!!"}); err != nil {
+		t.Errorf("Expected the rule to be off by default, got %v", err)
+	}
+}
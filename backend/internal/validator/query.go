@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxQueryLength is the longest search query in characters accepted
+// when Config.MaxQueryLength is zero
+const DefaultMaxQueryLength = 256
+
+// validateQuery rejects search queries that are too long or contain control
+// characters such as newlines or NUL, which would only bloat the LIKE pattern
+func (v *TodoValidator) validateQuery(query string) error {
+	limit := v.maxQueryLength
+	if limit <= 0 {
+		limit = DefaultMaxQueryLength
+	}
+	if n := utf8.RuneCountInString(query); n > limit {
+		return ValidationError{Field: "query", Message: fmt.Sprintf("query cannot exceed %d characters, got %d", limit, n)}
+	}
+
+	if !utf8.ValidString(query) {
+		return ValidationError{Field: "query", Message: "query must be valid UTF-8"}
+	}
+	for i, r := range query {
+		if unicode.IsControl(r) {
+			return ValidationError{Field: "query", Message: fmt.Sprintf("query contains a control character %U at byte %d", r, i)}
+		}
+	}
+	return nil
+}
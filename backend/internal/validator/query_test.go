@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestTodoValidator_ListTasksQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		query    string
+		rejected bool
+	}{
+		{name: "empty", query: ""},
+		{name: "plain words", query: "buy milk"},
+		{name: "at the default limit", query: strings.Repeat("a", DefaultMaxQueryLength)},
+		{name: "limit counts characters not bytes", query: strings.Repeat("é", DefaultMaxQueryLength)},
+		{name: "over the default limit", query: strings.Repeat("a", DefaultMaxQueryLength+1), rejected: true},
+		{name: "multi-kilobyte", query: strings.Repeat("milk ", 1000), rejected: true},
+		{name: "configured limit", config: Config{MaxQueryLength: 8}, query: "buy milk!", rejected: true},
+		{name: "newline", query: "buy\nmilk", rejected: true},
+		{name: "null byte", query: "milk\x00", rejected: true},
+		{name: "tab", query: "buy\tmilk", rejected: true},
+		{name: "invalid utf-8", query: "milk\xff", rejected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewTodoValidatorWithConfig(tt.config)
+			for _, err := range []error{
+				v.ValidateListTasks(&todov1.ListTasksRequest{Query: tt.query}),
+				v.ValidateListTasksStream(&todov1.ListTasksRequest{Query: tt.query}),
+			} {
+				if tt.rejected {
+					if GetValidationField(err) != "query" {
+						t.Errorf("Expected a query validation error, got %v", err)
+					}
+				} else if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package validator
+
+import (
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+// CreateTaskRule is a deployment-specific check run after the built-in
+// CreateTask validation has passed. It should return a ValidationError
+// describing the offending field, or nil to accept the request.
+type CreateTaskRule func(req *todov1.CreateTaskRequest) error
+
+// UpdateTaskRule is the UpdateTask counterpart of CreateTaskRule
+type UpdateTaskRule func(req *todov1.UpdateTaskRequest) error
+
+// AddCreateRule registers a rule for ValidateCreateTask. Rules run in the
+// order they were added and the first failure is returned. Register rules
+// before the validator starts serving requests.
+func (v *TodoValidator) AddCreateRule(rule CreateTaskRule) {
+	v.createRules = append(v.createRules, rule)
+}
+
+// AddUpdateRule registers a rule for ValidateUpdateTask, like AddCreateRule
+func (v *TodoValidator) AddUpdateRule(rule UpdateTaskRule) {
+	v.updateRules = append(v.updateRules, rule)
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+// noTodoPrefix rejects titles that start with "TODO:"
+func noTodoPrefix(title string) error {
+	if strings.HasPrefix(strings.TrimSpace(title), "TODO:") {
+		return ValidationError{Field: "title", Message: `title cannot start with "TODO:"`}
+	}
+	return nil
+}
+
+func TestTodoValidator_CustomRules(t *testing.T) {
+	v := NewTodoValidator()
+	v.AddCreateRule(func(req *todov1.CreateTaskRequest) error { return noTodoPrefix(req.Title) })
+	v.AddUpdateRule(func(req *todov1.UpdateTaskRequest) error { return noTodoPrefix(req.Title) })
+
+	err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: "TODO: buy milk"})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != "title" {
+		t.Errorf("Expected a title ValidationError, got %v", err)
+	}
+	if err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: "Buy milk"}); err != nil {
+		t.Errorf("Expected other titles to pass, got %v", err)
+	}
+
+	if err := v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "task-1", Title: "TODO: later"}); err == nil {
+		t.Error("Expected the update rule to reject the title")
+	}
+	if err := v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "task-1", Completed: true}); err != nil {
+		t.Errorf("Expected updates without a title to pass, got %v", err)
+	}
+}
+
+func TestTodoValidator_CustomRulesRunAfterBuiltins(t *testing.T) {
+	called := false
+	v := NewTodoValidatorWithConfig(Config{
+		CreateRules: []CreateTaskRule{func(req *todov1.CreateTaskRequest) error {
+			called = true
+			return nil
+		}},
+	})
+
+	err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: "  "})
+	if err == nil || err.Error() != "title cannot be empty" {
+		t.Errorf("Expected the built-in empty title error, got %v", err)
+	}
+	if called {
+		t.Error("Expected custom rules to be skipped when built-in checks fail")
+	}
+}
@@ -2,15 +2,21 @@ package validator
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
-	todov1 "buf.build/gen/go/wcygan/simple-connect-web-stack/protocolbuffers/go/todo/v1"
+	"github.com/google/uuid"
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
 )
 
 // ValidationError represents a validation error with context
 type ValidationError struct {
 	Field   string
 	Message string
+
+	// Suggestion is an acceptable replacement value the client may offer,
+	// e.g. a truncated title; empty when there is none
+	Suggestion string
 }
 
 func (e ValidationError) Error() string {
@@ -18,13 +24,33 @@ func (e ValidationError) Error() string {
 }
 
 // TodoValidator handles validation for todo-related operations
-type TodoValidator struct{}
+type TodoValidator struct {
+	blocklist            [][]string
+	suggestTruncation    bool
+	requireLetterOrDigit bool
+	maxQueryLength       int
+
+	createRules []CreateTaskRule
+	updateRules []UpdateTaskRule
+}
 
 // NewTodoValidator creates a new todo validator
 func NewTodoValidator() *TodoValidator {
 	return &TodoValidator{}
 }
 
+// NewTodoValidatorWithConfig creates a todo validator with optional checks enabled
+func NewTodoValidatorWithConfig(config Config) *TodoValidator {
+	return &TodoValidator{
+		blocklist:            compileBlocklist(config.Blocklist),
+		suggestTruncation:    config.SuggestTruncation,
+		requireLetterOrDigit: config.RequireLetterOrDigit,
+		maxQueryLength:       config.MaxQueryLength,
+		createRules:          append([]CreateTaskRule(nil), config.CreateRules...),
+		updateRules:          append([]UpdateTaskRule(nil), config.UpdateRules...),
+	}
+}
+
 // ValidateCreateTask validates a create task request
 func (v *TodoValidator) ValidateCreateTask(req *todov1.CreateTaskRequest) error {
 	if req == nil {
@@ -36,13 +62,54 @@ func (v *TodoValidator) ValidateCreateTask(req *todov1.CreateTaskRequest) error
 		return ValidationError{Field: "title", Message: "title cannot be empty"}
 	}
 
-	if len(title) > 255 {
-		return ValidationError{Field: "title", Message: "title cannot exceed 255 characters"}
+	if len(title) > MaxTitleLength {
+		return v.titleTooLong(title)
+	}
+
+	if v.containsBlockedTerm(title) {
+		return ValidationError{Field: "title", Message: "title contains a blocked term"}
+	}
+
+	if err := v.checkLetterOrDigit(title); err != nil {
+		return err
+	}
+
+	if req.Id != "" {
+		// Require the canonical lowercase form so the same UUID can't be stored twice
+		if parsed, err := uuid.Parse(req.Id); err != nil || parsed.String() != req.Id {
+			return ValidationError{Field: "id", Message: "id must be a lowercase hyphenated UUID"}
+		}
+	}
+
+	if req.DueDate != nil {
+		if err := validateDueDate(req.DueDate); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range v.createRules {
+		if err := rule(req); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// MaxTitleLength is the longest title in bytes that the tasks table accepts;
+// the repository holds duplicated and stored titles to it as well
+const MaxTitleLength = 255
+
+// titleTooLong returns the error for an over-long title, suggesting a
+// truncated title when enabled
+func (v *TodoValidator) titleTooLong(title string) error {
+	err := ValidationError{Field: "title", Message: fmt.Sprintf("title cannot exceed %d characters", MaxTitleLength)}
+	if v.suggestTruncation {
+		err.Suggestion = truncateTitle(title, MaxTitleLength)
+	}
+	return err
+}
+
 // ValidateGetTask validates a get task request
 func (v *TodoValidator) ValidateGetTask(req *todov1.GetTaskRequest) error {
 	if req == nil {
@@ -53,6 +120,10 @@ func (v *TodoValidator) ValidateGetTask(req *todov1.GetTaskRequest) error {
 		return ValidationError{Field: "id", Message: "id cannot be empty"}
 	}
 
+	if req.IfModifiedSince != nil && req.IfModifiedSince.CheckValid() != nil {
+		return ValidationError{Field: "if_modified_since", Message: "if_modified_since is not a valid timestamp"}
+	}
+
 	return nil
 }
 
@@ -68,14 +139,64 @@ func (v *TodoValidator) ValidateUpdateTask(req *todov1.UpdateTaskRequest) error
 
 	if req.Title != "" {
 		title := strings.TrimSpace(req.Title)
-		if len(title) > 255 {
-			return ValidationError{Field: "title", Message: "title cannot exceed 255 characters"}
+		if len(title) > MaxTitleLength {
+			return v.titleTooLong(title)
+		}
+		if v.containsBlockedTerm(title) {
+			return ValidationError{Field: "title", Message: "title contains a blocked term"}
+		}
+		if err := v.checkLetterOrDigit(title); err != nil {
+			return err
+		}
+	}
+
+	if req.DueDate != nil {
+		if req.ClearDueDate {
+			return ValidationError{Field: "due_date", Message: "due_date cannot be combined with clear_due_date"}
+		}
+		if err := validateDueDate(req.DueDate); err != nil {
+			return err
+		}
+	}
+
+	if req.UpdateMask != nil {
+		if err := validateUpdateMask(req); err != nil {
+			return err
+		}
+	}
+
+	for _, rule := range v.updateRules {
+		if err := rule(req); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// updateMaskPaths are the fields an UpdateTask update_mask may name
+var updateMaskPaths = map[string]bool{"title": true, "completed": true, "due_date": true}
+
+// validateUpdateMask checks that an update mask names at least one known field
+func validateUpdateMask(req *todov1.UpdateTaskRequest) error {
+	paths := req.UpdateMask.GetPaths()
+	if len(paths) == 0 {
+		return ValidationError{Field: "update_mask", Message: "update_mask must name at least one field"}
+	}
+	for _, path := range paths {
+		if !updateMaskPaths[path] {
+			return ValidationError{Field: "update_mask", Message: fmt.Sprintf("unknown update_mask path %q: expected title, completed or due_date", path)}
+		}
+		if path == "title" && strings.TrimSpace(req.Title) == "" {
+			return ValidationError{Field: "title", Message: "title cannot be empty"}
+		}
+	}
+	if req.ClearDueDate {
+		return ValidationError{Field: "clear_due_date", Message: "clear_due_date cannot be combined with update_mask; mask due_date and leave it unset"}
+	}
+	return nil
+}
+
 // ValidateDeleteTask validates a delete task request
 func (v *TodoValidator) ValidateDeleteTask(req *todov1.DeleteTaskRequest) error {
 	if req == nil {
@@ -89,6 +210,85 @@ func (v *TodoValidator) ValidateDeleteTask(req *todov1.DeleteTaskRequest) error
 	return nil
 }
 
+// ValidateDuplicateTask validates a duplicate task request
+func (v *TodoValidator) ValidateDuplicateTask(req *todov1.DuplicateTaskRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.Id == "" {
+		return ValidationError{Field: "id", Message: "id cannot be empty"}
+	}
+
+	return nil
+}
+
+// ValidateReorderTask validates a reorder task request
+func (v *TodoValidator) ValidateReorderTask(req *todov1.ReorderTaskRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.Id == "" {
+		return ValidationError{Field: "id", Message: "id cannot be empty"}
+	}
+
+	if req.AfterId == req.Id {
+		return ValidationError{Field: "after_id", Message: "a task cannot be moved after itself"}
+	}
+
+	return nil
+}
+
+// ValidateArchiveOldCompleted validates an archive request
+func (v *TodoValidator) ValidateArchiveOldCompleted(req *todov1.ArchiveOldCompletedRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.OlderThanDays > 36500 {
+		return ValidationError{Field: "older_than_days", Message: "older_than_days cannot exceed 36500"}
+	}
+
+	return nil
+}
+
+// ValidatePurgeDeleted validates a purge request
+func (v *TodoValidator) ValidatePurgeDeleted(req *todov1.PurgeDeletedRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.OlderThanDays > 36500 {
+		return ValidationError{Field: "older_than_days", Message: "older_than_days cannot exceed 36500"}
+	}
+
+	return nil
+}
+
+// ValidateDeleteTasks validates a batch delete request
+func (v *TodoValidator) ValidateDeleteTasks(req *todov1.DeleteTasksRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if len(req.Ids) == 0 {
+		return ValidationError{Field: "ids", Message: "ids cannot be empty"}
+	}
+
+	if len(req.Ids) > 100 {
+		return ValidationError{Field: "ids", Message: "cannot delete more than 100 tasks at once"}
+	}
+
+	for i, id := range req.Ids {
+		if id == "" {
+			return ValidationError{Field: fmt.Sprintf("ids[%d]", i), Message: fmt.Sprintf("id at index %d cannot be empty", i)}
+		}
+	}
+
+	return nil
+}
+
 // ValidateListTasks validates a list tasks request
 func (v *TodoValidator) ValidateListTasks(req *todov1.ListTasksRequest) error {
 	if req == nil {
@@ -99,6 +299,85 @@ func (v *TodoValidator) ValidateListTasks(req *todov1.ListTasksRequest) error {
 		return ValidationError{Field: "page_size", Message: "page size cannot exceed 100"}
 	}
 
+	if err := v.validateQuery(req.Query); err != nil {
+		return err
+	}
+
+	if req.GroupByStatus && req.Status != todov1.StatusFilter_STATUS_FILTER_UNSPECIFIED && req.Status != todov1.StatusFilter_STATUS_FILTER_ALL {
+		return ValidationError{Field: "status", Message: "status filter cannot be combined with group_by_status"}
+	}
+
+	if req.IdsOnly && req.GroupByStatus {
+		return ValidationError{Field: "ids_only", Message: "ids_only cannot be combined with group_by_status"}
+	}
+
+	if _, ok := todov1.DueDateFilter_name[int32(req.DueDate)]; !ok {
+		return ValidationError{Field: "due_date", Message: "unknown due_date filter"}
+	}
+
+	return nil
+}
+
+// ValidateListTasksStream validates a ListTasksStream request, which takes the
+// ListTasks filters but allows pages of up to 10000 tasks and no grouping
+func (v *TodoValidator) ValidateListTasksStream(req *todov1.ListTasksRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.PageSize > 10000 {
+		return ValidationError{Field: "page_size", Message: "page size cannot exceed 10000"}
+	}
+
+	if err := v.validateQuery(req.Query); err != nil {
+		return err
+	}
+
+	if req.GroupByStatus {
+		return ValidationError{Field: "group_by_status", Message: "group_by_status is not supported when streaming"}
+	}
+
+	if req.IdsOnly {
+		return ValidationError{Field: "ids_only", Message: "ids_only is not supported when streaming"}
+	}
+
+	if _, ok := todov1.DueDateFilter_name[int32(req.DueDate)]; !ok {
+		return ValidationError{Field: "due_date", Message: "unknown due_date filter"}
+	}
+
+	return nil
+}
+
+// ValidateListChangedSince validates a ListChangedSince request
+func (v *TodoValidator) ValidateListChangedSince(req *todov1.ListChangedSinceRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.Since != nil && req.Since.CheckValid() != nil {
+		return ValidationError{Field: "since", Message: "since is not a valid timestamp"}
+	}
+
+	if req.WaitTimeoutMs > 30000 {
+		return ValidationError{Field: "wait_timeout_ms", Message: "wait_timeout_ms cannot exceed 30000"}
+	}
+
+	return nil
+}
+
+// ValidateCountCreatedSince validates a CountCreatedSince request
+func (v *TodoValidator) ValidateCountCreatedSince(req *todov1.CountCreatedSinceRequest) error {
+	if req == nil {
+		return ValidationError{Field: "request", Message: "request cannot be nil"}
+	}
+
+	if req.Since == nil {
+		return ValidationError{Field: "since", Message: "since is required"}
+	}
+	if req.Since.CheckValid() != nil {
+		return ValidationError{Field: "since", Message: "since is not a valid timestamp"}
+	}
+
 	return nil
 }
 
@@ -115,4 +394,4 @@ func GetValidationField(err error) string {
 		return validationErr.Field
 	}
 	return ""
-}
\ No newline at end of file
+}
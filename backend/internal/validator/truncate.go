@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner = '\u200d'
+	keycapCombiner  = '\u20e3'
+)
+
+// truncateTitle shortens title to at most maxBytes bytes without splitting a
+// rune or a user-perceived character: combining marks, variation selectors,
+// emoji skin-tone modifiers, tag sequences, ZWJ sequences and flag pairs stay
+// with the character they modify. Trailing whitespace is trimmed.
+func truncateTitle(title string, maxBytes int) string {
+	if len(title) <= maxBytes {
+		return title
+	}
+
+	// Start at the last rune boundary that fits, then back up to a character boundary
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(title[cut]) {
+		cut--
+	}
+	for cut > 0 && !isCharacterBoundary(title, cut) {
+		_, size := utf8.DecodeLastRuneInString(title[:cut])
+		cut -= size
+	}
+
+	return strings.TrimRightFunc(title[:cut], unicode.IsSpace)
+}
+
+// isCharacterBoundary reports whether cutting s at byte offset i keeps every
+// user-perceived character whole
+func isCharacterBoundary(s string, i int) bool {
+	if i <= 0 || i >= len(s) {
+		return true
+	}
+
+	next, _ := utf8.DecodeRuneInString(s[i:])
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+
+	if extendsPrevious(next) || prev == zeroWidthJoiner {
+		return false
+	}
+
+	// Regional indicators pair up into flags; cutting after an odd count splits one
+	if isRegionalIndicator(next) && isRegionalIndicator(prev) {
+		count := 0
+		for j := i; j > 0; {
+			r, size := utf8.DecodeLastRuneInString(s[:j])
+			if !isRegionalIndicator(r) {
+				break
+			}
+			count++
+			j -= size
+		}
+		return count%2 == 0
+	}
+
+	return true
+}
+
+// extendsPrevious reports whether r attaches to the character before it
+func extendsPrevious(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner, r == keycapCombiner:
+		return true
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r), unicode.Is(unicode.Mc, r):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f: // variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff: // emoji skin-tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f: // tag characters used by subdivision flags
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,93 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	todov1 "github.com/wcygan/simple-connect-web-stack/internal/gen/todo/v1"
+)
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		maxBytes int
+		want     string
+	}{
+		{name: "fits", title: "Buy milk", maxBytes: 10, want: "Buy milk"},
+		{name: "ascii", title: "Buy milk", maxBytes: 5, want: "Buy m"},
+		{name: "trailing space trimmed", title: "Buy milk", maxBytes: 4, want: "Buy"},
+		{name: "two-byte rune", title: "café", maxBytes: 4, want: "caf"},
+		{name: "combining accent", title: "café", maxBytes: 5, want: "caf"},
+		{name: "emoji", title: "ok 😀", maxBytes: 5, want: "ok"},
+		{name: "skin tone", title: "a 👍🏽", maxBytes: 6, want: "a"},
+		{name: "variation selector", title: "a ❤️", maxBytes: 5, want: "a"},
+		{name: "zwj family", title: "a 👨‍👩‍👧", maxBytes: 14, want: "a"},
+		{name: "keycap", title: "a 1️⃣", maxBytes: 6, want: "a"},
+		{name: "flag", title: "a 🇳🇱", maxBytes: 6, want: "a"},
+		{name: "second flag split", title: "🇳🇱🇧🇪", maxBytes: 12, want: "🇳🇱"},
+		{name: "subdivision flag", title: "a 🏴󠁧󠁢󠁳󠁣󠁴󠁿", maxBytes: 20, want: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateTitle(tt.title, tt.maxBytes); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTruncateTitle_NeverSplitsCharacters(t *testing.T) {
+	title := strings.Repeat("é👨‍👩‍👧🇳🇱👍🏽é ", 20)
+
+	for maxBytes := 0; maxBytes <= len(title); maxBytes++ {
+		got := truncateTitle(title, maxBytes)
+		if len(got) > maxBytes {
+			t.Fatalf("maxBytes %d: result is %d bytes", maxBytes, len(got))
+		}
+		if !utf8.ValidString(got) {
+			t.Fatalf("maxBytes %d: result %q is not valid UTF-8", maxBytes, got)
+		}
+		if !strings.HasPrefix(title, got) || !isCharacterBoundary(title, len(got)) {
+			t.Fatalf("maxBytes %d: result %q splits a character", maxBytes, got)
+		}
+	}
+}
+
+func TestTodoValidator_SuggestTruncation(t *testing.T) {
+	title := strings.Repeat("a", 250) + " 👨‍👩‍👧"
+
+	t.Run("disabled", func(t *testing.T) {
+		err := NewTodoValidator().ValidateCreateTask(&todov1.CreateTaskRequest{Title: title})
+		verr, ok := err.(ValidationError)
+		if !ok {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		if verr.Suggestion != "" {
+			t.Errorf("Expected no suggestion, got %q", verr.Suggestion)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		v := NewTodoValidatorWithConfig(Config{SuggestTruncation: true})
+		want := strings.Repeat("a", 250)
+
+		for _, err := range []error{
+			v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: title}),
+			v.ValidateUpdateTask(&todov1.UpdateTaskRequest{Id: "task-1", Title: title}),
+		} {
+			verr, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if verr.Suggestion != want {
+				t.Errorf("Expected suggestion of %d bytes, got %q", len(want), verr.Suggestion)
+			}
+			if err := v.ValidateCreateTask(&todov1.CreateTaskRequest{Title: verr.Suggestion}); err != nil {
+				t.Errorf("Expected suggestion to be valid, got %v", err)
+			}
+		}
+	})
+}
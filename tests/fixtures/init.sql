@@ -8,11 +8,20 @@ CREATE TABLE IF NOT EXISTS tasks (
     completed BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    archived_at TIMESTAMP NULL DEFAULT NULL,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    position DOUBLE NOT NULL DEFAULT 0,
+    due_date TIMESTAMP NULL DEFAULT NULL,
+    reminded_at TIMESTAMP NULL DEFAULT NULL,
     
     -- Add indexes for better test performance
     INDEX idx_completed (completed),
     INDEX idx_created_at (created_at),
-    INDEX idx_title (title(100))  -- Partial index for title searches
+    INDEX idx_archived_at (archived_at),
+    INDEX idx_title (title(100)),  -- Partial index for title searches
+    INDEX idx_tenant_created_at (tenant_id, created_at),
+    INDEX idx_position (position),
+    INDEX idx_due_date (due_date)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 -- Create a test audit table for tracking test operations